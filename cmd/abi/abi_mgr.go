@@ -47,6 +47,19 @@ func (this *AbiMgr) GetNativeAbi(address string) *NativeContractAbi {
 	return nil
 }
 
+//GetNativeAbiByName return the abi of native contract by contract name or address
+func (this *AbiMgr) GetNativeAbiByName(name string) *NativeContractAbi {
+	if abi := this.GetNativeAbi(name); abi != nil {
+		return abi
+	}
+	for _, abi := range this.nativeAbis {
+		if strings.EqualFold(abi.Name, name) {
+			return abi
+		}
+	}
+	return nil
+}
+
 func (this *AbiMgr) Init(path string) {
 	this.Path = path
 	this.loadNativeAbi()
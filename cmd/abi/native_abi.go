@@ -20,19 +20,28 @@ package abi
 
 import "strings"
 
+//Native param types, each one maps to the ZeroCopySink encoding used by the Serialization of contract params
 const (
-	NATIVE_PARAM_TYPE_BOOL      = "bool"
-	NATIVE_PARAM_TYPE_BYTE      = "byte"
-	NATIVE_PARAM_TYPE_INTEGER   = "int"
-	NATIVE_PARAM_TYPE_STRING    = "string"
-	NATIVE_PARAM_TYPE_BYTEARRAY = "bytearray"
-	NATIVE_PARAM_TYPE_ARRAY     = "array"
-	NATIVE_PARAM_TYPE_ADDRESS   = "address"
-	NATIVE_PARAM_TYPE_UINT256   = "uint256"
-	NATIVE_PARAM_TYPE_STRUCT    = "struct"
+	NATIVE_PARAM_TYPE_BOOL       = "bool"       //WriteBool
+	NATIVE_PARAM_TYPE_BYTE       = "byte"       //WriteByte
+	NATIVE_PARAM_TYPE_INTEGER    = "int"        //WriteVarUint, same as varuint
+	NATIVE_PARAM_TYPE_UINT32     = "uint32"     //WriteUint32
+	NATIVE_PARAM_TYPE_UINT64     = "uint64"     //WriteUint64
+	NATIVE_PARAM_TYPE_VARUINT    = "varuint"    //WriteVarUint
+	NATIVE_PARAM_TYPE_STRING     = "string"     //WriteString
+	NATIVE_PARAM_TYPE_BYTEARRAY  = "bytearray"  //WriteVarBytes, hex string in JSON
+	NATIVE_PARAM_TYPE_BIGINT     = "bigint"     //WriteVarBytes of big.Int bytes, decimal string or number in JSON
+	NATIVE_PARAM_TYPE_ADDRESS    = "address"    //WriteAddress, base58 or hex string in JSON
+	NATIVE_PARAM_TYPE_VARADDRESS = "varaddress" //WriteVarBytes of address, base58 or hex string in JSON
+	NATIVE_PARAM_TYPE_UINT256    = "uint256"    //WriteHash, hex string in JSON
+	NATIVE_PARAM_TYPE_ARRAY      = "array"      //WriteVarUint of length and then the items of SubType[0]
+	NATIVE_PARAM_TYPE_ARRAY64    = "array64"    //WriteUint64 of length and then the items of SubType[0]
+	NATIVE_PARAM_TYPE_MAP        = "map"        //WriteVarUint of length and then the pairs of SubType[0] and SubType[1] in key descending order
+	NATIVE_PARAM_TYPE_STRUCT     = "struct"     //fields of SubType in order
 )
 
 type NativeContractAbi struct {
+	Name      string                       `json:"name"`
 	Address   string                       `json:"hash"`
 	Functions []*NativeContractFunctionAbi `json:"functions"`
 	Events    []*NativeContractEventAbi    `json:"events"`
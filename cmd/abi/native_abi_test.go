@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/ripple"
+	"github.com/polynetwork/poly/native/service/governance/neo3_state_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/replenish"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/governance/signature_manager"
+	hscom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type nativeParam interface {
+	Deserialization(source *common.ZeroCopySource) error
+}
+
+// The param types deserialized by the native contract methods
+var nativeParams = map[string]func() nativeParam{
+	"header_sync.syncGenesisHeader":  func() nativeParam { return new(hscom.SyncGenesisHeaderParam) },
+	"header_sync.syncBlockHeader":    func() nativeParam { return new(hscom.SyncBlockHeaderParam) },
+	"header_sync.syncCrossChainMsg":  func() nativeParam { return new(hscom.SyncCrossChainMsgParam) },
+	"header_sync.setHeaderRetention": func() nativeParam { return new(hscom.SetHeaderRetentionParam) },
+
+	"cross_chain_manager.ImportOuterTransfer":    func() nativeParam { return new(ccom.EntranceParam) },
+	"cross_chain_manager.MultiSign":              func() nativeParam { return new(ccom.MultiSignParam) },
+	"cross_chain_manager.MultiSignRipple":        func() nativeParam { return new(ripple.MultiSignParam) },
+	"cross_chain_manager.ReconstructRippleTx":    func() nativeParam { return new(ripple.ReconstructTxParam) },
+	"cross_chain_manager.BlackChain":             func() nativeParam { return new(ccom.BlackChainParam) },
+	"cross_chain_manager.WhiteChain":             func() nativeParam { return new(ccom.BlackChainParam) },
+	"cross_chain_manager.isChainBlacked":         func() nativeParam { return new(ccom.BlackChainParam) },
+	"cross_chain_manager.recordFeePayment":       func() nativeParam { return new(ccom.RecordFeePaymentParam) },
+	"cross_chain_manager.releaseHeldTransfer":    func() nativeParam { return new(ccom.HeldTransferParam) },
+	"cross_chain_manager.setRateLimit":           func() nativeParam { return new(ccom.RateLimit) },
+	"cross_chain_manager.releasePendingTransfer": func() nativeParam { return new(ccom.VotePendingTransferParam) },
+	"cross_chain_manager.rejectPendingTransfer":  func() nativeParam { return new(ccom.VotePendingTransferParam) },
+	"cross_chain_manager.setQuarantineConfig":    func() nativeParam { return new(ccom.QuarantineConfig) },
+	"cross_chain_manager.getAssetSupply":         func() nativeParam { return new(side_chain_manager.QueryAssetParam) },
+
+	"side_chain_manager.registerSideChain":        func() nativeParam { return new(side_chain_manager.RegisterSideChainParam) },
+	"side_chain_manager.approveRegisterSideChain": func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.updateSideChain":          func() nativeParam { return new(side_chain_manager.RegisterSideChainParam) },
+	"side_chain_manager.approveUpdateSideChain":   func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.quitSideChain":            func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.approveQuitSideChain":     func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.cancelRegisterSideChain":  func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.cancelUpdateSideChain":    func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.cancelQuitSideChain":      func() nativeParam { return new(side_chain_manager.ChainidParam) },
+	"side_chain_manager.registerAsset":            func() nativeParam { return new(side_chain_manager.RegisterAssetParam) },
+	"side_chain_manager.updateFee":                func() nativeParam { return new(side_chain_manager.UpdateFeeParam) },
+	"side_chain_manager.registerRedeem":           func() nativeParam { return new(side_chain_manager.RegisterRedeemParam) },
+	"side_chain_manager.setBtcTxParam":            func() nativeParam { return new(side_chain_manager.BtcTxParam) },
+	"side_chain_manager.getSideChain":             func() nativeParam { return new(side_chain_manager.QueryChainParam) },
+	"side_chain_manager.getFee":                   func() nativeParam { return new(side_chain_manager.QueryChainParam) },
+	"side_chain_manager.getAssetBind":             func() nativeParam { return new(side_chain_manager.QueryChainParam) },
+	"side_chain_manager.applyAsset":               func() nativeParam { return new(side_chain_manager.ApplyAssetParam) },
+	"side_chain_manager.approveAsset":             func() nativeParam { return new(side_chain_manager.AssetParam) },
+	"side_chain_manager.cancelAsset":              func() nativeParam { return new(side_chain_manager.AssetParam) },
+	"side_chain_manager.getAsset":                 func() nativeParam { return new(side_chain_manager.QueryAssetParam) },
+	"side_chain_manager.getAssetApply":            func() nativeParam { return new(side_chain_manager.QueryAssetParam) },
+
+	"node_manager.registerCandidate":   func() nativeParam { return new(node_manager.RegisterPeerParam) },
+	"node_manager.unRegisterCandidate": func() nativeParam { return new(node_manager.PeerParam) },
+	"node_manager.quitNode":            func() nativeParam { return new(node_manager.PeerParam) },
+	"node_manager.approveCandidate":    func() nativeParam { return new(node_manager.PeerParam) },
+	"node_manager.blackNode":           func() nativeParam { return new(node_manager.PeerListParam) },
+	"node_manager.whiteNode":           func() nativeParam { return new(node_manager.PeerParam) },
+	"node_manager.updateConfig":        func() nativeParam { return new(node_manager.UpdateConfigParam) },
+	"node_manager.getPeerPool":         func() nativeParam { return new(node_manager.ViewParam) },
+	"node_manager.revokeVote":          func() nativeParam { return new(node_manager.RevokeVoteParam) },
+	"node_manager.getProposal":         func() nativeParam { return new(node_manager.ProposalParam) },
+	"node_manager.setProposalConfig":   func() nativeParam { return new(node_manager.ProposalConfig) },
+	"node_manager.setApplyExpiry":      func() nativeParam { return new(node_manager.ApplyExpiryParam) },
+
+	"relayer_manager.registerRelayer":        func() nativeParam { return new(relayer_manager.RelayerListParam) },
+	"relayer_manager.approveRegisterRelayer": func() nativeParam { return new(relayer_manager.ApproveRelayerParam) },
+	"relayer_manager.RemoveRelayer":          func() nativeParam { return new(relayer_manager.RelayerListParam) },
+	"relayer_manager.approveRemoveRelayer":   func() nativeParam { return new(relayer_manager.ApproveRelayerParam) },
+	"relayer_manager.cancelRegisterRelayer":  func() nativeParam { return new(relayer_manager.ApproveRelayerParam) },
+	"relayer_manager.cancelRemoveRelayer":    func() nativeParam { return new(relayer_manager.ApproveRelayerParam) },
+
+	"neo3_state_manager.registerStateValidator":        func() nativeParam { return new(neo3_state_manager.StateValidatorListParam) },
+	"neo3_state_manager.approveRegisterStateValidator": func() nativeParam { return new(neo3_state_manager.ApproveStateValidatorParam) },
+	"neo3_state_manager.removeStateValidator":          func() nativeParam { return new(neo3_state_manager.StateValidatorListParam) },
+	"neo3_state_manager.approveRemoveStateValidator":   func() nativeParam { return new(neo3_state_manager.ApproveStateValidatorParam) },
+	"neo3_state_manager.cancelRegisterStateValidator":  func() nativeParam { return new(neo3_state_manager.ApproveStateValidatorParam) },
+	"neo3_state_manager.cancelRemoveStateValidator":    func() nativeParam { return new(neo3_state_manager.ApproveStateValidatorParam) },
+
+	"signature_manager.addSignature": func() nativeParam { return new(signature_manager.AddSignatureParam) },
+
+	"replenish.replenishTx": func() nativeParam { return new(replenish.ReplenishTxParam) },
+}
+
+// Sample JSON value of param
+func sampleValue(param *NativeContractParamAbi) interface{} {
+	switch strings.ToLower(param.Type) {
+	case NATIVE_PARAM_TYPE_BOOL:
+		return true
+	case NATIVE_PARAM_TYPE_BYTE:
+		return 1
+	case NATIVE_PARAM_TYPE_UINT32, NATIVE_PARAM_TYPE_UINT64, NATIVE_PARAM_TYPE_VARUINT, NATIVE_PARAM_TYPE_INTEGER:
+		return 300
+	case NATIVE_PARAM_TYPE_STRING:
+		return "sample"
+	case NATIVE_PARAM_TYPE_BYTEARRAY:
+		return "0102030405"
+	case NATIVE_PARAM_TYPE_BIGINT:
+		return "1000000000000000000000"
+	case NATIVE_PARAM_TYPE_ADDRESS, NATIVE_PARAM_TYPE_VARADDRESS:
+		addr := common.Address{1, 2, 3}
+		return addr.ToBase58()
+	case NATIVE_PARAM_TYPE_UINT256:
+		return strings.Repeat("ab", 32)
+	case NATIVE_PARAM_TYPE_ARRAY, NATIVE_PARAM_TYPE_ARRAY64:
+		return []interface{}{sampleValue(param.SubType[0]), sampleValue(param.SubType[0])}
+	case NATIVE_PARAM_TYPE_MAP:
+		return map[string]interface{}{"2": sampleValue(param.SubType[1]), "10": sampleValue(param.SubType[1])}
+	case NATIVE_PARAM_TYPE_STRUCT:
+		return sampleFields(param.SubType)
+	}
+	return nil
+}
+
+func sampleFields(params []*NativeContractParamAbi) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for _, param := range params {
+		fields[param.Name] = sampleValue(param)
+	}
+	return fields
+}
+
+// Check the encoded args are fully consumed by the Deserialization of contract param, and the Serialization gets the same bytes
+func TestNativeAbiMatchesParams(t *testing.T) {
+	mgr := NewAbiMgr()
+	mgr.Init("native_abi_script")
+	assert.Equal(t, 8, len(mgr.nativeAbis))
+
+	checked := 0
+	for _, contract := range mgr.nativeAbis {
+		assert.NotNil(t, mgr.GetNativeAbiByName(contract.Name))
+		for _, fn := range contract.Functions {
+			key := fmt.Sprintf("%s.%s", contract.Name, fn.Name)
+			args, err := json.Marshal(sampleFields(fn.Parameters))
+			assert.NoError(t, err, key)
+			data, err := fn.EncodeParams(args)
+			assert.NoError(t, err, key)
+
+			newParam, ok := nativeParams[key]
+			if !ok {
+				assert.Empty(t, fn.Parameters, key)
+				assert.Empty(t, data, key)
+				continue
+			}
+			param := newParam()
+			source := common.NewZeroCopySource(data)
+			if !assert.NoError(t, param.Deserialization(source), key) {
+				continue
+			}
+			assert.Equal(t, uint64(0), source.Len(), key)
+
+			sink := common.NewZeroCopySink(nil)
+			switch p := param.(type) {
+			case interface{ Serialization(*common.ZeroCopySink) }:
+				p.Serialization(sink)
+			case interface {
+				Serialization(*common.ZeroCopySink) error
+			}:
+				assert.NoError(t, p.Serialization(sink), key)
+			}
+			assert.Equal(t, data, sink.Bytes(), key)
+			checked++
+		}
+	}
+	assert.Equal(t, len(nativeParams), checked)
+}
+
+func TestEncodeParamsArray(t *testing.T) {
+	fn := &NativeContractFunctionAbi{
+		Name:       "setHeaderRetention",
+		Parameters: []*NativeContractParamAbi{{Name: "ChainID", Type: "uint64"}, {Name: "Depth", Type: "uint64"}},
+	}
+	data, err := fn.EncodeParams([]byte(`[2, "18446744073709551615"]`))
+	assert.NoError(t, err)
+	param := new(hscom.SetHeaderRetentionParam)
+	assert.NoError(t, param.Deserialization(common.NewZeroCopySource(data)))
+	assert.Equal(t, uint64(2), param.ChainID)
+	assert.Equal(t, uint64(18446744073709551615), param.Depth)
+
+	_, err = fn.EncodeParams([]byte(`[2]`))
+	assert.Error(t, err)
+	_, err = fn.EncodeParams([]byte(`{"ChainID": 2}`))
+	assert.Error(t, err)
+	_, err = fn.EncodeParams([]byte(`{"ChainID": -2, "Depth": 1}`))
+	assert.Error(t, err)
+}
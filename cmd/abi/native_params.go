@@ -0,0 +1,232 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+)
+
+//EncodeParams encode the JSON arguments of function into the args of native contract invocation.
+//Arguments can be a JSON object keyed by parameter name, or a JSON array in parameter order
+func (this *NativeContractFunctionAbi) EncodeParams(args []byte) ([]byte, error) {
+	sink := common.NewZeroCopySink(nil)
+	if len(this.Parameters) == 0 {
+		return sink.Bytes(), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode arguments of %s error:%s", this.Name, err)
+	}
+	if err := encodeFields(sink, this.Parameters, value); err != nil {
+		return nil, fmt.Errorf("encode arguments of %s error:%s", this.Name, err)
+	}
+	return sink.Bytes(), nil
+}
+
+func encodeFields(sink *common.ZeroCopySink, fields []*NativeContractParamAbi, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, field := range fields {
+			fieldValue, ok := v[field.Name]
+			if !ok {
+				return fmt.Errorf("missing param %s", field.Name)
+			}
+			if err := encodeParam(sink, field, fieldValue); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if len(v) != len(fields) {
+			return fmt.Errorf("expect %d params, got %d", len(fields), len(v))
+		}
+		for i, field := range fields {
+			if err := encodeParam(sink, field, v[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("params should be JSON object or array")
+	}
+	return nil
+}
+
+func encodeParam(sink *common.ZeroCopySink, param *NativeContractParamAbi, value interface{}) error {
+	pType := strings.ToLower(param.Type)
+	switch pType {
+	case NATIVE_PARAM_TYPE_BOOL:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("param %s should be bool", param.Name)
+		}
+		sink.WriteBool(b)
+	case NATIVE_PARAM_TYPE_BYTE, NATIVE_PARAM_TYPE_UINT32, NATIVE_PARAM_TYPE_UINT64, NATIVE_PARAM_TYPE_VARUINT, NATIVE_PARAM_TYPE_INTEGER:
+		n, err := parseUint(value)
+		if err != nil {
+			return fmt.Errorf("param %s error:%s", param.Name, err)
+		}
+		switch pType {
+		case NATIVE_PARAM_TYPE_BYTE:
+			if n > 0xff {
+				return fmt.Errorf("param %s out of byte range", param.Name)
+			}
+			sink.WriteByte(byte(n))
+		case NATIVE_PARAM_TYPE_UINT32:
+			if n > 0xffffffff {
+				return fmt.Errorf("param %s out of uint32 range", param.Name)
+			}
+			sink.WriteUint32(uint32(n))
+		case NATIVE_PARAM_TYPE_UINT64:
+			sink.WriteUint64(n)
+		default:
+			sink.WriteVarUint(n)
+		}
+	case NATIVE_PARAM_TYPE_STRING:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("param %s should be string", param.Name)
+		}
+		sink.WriteString(s)
+	case NATIVE_PARAM_TYPE_BYTEARRAY:
+		data, err := parseHex(value)
+		if err != nil {
+			return fmt.Errorf("param %s error:%s", param.Name, err)
+		}
+		sink.WriteVarBytes(data)
+	case NATIVE_PARAM_TYPE_BIGINT:
+		str := fmt.Sprint(value)
+		n, ok := new(big.Int).SetString(str, 10)
+		if !ok || n.Sign() < 0 {
+			return fmt.Errorf("param %s should be non-negative integer, got %s", param.Name, str)
+		}
+		sink.WriteVarBytes(n.Bytes())
+	case NATIVE_PARAM_TYPE_ADDRESS, NATIVE_PARAM_TYPE_VARADDRESS:
+		addr, err := parseAddress(value)
+		if err != nil {
+			return fmt.Errorf("param %s error:%s", param.Name, err)
+		}
+		if pType == NATIVE_PARAM_TYPE_ADDRESS {
+			sink.WriteAddress(addr)
+		} else {
+			sink.WriteVarBytes(addr[:])
+		}
+	case NATIVE_PARAM_TYPE_UINT256:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("param %s should be hex string", param.Name)
+		}
+		hash, err := common.Uint256FromHexString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return fmt.Errorf("param %s error:%s", param.Name, err)
+		}
+		sink.WriteHash(hash)
+	case NATIVE_PARAM_TYPE_ARRAY, NATIVE_PARAM_TYPE_ARRAY64:
+		items, ok := value.([]interface{})
+		if !ok || len(param.SubType) != 1 {
+			return fmt.Errorf("param %s should be array of one sub type", param.Name)
+		}
+		if pType == NATIVE_PARAM_TYPE_ARRAY {
+			sink.WriteVarUint(uint64(len(items)))
+		} else {
+			sink.WriteUint64(uint64(len(items)))
+		}
+		for _, item := range items {
+			if err := encodeParam(sink, param.SubType[0], item); err != nil {
+				return err
+			}
+		}
+	case NATIVE_PARAM_TYPE_MAP:
+		return encodeMap(sink, param, value)
+	case NATIVE_PARAM_TYPE_STRUCT:
+		return encodeFields(sink, param.SubType, value)
+	default:
+		return fmt.Errorf("unsupported type %s of param %s", param.Type, param.Name)
+	}
+	return nil
+}
+
+//Only maps of integer keys are used by native contract params, keys are written in descending order
+func encodeMap(sink *common.ZeroCopySink, param *NativeContractParamAbi, value interface{}) error {
+	pairs, ok := value.(map[string]interface{})
+	if !ok || len(param.SubType) != 2 {
+		return fmt.Errorf("param %s should be map of key and value sub types", param.Name)
+	}
+	keys := make([]uint64, 0, len(pairs))
+	values := make(map[uint64]interface{}, len(pairs))
+	for k, v := range pairs {
+		key, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			return fmt.Errorf("param %s key %s error:%s", param.Name, k, err)
+		}
+		keys = append(keys, key)
+		values[key] = v
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] > keys[j]
+	})
+	sink.WriteVarUint(uint64(len(keys)))
+	for _, key := range keys {
+		if err := encodeParam(sink, param.SubType[0], json.Number(strconv.FormatUint(key, 10))); err != nil {
+			return err
+		}
+		if err := encodeParam(sink, param.SubType[1], values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseUint(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return strconv.ParseUint(v.String(), 10, 64)
+	case string:
+		return strconv.ParseUint(v, 10, 64)
+	}
+	return 0, fmt.Errorf("invalid integer %v", value)
+}
+
+func parseHex(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("should be hex string")
+	}
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func parseAddress(value interface{}) (common.Address, error) {
+	s, ok := value.(string)
+	if !ok {
+		return common.ADDRESS_EMPTY, fmt.Errorf("should be address string")
+	}
+	if addr, err := common.AddressFromBase58(s); err == nil {
+		return addr, nil
+	}
+	return common.AddressFromHexString(strings.TrimPrefix(s, "0x"))
+}
@@ -34,11 +34,16 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 		return nil, fmt.Errorf("setGenesis error:%s", err)
 	}
 	setCommonConfig(ctx, cfg.Common)
-	setConsensusConfig(ctx, cfg.Consensus)
+	err = setConsensusConfig(ctx, cfg.Consensus)
+	if err != nil {
+		return nil, fmt.Errorf("setConsensusConfig error:%s", err)
+	}
 	setP2PNodeConfig(ctx, cfg.P2PNode)
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setWebSocketConfig(ctx, cfg.Ws)
+	setPruneConfig(ctx, cfg.Prune)
+	setMetricsConfig(ctx, cfg.Metrics)
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -123,9 +128,17 @@ func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
 	cfg.DataDir = ctx.String(utils.GetFlagName(utils.DataDirFlag))
 }
 
-func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) {
+func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) error {
 	cfg.EnableConsensus = ctx.Bool(utils.GetFlagName(utils.EnableConsensusFlag))
 	cfg.MaxTxInBlock = ctx.Uint(utils.GetFlagName(utils.MaxTxInBlockFlag))
+	cfg.TxSelectPolicy = ctx.String(utils.GetFlagName(utils.TxSelectPolicyFlag))
+	switch cfg.TxSelectPolicy {
+	case config.TX_SELECT_POLICY_PRIORITY, config.TX_SELECT_POLICY_FIFO:
+	default:
+		return fmt.Errorf("unknown tx select policy:%s", cfg.TxSelectPolicy)
+	}
+	cfg.MaxTxPerRelayerInBlock = ctx.Uint(utils.GetFlagName(utils.MaxTxPerRelayerInBlockFlag))
+	return nil
 }
 
 func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
@@ -179,6 +192,21 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setPruneConfig(ctx *cli.Context, cfg *config.PruneConfig) {
+	cfg.EnablePrune = ctx.Bool(utils.GetFlagName(utils.EnablePruneFlag))
+	cfg.PruneDepth = ctx.Uint(utils.GetFlagName(utils.PruneDepthFlag))
+	if cfg.EnablePrune && cfg.PruneDepth < config.MIN_PRUNE_DEPTH {
+		log.Warnf("prune depth %d is too small, use %d instead", cfg.PruneDepth, config.MIN_PRUNE_DEPTH)
+		cfg.PruneDepth = config.MIN_PRUNE_DEPTH
+	}
+	cfg.StateHistoryDepth = ctx.Uint(utils.GetFlagName(utils.StateHistoryDepthFlag))
+}
+
+func setMetricsConfig(ctx *cli.Context, cfg *config.MetricsConfig) {
+	cfg.EnableMetrics = ctx.Bool(utils.GetFlagName(utils.MetricsEnableFlag))
+	cfg.MetricsPort = ctx.Uint(utils.GetFlagName(utils.MetricsPortFlag))
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
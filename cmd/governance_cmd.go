@@ -0,0 +1,501 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	cmdcom "github.com/polynetwork/poly/cmd/common"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/constants"
+	"github.com/polynetwork/poly/core/types"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/urfave/cli"
+)
+
+//Flags of signing and sending governance transaction
+var governanceTxFlags = []cli.Flag{
+	utils.RPCPortFlag,
+	utils.WalletFileFlag,
+	utils.AccountAddressFlag,
+	utils.AccountMultiMFlag,
+	utils.AccountMultiPubKeyFlag,
+	utils.SendTxFlag,
+	utils.PrepareExecTransactionFlag,
+}
+
+func governanceFlags(flags ...cli.Flag) []cli.Flag {
+	return append(flags, governanceTxFlags...)
+}
+
+var GovernanceCommand = cli.Command{
+	Action:    cli.ShowSubcommandHelp,
+	Name:      "governance",
+	Usage:     "Manage side chains, relayers and consensus candidates",
+	ArgsUsage: "[arguments...]",
+	Description: `Governance commands build the transactions of governance contracts and sign them with the account.
+If --pubkey is set, the transaction is signed for the m-of-n multi-signature address, and the raw transaction
+can be signed by the other signers with ./poly multisigtx. Using --prepare to dry run the transaction, and --send to send it.`,
+	Subcommands: []cli.Command{
+		{
+			Action: registerSideChain,
+			Name:   side_chain_manager.REGISTER_SIDE_CHAIN,
+			Usage:  "Apply to register a side chain",
+			Flags: governanceFlags(utils.GovChainIDFlag, utils.GovRouterFlag, utils.GovChainNameFlag, utils.GovBlocksToWaitFlag,
+				utils.GovCCMCAddressFlag, utils.GovExtraInfoFlag),
+		},
+		{
+			Action: approveRegisterSideChain,
+			Name:   side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN,
+			Usage:  "Approve the side chain registration",
+			Flags:  governanceFlags(utils.GovChainIDFlag),
+		},
+		{
+			Action: updateSideChain,
+			Name:   side_chain_manager.UPDATE_SIDE_CHAIN,
+			Usage:  "Apply to update a side chain",
+			Flags: governanceFlags(utils.GovChainIDFlag, utils.GovRouterFlag, utils.GovChainNameFlag, utils.GovBlocksToWaitFlag,
+				utils.GovCCMCAddressFlag, utils.GovExtraInfoFlag),
+		},
+		{
+			Action: approveUpdateSideChain,
+			Name:   side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN,
+			Usage:  "Approve the side chain update",
+			Flags:  governanceFlags(utils.GovChainIDFlag),
+		},
+		{
+			Action: quitSideChain,
+			Name:   side_chain_manager.QUIT_SIDE_CHAIN,
+			Usage:  "Apply to quit a side chain",
+			Flags:  governanceFlags(utils.GovChainIDFlag),
+		},
+		{
+			Action: approveQuitSideChain,
+			Name:   side_chain_manager.APPROVE_QUIT_SIDE_CHAIN,
+			Usage:  "Approve the side chain quit",
+			Flags:  governanceFlags(utils.GovChainIDFlag),
+		},
+		{
+			Action: updateFee,
+			Name:   side_chain_manager.UPDATE_FEE,
+			Usage:  "Vote the fee of a side chain",
+			Flags:  governanceFlags(utils.GovChainIDFlag, utils.GovFeeViewFlag, utils.GovFeeFlag),
+		},
+		{
+			Action: registerRelayer,
+			Name:   relayer_manager.REGISTER_RELAYER,
+			Usage:  "Apply to register relayers",
+			Flags:  governanceFlags(utils.GovRelayersFlag),
+		},
+		{
+			Action: approveRegisterRelayer,
+			Name:   relayer_manager.APPROVE_REGISTER_RELAYER,
+			Usage:  "Approve the relayer registration",
+			Flags:  governanceFlags(utils.GovApproveIDFlag),
+		},
+		{
+			Action: removeRelayer,
+			Name:   relayer_manager.REMOVE_RELAYER,
+			Usage:  "Apply to remove relayers",
+			Flags:  governanceFlags(utils.GovRelayersFlag),
+		},
+		{
+			Action: approveRemoveRelayer,
+			Name:   relayer_manager.APPROVE_REMOVE_RELAYER,
+			Usage:  "Approve the relayer removal",
+			Flags:  governanceFlags(utils.GovApproveIDFlag),
+		},
+		{
+			Action: registerCandidate,
+			Name:   node_manager.REGISTER_CANDIDATE,
+			Usage:  "Register a consensus candidate",
+			Flags:  governanceFlags(utils.GovPeerPubkeyFlag),
+		},
+		{
+			Action: approveCandidate,
+			Name:   node_manager.APPROVE_CANDIDATE,
+			Usage:  "Approve the consensus candidate",
+			Flags:  governanceFlags(utils.GovPeerPubkeyFlag),
+		},
+		{
+			Action: blackNode,
+			Name:   node_manager.BLACK_NODE,
+			Usage:  "Put consensus nodes into black list",
+			Flags:  governanceFlags(utils.GovPeerPubkeysFlag),
+		},
+		{
+			Action: commitDpos,
+			Name:   node_manager.COMMIT_DPOS,
+			Usage:  "Commit the consensus nodes change",
+			Flags:  governanceTxFlags,
+		},
+		{
+			Action: blackChain,
+			Name:   ccom.BLACK_CHAIN,
+			Usage:  "Stop the cross chain transactions of a side chain",
+			Flags:  governanceFlags(utils.GovChainIDFlag),
+		},
+		{
+			Action: whiteChain,
+			Name:   ccom.WHITE_CHAIN,
+			Usage:  "Resume the cross chain transactions of a side chain",
+			Flags:  governanceFlags(utils.GovChainIDFlag),
+		},
+	},
+}
+
+func registerSideChain(ctx *cli.Context) error {
+	return sendSideChainTx(ctx, side_chain_manager.REGISTER_SIDE_CHAIN)
+}
+
+func updateSideChain(ctx *cli.Context) error {
+	return sendSideChainTx(ctx, side_chain_manager.UPDATE_SIDE_CHAIN)
+}
+
+func sendSideChainTx(ctx *cli.Context, method string) error {
+	if err := checkGovernanceFlags(ctx, utils.GovChainIDFlag, utils.GovRouterFlag, utils.GovChainNameFlag); err != nil {
+		return err
+	}
+	ccmc, err := hex.DecodeString(strings.TrimPrefix(ctx.String(utils.GetFlagName(utils.GovCCMCAddressFlag)), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid %s:%s", utils.GetFlagName(utils.GovCCMCAddressFlag), err)
+	}
+	extra, err := hex.DecodeString(strings.TrimPrefix(ctx.String(utils.GetFlagName(utils.GovExtraInfoFlag)), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid %s:%s", utils.GetFlagName(utils.GovExtraInfoFlag), err)
+	}
+	return sendGovernanceTx(ctx, nutils.SideChainManagerContractAddress, method, func(signer common.Address) ([]byte, error) {
+		param := &side_chain_manager.RegisterSideChainParam{
+			Address:      signer,
+			ChainId:      ctx.Uint64(utils.GetFlagName(utils.GovChainIDFlag)),
+			Router:       ctx.Uint64(utils.GetFlagName(utils.GovRouterFlag)),
+			Name:         ctx.String(utils.GetFlagName(utils.GovChainNameFlag)),
+			BlocksToWait: ctx.Uint64(utils.GetFlagName(utils.GovBlocksToWaitFlag)),
+			CCMCAddress:  ccmc,
+			ExtraInfo:    extra,
+		}
+		sink := common.NewZeroCopySink(nil)
+		if err := param.Serialization(sink); err != nil {
+			return nil, err
+		}
+		return sink.Bytes(), nil
+	})
+}
+
+func approveRegisterSideChain(ctx *cli.Context) error {
+	return sendChainIDTx(ctx, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN)
+}
+
+func approveUpdateSideChain(ctx *cli.Context) error {
+	return sendChainIDTx(ctx, side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN)
+}
+
+func quitSideChain(ctx *cli.Context) error {
+	return sendChainIDTx(ctx, side_chain_manager.QUIT_SIDE_CHAIN)
+}
+
+func approveQuitSideChain(ctx *cli.Context) error {
+	return sendChainIDTx(ctx, side_chain_manager.APPROVE_QUIT_SIDE_CHAIN)
+}
+
+func sendChainIDTx(ctx *cli.Context, method string) error {
+	if err := checkGovernanceFlags(ctx, utils.GovChainIDFlag); err != nil {
+		return err
+	}
+	return sendGovernanceTx(ctx, nutils.SideChainManagerContractAddress, method, func(signer common.Address) ([]byte, error) {
+		param := &side_chain_manager.ChainidParam{
+			Chainid: ctx.Uint64(utils.GetFlagName(utils.GovChainIDFlag)),
+			Address: signer,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func updateFee(ctx *cli.Context) error {
+	if err := checkGovernanceFlags(ctx, utils.GovChainIDFlag, utils.GovFeeViewFlag, utils.GovFeeFlag); err != nil {
+		return err
+	}
+	fee, ok := new(big.Int).SetString(ctx.String(utils.GetFlagName(utils.GovFeeFlag)), 10)
+	if !ok || fee.Sign() < 0 {
+		return fmt.Errorf("invalid %s:%s", utils.GetFlagName(utils.GovFeeFlag), ctx.String(utils.GetFlagName(utils.GovFeeFlag)))
+	}
+	return sendGovernanceTx(ctx, nutils.SideChainManagerContractAddress, side_chain_manager.UPDATE_FEE, func(signer common.Address) ([]byte, error) {
+		param := &side_chain_manager.UpdateFeeParam{
+			Address: signer,
+			ChainId: ctx.Uint64(utils.GetFlagName(utils.GovChainIDFlag)),
+			View:    ctx.Uint64(utils.GetFlagName(utils.GovFeeViewFlag)),
+			Fee:     fee,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func registerRelayer(ctx *cli.Context) error {
+	return sendRelayerListTx(ctx, relayer_manager.REGISTER_RELAYER)
+}
+
+func removeRelayer(ctx *cli.Context) error {
+	return sendRelayerListTx(ctx, relayer_manager.REMOVE_RELAYER)
+}
+
+func sendRelayerListTx(ctx *cli.Context, method string) error {
+	if err := checkGovernanceFlags(ctx, utils.GovRelayersFlag); err != nil {
+		return err
+	}
+	relayers := make([]common.Address, 0)
+	for _, relayer := range splitList(ctx.String(utils.GetFlagName(utils.GovRelayersFlag))) {
+		addr, err := common.AddressFromBase58(relayer)
+		if err != nil {
+			return fmt.Errorf("invalid relayer address %s:%s", relayer, err)
+		}
+		relayers = append(relayers, addr)
+	}
+	return sendGovernanceTx(ctx, nutils.RelayerManagerContractAddress, method, func(signer common.Address) ([]byte, error) {
+		param := &relayer_manager.RelayerListParam{
+			AddressList: relayers,
+			Address:     signer,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func approveRegisterRelayer(ctx *cli.Context) error {
+	return sendApproveRelayerTx(ctx, relayer_manager.APPROVE_REGISTER_RELAYER)
+}
+
+func approveRemoveRelayer(ctx *cli.Context) error {
+	return sendApproveRelayerTx(ctx, relayer_manager.APPROVE_REMOVE_RELAYER)
+}
+
+func sendApproveRelayerTx(ctx *cli.Context, method string) error {
+	if err := checkGovernanceFlags(ctx, utils.GovApproveIDFlag); err != nil {
+		return err
+	}
+	return sendGovernanceTx(ctx, nutils.RelayerManagerContractAddress, method, func(signer common.Address) ([]byte, error) {
+		param := &relayer_manager.ApproveRelayerParam{
+			ID:      ctx.Uint64(utils.GetFlagName(utils.GovApproveIDFlag)),
+			Address: signer,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func registerCandidate(ctx *cli.Context) error {
+	if err := checkGovernanceFlags(ctx, utils.GovPeerPubkeyFlag); err != nil {
+		return err
+	}
+	return sendGovernanceTx(ctx, nutils.NodeManagerContractAddress, node_manager.REGISTER_CANDIDATE, func(signer common.Address) ([]byte, error) {
+		param := &node_manager.RegisterPeerParam{
+			PeerPubkey: ctx.String(utils.GetFlagName(utils.GovPeerPubkeyFlag)),
+			Address:    signer,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func approveCandidate(ctx *cli.Context) error {
+	if err := checkGovernanceFlags(ctx, utils.GovPeerPubkeyFlag); err != nil {
+		return err
+	}
+	return sendGovernanceTx(ctx, nutils.NodeManagerContractAddress, node_manager.APPROVE_CANDIDATE, func(signer common.Address) ([]byte, error) {
+		param := &node_manager.PeerParam{
+			PeerPubkey: ctx.String(utils.GetFlagName(utils.GovPeerPubkeyFlag)),
+			Address:    signer,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func blackNode(ctx *cli.Context) error {
+	if err := checkGovernanceFlags(ctx, utils.GovPeerPubkeysFlag); err != nil {
+		return err
+	}
+	return sendGovernanceTx(ctx, nutils.NodeManagerContractAddress, node_manager.BLACK_NODE, func(signer common.Address) ([]byte, error) {
+		param := &node_manager.PeerListParam{
+			PeerPubkeyList: splitList(ctx.String(utils.GetFlagName(utils.GovPeerPubkeysFlag))),
+			Address:        signer,
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func commitDpos(ctx *cli.Context) error {
+	return sendGovernanceTx(ctx, nutils.NodeManagerContractAddress, node_manager.COMMIT_DPOS, func(signer common.Address) ([]byte, error) {
+		return []byte{}, nil
+	})
+}
+
+func blackChain(ctx *cli.Context) error {
+	return sendBlackChainTx(ctx, ccom.BLACK_CHAIN)
+}
+
+func whiteChain(ctx *cli.Context) error {
+	return sendBlackChainTx(ctx, ccom.WHITE_CHAIN)
+}
+
+func sendBlackChainTx(ctx *cli.Context, method string) error {
+	if err := checkGovernanceFlags(ctx, utils.GovChainIDFlag); err != nil {
+		return err
+	}
+	return sendGovernanceTx(ctx, nutils.CrossChainManagerContractAddress, method, func(signer common.Address) ([]byte, error) {
+		param := &ccom.BlackChainParam{
+			ChainID: ctx.Uint64(utils.GetFlagName(utils.GovChainIDFlag)),
+		}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes(), nil
+	})
+}
+
+func checkGovernanceFlags(ctx *cli.Context, flags ...cli.Flag) error {
+	for _, flag := range flags {
+		if !ctx.IsSet(utils.GetFlagName(flag)) {
+			cli.ShowSubcommandHelp(ctx)
+			return fmt.Errorf("missing argument %s", utils.GetFlagName(flag))
+		}
+	}
+	return nil
+}
+
+func splitList(str string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+//Build the governance transaction with the params of signer address, and sign it with the account. If the pub keys of
+//multi-signature address are specified, the multi-signature address is the signer, and the other signers can continue
+//signing the raw transaction with the multisigtx command
+func sendGovernanceTx(ctx *cli.Context, contract common.Address, method string, getArgs func(signer common.Address) ([]byte, error)) error {
+	SetRpcPort(ctx)
+	var m uint16
+	var pubKeys []keypair.PublicKey
+	if pkstr := ctx.String(utils.GetFlagName(utils.AccountMultiPubKeyFlag)); pkstr != "" {
+		for _, pk := range splitList(pkstr) {
+			data, err := hex.DecodeString(pk)
+			if err != nil {
+				return fmt.Errorf("invalid pub key:%s", pk)
+			}
+			pubKey, err := keypair.DeserializePublicKey(data)
+			if err != nil {
+				return fmt.Errorf("invalid pub key:%s", pk)
+			}
+			pubKeys = append(pubKeys, pubKey)
+		}
+		m = uint16(ctx.Uint(utils.GetFlagName(utils.AccountMultiMFlag)))
+		if !(1 <= m && int(m) <= len(pubKeys) && len(pubKeys) > 1 && len(pubKeys) <= constants.MULTI_SIG_MAX_PUBKEY_SIZE) {
+			return fmt.Errorf("invalid argument. %s must > 1 and <= %d, and m must > 0 and < number of pub key",
+				utils.GetFlagName(utils.AccountMultiPubKeyFlag), constants.MULTI_SIG_MAX_PUBKEY_SIZE)
+		}
+	}
+
+	acc, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("GetAccount error:%s", err)
+	}
+	signer := acc.Address
+	if len(pubKeys) > 0 {
+		signer, err = types.AddressFromMultiPubKeys(pubKeys, int(m))
+		if err != nil {
+			return fmt.Errorf("AddressFromMultiPubKeys error:%s", err)
+		}
+	}
+	args, err := getArgs(signer)
+	if err != nil {
+		return fmt.Errorf("build %s params error:%s", method, err)
+	}
+	tx, err := utils.NewNativeInvokeTransaction(contract, method, args)
+	if err != nil {
+		return fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	if len(pubKeys) > 0 {
+		err = utils.MultiSigTransaction(tx, m, pubKeys, acc)
+	} else {
+		err = utils.SignTransaction(acc, tx)
+	}
+	if err != nil {
+		return fmt.Errorf("sign transaction error:%s", err)
+	}
+
+	sink := common.ZeroCopySink{}
+	err = tx.Serialization(&sink)
+	if err != nil {
+		return fmt.Errorf("tx serialization error:%s", err)
+	}
+	rawTx := hex.EncodeToString(sink.Bytes())
+	PrintInfoMsg("Signer:%s", signer.ToBase58())
+	PrintInfoMsg("RawTx after signed:")
+	PrintInfoMsg(rawTx)
+	PrintInfoMsg("")
+
+	if ctx.IsSet(utils.GetFlagName(utils.PrepareExecTransactionFlag)) {
+		preResult, err := utils.PrepareSendRawTransaction(rawTx)
+		if err != nil {
+			return err
+		}
+		if preResult.State == 0 {
+			return fmt.Errorf("prepare execute %s failed. %v", method, preResult)
+		}
+		PrintInfoMsg("Prepare execute %s success.", method)
+		PrintInfoMsg("Result:%v", preResult.Result)
+		return nil
+	}
+	if ctx.IsSet(utils.GetFlagName(utils.SendTxFlag)) {
+		txHash, err := utils.SendRawTransactionData(rawTx)
+		if err != nil {
+			return err
+		}
+		PrintInfoMsg("Send %s transaction success.", method)
+		PrintInfoMsg("  TxHash:%s", txHash)
+		PrintInfoMsg("\nTip:")
+		PrintInfoMsg("  Using './poly info status %s' to query transaction status.", txHash)
+	} else if len(pubKeys) > 0 {
+		PrintInfoMsg("Tip:")
+		PrintInfoMsg("  Using './poly multisigtx' to sign the raw transaction by the other signers.")
+	}
+	return nil
+}
@@ -62,6 +62,30 @@ var InfoCommand = cli.Command{
 				utils.RPCPortFlag,
 			},
 		},
+		{
+			Action:    traceBlock,
+			Name:      "traceblock",
+			Usage:     "Re-execute block and display the trace of transactions",
+			ArgsUsage: "<height>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+			Description: `Re-execute block on the state of previous block, and display the storage read and written,
+the notifies, the cross hashes and the native calls of the transactions. The node should keep the state history
+of previous block, see --state-history-depth.`,
+		},
+		{
+			Action:    traceTx,
+			Name:      "tracetx",
+			Usage:     "Re-execute transaction and display the trace",
+			ArgsUsage: "<txHash>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+			Description: `Re-execute the block of transaction until the transaction, and display the storage read and written,
+the notifies, the cross hashes and the native calls of the transaction. The node should keep the state history
+of previous block, see --state-history-depth.`,
+		},
 		{
 			Action:      curBlockHeight,
 			Name:        "curblockheight",
@@ -155,6 +179,40 @@ func txStatus(ctx *cli.Context) error {
 	return nil
 }
 
+func traceBlock(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing argument. Block height expected.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	height, err := strconv.ParseUint(ctx.Args().First(), 10, 32)
+	if err != nil {
+		return fmt.Errorf("arg:%s invalid block height", ctx.Args().First())
+	}
+	data, err := utils.TraceBlock(uint32(height))
+	if err != nil {
+		return fmt.Errorf("TraceBlock error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func traceTx(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing argument. TxHash expected.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	data, err := utils.TraceTransaction(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("TraceTransaction error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
 func curBlockHeight(ctx *cli.Context) error {
 	SetRpcPort(ctx)
 	count, err := utils.GetBlockCount()
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/poly/cmd/abi"
+	cmdcom "github.com/polynetwork/poly/cmd/common"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/urfave/cli"
+)
+
+var InvokeCommand = cli.Command{
+	Name:      "invoke",
+	Usage:     "Invoke native contract",
+	ArgsUsage: " ",
+	Description: "Invoke native contract method by the native contract abi. Contract can be specified by abi name or address, " +
+		"and the parameters are JSON object keyed by parameter name or JSON array in parameter order, for example:\n" +
+		"  ./poly invoke --address side_chain_manager --method approveRegisterSideChain --params '{\"Chainid\":2,\"Address\":\"<address>\"}'",
+	Action: invokeNative,
+	Flags: []cli.Flag{
+		utils.RPCPortFlag,
+		utils.WalletFileFlag,
+		utils.AccountAddressFlag,
+		utils.CliABIPathFlag,
+		utils.ContractAddrFlag,
+		utils.ContractMethodFlag,
+		utils.ContractParamsFlag,
+		utils.ContractPrepareInvokeFlag,
+	},
+}
+
+func invokeNative(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract := ctx.String(utils.GetFlagName(utils.ContractAddrFlag))
+	method := ctx.String(utils.GetFlagName(utils.ContractMethodFlag))
+	if contract == "" || method == "" {
+		PrintErrorMsg("Missing argument. %s and %s expected.",
+			utils.GetFlagName(utils.ContractAddrFlag),
+			utils.GetFlagName(utils.ContractMethodFlag))
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	abi.DefAbiMgr.Init(ctx.String(utils.GetFlagName(utils.CliABIPathFlag)))
+	contractAbi := abi.DefAbiMgr.GetNativeAbiByName(contract)
+	if contractAbi == nil {
+		return fmt.Errorf("cannot find abi of contract %s", contract)
+	}
+	funcAbi := contractAbi.GetFunc(method)
+	if funcAbi == nil {
+		return fmt.Errorf("cannot find method %s in abi of contract %s", method, contractAbi.Name)
+	}
+	params := ctx.String(utils.GetFlagName(utils.ContractParamsFlag))
+	if params == "" {
+		params = "{}"
+	}
+	args, err := funcAbi.EncodeParams([]byte(params))
+	if err != nil {
+		return err
+	}
+	contractAddr, err := common.AddressFromHexString(contractAbi.Address)
+	if err != nil {
+		return fmt.Errorf("invalid contract address %s in abi:%s", contractAbi.Address, err)
+	}
+
+	tx, err := utils.NewNativeInvokeTransaction(contractAddr, funcAbi.Name, args)
+	if err != nil {
+		return fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	acc, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("GetAccount error:%s", err)
+	}
+	err = utils.SignTransaction(acc, tx)
+	if err != nil {
+		return fmt.Errorf("SignTransaction error:%s", err)
+	}
+	sink := common.ZeroCopySink{}
+	err = tx.Serialization(&sink)
+	if err != nil {
+		return fmt.Errorf("tx serialization error:%s", err)
+	}
+	rawTx := hex.EncodeToString(sink.Bytes())
+
+	if ctx.IsSet(utils.GetFlagName(utils.ContractPrepareInvokeFlag)) {
+		preResult, err := utils.PrepareSendRawTransaction(rawTx)
+		if err != nil {
+			return err
+		}
+		if preResult.State == 0 {
+			return fmt.Errorf("prepare invoke %s.%s failed. %v", contractAbi.Name, funcAbi.Name, preResult)
+		}
+		PrintInfoMsg("Prepare invoke %s.%s success.", contractAbi.Name, funcAbi.Name)
+		PrintInfoMsg("Result:%v", preResult.Result)
+		return nil
+	}
+	txHash, err := utils.SendRawTransactionData(rawTx)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Invoke %s.%s success.", contractAbi.Name, funcAbi.Name)
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './poly info status %s' to query transaction status.", txHash)
+	return nil
+}
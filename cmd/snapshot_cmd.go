@@ -0,0 +1,273 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"compress/zlib"
+	"fmt"
+	"os"
+
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/store/ledgerstore"
+	"github.com/urfave/cli"
+)
+
+var SnapshotCommand = cli.Command{
+	Name:  "snapshot",
+	Usage: "Export or import the state snapshot of ledger",
+	Subcommands: []cli.Command{
+		{
+			Action: exportSnapshot,
+			Name:   "export",
+			Usage:  "Export the state snapshot at current block height to a file",
+			Flags: []cli.Flag{
+				utils.SnapshotFileFlag,
+				utils.SnapshotMerkleTailFlag,
+				utils.DataDirFlag,
+				utils.ConfigFlag,
+				utils.NetworkIdFlag,
+				utils.StateHistoryDepthFlag,
+			},
+			Description: `Export the storage, the block merkle tree, the state merkle tree and the cross states at current
+block height, with the genesis block, the current and previous blocks, the vbft config block of the bookkeepers and the
+header index. The node should be stopped, and --state-history-depth should be the same as the node, otherwise the state
+history is deleted. The storage hash printed is the --trusted-storage-hash to verify the storage on import.`,
+		},
+		{
+			Action: importSnapshot,
+			Name:   "import",
+			Usage:  "Import the state snapshot to an empty ledger",
+			Flags: []cli.Flag{
+				utils.SnapshotFileFlag,
+				utils.SnapshotVerifyFlag,
+				utils.SnapshotBlockHashFlag,
+				utils.SnapshotStorageHashFlag,
+				utils.RPCPortFlag,
+				utils.DataDirFlag,
+				utils.ConfigFlag,
+				utils.NetworkIdFlag,
+			},
+			Description: `Import the state snapshot to an empty ledger, the node starts from the snapshot height and continues
+syncing. The block at snapshot height must match the hash given by --trusted-block-hash, or the hash of the trusted
+node with --verify-rpc. Its header is checked with the bookkeeper signatures of vbft peers, and the header index, the
+block merkle tree and the cross states are checked with its block root and cross state root. The storage items and the
+state merkle root are not committed by any block header. The hash of the storage items must match the hash given by
+--trusted-storage-hash, which is printed by the trusted node exporting the snapshot. Without it the storage is
+unverified: the count and hash carried in the file only detect a corrupted file, and --verify-rpc only compares the
+state merkle root in the file with the node. The blocks before snapshot height are not available, and merkle proofs
+are available for the latest blocks exported with --merkle-tail.`,
+		},
+	},
+}
+
+func exportSnapshot(ctx *cli.Context) error {
+	log.InitLog(log.InfoLog)
+	cfg, err := SetOntologyConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("SetOntologyConfig error:%s", err)
+	}
+	snapshotFile := ctx.String(utils.GetFlagName(utils.SnapshotFileFlag))
+	if snapshotFile == "" {
+		PrintErrorMsg("Missing %s argument.", utils.SnapshotFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	ledgerStore, err := ledgerstore.NewLedgerStore(utils.GetStoreDirPath(cfg.Common.DataDir, cfg.P2PNode.NetworkName))
+	if err != nil {
+		return fmt.Errorf("NewLedgerStore error:%s", err)
+	}
+	defer ledgerStore.Close()
+	bookKeepers, err := cfg.GetBookkeepers()
+	if err != nil {
+		return fmt.Errorf("GetBookkeepers error:%s", err)
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, cfg.Genesis)
+	if err != nil {
+		return fmt.Errorf("BuildGenesisBlock error %s", err)
+	}
+	err = ledgerStore.InitLedgerStoreWithGenesisBlock(genesisBlock, bookKeepers)
+	if err != nil {
+		return fmt.Errorf("init ledger error:%s", err)
+	}
+
+	sf, err := os.OpenFile(snapshotFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0664)
+	if err != nil {
+		return fmt.Errorf("open file:%s error:%s", snapshotFile, err)
+	}
+	defer sf.Close()
+	fWriter := bufio.NewWriter(sf)
+	zWriter := zlib.NewWriter(fWriter)
+
+	PrintInfoMsg("Start export snapshot.")
+	merkleTail := uint32(ctx.Uint(utils.GetFlagName(utils.SnapshotMerkleTailFlag)))
+	metadata, err := ledgerStore.ExportSnapshot(zWriter, merkleTail)
+	if err != nil {
+		return fmt.Errorf("export snapshot error:%s", err)
+	}
+	err = zWriter.Close()
+	if err != nil {
+		return fmt.Errorf("export compress error:%s", err)
+	}
+	err = fWriter.Flush()
+	if err != nil {
+		return fmt.Errorf("export flush file error:%s", err)
+	}
+	PrintInfoMsg("Export snapshot successfully.")
+	printSnapshotMetadata(metadata)
+	PrintInfoMsg("Snapshot file:%s", snapshotFile)
+	return nil
+}
+
+func importSnapshot(ctx *cli.Context) error {
+	log.InitLog(log.InfoLog)
+	cfg, err := SetOntologyConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("SetOntologyConfig error:%s", err)
+	}
+	snapshotFile := ctx.String(utils.GetFlagName(utils.SnapshotFileFlag))
+	if snapshotFile == "" {
+		PrintErrorMsg("Missing %s argument.", utils.SnapshotFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	trustedBlockHash := common.UINT256_EMPTY
+	if hash := ctx.String(utils.GetFlagName(utils.SnapshotBlockHashFlag)); hash != "" {
+		trustedBlockHash, err = common.Uint256FromHexString(hash)
+		if err != nil {
+			return fmt.Errorf("invalid %s argument:%s", utils.SnapshotBlockHashFlag.Name, err)
+		}
+	}
+	if ctx.Bool(utils.GetFlagName(utils.SnapshotVerifyFlag)) {
+		SetRpcPort(ctx)
+		blockHash, err := verifySnapshot(snapshotFile)
+		if err != nil {
+			return err
+		}
+		if trustedBlockHash != common.UINT256_EMPTY && trustedBlockHash != blockHash {
+			return fmt.Errorf("block hash of node:%s mismatch %s", blockHash.ToHexString(), utils.SnapshotBlockHashFlag.Name)
+		}
+		trustedBlockHash = blockHash
+	}
+	if trustedBlockHash == common.UINT256_EMPTY {
+		PrintErrorMsg("Missing %s or %s argument.", utils.SnapshotBlockHashFlag.Name, utils.SnapshotVerifyFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	trustedStorageHash := common.UINT256_EMPTY
+	if hash := ctx.String(utils.GetFlagName(utils.SnapshotStorageHashFlag)); hash != "" {
+		trustedStorageHash, err = common.Uint256FromHexString(hash)
+		if err != nil {
+			return fmt.Errorf("invalid %s argument:%s", utils.SnapshotStorageHashFlag.Name, err)
+		}
+	} else {
+		PrintWarnMsg("Missing %s argument, the storage of snapshot is unverified.", utils.SnapshotStorageHashFlag.Name)
+	}
+
+	sf, err := os.OpenFile(snapshotFile, os.O_RDONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("OpenFile error:%s", err)
+	}
+	defer sf.Close()
+	zReader, err := zlib.NewReader(bufio.NewReader(sf))
+	if err != nil {
+		return fmt.Errorf("snapshot file decompress error:%s", err)
+	}
+	defer zReader.Close()
+
+	ledgerStore, err := ledgerstore.NewLedgerStore(utils.GetStoreDirPath(cfg.Common.DataDir, cfg.P2PNode.NetworkName))
+	if err != nil {
+		return fmt.Errorf("NewLedgerStore error:%s", err)
+	}
+	defer ledgerStore.Close()
+	bookKeepers, err := cfg.GetBookkeepers()
+	if err != nil {
+		return fmt.Errorf("GetBookkeepers error:%s", err)
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, cfg.Genesis)
+	if err != nil {
+		return fmt.Errorf("BuildGenesisBlock error %s", err)
+	}
+
+	PrintInfoMsg("Start import snapshot.")
+	metadata, err := ledgerStore.ImportSnapshot(zReader, genesisBlock, trustedBlockHash, trustedStorageHash)
+	if err != nil {
+		return fmt.Errorf("import snapshot error:%s", err)
+	}
+	PrintInfoMsg("Import snapshot completed.")
+	printSnapshotMetadata(metadata)
+	return nil
+}
+
+//verifySnapshot compare the block hash and state merkle root of snapshot with the trusted node, and return the block
+//hash of the node at snapshot height
+func verifySnapshot(snapshotFile string) (common.Uint256, error) {
+	sf, err := os.OpenFile(snapshotFile, os.O_RDONLY, 0644)
+	if err != nil {
+		return common.UINT256_EMPTY, fmt.Errorf("OpenFile error:%s", err)
+	}
+	defer sf.Close()
+	zReader, err := zlib.NewReader(bufio.NewReader(sf))
+	if err != nil {
+		return common.UINT256_EMPTY, fmt.Errorf("snapshot file decompress error:%s", err)
+	}
+	defer zReader.Close()
+	metadata := new(ledgerstore.SnapshotMetadata)
+	err = metadata.Deserialize(zReader)
+	if err != nil {
+		return common.UINT256_EMPTY, fmt.Errorf("snapshot metadata deserialize error:%s", err)
+	}
+
+	hash, err := utils.GetBlockHash(metadata.Height)
+	if err != nil {
+		return common.UINT256_EMPTY, fmt.Errorf("GetBlockHash error:%s", err)
+	}
+	blockHash, err := common.Uint256FromHexString(hash)
+	if err != nil {
+		return common.UINT256_EMPTY, fmt.Errorf("invalid block hash:%s of node error:%s", hash, err)
+	}
+	if blockHash != metadata.BlockHash {
+		return common.UINT256_EMPTY, fmt.Errorf("block hash of height:%d mismatch, snapshot:%s, node:%s", metadata.Height,
+			metadata.BlockHash.ToHexString(), hash)
+	}
+	root, err := utils.GetStateMerkleRoot(metadata.Height)
+	if err != nil {
+		return common.UINT256_EMPTY, fmt.Errorf("GetStateMerkleRoot error:%s", err)
+	}
+	if root != metadata.StateMerkleRoot.ToHexString() {
+		return common.UINT256_EMPTY, fmt.Errorf("state merkle root of height:%d mismatch, snapshot:%s, node:%s", metadata.Height,
+			metadata.StateMerkleRoot.ToHexString(), root)
+	}
+	PrintInfoMsg("Snapshot block hash and state merkle root match the node at rpc port:%d.", config.DefConfig.Rpc.HttpJsonPort)
+	return blockHash, nil
+}
+
+func printSnapshotMetadata(metadata *ledgerstore.SnapshotMetadata) {
+	PrintInfoMsg("BlockHeight:%d", metadata.Height)
+	PrintInfoMsg("BlockHash:%s", metadata.BlockHash.ToHexString())
+	PrintInfoMsg("StateMerkleRoot:%s", metadata.StateMerkleRoot.ToHexString())
+	PrintInfoMsg("CrossStateRoot:%s", metadata.CrossStateRoot.ToHexString())
+	PrintInfoMsg("StorageCount:%d", metadata.StorageCount)
+	PrintInfoMsg("StorageHash:%s", metadata.StorageHash.ToHexString())
+	PrintInfoMsg("MerkleProofHeight:%d", metadata.MerkleTail)
+}
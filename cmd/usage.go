@@ -132,6 +132,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.EnableConsensusFlag,
 			utils.MaxTxInBlockFlag,
+			utils.TxSelectPolicyFlag,
+			utils.MaxTxPerRelayerInBlockFlag,
 		},
 	},
 	{
@@ -180,6 +182,21 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WsPortFlag,
 		},
 	},
+	{
+		Name: "PRUNE",
+		Flags: []cli.Flag{
+			utils.EnablePruneFlag,
+			utils.PruneDepthFlag,
+			utils.StateHistoryDepthFlag,
+		},
+	},
+	{
+		Name: "METRICS",
+		Flags: []cli.Flag{
+			utils.MetricsEnableFlag,
+			utils.MetricsPortFlag,
+		},
+	},
 	{
 		Name: "TEST MODE",
 		Flags: []cli.Flag{
@@ -201,9 +218,27 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ContractStorageFlag,
 			utils.ContractPrepareInvokeFlag,
 			utils.ContractParamsFlag,
+			utils.ContractMethodFlag,
 			utils.ContractReturnTypeFlag,
 		},
 	},
+	{
+		Name: "GOVERNANCE",
+		Flags: []cli.Flag{
+			utils.GovChainIDFlag,
+			utils.GovRouterFlag,
+			utils.GovChainNameFlag,
+			utils.GovBlocksToWaitFlag,
+			utils.GovCCMCAddressFlag,
+			utils.GovExtraInfoFlag,
+			utils.GovRelayersFlag,
+			utils.GovApproveIDFlag,
+			utils.GovPeerPubkeyFlag,
+			utils.GovPeerPubkeysFlag,
+			utils.GovFeeViewFlag,
+			utils.GovFeeFlag,
+		},
+	},
 	{
 		Name: "TRANSACTION",
 		Flags: []cli.Flag{
@@ -245,6 +280,16 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ImportEndHeightFlag,
 		},
 	},
+	{
+		Name: "SNAPSHOT",
+		Flags: []cli.Flag{
+			utils.SnapshotFileFlag,
+			utils.SnapshotMerkleTailFlag,
+			utils.SnapshotVerifyFlag,
+			utils.SnapshotBlockHashFlag,
+			utils.SnapshotStorageHashFlag,
+		},
+	},
 	{
 		Name: "MISC",
 	},
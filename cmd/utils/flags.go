@@ -30,6 +30,9 @@ const (
 	DEFAULT_ABI_PATH      = "./abi"
 	DEFAULT_EXPORT_HEIGHT = 0
 	DEFAULT_WALLET_PATH   = "./wallet_data"
+
+	DEFAULT_SNAPSHOT_FILE        = "./PolySnapshot.dat"
+	DEFAULT_SNAPSHOT_MERKLE_TAIL = 0
 )
 
 var (
@@ -78,6 +81,16 @@ var (
 		Usage: "Max transaction `<number>` in block",
 		Value: config.DEFAULT_MAX_TX_IN_BLOCK,
 	}
+	TxSelectPolicyFlag = cli.StringFlag{
+		Name:  "tx-select-policy",
+		Usage: "Policy `<priority|fifo>` to select transactions in block, priority: header syncs before cross chain transfers and fair across relayers, fifo: in the order of entering txnpool",
+		Value: config.DEFAULT_TX_SELECT_POLICY,
+	}
+	MaxTxPerRelayerInBlockFlag = cli.UintFlag{
+		Name:  "max-relayer-tx-in-block",
+		Usage: "Max transaction `<number>` of a relayer in block, 0 means no limit",
+		Value: config.DEFAULT_MAX_TX_PER_RELAYER_IN_BLOCK,
+	}
 
 	//Test Mode setting
 	EnableTestModeFlag = cli.BoolFlag{
@@ -186,6 +199,33 @@ var (
 		Value: config.DEFAULT_REST_MAX_CONN,
 	}
 
+	//Metrics setting
+	MetricsEnableFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable prometheus metrics server",
+	}
+	MetricsPortFlag = cli.UintFlag{
+		Name:  "metricsport",
+		Usage: "Metrics server listening port `<number>`",
+		Value: config.DEFAULT_METRICS_PORT,
+	}
+
+	//Prune setting
+	EnablePruneFlag = cli.BoolFlag{
+		Name:  "enable-prune",
+		Usage: "Keep the full state but only the latest blocks and events, the node could not serve the history data any more",
+	}
+	PruneDepthFlag = cli.UintFlag{
+		Name:  "prune-depth",
+		Usage: "Keep the latest `<number>` blocks and events in pruning mode",
+		Value: config.DEFAULT_PRUNE_DEPTH,
+	}
+	StateHistoryDepthFlag = cli.UintFlag{
+		Name:  "state-history-depth",
+		Usage: "Keep the state write sets of latest `<number>` blocks to serve pre-execution and storage at history heights, 0 disables",
+		Value: config.DEFAULT_STATE_HISTORY_DEPTH,
+	}
+
 	//Account setting
 	AccountPassFlag = cli.StringFlag{
 		Name:   "password,p",
@@ -301,7 +341,11 @@ var (
 	}
 	ContractParamsFlag = cli.StringFlag{
 		Name:  "params",
-		Usage: "Contract parameters list to invoke. separate params with comma ','",
+		Usage: "Contract parameters to invoke. JSON object keyed by parameter name, or JSON array in parameter order",
+	}
+	ContractMethodFlag = cli.StringFlag{
+		Name:  "method",
+		Usage: "Contract `<method>` to invoke",
 	}
 	ContractPrepareDeployFlag = cli.BoolFlag{
 		Name:  "prepare,p",
@@ -390,6 +434,57 @@ var (
 		Usage: "Force to send transaction",
 	}
 
+	//Governance setting
+	GovChainIDFlag = cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "Side chain `<id>`",
+	}
+	GovRouterFlag = cli.Uint64Flag{
+		Name:  "router",
+		Usage: "Router `<number>` of side chain",
+	}
+	GovChainNameFlag = cli.StringFlag{
+		Name:  "chainname",
+		Usage: "Side chain `<name>`",
+	}
+	GovBlocksToWaitFlag = cli.Uint64Flag{
+		Name:  "blockstowait",
+		Usage: "Confirmation blocks `<number>` of side chain",
+		Value: 1,
+	}
+	GovCCMCAddressFlag = cli.StringFlag{
+		Name:  "ccmc",
+		Usage: "Cross chain manager contract `<address>` of side chain, encode with hex string",
+	}
+	GovExtraInfoFlag = cli.StringFlag{
+		Name:  "extra",
+		Usage: "Extra `<info>` of side chain router, encode with hex string",
+	}
+	GovRelayersFlag = cli.StringFlag{
+		Name:  "relayers",
+		Usage: "Relayer `<addresses>`, separate addresses with comma `,`",
+	}
+	GovApproveIDFlag = cli.Uint64Flag{
+		Name:  "id",
+		Usage: "Application `<id>` to approve",
+	}
+	GovPeerPubkeyFlag = cli.StringFlag{
+		Name:  "peer",
+		Usage: "Peer `<pubkey>` of candidate, encode with hex string",
+	}
+	GovPeerPubkeysFlag = cli.StringFlag{
+		Name:  "peers",
+		Usage: "Peer `<pubkeys>` of candidates, separate pubkeys with comma `,`",
+	}
+	GovFeeViewFlag = cli.Uint64Flag{
+		Name:  "view",
+		Usage: "Fee `<view>` of side chain to vote",
+	}
+	GovFeeFlag = cli.StringFlag{
+		Name:  "fee",
+		Usage: "Fee `<amount>` of side chain, integer in the smallest unit of side chain",
+	}
+
 	//Cli setting
 	CliAddressFlag = cli.StringFlag{
 		Name:  "cliaddress",
@@ -434,6 +529,30 @@ var (
 		Value: "m",
 	}
 
+	//Snapshot setting
+	SnapshotFileFlag = cli.StringFlag{
+		Name:  "snapshot-file",
+		Usage: "State snapshot `<file>` path",
+		Value: DEFAULT_SNAPSHOT_FILE,
+	}
+	SnapshotMerkleTailFlag = cli.UintFlag{
+		Name:  "merkle-tail",
+		Usage: "Count of latest `<blocks>` with merkle proof available in the ledger imported from snapshot",
+		Value: DEFAULT_SNAPSHOT_MERKLE_TAIL,
+	}
+	SnapshotVerifyFlag = cli.BoolFlag{
+		Name:  "verify-rpc",
+		Usage: "Compare the block hash and state merkle root of snapshot with the trusted node at --rpcport, and trust the block hash of the node",
+	}
+	SnapshotBlockHashFlag = cli.StringFlag{
+		Name:  "trusted-block-hash",
+		Usage: "Trusted `<hash>` of the block at snapshot height, the snapshot with different block hash is rejected",
+	}
+	SnapshotStorageHashFlag = cli.StringFlag{
+		Name:  "trusted-storage-hash",
+		Usage: "Trusted `<hash>` of the storage items printed by the trusted node exporting the snapshot, the storage is unverified without it",
+	}
+
 	//PreExecute switcher
 	TxpoolPreExecDisableFlag = cli.BoolFlag{
 		Name:  "disable-tx-pool-pre-exec",
@@ -29,10 +29,14 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	sig "github.com/ontio/ontology-crypto/signature"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/payload"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native/states"
 )
@@ -172,6 +176,32 @@ func GetBlockCount() (uint32, error) {
 	return num, nil
 }
 
+func GetBlockHash(height uint32) (string, error) {
+	data, ontErr := sendRpcRequest("getblockhash", []interface{}{height})
+	if ontErr != nil {
+		return "", ontErr.Error
+	}
+	blockHash := ""
+	err := json.Unmarshal(data, &blockHash)
+	if err != nil {
+		return "", fmt.Errorf("json.Unmarshal:%s error:%s", data, err)
+	}
+	return blockHash, nil
+}
+
+func GetStateMerkleRoot(height uint32) (string, error) {
+	data, ontErr := sendRpcRequest("getstatemerkleroot", []interface{}{height})
+	if ontErr != nil {
+		return "", ontErr.Error
+	}
+	root := ""
+	err := json.Unmarshal(data, &root)
+	if err != nil {
+		return "", fmt.Errorf("json.Unmarshal:%s error:%s", data, err)
+	}
+	return root, nil
+}
+
 func GetTxHeight(txHash string) (uint32, error) {
 	data, ontErr := sendRpcRequest("getblockheightbytxhash", []interface{}{txHash})
 	if ontErr != nil {
@@ -189,6 +219,31 @@ func GetTxHeight(txHash string) (uint32, error) {
 	return height, nil
 }
 
+//NewNativeInvokeTransaction return the transaction invoking method of native contract, chain id is got from the network id of node
+func NewNativeInvokeTransaction(contract common.Address, method string, args []byte) (*types.Transaction, error) {
+	networkId, err := GetNetworkId()
+	if err != nil {
+		return nil, fmt.Errorf("GetNetworkId error:%s", err)
+	}
+	invokeCode := common.NewZeroCopySink(nil)
+	invokeParam := &states.ContractInvokeParam{Address: contract, Method: method, Args: args}
+	invokeParam.Serialization(invokeCode)
+	tx := &types.Transaction{
+		Version: types.CURR_TX_VERSION,
+		TxType:  types.Invoke,
+		Payload: &payload.InvokeCode{Code: invokeCode.Bytes()},
+		Nonce:   uint32(time.Now().Unix()),
+		ChainID: config.GetChainIdByNetId(networkId),
+		Sigs:    []types.Sig{},
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("tx serialization error:%s", err)
+	}
+	//deserialize to get the tx hash
+	return types.TransactionFromRawBytes(sink.Bytes())
+}
+
 func SignTransaction(signer *account.Account, tx *types.Transaction) error {
 	txHash := tx.Hash()
 	sigData, err := Sign(txHash.ToArray(), signer)
@@ -278,6 +333,22 @@ func GetSmartContractEventInfo(txHash string) ([]byte, error) {
 	return nil, ontErr.Error
 }
 
+func TraceBlock(height uint32) ([]byte, error) {
+	data, ontErr := sendRpcRequest("traceblock", []interface{}{height})
+	if ontErr == nil {
+		return data, nil
+	}
+	return nil, ontErr.Error
+}
+
+func TraceTransaction(txHash string) ([]byte, error) {
+	data, ontErr := sendRpcRequest("tracetx", []interface{}{txHash})
+	if ontErr == nil {
+		return data, nil
+	}
+	return nil, ontErr.Error
+}
+
 func hasAlreadySig(data []byte, pk keypair.PublicKey, sigDatas [][]byte) bool {
 	for _, sigData := range sigDatas {
 		err := signature.Verify(pk, data, sigData)
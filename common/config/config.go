@@ -54,12 +54,14 @@ const (
 	DEFAULT_RPC_LOCAL_PORT                  = uint(20337)
 	DEFAULT_REST_PORT                       = uint(20334)
 	DEFAULT_WS_PORT                         = uint(20335)
+	DEFAULT_METRICS_PORT                    = uint(20340)
 	DEFAULT_REST_MAX_CONN                   = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND               = uint(1024)
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP = uint(16)
 	DEFAULT_HTTP_INFO_PORT                  = uint(0)
 	DEFAULT_MAX_TX_IN_BLOCK                 = 60000
+	DEFAULT_MAX_TX_PER_RELAYER_IN_BLOCK     = uint(0)
 	DEFAULT_MAX_SYNC_HEADER                 = 500
 	DEFAULT_ENABLE_CONSENSUS                = true
 	DEFAULT_ENABLE_EVENT_LOG                = true
@@ -67,11 +69,22 @@ const (
 	DEFUALT_CLI_RPC_ADDRESS                 = "127.0.0.1"
 	DEFAULT_GAS_LIMIT                       = 20000
 	DEFAULT_GAS_PRICE                       = 500
+	DEFAULT_PRUNE_DEPTH                     = uint(200000)
+	MIN_PRUNE_DEPTH                         = uint(1000)
+	DEFAULT_STATE_HISTORY_DEPTH             = uint(0)
 
 	DEFAULT_DATA_DIR      = "./Chain"
 	DEFAULT_RESERVED_FILE = "./peers.rsv"
 )
 
+//Policies to select the transactions of block from txnpool
+const (
+	TX_SELECT_POLICY_PRIORITY = "priority" //header syncs first, then others and cross chain transfers, fair across relayers
+	TX_SELECT_POLICY_FIFO     = "fifo"     //the order that transactions are verified and added to txnpool
+
+	DEFAULT_TX_SELECT_POLICY = TX_SELECT_POLICY_PRIORITY
+)
+
 const (
 	NETWORK_ID_MAIN_NET   = 1
 	NETWORK_ID_TEST_NET   = 2
@@ -118,6 +131,41 @@ var HECO120_HEIGHT = map[uint32]uint64{
 	NETWORK_ID_TEST_NET: constants.HECO120_HEIGHT_TESTNET,
 }
 
+var HEADER_TIME_CHECK_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.HEADER_TIME_CHECK_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.HEADER_TIME_CHECK_HEIGHT_TESTNET,
+}
+
+var TRANSFER_FEE_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.TRANSFER_FEE_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.TRANSFER_FEE_HEIGHT_TESTNET,
+}
+
+var REPLENISH_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.REPLENISH_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.REPLENISH_HEIGHT_TESTNET,
+}
+
+var PROPOSAL_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.PROPOSAL_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.PROPOSAL_HEIGHT_TESTNET,
+}
+
+var STORAGE_INDEX_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.STORAGE_INDEX_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.STORAGE_INDEX_HEIGHT_TESTNET,
+}
+
+var APPLY_EXPIRY_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.APPLY_EXPIRY_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.APPLY_EXPIRY_HEIGHT_TESTNET,
+}
+
+var QUARANTINE_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.QUARANTINE_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.QUARANTINE_HEIGHT_TESTNET,
+}
+
 var POLYGON_SNAP_CHAINID = map[uint32]uint32{
 	NETWORK_ID_MAIN_NET: constants.POLYGON_SNAP_CHAINID_MAINNET,
 }
@@ -165,6 +213,34 @@ func GetExtraInfoHeight(id uint32) uint32 {
 	return EXTRA_INFO_HEIGHT[id]
 }
 
+func GetHeaderTimeCheckHeight(id uint32) uint32 {
+	return HEADER_TIME_CHECK_HEIGHT[id]
+}
+
+func GetTransferFeeHeight(id uint32) uint32 {
+	return TRANSFER_FEE_HEIGHT[id]
+}
+
+func GetReplenishHeight(id uint32) uint32 {
+	return REPLENISH_HEIGHT[id]
+}
+
+func GetProposalHeight(id uint32) uint32 {
+	return PROPOSAL_HEIGHT[id]
+}
+
+func GetStorageIndexHeight(id uint32) uint32 {
+	return STORAGE_INDEX_HEIGHT[id]
+}
+
+func GetApplyExpiryHeight(id uint32) uint32 {
+	return APPLY_EXPIRY_HEIGHT[id]
+}
+
+func GetQuarantineHeight(id uint32) uint32 {
+	return QUARANTINE_HEIGHT[id]
+}
+
 func GetNetworkName(id uint32) string {
 	name, ok := NETWORK_NAME[id]
 	if ok {
@@ -437,8 +513,10 @@ type CommonConfig struct {
 }
 
 type ConsensusConfig struct {
-	EnableConsensus bool
-	MaxTxInBlock    uint
+	EnableConsensus        bool
+	MaxTxInBlock           uint
+	TxSelectPolicy         string
+	MaxTxPerRelayerInBlock uint //0 means no limit
 }
 
 type P2PRsvConfig struct {
@@ -487,6 +565,19 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+//PruneConfig of ledger, the full state is always kept, the blocks and events older than PruneDepth are deleted if enabled.
+//The state write sets of latest StateHistoryDepth blocks are kept to serve the state at history heights, 0 means disabled
+type PruneConfig struct {
+	EnablePrune       bool
+	PruneDepth        uint
+	StateHistoryDepth uint
+}
+
+type MetricsConfig struct {
+	EnableMetrics bool
+	MetricsPort   uint
+}
+
 type OntologyConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
@@ -495,6 +586,8 @@ type OntologyConfig struct {
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	Ws        *WebSocketConfig
+	Prune     *PruneConfig
+	Metrics   *MetricsConfig
 }
 
 func NewOntologyConfig() *OntologyConfig {
@@ -508,8 +601,10 @@ func NewOntologyConfig() *OntologyConfig {
 			DataDir:        DEFAULT_DATA_DIR,
 		},
 		Consensus: &ConsensusConfig{
-			EnableConsensus: true,
-			MaxTxInBlock:    DEFAULT_MAX_TX_IN_BLOCK,
+			EnableConsensus:        true,
+			MaxTxInBlock:           DEFAULT_MAX_TX_IN_BLOCK,
+			TxSelectPolicy:         DEFAULT_TX_SELECT_POLICY,
+			MaxTxPerRelayerInBlock: DEFAULT_MAX_TX_PER_RELAYER_IN_BLOCK,
 		},
 		P2PNode: &P2PNodeConfig{
 			ReservedCfg:               &P2PRsvConfig{},
@@ -543,6 +638,15 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		Prune: &PruneConfig{
+			EnablePrune:       false,
+			PruneDepth:        DEFAULT_PRUNE_DEPTH,
+			StateHistoryDepth: DEFAULT_STATE_HISTORY_DEPTH,
+		},
+		Metrics: &MetricsConfig{
+			EnableMetrics: false,
+			MetricsPort:   DEFAULT_METRICS_PORT,
+		},
 	}
 }
 
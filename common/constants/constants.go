@@ -56,3 +56,35 @@ const POLYGON_SNAP_CHAINID_MAINNET = 16
 
 // eth arrow glacier upgrade
 const ETH4345_HEIGHT_MAINNET = 13_773_000
+
+// side chain header time checked against poly block time, not scheduled on main net and test net yet
+const HEADER_TIME_CHECK_HEIGHT_MAINNET = 0xFFFFFFFF
+const HEADER_TIME_CHECK_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// cross chain transfers held till the fee of target chain is paid, not scheduled on main net and test net yet
+const TRANSFER_FEE_HEIGHT_MAINNET = 0xFFFFFFFF
+const TRANSFER_FEE_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// makeProof of stored requests re-emitted by replenishTx, not scheduled on main net and test net yet
+const REPLENISH_HEIGHT_MAINNET = 0xFFFFFFFF
+const REPLENISH_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// consensus votes kept as node manager proposals, not scheduled on main net and test net yet
+const PROPOSAL_HEIGHT_MAINNET = 0xFFFFFFFF
+const PROPOSAL_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// storage indexes of the governance and cross chain manager lists written, not scheduled on main net and test net yet
+const STORAGE_INDEX_HEIGHT_MAINNET = 0xFFFFFFFF
+const STORAGE_INDEX_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// governance applications expired, cancelled and swept at commitDpos, not scheduled on main net and test net yet
+const APPLY_EXPIRY_HEIGHT_MAINNET = 0xFFFFFFFF
+const APPLY_EXPIRY_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// transfers to the chain registered or whitelisted quarantined by its height, not scheduled on main net and test net yet
+const QUARANTINE_HEIGHT_MAINNET = 0xFFFFFFFF
+const QUARANTINE_HEIGHT_TESTNET = 0xFFFFFFFF
+
+// eth beacon chain light client router, not scheduled on main net and test net yet
+const ETH_BEACON_ROUTER_HEIGHT_MAINNET = 0xFFFFFFFF
+const ETH_BEACON_ROUTER_HEIGHT_TESTNET = 0xFFFFFFFF
@@ -0,0 +1,365 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package metrics provides the counters, gauges and histograms of the node, exported in the prometheus text format
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//Default buckets in seconds of the latency histograms
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type collector interface {
+	write(w io.Writer, name string)
+}
+
+type metric struct {
+	name      string
+	help      string
+	kind      string
+	collector collector
+}
+
+//Registry holds the metrics by name, a metric registered again with the same name replaces the old one
+type Registry struct {
+	lock    sync.RWMutex
+	metrics map[string]*metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+//The registry the metrics of the node are registered to
+var DefaultRegistry = NewRegistry()
+
+func (this *Registry) register(name, help, kind string, c collector) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.metrics[name] = &metric{name: name, help: help, kind: kind, collector: c}
+}
+
+//Write all the metrics sorted by name in the prometheus text format
+func (this *Registry) WriteText(w io.Writer) {
+	this.lock.RLock()
+	metrics := make([]*metric, 0, len(this.metrics))
+	for _, m := range this.metrics {
+		metrics = append(metrics, m)
+	}
+	this.lock.RUnlock()
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name < metrics[j].name })
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+		m.collector.write(w, m.name)
+	}
+}
+
+//Http handler serving the metrics of the registry
+func (this *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		this.WriteText(buf)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write(buf.Bytes())
+	})
+}
+
+func Handler() http.Handler {
+	return DefaultRegistry.Handler()
+}
+
+type Counter struct {
+	value uint64
+}
+
+func (this *Counter) Inc() {
+	atomic.AddUint64(&this.value, 1)
+}
+
+func (this *Counter) Add(delta uint64) {
+	atomic.AddUint64(&this.value, delta)
+}
+
+func (this *Counter) Value() uint64 {
+	return atomic.LoadUint64(&this.value)
+}
+
+func (this *Counter) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %d\n", name, this.Value())
+}
+
+type Gauge struct {
+	bits uint64
+}
+
+func (this *Gauge) Set(value float64) {
+	atomic.StoreUint64(&this.bits, math.Float64bits(value))
+}
+
+func (this *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&this.bits)
+		if atomic.CompareAndSwapUint64(&this.bits, old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func (this *Gauge) Inc() {
+	this.Add(1)
+}
+
+func (this *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&this.bits))
+}
+
+func (this *Gauge) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(this.Value()))
+}
+
+//GaugeFunc gets the value when the metrics are collected
+type GaugeFunc func() float64
+
+func (this GaugeFunc) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(this()))
+}
+
+type Histogram struct {
+	lock    sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (this *Histogram) Observe(value float64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for i, bound := range this.buckets {
+		if value <= bound {
+			this.counts[i]++
+		}
+	}
+	this.count++
+	this.sum += value
+}
+
+//Observe the seconds elapsed since start
+func (this *Histogram) ObserveSince(start time.Time) {
+	this.Observe(time.Since(start).Seconds())
+}
+
+func (this *Histogram) Count() uint64 {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.count
+}
+
+func (this *Histogram) write(w io.Writer, name string) {
+	this.writeLabeled(w, name, "")
+}
+
+func (this *Histogram) writeLabeled(w io.Writer, name, labels string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	for i, bound := range this.buckets {
+		fmt.Fprintf(w, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels, sep, formatFloat(bound), this.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, this.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(this.sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, this.count)
+}
+
+//vec holds the metrics of the same name distinguished by label values
+type vec struct {
+	lock       sync.RWMutex
+	labelNames []string
+	children   map[string]interface{}
+	newChild   func() interface{}
+}
+
+func newVec(labelNames []string, newChild func() interface{}) *vec {
+	return &vec{labelNames: labelNames, children: make(map[string]interface{}), newChild: newChild}
+}
+
+func (this *vec) with(values []string) interface{} {
+	if len(values) != len(this.labelNames) {
+		panic(fmt.Sprintf("metrics: expected %d label values, got %d", len(this.labelNames), len(values)))
+	}
+	labels := make([]string, len(values))
+	for i, v := range values {
+		labels[i] = this.labelNames[i] + "=" + strconv.Quote(v)
+	}
+	key := strings.Join(labels, ",")
+	this.lock.RLock()
+	child, ok := this.children[key]
+	this.lock.RUnlock()
+	if ok {
+		return child
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if child, ok = this.children[key]; !ok {
+		child = this.newChild()
+		this.children[key] = child
+	}
+	return child
+}
+
+func (this *vec) write(w io.Writer, name string) {
+	this.lock.RLock()
+	keys := make([]string, 0, len(this.children))
+	for key := range this.children {
+		keys = append(keys, key)
+	}
+	this.lock.RUnlock()
+	sort.Strings(keys)
+	for _, key := range keys {
+		this.lock.RLock()
+		child := this.children[key]
+		this.lock.RUnlock()
+		switch c := child.(type) {
+		case *Counter:
+			fmt.Fprintf(w, "%s{%s} %d\n", name, key, c.Value())
+		case *Gauge:
+			fmt.Fprintf(w, "%s{%s} %s\n", name, key, formatFloat(c.Value()))
+		case *Histogram:
+			c.writeLabeled(w, name, key)
+		}
+	}
+}
+
+type CounterVec struct {
+	*vec
+}
+
+func (this *CounterVec) WithLabelValues(values ...string) *Counter {
+	return this.with(values).(*Counter)
+}
+
+type GaugeVec struct {
+	*vec
+}
+
+func (this *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	return this.with(values).(*Gauge)
+}
+
+type HistogramVec struct {
+	*vec
+}
+
+func (this *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	return this.with(values).(*Histogram)
+}
+
+func (this *Registry) NewCounter(name, help string) *Counter {
+	c := new(Counter)
+	this.register(name, help, "counter", c)
+	return c
+}
+
+func (this *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{newVec(labelNames, func() interface{} { return new(Counter) })}
+	this.register(name, help, "counter", c)
+	return c
+}
+
+func (this *Registry) NewGauge(name, help string) *Gauge {
+	g := new(Gauge)
+	this.register(name, help, "gauge", g)
+	return g
+}
+
+func (this *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{newVec(labelNames, func() interface{} { return new(Gauge) })}
+	this.register(name, help, "gauge", g)
+	return g
+}
+
+func (this *Registry) NewGaugeFunc(name, help string, f func() float64) {
+	this.register(name, help, "gauge", GaugeFunc(f))
+}
+
+func (this *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(buckets)
+	this.register(name, help, "histogram", h)
+	return h
+}
+
+func (this *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{newVec(labelNames, func() interface{} { return newHistogram(buckets) })}
+	this.register(name, help, "histogram", h)
+	return h
+}
+
+func NewCounter(name, help string) *Counter {
+	return DefaultRegistry.NewCounter(name, help)
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return DefaultRegistry.NewCounterVec(name, help, labelNames...)
+}
+
+func NewGauge(name, help string) *Gauge {
+	return DefaultRegistry.NewGauge(name, help)
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return DefaultRegistry.NewGaugeVec(name, help, labelNames...)
+}
+
+func NewGaugeFunc(name, help string, f func() float64) {
+	DefaultRegistry.NewGaugeFunc(name, help, f)
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return DefaultRegistry.NewHistogram(name, help, buckets)
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return DefaultRegistry.NewHistogramVec(name, help, buckets, labelNames...)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
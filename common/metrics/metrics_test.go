@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("poly_test_total", "test counter")
+	counter.Inc()
+	counter.Add(2)
+	registry.NewGauge("poly_test_gauge", "test gauge").Set(1.5)
+	registry.NewGaugeFunc("poly_test_func", "test gauge func", func() float64 { return 7 })
+	vec := registry.NewCounterVec("poly_test_vec_total", "test counter vec", "chain_id", "result")
+	vec.WithLabelValues("2", "success").Inc()
+	vec.WithLabelValues("2", "success").Inc()
+	vec.WithLabelValues("1", "failure").Inc()
+	histogram := registry.NewHistogram("poly_test_seconds", "test histogram", []float64{0.1, 1})
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(5)
+
+	buf := new(bytes.Buffer)
+	registry.WriteText(buf)
+	assert.Equal(t, `# HELP poly_test_func test gauge func
+# TYPE poly_test_func gauge
+poly_test_func 7
+# HELP poly_test_gauge test gauge
+# TYPE poly_test_gauge gauge
+poly_test_gauge 1.5
+# HELP poly_test_seconds test histogram
+# TYPE poly_test_seconds histogram
+poly_test_seconds_bucket{le="0.1"} 1
+poly_test_seconds_bucket{le="1"} 2
+poly_test_seconds_bucket{le="+Inf"} 3
+poly_test_seconds_sum 5.55
+poly_test_seconds_count 3
+# HELP poly_test_total test counter
+# TYPE poly_test_total counter
+poly_test_total 3
+# HELP poly_test_vec_total test counter vec
+# TYPE poly_test_vec_total counter
+poly_test_vec_total{chain_id="1",result="failure"} 1
+poly_test_vec_total{chain_id="2",result="success"} 2
+`, buf.String())
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, buf.String(), rec.Body.String())
+}
+
+func TestHistogramVec(t *testing.T) {
+	registry := NewRegistry()
+	vec := registry.NewHistogramVec("poly_test_seconds", "test histogram vec", []float64{1}, "step")
+	vec.WithLabelValues("execute").Observe(0.5)
+	buf := new(bytes.Buffer)
+	registry.WriteText(buf)
+	assert.Contains(t, buf.String(), `poly_test_seconds_bucket{step="execute",le="1"} 1`)
+	assert.Contains(t, buf.String(), `poly_test_seconds_count{step="execute"} 1`)
+	assert.Panics(t, func() { vec.WithLabelValues() })
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"github.com/polynetwork/poly/common/metrics"
+)
+
+var (
+	consensusRound = metrics.NewGauge("poly_consensus_round",
+		"Block number of the current consensus round")
+	chainConfigView = metrics.NewGauge("poly_consensus_chain_config_view",
+		"View of the current chain config")
+	viewChanges = metrics.NewCounter("poly_consensus_view_changes_total",
+		"Count of the chain config updates")
+	proposalTimeouts = metrics.NewCounterVec("poly_consensus_proposal_timeouts_total",
+		"Count of the proposal timeouts", "type")
+	catchConsensusEvents = metrics.NewCounterVec("poly_consensus_catch_consensus_total",
+		"Count of catching the consensus of the peers after fast forward", "result")
+)
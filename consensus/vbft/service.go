@@ -283,6 +283,7 @@ func (self *Server) LoadChainConfig(blkNum uint32) error {
 	self.metaLock.Lock()
 	self.config = &cfg
 	self.metaLock.Unlock()
+	chainConfigView.Set(float64(cfg.View))
 
 	self.metaLock.RLock()
 	defer self.metaLock.RUnlock()
@@ -336,6 +337,8 @@ func (self *Server) updateChainConfig() error {
 	self.config = block.Info.NewChainConfig
 	self.LastConfigBlockNum = block.getLastConfigBlockNum()
 	self.metaLock.Unlock()
+	viewChanges.Inc()
+	chainConfigView.Set(float64(block.Info.NewChainConfig.View))
 
 	self.metaLock.RLock()
 	defer self.metaLock.RUnlock()
@@ -649,6 +652,7 @@ func (self *Server) updateParticipantConfig() error {
 
 func (self *Server) startNewRound() error {
 	blkNum := self.GetCurrentBlockNo()
+	consensusRound.Set(float64(blkNum))
 
 	if err := self.updateParticipantConfig(); err != nil {
 		log.Errorf("startNewRound error:%s", err)
@@ -1534,7 +1538,10 @@ func (self *Server) actionLoop() {
 					proposer, forEmpty := getCommitConsensus(commitMsgs, C, N)
 					if proposer == math.MaxUint32 {
 						if err := self.catchConsensus(blkNum); err != nil {
+							catchConsensusEvents.WithLabelValues("failure").Inc()
 							log.Infof("server %d fastforward done, catch consensus: %s", self.Index, err)
+						} else {
+							catchConsensusEvents.WithLabelValues("success").Inc()
 						}
 						log.Infof("server %d fastforward done at blk %d, no consensus", self.Index, blkNum)
 						break
@@ -1707,6 +1714,7 @@ func (self *Server) processTimerEvent(evt *TimerEvent) error {
 		//
 		// then propose for empty block, start 2ndProposal timeout, return
 		//
+		proposalTimeouts.WithLabelValues("propose").Inc()
 		return self.handleProposalTimeout(evt)
 
 	case EventRandomBackoff:
@@ -1721,6 +1729,7 @@ func (self *Server) processTimerEvent(evt *TimerEvent) error {
 		// 2. there must some valid proposal, if not, force resync, reset peer neighbours
 		// 3. endorse on highest-priority one, start endorse timeout, return
 		//
+		proposalTimeouts.WithLabelValues("propose_2nd").Inc()
 		return self.handleProposalTimeout(evt)
 
 	case EventEndorseBlockTimeout:
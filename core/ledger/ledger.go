@@ -26,8 +26,10 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/store/ledgerstore"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
 	cstate "github.com/polynetwork/poly/native/states"
 )
@@ -165,6 +167,18 @@ func (self *Ledger) GetStorageItem(codeHash common.Address, key []byte) ([]byte,
 	return storageItem.Value, nil
 }
 
+func (self *Ledger) GetStorageItemAtHeight(codeHash common.Address, key []byte, height uint32) ([]byte, error) {
+	storageKey := &states.StorageKey{
+		ContractAddress: codeHash,
+		Key:             key,
+	}
+	storageItem, err := self.ldgStore.GetStorageItemAtHeight(storageKey, height)
+	if err != nil {
+		return nil, err
+	}
+	return storageItem.Value, nil
+}
+
 func (self *Ledger) GetMerkleProof(proofHeight, rootHeight uint32) ([]byte, error) {
 	blockHash := self.ldgStore.GetBlockHash(proofHeight)
 	if bytes.Equal(blockHash.ToArray(), common.UINT256_EMPTY.ToArray()) {
@@ -181,6 +195,18 @@ func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecRe
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) PreExecuteContractAtHeight(tx *types.Transaction, height uint32) (*cstate.PreExecResult, error) {
+	return self.ldgStore.PreExecuteContractAtHeight(tx, height)
+}
+
+func (self *Ledger) TraceBlock(height uint32) ([]*native.TxTrace, error) {
+	return self.ldgStore.TraceBlock(height)
+}
+
+func (self *Ledger) TraceTransaction(txHash common.Uint256) (*native.TxTrace, error) {
+	return self.ldgStore.TraceTransaction(txHash)
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
@@ -189,6 +215,14 @@ func (self *Ledger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify
 	return self.ldgStore.GetEventNotifyByBlock(height)
 }
 
+func (self *Ledger) GetCrossChainTx(fromChainID uint64, txHash []byte) (*scom.CrossChainTx, error) {
+	return self.ldgStore.GetCrossChainTx(fromChainID, txHash)
+}
+
+func (self *Ledger) ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*scom.CrossChainTx, error) {
+	return self.ldgStore.ListCrossChainTxs(fromChainID, offset, limit)
+}
+
 func (self *Ledger) Close() error {
 	return self.ldgStore.Close()
 }
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+)
+
+//Status of cross chain transaction in poly
+type CrossChainTxStatus byte
+
+const (
+	CROSS_CHAIN_TX_PROVED   CrossChainTxStatus = 0x01 //Transaction is verified and the merkle proof to target chain is ready
+	CROSS_CHAIN_TX_HELD     CrossChainTxStatus = 0x02 //Transaction is held until the fee of target chain is paid
+	CROSS_CHAIN_TX_PENDING  CrossChainTxStatus = 0x03 //Transaction is pending for the release of consensus nodes
+	CROSS_CHAIN_TX_REJECTED CrossChainTxStatus = 0x04 //Pending transaction is rejected by consensus nodes
+)
+
+func (this CrossChainTxStatus) String() string {
+	switch this {
+	case CROSS_CHAIN_TX_PROVED:
+		return "proved"
+	case CROSS_CHAIN_TX_HELD:
+		return "held"
+	case CROSS_CHAIN_TX_PENDING:
+		return "pending"
+	case CROSS_CHAIN_TX_REJECTED:
+		return "rejected"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(this))
+	}
+}
+
+//CrossChainTx index the cross chain transaction by source chain id and source transaction hash
+type CrossChainTx struct {
+	FromChainID uint64
+	TxHash      []byte //Source chain transaction hash
+	PolyTxHash  common.Uint256
+	ToChainID   uint64
+	Height      uint32 //Poly block height of the latest status, the cross states of the height contain the merkle key if proved
+	Key         []byte //Merkle key of the cross states, empty if not proved
+	Status      CrossChainTxStatus
+}
+
+func (this *CrossChainTx) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.FromChainID)
+	sink.WriteVarBytes(this.TxHash)
+	sink.WriteHash(this.PolyTxHash)
+	sink.WriteUint64(this.ToChainID)
+	sink.WriteUint32(this.Height)
+	sink.WriteVarBytes(this.Key)
+	sink.WriteByte(byte(this.Status))
+}
+
+func (this *CrossChainTx) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.FromChainID, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize fromChainID error")
+	}
+	this.TxHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize txHash error")
+	}
+	this.PolyTxHash, eof = source.NextHash()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize polyTxHash error")
+	}
+	this.ToChainID, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize toChainID error")
+	}
+	this.Height, eof = source.NextUint32()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize height error")
+	}
+	this.Key, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize key error")
+	}
+	status, eof := source.NextByte()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize status error")
+	}
+	this.Status = CrossChainTxStatus(status)
+	return nil
+}
@@ -45,6 +45,13 @@ const (
 	SYS_STATE_MERKLE_TREE  DataEntryPrefix = 0x20 // state merkle tree root key prefix
 	SYS_CROSS_STATES       DataEntryPrefix = 0x22
 	SYS_CROSS_STATES_HASH  DataEntryPrefix = 0x23
+	SYS_PRUNED_HEIGHT      DataEntryPrefix = 0x24 //Pruned block height key prefix
+	SYS_STATE_HISTORY      DataEntryPrefix = 0x25 //Block height => previous values of the states written in block key prefix
+	SYS_HISTORY_HEIGHT     DataEntryPrefix = 0x26 //Lowest height of the retained state history key prefix
+	SYS_BLOCK_MERKLE_TAIL  DataEntryPrefix = 0x27 //Lowest leaf of block merkle tree with hashes in merkle hash store key prefix
 
 	EVENT_NOTIFY DataEntryPrefix = 0x14 //Event notify key prefix
+
+	EVENT_CROSS_CHAIN_TX DataEntryPrefix = 0x15 //Source chain id + source tx hash => cross chain tx key prefix
+	IX_CROSS_CHAIN_TX    DataEntryPrefix = 0x16 //Source chain id + block height + index => source tx hash key prefix
 )
@@ -79,6 +79,11 @@ func (this *BlockCache) ContainBlock(blockHash common.Uint256) bool {
 	return this.blockCache.Contains(string(blockHash.ToArray()))
 }
 
+//RemoveBlock remove block from cache
+func (this *BlockCache) RemoveBlock(blockHash common.Uint256) {
+	this.blockCache.Remove(string(blockHash.ToArray()))
+}
+
 //AddTransaction add transaction to block cache
 func (this *BlockCache) AddTransaction(tx *types.Transaction, height uint32) {
 	txHash := tx.Hash()
@@ -102,3 +107,8 @@ func (this *BlockCache) GetTransaction(txHash common.Uint256) (*types.Transactio
 func (this *BlockCache) ContainTransaction(txHash common.Uint256) bool {
 	return this.transactionCache.Contains(string(txHash.ToArray()))
 }
+
+//RemoveTransaction remove transaction from cache
+func (this *BlockCache) RemoveTransaction(txHash common.Uint256) {
+	this.transactionCache.Remove(string(txHash.ToArray()))
+}
@@ -123,6 +123,9 @@ func (this *BlockStore) GetBlock(blockHash common.Uint256) (*types.Block, error)
 	txList := make([]*types.Transaction, 0, len(txHashes))
 	for _, txHash := range txHashes {
 		tx, _, err := this.GetTransaction(txHash)
+		if err == scom.ErrPruned {
+			return nil, err
+		}
 		if err != nil {
 			return nil, fmt.Errorf("GetTransaction %s error %s", txHash.ToHexString(), err)
 		}
@@ -356,6 +359,9 @@ func (this *BlockStore) loadTransaction(txHash common.Uint256) (*types.Transacti
 	if eof {
 		return nil, 0, io.ErrUnexpectedEOF
 	}
+	if source.Len() == 0 {
+		return nil, height, scom.ErrPruned
+	}
 	tx = new(types.Transaction)
 	err = tx.Deserialization(source)
 	if err != nil {
@@ -364,6 +370,22 @@ func (this *BlockStore) loadTransaction(txHash common.Uint256) (*types.Transacti
 	return tx, height, nil
 }
 
+//PruneBlock delete the transactions of block but keep their heights, so that the transactions are still known
+//to be on chain. The block header is kept
+func (this *BlockStore) PruneBlock(blockHash common.Uint256, txHashes []common.Uint256, height uint32) {
+	if this.enableCache {
+		this.cache.RemoveBlock(blockHash)
+	}
+	value := make([]byte, 4)
+	binary.LittleEndian.PutUint32(value, height)
+	for _, txHash := range txHashes {
+		if this.enableCache {
+			this.cache.RemoveTransaction(txHash)
+		}
+		this.store.BatchPut(this.getTransactionKey(txHash), value)
+	}
+}
+
 //IsContainTransaction return whether the transaction is in store
 func (this *BlockStore) ContainTransaction(txHash common.Uint256) (bool, error) {
 	key := this.getTransactionKey(txHash)
@@ -33,8 +33,9 @@ import (
 
 //Saving event notifies gen by smart contract execution
 type EventStore struct {
-	dbDir string                     //Store path
-	store *leveldbstore.LevelDBStore //Store handler
+	dbDir         string                     //Store path
+	store         *leveldbstore.LevelDBStore //Store handler
+	crossChainTxs map[string]bool            //Keys of cross chain transactions saved in current batch
 }
 
 //NewEventStore return event store instance
@@ -44,14 +45,16 @@ func NewEventStore(dbDir string) (*EventStore, error) {
 		return nil, err
 	}
 	return &EventStore{
-		dbDir: dbDir,
-		store: store,
+		dbDir:         dbDir,
+		store:         store,
+		crossChainTxs: make(map[string]bool),
 	}, nil
 }
 
 //NewBatch start event commit batch
 func (this *EventStore) NewBatch() {
 	this.store.NewBatch()
+	this.crossChainTxs = make(map[string]bool)
 }
 
 //SaveEventNotifyByTx persist event notify by transaction hash
@@ -134,6 +137,91 @@ func (this *EventStore) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNo
 	return evtNotifies, nil
 }
 
+//PruneEventNotify delete the event notifies of transactions in block
+func (this *EventStore) PruneEventNotify(height uint32) error {
+	key, err := this.getEventNotifyByBlockKey(height)
+	if err != nil {
+		return err
+	}
+	data, err := this.store.Get(key)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	reader := bytes.NewBuffer(data)
+	size, err := serialization.ReadUint32(reader)
+	if err != nil {
+		return fmt.Errorf("ReadUint32 error %s", err)
+	}
+	for i := uint32(0); i < size; i++ {
+		var txHash common.Uint256
+		err = txHash.Deserialize(reader)
+		if err != nil {
+			return fmt.Errorf("txHash.Deserialize error %s", err)
+		}
+		this.store.BatchDelete(this.getEventNotifyByTxKey(txHash))
+	}
+	this.store.BatchDelete(key)
+	return nil
+}
+
+//SaveCrossChainTx persist cross chain transaction, index is the order of it in the block of height. The transaction
+//already saved, in store or in current batch, is overwritten, and keeps its position in the list of the block it was
+//first saved
+func (this *EventStore) SaveCrossChainTx(tx *scom.CrossChainTx, height, index uint32) error {
+	key := this.getCrossChainTxKey(tx.FromChainID, tx.TxHash)
+	if !this.crossChainTxs[string(key)] {
+		_, err := this.store.Get(key)
+		if err != nil && err != scom.ErrNotFound {
+			return err
+		}
+		if err == scom.ErrNotFound {
+			this.store.BatchPut(this.getCrossChainTxIndexKey(tx.FromChainID, height, index), tx.TxHash)
+		}
+		this.crossChainTxs[string(key)] = true
+	}
+	sink := common.NewZeroCopySink(nil)
+	tx.Serialization(sink)
+	this.store.BatchPut(key, sink.Bytes())
+	return nil
+}
+
+//GetCrossChainTx return cross chain transaction by source chain id and source transaction hash
+func (this *EventStore) GetCrossChainTx(fromChainID uint64, txHash []byte) (*scom.CrossChainTx, error) {
+	data, err := this.store.Get(this.getCrossChainTxKey(fromChainID, txHash))
+	if err != nil {
+		return nil, err
+	}
+	tx := new(scom.CrossChainTx)
+	if err = tx.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return nil, fmt.Errorf("CrossChainTx.Deserialization error %s", err)
+	}
+	return tx, nil
+}
+
+//ListCrossChainTxs return at most limit cross chain transactions of source chain in block height order, skipping the first offset ones
+func (this *EventStore) ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*scom.CrossChainTx, error) {
+	txs := make([]*scom.CrossChainTx, 0)
+	iter := this.store.NewIterator(this.getCrossChainTxIndexKey(fromChainID, 0, 0)[:9])
+	defer iter.Release()
+	for i := uint32(0); uint32(len(txs)) < limit && iter.Next(); i++ {
+		if i < offset {
+			continue
+		}
+		tx, err := this.GetCrossChainTx(fromChainID, iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("GetCrossChainTx error %s", err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
 //CommitTo event store batch to store
 func (this *EventStore) CommitTo() error {
 	return this.store.BatchCommit()
@@ -207,3 +295,20 @@ func (this *EventStore) getEventNotifyByTxKey(txHash common.Uint256) []byte {
 	copy(key[1:], data)
 	return key
 }
+
+func (this *EventStore) getCrossChainTxKey(fromChainID uint64, txHash []byte) []byte {
+	key := make([]byte, 9+len(txHash))
+	key[0] = byte(scom.EVENT_CROSS_CHAIN_TX)
+	binary.BigEndian.PutUint64(key[1:], fromChainID)
+	copy(key[9:], txHash)
+	return key
+}
+
+func (this *EventStore) getCrossChainTxIndexKey(fromChainID uint64, height, index uint32) []byte {
+	key := make([]byte, 17)
+	key[0] = byte(scom.IX_CROSS_CHAIN_TX)
+	binary.BigEndian.PutUint64(key[1:], fromChainID)
+	binary.BigEndian.PutUint32(key[9:], height)
+	binary.BigEndian.PutUint32(key[13:], index)
+	return key
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func TestCrossChainTx(t *testing.T) {
+	eventStore, err := NewEventStore("test/event")
+	if err != nil {
+		t.Errorf("NewEventStore error %s", err)
+		return
+	}
+	defer eventStore.Close()
+	ledgerStore := &LedgerStoreImp{eventStore: eventStore}
+
+	notifies := make([]*event.ExecuteNotify, 0)
+	for i := 0; i < 3; i++ {
+		txHash := sha256.Sum256([]byte{byte(i)})
+		notifies = append(notifies, &event.ExecuteNotify{
+			TxHash: common.Uint256(sha256.Sum256(txHash[:])),
+			State:  event.CONTRACT_STATE_SUCCESS,
+			Notify: []*event.NotifyEventInfo{{
+				ContractAddress: utils.CrossChainManagerContractAddress,
+				States:          []interface{}{ccom.NOTIFY_MAKE_PROOF, uint64(2), uint64(3), hex.EncodeToString(txHash[:]), uint32(10), "abcd"},
+			}},
+		})
+	}
+	notifies[2].State = event.CONTRACT_STATE_FAIL
+	eventStore.NewBatch()
+	err = ledgerStore.saveCrossChainTxs(10, notifies)
+	if err != nil {
+		t.Errorf("saveCrossChainTxs error %s", err)
+		return
+	}
+	err = eventStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+
+	txHash := sha256.Sum256([]byte{1})
+	tx, err := eventStore.GetCrossChainTx(2, txHash[:])
+	if err != nil {
+		t.Errorf("GetCrossChainTx error %s", err)
+		return
+	}
+	if tx.PolyTxHash != notifies[1].TxHash || tx.ToChainID != 3 || tx.Height != 10 ||
+		!bytes.Equal(tx.Key, []byte{0xab, 0xcd}) || tx.Status != scom.CROSS_CHAIN_TX_PROVED {
+		t.Errorf("TestCrossChainTx unexpected tx %+v", tx)
+		return
+	}
+	txHash = sha256.Sum256([]byte{2})
+	_, err = eventStore.GetCrossChainTx(2, txHash[:])
+	if err != scom.ErrNotFound {
+		t.Errorf("TestCrossChainTx failed tx should not be indexed, err %v", err)
+		return
+	}
+
+	txs, err := eventStore.ListCrossChainTxs(2, 0, 10)
+	if err != nil {
+		t.Errorf("ListCrossChainTxs error %s", err)
+		return
+	}
+	if len(txs) != 2 || txs[0].PolyTxHash != notifies[0].TxHash || txs[1].PolyTxHash != notifies[1].TxHash {
+		t.Errorf("TestCrossChainTx unexpected list %+v", txs)
+		return
+	}
+	txs, err = eventStore.ListCrossChainTxs(2, 1, 10)
+	if err != nil {
+		t.Errorf("ListCrossChainTxs error %s", err)
+		return
+	}
+	if len(txs) != 1 || txs[0].PolyTxHash != notifies[1].TxHash {
+		t.Errorf("TestCrossChainTx unexpected list with offset %+v", txs)
+		return
+	}
+	txs, err = eventStore.ListCrossChainTxs(3, 0, 10)
+	if err != nil || len(txs) != 0 {
+		t.Errorf("TestCrossChainTx unexpected list of other chain %+v, err %v", txs, err)
+		return
+	}
+
+	//transaction saved twice in one batch is listed once
+	txHash = sha256.Sum256([]byte{3})
+	eventStore.NewBatch()
+	for i := uint32(0); i < 2; i++ {
+		err = eventStore.SaveCrossChainTx(&scom.CrossChainTx{FromChainID: 4, TxHash: txHash[:], Height: 11}, 11, i)
+		if err != nil {
+			t.Errorf("SaveCrossChainTx error %s", err)
+			return
+		}
+	}
+	err = eventStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+	txs, err = eventStore.ListCrossChainTxs(4, 0, 10)
+	if err != nil || len(txs) != 1 {
+		t.Errorf("TestCrossChainTx unexpected list of tx saved twice %+v, err %v", txs, err)
+		return
+	}
+}
+
+func TestCrossChainTxStatus(t *testing.T) {
+	eventStore, err := NewEventStore("test/event_status")
+	if err != nil {
+		t.Errorf("NewEventStore error %s", err)
+		return
+	}
+	defer eventStore.Close()
+	ledgerStore := &LedgerStoreImp{eventStore: eventStore}
+
+	held, pending := sha256.Sum256([]byte("held")), sha256.Sum256([]byte("pending"))
+	heldHex, pendingHex := hex.EncodeToString(held[:]), hex.EncodeToString(pending[:])
+	polyTxHash := common.Uint256(sha256.Sum256([]byte("poly")))
+	save := func(height uint32, states ...[]interface{}) {
+		notifies := make([]*event.ExecuteNotify, 0)
+		for i, s := range states {
+			notifies = append(notifies, &event.ExecuteNotify{
+				TxHash: common.Uint256(sha256.Sum256([]byte{byte(height), byte(i)})),
+				State:  event.CONTRACT_STATE_SUCCESS,
+				Notify: []*event.NotifyEventInfo{{ContractAddress: utils.CrossChainManagerContractAddress, States: s}},
+			})
+		}
+		eventStore.NewBatch()
+		if err := ledgerStore.saveCrossChainTxs(height, notifies); err != nil {
+			t.Fatalf("saveCrossChainTxs error %s", err)
+		}
+		if err := eventStore.CommitTo(); err != nil {
+			t.Fatalf("CommitTo error %s", err)
+		}
+	}
+	check := func(txHash []byte, status scom.CrossChainTxStatus, height uint32) *scom.CrossChainTx {
+		tx, err := eventStore.GetCrossChainTx(2, txHash)
+		if err != nil {
+			t.Fatalf("GetCrossChainTx error %s", err)
+		}
+		if tx.Status != status || tx.Height != height || tx.ToChainID != 3 {
+			t.Fatalf("TestCrossChainTxStatus unexpected tx %+v", tx)
+		}
+		return tx
+	}
+
+	save(10,
+		[]interface{}{ccom.NOTIFY_HOLD_TRANSFER, uint64(2), uint64(3), heldHex, uint32(10), "100", "0"},
+		[]interface{}{ccom.NOTIFY_PEND_TRANSFER, uint64(2), uint64(3), pendingHex, uint32(10), "rateLimit", hex.EncodeToString(polyTxHash[:])})
+	check(held[:], scom.CROSS_CHAIN_TX_HELD, 10)
+	check(pending[:], scom.CROSS_CHAIN_TX_PENDING, 10)
+
+	//released held transfer, and replenished proof of it in the same block
+	save(11,
+		[]interface{}{ccom.NOTIFY_MAKE_PROOF, uint64(2), uint64(3), heldHex, uint32(11), "abcd"},
+		[]interface{}{ccom.NOTIFY_MAKE_PROOF, uint64(2), uint64(3), heldHex, uint32(11), "abcd"})
+	tx := check(held[:], scom.CROSS_CHAIN_TX_PROVED, 11)
+	if !bytes.Equal(tx.Key, []byte{0xab, 0xcd}) {
+		t.Errorf("TestCrossChainTxStatus unexpected key %x", tx.Key)
+	}
+	save(12, []interface{}{ccom.REJECT_PENDING_TRANSFER, uint64(2), uint64(3), pendingHex, hex.EncodeToString(polyTxHash[:])})
+	tx = check(pending[:], scom.CROSS_CHAIN_TX_REJECTED, 12)
+	if tx.PolyTxHash != polyTxHash {
+		t.Errorf("TestCrossChainTxStatus unexpected poly tx hash %s", tx.PolyTxHash.ToHexString())
+	}
+
+	txs, err := eventStore.ListCrossChainTxs(2, 0, 10)
+	if err != nil {
+		t.Errorf("ListCrossChainTxs error %s", err)
+		return
+	}
+	if len(txs) != 2 || !bytes.Equal(txs[0].TxHash, held[:]) || !bytes.Equal(txs[1].TxHash, pending[:]) {
+		t.Errorf("TestCrossChainTxStatus unexpected list %+v", txs)
+	}
+}
+
+func TestFailedEventNotify(t *testing.T) {
+	eventStore, err := NewEventStore("test/event")
+	if err != nil {
+		t.Errorf("NewEventStore error %s", err)
+		return
+	}
+	defer eventStore.Close()
+
+	failed := &event.ExecuteNotify{TxHash: common.Uint256{1}, State: event.CONTRACT_STATE_FAIL}
+	failed.SetError(event.NewExecuteError(event.CONTRACT_ERROR_UNKNOWN_METHOD, "unknown method %s", "foo"))
+	notify := &event.ExecuteNotify{TxHash: common.Uint256{2}, State: event.CONTRACT_STATE_SUCCESS}
+	notify.SetError(nil)
+	eventStore.NewBatch()
+	for _, n := range []*event.ExecuteNotify{failed, notify} {
+		if err = eventStore.SaveEventNotifyByTx(n.TxHash, n); err != nil {
+			t.Errorf("SaveEventNotifyByTx error %s", err)
+			return
+		}
+	}
+	if err = eventStore.CommitTo(); err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+
+	n, err := eventStore.GetEventNotifyByTx(failed.TxHash)
+	if err != nil {
+		t.Errorf("GetEventNotifyByTx error %s", err)
+		return
+	}
+	if n.ErrorCode != event.CONTRACT_ERROR_UNKNOWN_METHOD || n.Error != "unknown method foo" {
+		t.Errorf("TestFailedEventNotify unexpected failed notify %+v", n)
+		return
+	}
+	n, err = eventStore.GetEventNotifyByTx(notify.TxHash)
+	if err != nil {
+		t.Errorf("GetEventNotifyByTx error %s", err)
+		return
+	}
+	if n.ErrorCode != event.CONTRACT_ERROR_NONE || n.Error != "" {
+		t.Errorf("TestFailedEventNotify unexpected notify %+v", n)
+		return
+	}
+
+	other := &event.ExecuteNotify{}
+	other.SetError(fmt.Errorf("other error"))
+	if other.ErrorCode != event.CONTRACT_ERROR_EXECUTE || other.Error != "other error" {
+		t.Errorf("TestFailedEventNotify unexpected notify of plain error %+v", other)
+	}
+}
@@ -19,6 +19,8 @@
 package ledgerstore
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"sort"
@@ -38,12 +40,15 @@ import (
 	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/core/store"
 	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/events"
 	"github.com/polynetwork/poly/events/message"
 	"github.com/polynetwork/poly/merkle"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/utils"
 	cstates "github.com/polynetwork/poly/native/states"
 	sstate "github.com/polynetwork/poly/native/states"
 	"github.com/polynetwork/poly/native/storage"
@@ -52,6 +57,7 @@ import (
 const (
 	SYSTEM_VERSION          = byte(1)      //Version of ledger store
 	HEADER_INDEX_BATCH_SIZE = uint32(2000) //Bath size of saving header index
+	PRUNE_BATCH_SIZE        = uint32(100)  //Max count of blocks pruned when saving a block
 )
 
 var (
@@ -70,6 +76,11 @@ type LedgerStoreImp struct {
 	storedIndexCount     uint32                           //record the count of have saved block index
 	currBlockHeight      uint32                           //Current block height
 	currBlockHash        common.Uint256                   //Current block hash
+	pruneDepth           uint32                           //Count of latest blocks and events to keep, 0 means archive mode
+	prunedHeight         uint32                           //Blocks and events not higher than this height are pruned
+	historyDepth         uint32                           //Count of latest blocks to keep the state history, 0 means disabled
+	historyHeight        uint32                           //Lowest height that the state could be served from state history
+	historyLock          sync.RWMutex                     //Lock of committing state, the snapshot of state history view is taken with read lock
 	headerCache          map[common.Uint256]*types.Header //BlockHash => Header
 	headerIndex          map[uint32]common.Uint256        //Header index, Mapping header height => block hash
 	savingBlockSemaphore chan bool
@@ -87,6 +98,10 @@ func NewLedgerStore(dataDir string) (*LedgerStoreImp, error) {
 		vbftPeerInfoblock:    make(map[string]uint32),
 		savingBlockSemaphore: make(chan bool, 1),
 	}
+	if config.DefConfig.Prune.EnablePrune {
+		ledgerStore.pruneDepth = uint32(config.DefConfig.Prune.PruneDepth)
+	}
+	ledgerStore.historyDepth = uint32(config.DefConfig.Prune.StateHistoryDepth)
 
 	blockStore, err := NewBlockStore(fmt.Sprintf("%s%s%s", dataDir, string(os.PathSeparator), DBDirBlock), true)
 	if err != nil {
@@ -229,10 +244,52 @@ func (this *LedgerStoreImp) init() error {
 	if err != nil {
 		return fmt.Errorf("loadHeaderIndexList error %s", err)
 	}
+	err = this.loadHistoryHeight()
+	if err != nil {
+		return fmt.Errorf("loadHistoryHeight error %s", err)
+	}
 	err = this.recoverStore()
 	if err != nil {
 		return fmt.Errorf("recoverStore error %s", err)
 	}
+	err = this.loadPrunedHeight()
+	if err != nil {
+		return fmt.Errorf("loadPrunedHeight error %s", err)
+	}
+	return nil
+}
+
+func (this *LedgerStoreImp) loadPrunedHeight() error {
+	prunedHeight, err := this.stateStore.GetPrunedHeight()
+	if err != nil && err != scom.ErrNotFound {
+		return err
+	}
+	this.prunedHeight = prunedHeight
+	if this.pruneDepth > 0 {
+		log.Infof("Ledger pruning enabled, prune depth %d, pruned height %d", this.pruneDepth, prunedHeight)
+	}
+	return nil
+}
+
+//loadHistoryHeight load the lowest height of state history, the state history starts from current state height
+//when it's enabled first time, and it's deleted when disabled
+func (this *LedgerStoreImp) loadHistoryHeight() error {
+	if this.historyDepth == 0 {
+		if _, err := this.stateStore.GetHistoryHeight(); err == scom.ErrNotFound {
+			return nil
+		}
+		log.Infof("State history disabled, delete the state history")
+		return this.stateStore.ClearStateHistory()
+	}
+	historyHeight, err := this.stateStore.GetHistoryHeight()
+	if err == scom.ErrNotFound {
+		_, historyHeight, err = this.stateStore.GetCurrentBlock()
+	}
+	if err != nil {
+		return err
+	}
+	this.historyHeight = historyHeight
+	log.Infof("State history enabled, history depth %d, history height %d", this.historyDepth, historyHeight)
 	return nil
 }
 
@@ -427,29 +484,8 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header, vbftPeerInfo map[
 	}
 	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
 	if consensusType == "vbft" {
-		//check bookkeeppers
-		needFix := config.NETWORK_ID_MAIN_NET != config.DefConfig.P2PNode.NetworkId || this.GetCurrentHeaderHeight() <= 20000000
-		m := len(vbftPeerInfo) - (len(vbftPeerInfo)-1)/3
-		if needFix {
-			m = len(vbftPeerInfo) - (len(vbftPeerInfo)*6)/7
-		}
-		if len(header.Bookkeepers) < m {
-			return vbftPeerInfo, fmt.Errorf("header Bookkeepers %d more than 2/3 len vbftPeerInfo%d", len(header.Bookkeepers), len(vbftPeerInfo))
-		}
-		usedPubKey := make(map[string]bool)
-		for _, bookkeeper := range header.Bookkeepers {
-			pubkey := vconfig.PubkeyID(bookkeeper)
-			_, present := vbftPeerInfo[pubkey]
-			if !present || usedPubKey[pubkey] {
-				log.Errorf("invalid pubkey :%v,height:%d", pubkey, header.Height)
-				return vbftPeerInfo, fmt.Errorf("invalid pubkey :%v", pubkey)
-			}
-			usedPubKey[pubkey] = true
-		}
-		hash := header.Hash()
-		err = signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData)
+		err = verifyVbftBookkeepers(header, vbftPeerInfo, this.GetCurrentHeaderHeight())
 		if err != nil {
-			log.Errorf("VerifyMultiSignature:%s,Bookkeepers:%d,pubkey:%d,heigh:%d", err, len(header.Bookkeepers), len(vbftPeerInfo), header.Height)
 			return vbftPeerInfo, err
 		}
 		blkInfo, err := vconfig.VbftBlock(header)
@@ -483,6 +519,35 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header, vbftPeerInfo map[
 	return vbftPeerInfo, nil
 }
 
+//verifyVbftBookkeepers check the bookkeepers of header are the vbft peers and verify the multiple signature of them
+func verifyVbftBookkeepers(header *types.Header, vbftPeerInfo map[string]uint32, currentHeaderHeight uint32) error {
+	needFix := config.NETWORK_ID_MAIN_NET != config.DefConfig.P2PNode.NetworkId || currentHeaderHeight <= 20000000
+	m := len(vbftPeerInfo) - (len(vbftPeerInfo)-1)/3
+	if needFix {
+		m = len(vbftPeerInfo) - (len(vbftPeerInfo)*6)/7
+	}
+	if len(header.Bookkeepers) < m {
+		return fmt.Errorf("header Bookkeepers %d more than 2/3 len vbftPeerInfo%d", len(header.Bookkeepers), len(vbftPeerInfo))
+	}
+	usedPubKey := make(map[string]bool)
+	for _, bookkeeper := range header.Bookkeepers {
+		pubkey := vconfig.PubkeyID(bookkeeper)
+		_, present := vbftPeerInfo[pubkey]
+		if !present || usedPubKey[pubkey] {
+			log.Errorf("invalid pubkey :%v,height:%d", pubkey, header.Height)
+			return fmt.Errorf("invalid pubkey :%v", pubkey)
+		}
+		usedPubKey[pubkey] = true
+	}
+	hash := header.Hash()
+	err := signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData)
+	if err != nil {
+		log.Errorf("VerifyMultiSignature:%s,Bookkeepers:%d,pubkey:%d,heigh:%d", err, len(header.Bookkeepers), len(vbftPeerInfo), header.Height)
+		return err
+	}
+	return nil
+}
+
 //AddHeader add header to cache, and add the mapping of block height to block hash. Using in block sync
 func (this *LedgerStoreImp) AddHeader(header *types.Header) error {
 	nextHeaderHeight := this.GetCurrentHeaderHeight() + 1
@@ -536,7 +601,9 @@ func (this *LedgerStoreImp) ExecuteBlock(block *types.Block) (result store.Execu
 		err = fmt.Errorf("block height %d not equal next block height %d", blockHeight, nextBlockHeight)
 		return
 	}
+	start := time.Now()
 	result, err = this.executeBlock(block)
+	executeBlockDuration.ObserveSince(start)
 	return
 }
 
@@ -558,10 +625,12 @@ func (this *LedgerStoreImp) SubmitBlock(block *types.Block, result store.Execute
 		return fmt.Errorf("verifyHeader error %s", err)
 	}
 
+	start := time.Now()
 	err = this.submitBlock(block, result)
 	if err != nil {
 		return fmt.Errorf("saveBlock error %s", err)
 	}
+	submitBlockDuration.ObserveSince(start)
 	this.delHeaderCache(block.Hash())
 	return nil
 }
@@ -635,7 +704,7 @@ func (this *LedgerStoreImp) executeBlock(block *types.Block) (result store.Execu
 	cache := storage.NewCacheDB(overlay)
 	for _, tx := range block.Transactions {
 		cache.Reset()
-		notify, crossHashes, e := this.handleTransaction(overlay, cache, block, tx)
+		notify, crossHashes, e := this.handleTransaction(overlay, cache, block, tx, nil, false)
 		if e != nil {
 			err = e
 			return
@@ -664,8 +733,12 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 			return fmt.Errorf("SaveNotify error %s", err)
 		}
 	}
+	err := this.saveCrossChainTxs(blockHeight, result.Notify)
+	if err != nil {
+		return fmt.Errorf("saveCrossChainTxs error %s", err)
+	}
 
-	err := this.stateStore.AddStateMerkleTreeRoot(blockHeight, result.Hash)
+	err = this.stateStore.AddStateMerkleTreeRoot(blockHeight, result.Hash)
 	if err != nil {
 		return fmt.Errorf("AddBlockMerkleTreeRoot error %s", err)
 	}
@@ -687,6 +760,13 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 
 	log.Debugf("the state transition hash of block %d is:%s", blockHeight, result.Hash.ToHexString())
 
+	if this.historyDepth > 0 && blockHeight > this.historyHeight {
+		err = this.stateStore.SaveStateHistory(blockHeight, result.WriteSet)
+		if err != nil {
+			return fmt.Errorf("SaveStateHistory error %s", err)
+		}
+	}
+
 	result.WriteSet.ForEach(func(key, val []byte) {
 		if len(val) == 0 {
 			this.stateStore.BatchDeleteRawKey(key)
@@ -698,6 +778,129 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 	return nil
 }
 
+//saveCrossChainTxs index the cross chain transactions by the makeProof, holdTransfer, pendTransfer and
+//rejectPendingTransfer notifies of cross chain manager, a transaction notified again is updated to the latest status
+func (this *LedgerStoreImp) saveCrossChainTxs(height uint32, notifies []*event.ExecuteNotify) error {
+	txs := make([]*scom.CrossChainTx, 0)
+	latest := make(map[string]int)
+	for _, notify := range notifies {
+		if notify.State != event.CONTRACT_STATE_SUCCESS {
+			continue
+		}
+		for _, n := range notify.Notify {
+			if n.ContractAddress != utils.CrossChainManagerContractAddress {
+				continue
+			}
+			tx, ok := parseCrossChainTxNotify(n.States)
+			if !ok {
+				continue
+			}
+			if tx.PolyTxHash == common.UINT256_EMPTY {
+				tx.PolyTxHash = notify.TxHash
+			}
+			if tx.Height == 0 {
+				tx.Height = height
+			}
+			id := fmt.Sprintf("%d:%x", tx.FromChainID, tx.TxHash)
+			if i, ok := latest[id]; ok {
+				txs[i] = tx
+				continue
+			}
+			latest[id] = len(txs)
+			txs = append(txs, tx)
+		}
+	}
+	for index, tx := range txs {
+		err := this.eventStore.SaveCrossChainTx(tx, height, uint32(index))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//parseCrossChainTxNotify parse states of notify:
+//[makeProof, fromChainID, toChainID, txHash, height, key]
+//[holdTransfer, fromChainID, toChainID, txHash, height, fee, paid]
+//[pendTransfer, fromChainID, toChainID, txHash, height, reason, polyTxHash]
+//[rejectPendingTransfer, fromChainID, toChainID, txHash, polyTxHash]
+//the replenishMakeProof is not parsed, the transfer keeps the status indexed from its original makeProof
+func parseCrossChainTxNotify(states interface{}) (*scom.CrossChainTx, bool) {
+	list, ok := states.([]interface{})
+	if !ok || len(list) < 5 {
+		return nil, false
+	}
+	name, ok := list[0].(string)
+	if !ok {
+		return nil, false
+	}
+	tx := new(scom.CrossChainTx)
+	switch {
+	case name == ccom.NOTIFY_MAKE_PROOF && len(list) == 6:
+		tx.Status = scom.CROSS_CHAIN_TX_PROVED
+	case name == ccom.NOTIFY_HOLD_TRANSFER && len(list) == 7:
+		tx.Status = scom.CROSS_CHAIN_TX_HELD
+	case name == ccom.NOTIFY_PEND_TRANSFER && len(list) == 7:
+		tx.Status = scom.CROSS_CHAIN_TX_PENDING
+	case name == ccom.REJECT_PENDING_TRANSFER && len(list) == 5:
+		tx.Status = scom.CROSS_CHAIN_TX_REJECTED
+	default:
+		return nil, false
+	}
+	fromChainID, ok1 := list[1].(uint64)
+	toChainID, ok2 := list[2].(uint64)
+	txHashStr, ok3 := list[3].(string)
+	if !ok1 || !ok2 || !ok3 {
+		log.Warnf("parseCrossChainTxNotify, unexpected %s states: %v", name, list)
+		return nil, false
+	}
+	txHash, err := hex.DecodeString(txHashStr)
+	if err != nil {
+		log.Warnf("parseCrossChainTxNotify, decode tx hash %s error %s", txHashStr, err)
+		return nil, false
+	}
+	tx.FromChainID = fromChainID
+	tx.TxHash = txHash
+	tx.ToChainID = toChainID
+
+	switch tx.Status {
+	case scom.CROSS_CHAIN_TX_REJECTED:
+		polyTxHash, ok := list[4].(string)
+		if !ok {
+			log.Warnf("parseCrossChainTxNotify, unexpected %s states: %v", name, list)
+			return nil, false
+		}
+		data, err := hex.DecodeString(polyTxHash)
+		if err == nil {
+			tx.PolyTxHash, err = common.Uint256ParseFromBytes(data)
+		}
+		if err != nil {
+			log.Warnf("parseCrossChainTxNotify, decode poly tx hash %s error %s", polyTxHash, err)
+			return nil, false
+		}
+	default:
+		height, ok := list[4].(uint32)
+		if !ok {
+			log.Warnf("parseCrossChainTxNotify, unexpected %s states: %v", name, list)
+			return nil, false
+		}
+		tx.Height = height
+	}
+	if tx.Status == scom.CROSS_CHAIN_TX_PROVED {
+		keyStr, ok := list[5].(string)
+		if !ok {
+			log.Warnf("parseCrossChainTxNotify, unexpected %s states: %v", name, list)
+			return nil, false
+		}
+		tx.Key, err = hex.DecodeString(keyStr)
+		if err != nil {
+			log.Warnf("parseCrossChainTxNotify, decode key %s error %s", keyStr, err)
+			return nil, false
+		}
+	}
+	return tx, true
+}
+
 func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
@@ -741,6 +944,57 @@ func (this *LedgerStoreImp) releaseSavingBlockLock() {
 	}
 }
 
+//pruneBlocks prune the blocks and events older than prune depth. At most PRUNE_BATCH_SIZE blocks are pruned each time,
+//so that an archive store switched to pruning mode catches up smoothly. The pruned height is saved to state store,
+//which is committed last, and pruning is idempotent to redo if the process exits halfway.
+func (this *LedgerStoreImp) pruneBlocks(currHeight uint32) (uint32, error) {
+	if this.pruneDepth == 0 || currHeight <= this.pruneDepth {
+		return this.prunedHeight, nil
+	}
+	target := currHeight - this.pruneDepth
+	height := this.prunedHeight
+	for height < target && height-this.prunedHeight < PRUNE_BATCH_SIZE {
+		height++
+		err := this.pruneBlock(height)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if height != this.prunedHeight {
+		this.stateStore.SavePrunedHeight(height)
+	}
+	return height, nil
+}
+
+//pruneBlock delete the transactions, events and cross states of block. The header, block merkle tree and cross states root
+//are kept for the merkle proofs. Blocks with new vbft chain config keep the transactions, consensus loads them on start.
+func (this *LedgerStoreImp) pruneBlock(height uint32) error {
+	blockHash := this.GetBlockHash(height)
+	header, txHashes, err := this.blockStore.loadHeaderWithTx(blockHash)
+	if err != nil {
+		return fmt.Errorf("loadHeaderWithTx height:%d error %s", height, err)
+	}
+	if blkInfo, err := vconfig.VbftBlock(header); err != nil || blkInfo.NewChainConfig == nil {
+		this.blockStore.PruneBlock(blockHash, txHashes, height)
+	}
+	this.stateStore.PruneCrossStates(height)
+	return this.eventStore.PruneEventNotify(height)
+}
+
+//pruneStateHistory delete the state history older than history depth, at most PRUNE_BATCH_SIZE blocks each time
+func (this *LedgerStoreImp) pruneStateHistory(currHeight uint32) uint32 {
+	if this.historyDepth == 0 {
+		return this.historyHeight
+	}
+	height := this.historyHeight
+	for currHeight > this.historyDepth && height < currHeight-this.historyDepth && height-this.historyHeight < PRUNE_BATCH_SIZE {
+		height++
+		this.stateStore.PruneStateHistory(height)
+	}
+	this.stateStore.SaveHistoryHeight(height)
+	return height
+}
+
 //saveBlock do the job of execution samrt contract and commit block to store.
 func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.ExecuteResult) error {
 	blockHash := block.Hash()
@@ -766,6 +1020,11 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 	if err != nil {
 		return fmt.Errorf("save to event store height:%d error:%s", blockHeight, err)
 	}
+	prunedHeight, err := this.pruneBlocks(blockHeight)
+	if err != nil {
+		return fmt.Errorf("prune blocks height:%d error:%s", blockHeight, err)
+	}
+	historyHeight := this.pruneStateHistory(blockHeight)
 	err = this.blockStore.CommitTo()
 	if err != nil {
 		return fmt.Errorf("blockStore.CommitTo height:%d error %s", blockHeight, err)
@@ -775,11 +1034,16 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 	if err != nil {
 		return fmt.Errorf("eventStore.CommitTo height:%d error %s", blockHeight, err)
 	}
+	this.historyLock.Lock()
 	err = this.stateStore.CommitTo()
 	if err != nil {
+		this.historyLock.Unlock()
 		return fmt.Errorf("stateStore.CommitTo height:%d error %s", blockHeight, err)
 	}
 	this.setCurrentBlock(blockHeight, blockHash)
+	this.historyHeight = historyHeight
+	this.historyLock.Unlock()
+	this.prunedHeight = prunedHeight
 
 	if events.DefActorPublisher != nil {
 		events.DefActorPublisher.Publish(
@@ -815,16 +1079,18 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block, stateMerkleRoot common
 	return this.submitBlock(block, result)
 }
 
-func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, cache *storage.CacheDB, block *types.Block, tx *types.Transaction) (*event.ExecuteNotify, []common.Uint256, error) {
+func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, cache *storage.CacheDB, block *types.Block,
+	tx *types.Transaction, tracer *native.Tracer, replay bool) (*event.ExecuteNotify, []common.Uint256, error) {
 	txHash := tx.Hash()
 	notify := &event.ExecuteNotify{TxHash: txHash, State: event.CONTRACT_STATE_FAIL}
 	if tx.TxType == types.Invoke {
-		crossHashes, err := this.stateStore.HandleInvokeTransaction(this, overlay, cache, tx, block, notify)
+		crossHashes, err := this.stateStore.HandleInvokeTransaction(this, overlay, cache, tx, block, notify, tracer, replay)
 		if overlay.Error() != nil {
 			return nil, nil, fmt.Errorf("HandleInvokeTransaction tx %s error %s", txHash.ToHexString(), overlay.Error())
 		}
 		if err != nil {
 			log.Debugf("HandleInvokeTransaction tx %s error %s", txHash.ToHexString(), err)
+			notify.SetError(err)
 		}
 		return notify, crossHashes, nil
 	} else {
@@ -869,11 +1135,33 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*cstates.
 	if err != nil {
 		return result, fmt.Errorf("get current block error")
 	}
-	overlay := this.stateStore.NewOverlayDB()
-	cache := storage.NewCacheDB(overlay)
+	return this.preExecuteContract(this.stateStore.NewOverlayDB(), tx, uint32(time.Now().Unix()), block.Header)
+}
 
-	service, err := native.NewNativeService(cache, tx, uint32(time.Now().Unix()), block.Header.Height,
-		hash, block.Header.ChainID, tx.Payload.(*payload.InvokeCode).Code, true)
+//PreExecuteContractAtHeight pre execute the transaction on the state of block height, with the block time. The state
+//of the heights older than history depth is not retained
+func (this *LedgerStoreImp) PreExecuteContractAtHeight(tx *types.Transaction, height uint32) (*cstates.PreExecResult, error) {
+	result := &sstate.PreExecResult{State: event.CONTRACT_STATE_FAIL, Result: nil}
+	if _, ok := tx.Payload.(*payload.InvokeCode); !ok {
+		return result, fmt.Errorf("transaction payload type error")
+	}
+	header, err := this.GetHeaderByHeight(height)
+	if err != nil {
+		return result, fmt.Errorf("get header of height %d error %s", height, err)
+	}
+	overlay, snapshot, err := this.getHistoryOverlayDB(height)
+	if err != nil {
+		return result, err
+	}
+	defer snapshot.Close()
+	return this.preExecuteContract(overlay, tx, header.Timestamp, header)
+}
+
+func (this *LedgerStoreImp) preExecuteContract(overlay *overlaydb.OverlayDB, tx *types.Transaction, timestamp uint32, header *types.Header) (*cstates.PreExecResult, error) {
+	result := &sstate.PreExecResult{State: event.CONTRACT_STATE_FAIL, Result: nil}
+	cache := storage.NewCacheDB(overlay)
+	service, err := native.NewNativeService(cache, tx, timestamp, header.Height,
+		header.Hash(), header.ChainID, tx.Payload.(*payload.InvokeCode).Code, true)
 	if err != nil {
 		return result, fmt.Errorf("PreExecuteContract Error: %+v\n", err)
 	}
@@ -884,6 +1172,43 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*cstates.
 	return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Result: common.ToHexString(res.([]byte)), Notify: service.GetNotify()}, nil
 }
 
+//getHistoryOverlayDB return the overlay db of the state of block height, the written states of later blocks are reverted
+//with state history. The overlay db is built on the snapshot of state store taken with current block height under the
+//read lock of historyLock, so blocks are committed while the overlay db is used. The snapshot should be closed after use
+func (this *LedgerStoreImp) getHistoryOverlayDB(height uint32) (*overlaydb.OverlayDB, *leveldbstore.SnapshotStore, error) {
+	this.historyLock.RLock()
+	currHeight, historyHeight := this.GetCurrentBlockHeight(), this.historyHeight
+	if height > currHeight {
+		this.historyLock.RUnlock()
+		return nil, nil, fmt.Errorf("height %d is higher than current block height %d", height, currHeight)
+	}
+	if height < currHeight && (this.historyDepth == 0 || height < historyHeight) {
+		this.historyLock.RUnlock()
+		return nil, nil, scom.ErrPruned
+	}
+	snapshot, err := this.stateStore.NewSnapshot()
+	this.historyLock.RUnlock()
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewSnapshot error %s", err)
+	}
+	overlay := overlaydb.NewOverlayDB(snapshot)
+	for h := currHeight; h > height; h-- {
+		history, err := getStateHistory(snapshot, h)
+		if err != nil {
+			snapshot.Close()
+			return nil, nil, fmt.Errorf("GetStateHistory height:%d error %s", h, err)
+		}
+		history.ForEach(func(key, val []byte) {
+			if len(val) == 0 {
+				overlay.Delete(key)
+			} else {
+				overlay.Put(key, val)
+			}
+		})
+	}
+	return overlay, snapshot, nil
+}
+
 //IsContainBlock return whether the block is in store
 func (this *LedgerStoreImp) IsContainBlock(blockHash common.Uint256) (bool, error) {
 	return this.blockStore.ContainBlock(blockHash)
@@ -968,6 +1293,94 @@ func (this *LedgerStoreImp) GetStorageItem(key *states.StorageKey) (*states.Stor
 	return this.stateStore.GetStorageState(key)
 }
 
+//TraceBlock re-execute the block on the state of previous block and trace the transactions, the state of previous block
+//should be retained in state history
+func (this *LedgerStoreImp) TraceBlock(height uint32) ([]*native.TxTrace, error) {
+	block, err := this.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return this.traceBlock(block, nil)
+}
+
+//TraceTransaction re-execute the transactions of block until the transaction, and trace the transaction
+func (this *LedgerStoreImp) TraceTransaction(txHash common.Uint256) (*native.TxTrace, error) {
+	_, height, err := this.GetTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := this.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := this.traceBlock(block, &txHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) == 0 {
+		return nil, scom.ErrNotFound
+	}
+	return traces[0], nil
+}
+
+//traceBlock re-execute the block like executeBlock on the history overlay db, the transactions are all traced if txHash is nil
+func (this *LedgerStoreImp) traceBlock(block *types.Block, txHash *common.Uint256) ([]*native.TxTrace, error) {
+	height := block.Header.Height
+	if height == 0 {
+		return nil, fmt.Errorf("genesis block could not be traced")
+	}
+	overlay, snapshot, err := this.getHistoryOverlayDB(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Close()
+	traces := make([]*native.TxTrace, 0)
+	for _, tx := range block.Transactions {
+		hash := tx.Hash()
+		var tracer *native.Tracer
+		if txHash == nil || *txHash == hash {
+			tracer = native.NewTracer()
+		}
+		notify, crossHashes, err := this.handleTransaction(overlay, storage.NewCacheDB(overlay), block, tx, tracer, true)
+		if err != nil {
+			return nil, err
+		}
+		if tracer != nil {
+			traces = append(traces, &native.TxTrace{Notify: notify, CrossHashes: crossHashes, Call: tracer.Call()})
+		}
+		if txHash != nil && *txHash == hash {
+			break
+		}
+	}
+	return traces, nil
+}
+
+//GetStorageItemAtHeight return the storage value of the key in smart contract at block height
+func (this *LedgerStoreImp) GetStorageItemAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error) {
+	storeKey, err := this.stateStore.getStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+	overlay, snapshot, err := this.getHistoryOverlayDB(height)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Close()
+	data, err := overlay.Get(storeKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, scom.ErrNotFound
+	}
+	item := new(states.StorageItem)
+	err = item.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
 //GetEventNotifyByTx return the events notify gen by executing of smart contract.  Wrap function of EventStore.GetEventNotifyByTx
 func (this *LedgerStoreImp) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return this.eventStore.GetEventNotifyByTx(tx)
@@ -978,6 +1391,16 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]*event.Execu
 	return this.eventStore.GetEventNotifyByBlock(height)
 }
 
+//GetCrossChainTx return the cross chain transaction by source chain id and source transaction hash. Wrap function of EventStore.GetCrossChainTx
+func (this *LedgerStoreImp) GetCrossChainTx(fromChainID uint64, txHash []byte) (*scom.CrossChainTx, error) {
+	return this.eventStore.GetCrossChainTx(fromChainID, txHash)
+}
+
+//ListCrossChainTxs return the cross chain transactions of source chain in block height order. Wrap function of EventStore.ListCrossChainTxs
+func (this *LedgerStoreImp) ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*scom.CrossChainTx, error) {
+	return this.eventStore.ListCrossChainTxs(fromChainID, offset, limit)
+}
+
 //Close ledger store.
 func (this *LedgerStoreImp) Close() error {
 	err := this.blockStore.Close()
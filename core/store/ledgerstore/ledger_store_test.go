@@ -19,13 +19,26 @@
 package ledgerstore
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/common/metrics"
 	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	nstates "github.com/polynetwork/poly/native/states"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -137,3 +150,336 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestPruneBlocks(t *testing.T) {
+	ledgerStore, err := NewLedgerStore("test/prune")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer ledgerStore.Close()
+	ledgerStore.pruneDepth = 2
+
+	blocks := make([]*types.Block, 0)
+	ledgerStore.blockStore.NewBatch()
+	for height := uint32(0); height <= 5; height++ {
+		mutable := &types.Transaction{
+			TxType:  types.Invoke,
+			Nonce:   height,
+			Payload: &payload.InvokeCode{},
+		}
+		tx, err := types.TransactionFromRawBytes(mutable.ToArray())
+		if err != nil {
+			t.Errorf("TransactionFromRawBytes error %s", err)
+			return
+		}
+		block := &types.Block{
+			Header:       &types.Header{Height: height},
+			Transactions: []*types.Transaction{tx},
+		}
+		err = ledgerStore.blockStore.SaveBlock(block)
+		if err != nil {
+			t.Errorf("SaveBlock error %s", err)
+			return
+		}
+		ledgerStore.headerIndex[height] = block.Hash()
+		blocks = append(blocks, block)
+	}
+	err = ledgerStore.blockStore.CommitTo()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+
+	ledgerStore.blockStore.NewBatch()
+	ledgerStore.stateStore.NewBatch()
+	ledgerStore.eventStore.NewBatch()
+	prunedHeight, err := ledgerStore.pruneBlocks(5)
+	if err != nil {
+		t.Errorf("pruneBlocks error %s", err)
+		return
+	}
+	if prunedHeight != 3 {
+		t.Errorf("TestPruneBlocks failed pruned height %d != 3", prunedHeight)
+		return
+	}
+	if err = ledgerStore.blockStore.CommitTo(); err == nil {
+		err = ledgerStore.stateStore.CommitTo()
+	}
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+	savedHeight, err := ledgerStore.stateStore.GetPrunedHeight()
+	if err != nil || savedHeight != prunedHeight {
+		t.Errorf("TestPruneBlocks failed saved pruned height %d, err %v", savedHeight, err)
+		return
+	}
+
+	for _, block := range blocks {
+		height := block.Header.Height
+		txHash := block.Transactions[0].Hash()
+		_, txHeight, err := ledgerStore.GetTransaction(txHash)
+		exist, _ := ledgerStore.IsContainTransaction(txHash)
+		if !exist || txHeight != height {
+			t.Errorf("TestPruneBlocks failed tx of height %d should be kept on chain, err %v", height, err)
+			return
+		}
+		_, blockErr := ledgerStore.GetBlockByHeight(height)
+		header, _ := ledgerStore.GetHeaderByHeight(height)
+		if header == nil || header.Hash() != block.Hash() {
+			t.Errorf("TestPruneBlocks failed header of height %d should be kept", height)
+			return
+		}
+		if height > 0 && height <= prunedHeight {
+			if err != scom.ErrPruned || blockErr != scom.ErrPruned {
+				t.Errorf("TestPruneBlocks failed block of height %d should be pruned, err %v, %v", height, err, blockErr)
+				return
+			}
+		} else if err != nil || blockErr != nil {
+			t.Errorf("TestPruneBlocks failed block of height %d should be kept, err %v, %v", height, err, blockErr)
+			return
+		}
+	}
+}
+
+func TestStateHistory(t *testing.T) {
+	ledgerStore, err := NewLedgerStore("test/history")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer ledgerStore.Close()
+	ledgerStore.historyDepth = 2
+
+	keyA := &states.StorageKey{ContractAddress: common.Address{1}, Key: []byte("a")}
+	keyB := &states.StorageKey{ContractAddress: common.Address{1}, Key: []byte("b")}
+	//height => written values of a and b, nil means deleted
+	writes := []map[*states.StorageKey][]byte{
+		{keyA: []byte{1}},
+		{keyA: []byte{2}, keyB: []byte{2}},
+		{keyA: nil},
+		{keyB: []byte{4}},
+	}
+	for i, write := range writes {
+		height := uint32(i + 1)
+		overlay := ledgerStore.stateStore.NewOverlayDB()
+		for key, value := range write {
+			storeKey, _ := ledgerStore.stateStore.getStorageKey(key)
+			if value == nil {
+				overlay.Delete(storeKey)
+			} else {
+				overlay.Put(storeKey, (&states.StorageItem{Value: value}).ToArray())
+			}
+		}
+		ledgerStore.stateStore.NewBatch()
+		err = ledgerStore.stateStore.SaveStateHistory(height, overlay.GetWriteSet())
+		if err != nil {
+			t.Errorf("SaveStateHistory error %s", err)
+			return
+		}
+		overlay.CommitTo()
+		historyHeight := ledgerStore.pruneStateHistory(height)
+		err = ledgerStore.stateStore.CommitTo()
+		if err != nil {
+			t.Errorf("CommitTo error %s", err)
+			return
+		}
+		ledgerStore.setCurrentBlock(height, common.Uint256{byte(height)})
+		ledgerStore.historyHeight = historyHeight
+	}
+	if ledgerStore.historyHeight != 2 {
+		t.Errorf("TestStateHistory failed history height %d != 2", ledgerStore.historyHeight)
+		return
+	}
+	if _, err = ledgerStore.stateStore.GetStateHistory(2); err != scom.ErrNotFound {
+		t.Errorf("TestStateHistory failed state history of height 2 should be pruned, err %v", err)
+		return
+	}
+
+	expects := map[uint32][2][]byte{
+		4: {nil, {4}},
+		3: {nil, {2}},
+		2: {{2}, {2}},
+	}
+	for height, expect := range expects {
+		for i, key := range []*states.StorageKey{keyA, keyB} {
+			item, err := ledgerStore.GetStorageItemAtHeight(key, height)
+			if expect[i] == nil {
+				if err != scom.ErrNotFound {
+					t.Errorf("TestStateHistory failed key %s should not exist at height %d, err %v", key.Key, height, err)
+					return
+				}
+			} else if err != nil || !bytes.Equal(item.Value, expect[i]) {
+				t.Errorf("TestStateHistory failed key %s at height %d, item %v, err %v", key.Key, height, item, err)
+				return
+			}
+		}
+	}
+	if _, err = ledgerStore.GetStorageItemAtHeight(keyB, 1); err != scom.ErrPruned {
+		t.Errorf("TestStateHistory failed state of height 1 should be pruned, err %v", err)
+		return
+	}
+	if _, err = ledgerStore.GetStorageItemAtHeight(keyB, 5); err == nil {
+		t.Errorf("TestStateHistory failed state of height 5 should not exist")
+		return
+	}
+	item, err := ledgerStore.GetStorageItem(keyB)
+	if err != nil || !bytes.Equal(item.Value, []byte{4}) {
+		t.Errorf("TestStateHistory failed current state is changed, item %v, err %v", item, err)
+		return
+	}
+
+	//blocks are committed while the history view is used, the view is not changed
+	overlay, snapshot, err := ledgerStore.getHistoryOverlayDB(3)
+	if err != nil {
+		t.Errorf("getHistoryOverlayDB error %s", err)
+		return
+	}
+	defer snapshot.Close()
+	storeKey, _ := ledgerStore.stateStore.getStorageKey(keyB)
+	ledgerStore.historyLock.Lock()
+	ledgerStore.stateStore.NewBatch()
+	ledgerStore.stateStore.store.BatchPut(storeKey, (&states.StorageItem{Value: []byte{5}}).ToArray())
+	err = ledgerStore.stateStore.CommitTo()
+	ledgerStore.historyLock.Unlock()
+	if err != nil {
+		t.Errorf("CommitTo error %s", err)
+		return
+	}
+	data, err := overlay.Get(storeKey)
+	if err != nil {
+		t.Errorf("overlay.Get error %s", err)
+		return
+	}
+	item = new(states.StorageItem)
+	if err = item.Deserialize(bytes.NewReader(data)); err != nil || !bytes.Equal(item.Value, []byte{2}) {
+		t.Errorf("TestStateHistory failed history view is changed by commit, item %v, err %v", item, err)
+	}
+}
+
+func TestTraceBlock(t *testing.T) {
+	ledgerStore, err := NewLedgerStore("test/trace")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer ledgerStore.Close()
+	ledgerStore.historyDepth = 10
+
+	bookkeepers := []keypair.PublicKey{}
+	for i := 0; i < 7; i++ {
+		bookkeepers = append(bookkeepers, account.NewAccount("").PublicKey)
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	if err != nil {
+		t.Errorf("BuildGenesisBlock error %s", err)
+		return
+	}
+	if err = ledgerStore.InitLedgerStoreWithGenesisBlock(genesisBlock, bookkeepers); err != nil {
+		t.Errorf("InitLedgerStoreWithGenesisBlock error %s", err)
+		return
+	}
+
+	//the contract counts a failed header sync like header_sync contract, and writes a storage
+	contract := common.Address{0xee}
+	native.Contracts[contract] = func(ns *native.NativeService) {
+		ns.Register("sync", func(ns *native.NativeService) ([]byte, error) {
+			err := event.NewExecuteError(event.CONTRACT_ERROR_NOT_REGISTERED, "side chain is not registered")
+			hscommon.CountSyncBlockHeader(ns, 99, err)
+			return utils.BYTE_FALSE, err
+		})
+		ns.Register("put", func(ns *native.NativeService) ([]byte, error) {
+			ns.GetCacheDB().Put(utils.ConcatKey(contract, []byte("trace")), states.GenRawStorageItem([]byte{1}))
+			ns.AddNotify(&event.NotifyEventInfo{ContractAddress: contract, States: []interface{}{"put"}})
+			return utils.BYTE_TRUE, nil
+		})
+	}
+	defer delete(native.Contracts, contract)
+	invokes := []*nstates.ContractInvokeParam{
+		{Address: contract, Method: "sync"},
+		{Address: contract, Method: "put"},
+	}
+	txs := make([]*types.Transaction, 0, len(invokes))
+	for i, invoke := range invokes {
+		sink := common.NewZeroCopySink(nil)
+		invoke.Serialization(sink)
+		txs = append(txs, genesis.NewInvokeTransaction(sink.Bytes(), uint32(i+1)))
+	}
+	prevHash := genesisBlock.Hash()
+	block := &types.Block{
+		Header: &types.Header{
+			Version:       types.CURR_HEADER_VERSION,
+			ChainID:       genesisBlock.Header.ChainID,
+			PrevBlockHash: prevHash,
+			Timestamp:     genesisBlock.Header.Timestamp + 1,
+			Height:        1,
+			BlockRoot:     ledgerStore.GetBlockRootWithPreBlockHashes(1, []common.Uint256{prevHash}),
+		},
+		Transactions: txs,
+	}
+	block.RebuildMerkleRoot()
+	result, err := ledgerStore.executeBlock(block)
+	if err != nil {
+		t.Errorf("executeBlock error %s", err)
+		return
+	}
+	if err = ledgerStore.submitBlock(block, result); err != nil {
+		t.Errorf("submitBlock error %s", err)
+		return
+	}
+	syncFailures := func() string {
+		buf := new(bytes.Buffer)
+		metrics.DefaultRegistry.WriteText(buf)
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.HasPrefix(line, `poly_header_sync_block_header_total{chain_id="unknown",result="failure"}`) {
+				return line
+			}
+		}
+		return ""
+	}
+	failures := syncFailures()
+	if failures == "" {
+		t.Errorf("TestTraceBlock failed header sync failure is not counted")
+		return
+	}
+
+	traces, err := ledgerStore.TraceBlock(1)
+	if err != nil {
+		t.Errorf("TraceBlock error %s", err)
+		return
+	}
+	if len(traces) != 2 {
+		t.Errorf("TestTraceBlock failed traces %d != 2", len(traces))
+		return
+	}
+	for i, trace := range traces {
+		if trace.Notify.TxHash != txs[i].Hash() || trace.Call == nil || trace.Call.Method != invokes[i].Method {
+			t.Errorf("TestTraceBlock failed trace of tx %d %+v", i, trace)
+			return
+		}
+	}
+	if traces[0].Notify.State != event.CONTRACT_STATE_FAIL || traces[0].Notify.ErrorCode != event.CONTRACT_ERROR_NOT_REGISTERED ||
+		traces[0].Call.Error == "" {
+		t.Errorf("TestTraceBlock failed trace of failed tx %+v", traces[0].Notify)
+		return
+	}
+	if traces[1].Notify.State != event.CONTRACT_STATE_SUCCESS || len(traces[1].Notify.Notify) != 1 ||
+		len(traces[1].Call.Storage) != 1 || traces[1].Call.Storage[0].Op != native.STORAGE_WRITE {
+		t.Errorf("TestTraceBlock failed trace of succeeded tx %+v", traces[1].Notify)
+		return
+	}
+
+	trace, err := ledgerStore.TraceTransaction(txs[1].Hash())
+	if err != nil {
+		t.Errorf("TraceTransaction error %s", err)
+		return
+	}
+	if trace.Notify.TxHash != txs[1].Hash() || trace.Call.Method != invokes[1].Method {
+		t.Errorf("TestTraceBlock failed trace of transaction %+v", trace.Notify)
+		return
+	}
+	if syncFailures() != failures {
+		t.Errorf("TestTraceBlock failed replay for tracing is counted, %s != %s", syncFailures(), failures)
+	}
+}
@@ -0,0 +1,30 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"github.com/polynetwork/poly/common/metrics"
+)
+
+var (
+	executeBlockDuration = metrics.NewHistogram("poly_ledger_execute_block_seconds",
+		"Time spent on executing the transactions of a block", metrics.DefaultBuckets)
+	submitBlockDuration = metrics.NewHistogram("poly_ledger_submit_block_seconds",
+		"Time spent on saving an executed block", metrics.DefaultBuckets)
+)
@@ -0,0 +1,678 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/serialization"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+)
+
+const (
+	SNAPSHOT_VERSION    = byte(1) //Version of state snapshot
+	SNAPSHOT_BATCH_SIZE = 10000   //Count of storage items committed in one batch when importing state snapshot
+)
+
+//SnapshotMetadata describe the state snapshot of ledger at block height.
+//The snapshot is followed by the blocks loaded on start or used to verify the header of snapshot height, the header
+//index, the bookkeeper state, the block merkle tree with the merkle hash store tail, the state merkle tree, the cross
+//states and all the storage items
+type SnapshotMetadata struct {
+	Version         byte
+	Height          uint32
+	BlockHash       common.Uint256
+	GenesisHash     common.Uint256
+	StateMerkleRoot common.Uint256
+	CrossStateRoot  common.Uint256
+	MerkleTail      uint32         //Lowest block height that merkle proof is available, set after exported or imported
+	StorageCount    uint64         //Count of storage items, set after exported or imported
+	StorageHash     common.Uint256 //Hash of storage items, set after exported or imported
+}
+
+func (this *SnapshotMetadata) Serialize(w io.Writer) error {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteByte(this.Version)
+	sink.WriteUint32(this.Height)
+	sink.WriteHash(this.BlockHash)
+	sink.WriteHash(this.GenesisHash)
+	sink.WriteHash(this.StateMerkleRoot)
+	sink.WriteHash(this.CrossStateRoot)
+	_, err := w.Write(sink.Bytes())
+	return err
+}
+
+func (this *SnapshotMetadata) Deserialize(r io.Reader) error {
+	data := make([]byte, 1+4+4*common.UINT256_SIZE)
+	_, err := io.ReadFull(r, data)
+	if err != nil {
+		return err
+	}
+	if data[0] != SNAPSHOT_VERSION {
+		return fmt.Errorf("version unmatch")
+	}
+	source := common.NewZeroCopySource(data)
+	this.Version, _ = source.NextByte()
+	this.Height, _ = source.NextUint32()
+	this.BlockHash, _ = source.NextHash()
+	this.GenesisHash, _ = source.NextHash()
+	this.StateMerkleRoot, _ = source.NextHash()
+	this.CrossStateRoot, _ = source.NextHash()
+	return nil
+}
+
+//ExportSnapshot write the state snapshot at current block height. The merkle hash store tail makes the merkle proofs
+//of latest merkleTail blocks available in the imported store. Blocks are not saved during exporting
+func (this *LedgerStoreImp) ExportSnapshot(w io.Writer, merkleTail uint32) (*SnapshotMetadata, error) {
+	this.getSavingBlockLock()
+	defer this.releaseSavingBlockLock()
+
+	height, blockHash := this.GetCurrentBlock()
+	if height == 0 {
+		return nil, fmt.Errorf("no block to export")
+	}
+	stateMerkleRoot, err := this.stateStore.GetStateMerkleRoot(height)
+	if err != nil {
+		return nil, fmt.Errorf("GetStateMerkleRoot height:%d error %s", height, err)
+	}
+	crossStateRoot, err := this.stateStore.GetCrossStateRoot(height)
+	if err != nil {
+		return nil, fmt.Errorf("GetCrossStateRoot height:%d error %s", height, err)
+	}
+	metadata := &SnapshotMetadata{
+		Version:         SNAPSHOT_VERSION,
+		Height:          height,
+		BlockHash:       blockHash,
+		GenesisHash:     this.GetBlockHash(0),
+		StateMerkleRoot: stateMerkleRoot,
+		CrossStateRoot:  crossStateRoot,
+	}
+	err = metadata.Serialize(w)
+	if err != nil {
+		return nil, fmt.Errorf("write metadata error %s", err)
+	}
+	err = this.exportSnapshotBlocks(w, height)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i <= height; i++ {
+		blockHash := this.GetBlockHash(i)
+		err = blockHash.Serialize(w)
+		if err != nil {
+			return nil, fmt.Errorf("write header index height:%d error %s", i, err)
+		}
+	}
+	metadata.MerkleTail, err = this.exportSnapshotStates(w, height, merkleTail)
+	if err != nil {
+		return nil, err
+	}
+	metadata.StorageCount, metadata.StorageHash, err = this.exportSnapshotStorage(w)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+//exportSnapshotBlocks write the genesis block, the vbft config block of the bookkeepers of current block, the previous
+//block and the current block. The current block and the last vbft config block are loaded on start
+func (this *LedgerStoreImp) exportSnapshotBlocks(w io.Writer, height uint32) error {
+	prevHeader, err := this.GetHeaderByHeight(height - 1)
+	if err != nil {
+		return fmt.Errorf("GetHeaderByHeight height:%d error %s", height-1, err)
+	}
+	configHeight, err := snapshotConfigHeight(prevHeader)
+	if err != nil {
+		return err
+	}
+	heights := []uint32{0}
+	if configHeight > 0 && configHeight < height-1 {
+		heights = append(heights, configHeight)
+	}
+	if height > 1 {
+		heights = append(heights, height-1)
+	}
+	heights = append(heights, height)
+	err = serialization.WriteVarUint(w, uint64(len(heights)))
+	if err != nil {
+		return fmt.Errorf("write block count error %s", err)
+	}
+	for _, h := range heights {
+		block, err := this.GetBlockByHeight(h)
+		if err == nil && block == nil {
+			err = scom.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("GetBlockByHeight height:%d error %s", h, err)
+		}
+		err = serialization.WriteVarBytes(w, block.ToArray())
+		if err != nil {
+			return fmt.Errorf("write block height:%d error %s", h, err)
+		}
+	}
+	return nil
+}
+
+//exportSnapshotStates write the bookkeeper state, the block merkle tree with merkle hash store tail, the state merkle tree
+//and the cross states of block. Merkle hash store tail starts from the lowest leaf of merkle proof available
+func (this *LedgerStoreImp) exportSnapshotStates(w io.Writer, height, merkleTail uint32) (uint32, error) {
+	bookkeeperKey, err := this.stateStore.getBookkeeperKey()
+	if err != nil {
+		return 0, err
+	}
+	keys := [][]byte{
+		bookkeeperKey,
+		this.stateStore.genBlockMerkleTreeKey(),
+		this.stateStore.genStateMerkleTreeKey(),
+		this.stateStore.genStateMerkleRootKey(height),
+	}
+	for _, key := range keys {
+		value, err := this.stateStore.store.Get(key)
+		if err != nil {
+			return 0, fmt.Errorf("get state key:%x error %s", key, err)
+		}
+		err = serialization.WriteVarBytes(w, value)
+		if err != nil {
+			return 0, fmt.Errorf("write state key:%x error %s", key, err)
+		}
+	}
+	crossStates, err := this.stateStore.store.Get(genCrossStatesKey(height))
+	if err != nil && err != scom.ErrNotFound {
+		return 0, fmt.Errorf("get cross states height:%d error %s", height, err)
+	}
+	err = serialization.WriteVarBytes(w, crossStates)
+	if err != nil {
+		return 0, fmt.Errorf("write cross states error %s", err)
+	}
+
+	treeSize := this.stateStore.merkleTree.TreeSize()
+	start := this.stateStore.merkleTail
+	if merkleTail < treeSize && treeSize-merkleTail > start {
+		start = treeSize - merkleTail
+	}
+	tail, err := this.stateStore.merkleTree.HashStoreTail(start)
+	if err != nil {
+		return 0, fmt.Errorf("HashStoreTail from %d error %s", start, err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	tail.Serialization(sink)
+	err = serialization.WriteVarBytes(w, sink.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("write merkle hash store tail error %s", err)
+	}
+	return merkleTailHeight(start), nil
+}
+
+//exportSnapshotStorage write all the storage items ended with an empty key, followed by the count and hash of them
+func (this *LedgerStoreImp) exportSnapshotStorage(w io.Writer) (uint64, common.Uint256, error) {
+	digest := sha256.New()
+	writer := io.MultiWriter(w, digest)
+	count := uint64(0)
+	iter := this.stateStore.store.NewIterator([]byte{byte(scom.ST_STORAGE)})
+	defer iter.Release()
+	for iter.Next() {
+		err := serialization.WriteVarBytes(writer, iter.Key()[1:])
+		if err != nil {
+			return 0, common.UINT256_EMPTY, fmt.Errorf("write storage key error %s", err)
+		}
+		err = serialization.WriteVarBytes(writer, iter.Value())
+		if err != nil {
+			return 0, common.UINT256_EMPTY, fmt.Errorf("write storage value error %s", err)
+		}
+		count++
+	}
+	if err := iter.Error(); err != nil {
+		return 0, common.UINT256_EMPTY, fmt.Errorf("iterate storage error %s", err)
+	}
+	err := serialization.WriteVarBytes(writer, nil)
+	if err != nil {
+		return 0, common.UINT256_EMPTY, err
+	}
+	err = serialization.WriteUint64(w, count)
+	if err != nil {
+		return 0, common.UINT256_EMPTY, err
+	}
+	var hash common.Uint256
+	copy(hash[:], digest.Sum(nil))
+	_, err = w.Write(hash[:])
+	return count, hash, err
+}
+
+//ImportSnapshot initialize the empty ledger store with the state snapshot, the store should be reopened after importing.
+//The block at snapshot height should match the trusted block hash, and its header is checked with the bookkeeper
+//signatures of vbft peers. The header index, the block merkle tree and the cross states are checked with the block root
+//and cross state root of the header. The state merkle root and the storage items are not committed by any header, so the
+//hash of storage items should match the trusted storage hash, e.g. the one printed by the trusted node exporting the
+//snapshot. Without the trusted storage hash, the storage items are unverified and the count and hash in the snapshot
+//only detect a corrupted file. Block store version is saved last, so that an interrupted import is cleared when
+//importing again
+func (this *LedgerStoreImp) ImportSnapshot(r io.Reader, genesisBlock *types.Block, trustedBlockHash,
+	trustedStorageHash common.Uint256) (*SnapshotMetadata, error) {
+	if trustedBlockHash == common.UINT256_EMPTY {
+		return nil, fmt.Errorf("trusted block hash is required")
+	}
+	hasInit, err := this.hasAlreadyInitGenesisBlock()
+	if err != nil {
+		return nil, fmt.Errorf("hasAlreadyInit error %s", err)
+	}
+	if hasInit {
+		return nil, fmt.Errorf("ledger store is already initialized")
+	}
+	err = this.blockStore.ClearAll()
+	if err != nil {
+		return nil, fmt.Errorf("blockStore.ClearAll error %s", err)
+	}
+	err = this.stateStore.ClearAll()
+	if err != nil {
+		return nil, fmt.Errorf("stateStore.ClearAll error %s", err)
+	}
+	err = this.eventStore.ClearAll()
+	if err != nil {
+		return nil, fmt.Errorf("eventStore.ClearAll error %s", err)
+	}
+
+	metadata := new(SnapshotMetadata)
+	err = metadata.Deserialize(r)
+	if err != nil {
+		return nil, fmt.Errorf("read metadata error %s", err)
+	}
+	if metadata.GenesisHash != genesisBlock.Hash() {
+		return nil, fmt.Errorf("genesis block hash %s mismatch", metadata.GenesisHash.ToHexString())
+	}
+	if metadata.Height == 0 {
+		return nil, fmt.Errorf("no block in snapshot")
+	}
+	if metadata.BlockHash != trustedBlockHash {
+		return nil, fmt.Errorf("block height:%d hash %s mismatch trusted block hash %s", metadata.Height,
+			metadata.BlockHash.ToHexString(), trustedBlockHash.ToHexString())
+	}
+	blocks, err := this.importSnapshotBlocks(r, metadata)
+	if err != nil {
+		return nil, err
+	}
+	err = verifySnapshotHeader(blocks, metadata.Height)
+	if err != nil {
+		return nil, err
+	}
+	err = this.importSnapshotHeaderIndex(r, metadata.Height, blocks)
+	if err != nil {
+		return nil, err
+	}
+	header := blocks[metadata.Height].Header
+	snapshot, err := this.importSnapshotStates(r, metadata, header)
+	if err != nil {
+		return nil, err
+	}
+	metadata.MerkleTail = merkleTailHeight(snapshot.tail.Start)
+	metadata.StorageCount, metadata.StorageHash, err = this.importSnapshotStorage(r)
+	if err != nil {
+		return nil, err
+	}
+	if trustedStorageHash != common.UINT256_EMPTY && metadata.StorageHash != trustedStorageHash {
+		return nil, fmt.Errorf("storage hash %s mismatch trusted storage hash %s", metadata.StorageHash.ToHexString(),
+			trustedStorageHash.ToHexString())
+	}
+	err = this.saveSnapshotStates(metadata.Height, metadata.BlockHash, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	this.blockStore.NewBatch()
+	for _, block := range blocks {
+		err = this.blockStore.SaveBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("SaveBlock height:%d error %s", block.Header.Height, err)
+		}
+	}
+	err = this.blockStore.SaveCurrentBlock(metadata.Height, metadata.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("blockStore.SaveCurrentBlock error %s", err)
+	}
+	err = this.blockStore.CommitTo()
+	if err != nil {
+		return nil, fmt.Errorf("blockStore.CommitTo error %s", err)
+	}
+	this.eventStore.NewBatch()
+	err = this.eventStore.SaveCurrentBlock(metadata.Height, metadata.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("eventStore.SaveCurrentBlock error %s", err)
+	}
+	err = this.eventStore.CommitTo()
+	if err != nil {
+		return nil, fmt.Errorf("eventStore.CommitTo error %s", err)
+	}
+	if this.stateStore.merkleHashStore != nil {
+		this.stateStore.merkleHashStore.Close()
+	}
+	err = merkle.WriteHashStoreTail(this.stateStore.merklePath, snapshot.tail)
+	if err != nil {
+		return nil, fmt.Errorf("WriteHashStoreTail error %s", err)
+	}
+	err = this.stateStore.CommitTo()
+	if err != nil {
+		return nil, fmt.Errorf("stateStore.CommitTo error %s", err)
+	}
+	err = this.initGenesisBlock()
+	if err != nil {
+		return nil, fmt.Errorf("init error %s", err)
+	}
+	return metadata, nil
+}
+
+//importSnapshotBlocks read the blocks of snapshot and check the hash of current block and the transactions root
+func (this *LedgerStoreImp) importSnapshotBlocks(r io.Reader, metadata *SnapshotMetadata) (map[uint32]*types.Block, error) {
+	count, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return nil, fmt.Errorf("read block count error %s", err)
+	}
+	blocks := make(map[uint32]*types.Block)
+	for i := uint64(0); i < count; i++ {
+		raw, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return nil, fmt.Errorf("read block error %s", err)
+		}
+		block, err := types.BlockFromRawBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("block deserialize error %s", err)
+		}
+		height := block.Header.Height
+		if height > 0 {
+			txHashes := make([]common.Uint256, 0, len(block.Transactions))
+			for _, tx := range block.Transactions {
+				txHashes = append(txHashes, tx.Hash())
+			}
+			if common.ComputeMerkleRoot(txHashes) != block.Header.TransactionsRoot {
+				return nil, fmt.Errorf("transactions root of block height:%d mismatch", height)
+			}
+		}
+		blocks[height] = block
+	}
+	block, ok := blocks[metadata.Height]
+	if !ok || block.Hash() != metadata.BlockHash {
+		return nil, fmt.Errorf("block height:%d hash %s mismatch", metadata.Height, metadata.BlockHash.ToHexString())
+	}
+	if _, ok = blocks[0]; !ok {
+		return nil, fmt.Errorf("genesis block not found")
+	}
+	if prevBlock, ok := blocks[metadata.Height-1]; !ok || prevBlock.Hash() != block.Header.PrevBlockHash {
+		return nil, fmt.Errorf("block height:%d mismatch previous block hash", metadata.Height-1)
+	}
+	return blocks, nil
+}
+
+//verifySnapshotHeader verify the bookkeeper signatures of the header at snapshot height with the peers of the last vbft
+//config block before it. The config block is checked with the header index later
+func verifySnapshotHeader(blocks map[uint32]*types.Block, height uint32) error {
+	if strings.ToLower(config.DefConfig.Genesis.ConsensusType) != "vbft" {
+		return fmt.Errorf("snapshot does not support %s consensus", config.DefConfig.Genesis.ConsensusType)
+	}
+	configHeight, err := snapshotConfigHeight(blocks[height-1].Header)
+	if err != nil {
+		return err
+	}
+	configBlock, ok := blocks[configHeight]
+	if !ok {
+		return fmt.Errorf("vbft config block height:%d not found", configHeight)
+	}
+	blkInfo, err := vconfig.VbftBlock(configBlock.Header)
+	if err != nil {
+		return fmt.Errorf("VbftBlock height:%d error %s", configHeight, err)
+	}
+	if blkInfo.NewChainConfig == nil {
+		return fmt.Errorf("getNewChainConfig error block num:%d", configHeight)
+	}
+	vbftPeerInfo := make(map[string]uint32)
+	for _, p := range blkInfo.NewChainConfig.Peers {
+		vbftPeerInfo[p.ID] = p.Index
+	}
+	err = verifyVbftBookkeepers(blocks[height].Header, vbftPeerInfo, height)
+	if err != nil {
+		return fmt.Errorf("verify bookkeepers of block height:%d error %s", height, err)
+	}
+	return nil
+}
+
+//snapshotConfigHeight return the height of vbft config block, whose peers sign the block next to prevHeader
+func snapshotConfigHeight(prevHeader *types.Header) (uint32, error) {
+	blkInfo, err := vconfig.VbftBlock(prevHeader)
+	if err != nil {
+		return 0, fmt.Errorf("VbftBlock height:%d error %s", prevHeader.Height, err)
+	}
+	if blkInfo.NewChainConfig != nil {
+		return prevHeader.Height, nil
+	}
+	return blkInfo.LastConfigBlockNum, nil
+}
+
+//importSnapshotHeaderIndex save the header index in batches, which should be consistent with the blocks of snapshot
+//and the block root of the header at snapshot height, the leaves of block merkle tree are the previous block hashes
+func (this *LedgerStoreImp) importSnapshotHeaderIndex(r io.Reader, height uint32, blocks map[uint32]*types.Block) error {
+	header := blocks[height].Header
+	blockTree := merkle.NewTree(0, nil, nil)
+	blockTree.Append(blocks[0].Header.PrevBlockHash.ToArray())
+	indexList := make([]common.Uint256, 0, HEADER_INDEX_BATCH_SIZE)
+	for start := uint32(0); start <= height; start += HEADER_INDEX_BATCH_SIZE {
+		this.blockStore.NewBatch()
+		indexList = indexList[:0]
+		for i := start; i <= height && i-start < HEADER_INDEX_BATCH_SIZE; i++ {
+			blockHash, err := serialization.ReadHash(r)
+			if err != nil {
+				return fmt.Errorf("read header index height:%d error %s", i, err)
+			}
+			if block, ok := blocks[i]; ok && block.Hash() != blockHash {
+				return fmt.Errorf("header index height:%d mismatch block hash", i)
+			}
+			if i < height {
+				blockTree.Append(blockHash.ToArray())
+			}
+			this.blockStore.SaveBlockHash(i, blockHash)
+			indexList = append(indexList, blockHash)
+		}
+		if uint32(len(indexList)) == HEADER_INDEX_BATCH_SIZE {
+			err := this.blockStore.SaveHeaderIndexList(start, indexList)
+			if err != nil {
+				return fmt.Errorf("SaveHeaderIndexList start %d error %s", start, err)
+			}
+		}
+		err := this.blockStore.CommitTo()
+		if err != nil {
+			return fmt.Errorf("blockStore.CommitTo error %s", err)
+		}
+	}
+	if blockTree.Root() != header.BlockRoot {
+		return fmt.Errorf("header index mismatch block root of header")
+	}
+	return nil
+}
+
+//snapshotStates is the verified states of snapshot, which are saved after the storage items
+type snapshotStates struct {
+	bookkeeper     []byte
+	blockTree      []byte
+	stateTree      []byte
+	stateRoot      []byte
+	crossStates    []common.Uint256
+	crossStateRoot common.Uint256
+	tail           *merkle.HashStoreTail
+}
+
+//importSnapshotStates verify the block merkle tree with the block root of header, the state merkle tree with the state
+//merkle root and the cross states with the cross state root of header
+func (this *LedgerStoreImp) importSnapshotStates(r io.Reader, metadata *SnapshotMetadata, header *types.Header) (*snapshotStates, error) {
+	height := metadata.Height
+	values := make([][]byte, 0, 6)
+	for i := 0; i < 6; i++ {
+		value, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return nil, fmt.Errorf("read states error %s", err)
+		}
+		values = append(values, value)
+	}
+	bookkeeper, blockTree, stateTree, stateRoot, crossStates, tailData := values[0], values[1], values[2], values[3], values[4], values[5]
+
+	err := new(states.BookkeeperState).Deserialize(bytes.NewReader(bookkeeper))
+	if err != nil {
+		return nil, fmt.Errorf("bookkeeper state deserialize error %s", err)
+	}
+
+	treeSize, hashes, err := parseMerkleTree(blockTree)
+	if err != nil {
+		return nil, fmt.Errorf("block merkle tree deserialize error %s", err)
+	}
+	if treeSize != height+1 || merkle.NewTree(treeSize, hashes, nil).Root() != header.BlockRoot {
+		return nil, fmt.Errorf("block merkle tree mismatch block root of header")
+	}
+	tail := new(merkle.HashStoreTail)
+	err = tail.Deserialization(common.NewZeroCopySource(tailData))
+	if err != nil {
+		return nil, fmt.Errorf("merkle hash store tail deserialize error %s", err)
+	}
+	err = tail.Verify(treeSize, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("merkle hash store tail mismatch block merkle tree: %s", err)
+	}
+
+	treeSize, hashes, err = parseMerkleTree(stateTree)
+	if err != nil {
+		return nil, fmt.Errorf("state merkle tree deserialize error %s", err)
+	}
+	source := common.NewZeroCopySource(stateRoot)
+	source.NextHash()
+	root, eof := source.NextHash()
+	if eof || root != metadata.StateMerkleRoot {
+		return nil, fmt.Errorf("state merkle root mismatch")
+	}
+	if treeSize != height-this.stateStore.stateHashCheckHeight+1 || merkle.NewTree(treeSize, hashes, nil).Root() != root {
+		return nil, fmt.Errorf("state merkle tree mismatch state merkle root")
+	}
+
+	if len(crossStates)%common.UINT256_SIZE != 0 {
+		return nil, fmt.Errorf("cross states deserialize error")
+	}
+	source = common.NewZeroCopySource(crossStates)
+	crossHashes := make([]common.Uint256, 0, len(crossStates)/common.UINT256_SIZE)
+	for source.Len() > 0 {
+		hash, _ := source.NextHash()
+		crossHashes = append(crossHashes, hash)
+	}
+	crossStateRoot := common.UINT256_EMPTY
+	if len(crossHashes) != 0 {
+		crossStateRoot = merkle.TreeHasher{}.HashFullTreeWithLeafHash(crossHashes)
+	}
+	if crossStateRoot != header.CrossStateRoot || crossStateRoot != metadata.CrossStateRoot {
+		return nil, fmt.Errorf("cross states mismatch cross state root of header")
+	}
+
+	return &snapshotStates{
+		bookkeeper:     bookkeeper,
+		blockTree:      blockTree,
+		stateTree:      stateTree,
+		stateRoot:      stateRoot,
+		crossStates:    crossHashes,
+		crossStateRoot: crossStateRoot,
+		tail:           tail,
+	}, nil
+}
+
+//saveSnapshotStates put the states of snapshot to the batch of state store, the blocks before snapshot height are
+//marked as pruned
+func (this *LedgerStoreImp) saveSnapshotStates(height uint32, blockHash common.Uint256, snapshot *snapshotStates) error {
+	bookkeeperKey, err := this.stateStore.getBookkeeperKey()
+	if err != nil {
+		return err
+	}
+	this.stateStore.BatchPutRawKeyVal(bookkeeperKey, snapshot.bookkeeper)
+	this.stateStore.BatchPutRawKeyVal(this.stateStore.genBlockMerkleTreeKey(), snapshot.blockTree)
+	this.stateStore.BatchPutRawKeyVal(this.stateStore.genStateMerkleTreeKey(), snapshot.stateTree)
+	this.stateStore.BatchPutRawKeyVal(this.stateStore.genStateMerkleRootKey(height), snapshot.stateRoot)
+	err = this.stateStore.AddCrossStates(height, snapshot.crossStates, snapshot.crossStateRoot)
+	if err != nil {
+		return fmt.Errorf("AddCrossStates error %s", err)
+	}
+	err = this.stateStore.SaveCurrentBlock(height, blockHash)
+	if err != nil {
+		return fmt.Errorf("stateStore.SaveCurrentBlock error %s", err)
+	}
+	this.stateStore.SavePrunedHeight(height - 1)
+	this.stateStore.SaveBlockMerkleTail(snapshot.tail.Start)
+	return nil
+}
+
+//merkleTailHeight return the lowest block height that merkle proof is available, the leaf of block merkle tree is
+//the hash of previous block
+func merkleTailHeight(start uint32) uint32 {
+	if start == 0 {
+		return 0
+	}
+	return start - 1
+}
+
+//importSnapshotStorage save the storage items in batches and check the count and hash of them. The count and hash carried
+//in the snapshot only detect a corrupted snapshot file, the hash returned should be compared with a trusted one
+func (this *LedgerStoreImp) importSnapshotStorage(r io.Reader) (uint64, common.Uint256, error) {
+	this.stateStore.NewBatch()
+	digest := sha256.New()
+	reader := io.TeeReader(r, digest)
+	count := uint64(0)
+	for {
+		key, err := serialization.ReadVarBytes(reader)
+		if err != nil {
+			return 0, common.UINT256_EMPTY, fmt.Errorf("read storage key error %s", err)
+		}
+		if len(key) == 0 {
+			break
+		}
+		value, err := serialization.ReadVarBytes(reader)
+		if err != nil {
+			return 0, common.UINT256_EMPTY, fmt.Errorf("read storage value error %s", err)
+		}
+		this.stateStore.BatchPutRawKeyVal(append([]byte{byte(scom.ST_STORAGE)}, key...), value)
+		count++
+		if count%SNAPSHOT_BATCH_SIZE == 0 {
+			err = this.stateStore.CommitTo()
+			if err != nil {
+				return 0, common.UINT256_EMPTY, fmt.Errorf("stateStore.CommitTo error %s", err)
+			}
+			this.stateStore.NewBatch()
+		}
+	}
+	storageCount, err := serialization.ReadUint64(r)
+	if err != nil {
+		return 0, common.UINT256_EMPTY, fmt.Errorf("read storage count error %s", err)
+	}
+	storageHash, err := serialization.ReadBytes(r, sha256.Size)
+	if err != nil {
+		return 0, common.UINT256_EMPTY, fmt.Errorf("read storage hash error %s", err)
+	}
+	var hash common.Uint256
+	copy(hash[:], digest.Sum(nil))
+	if storageCount != count || !bytes.Equal(storageHash, hash[:]) {
+		return 0, common.UINT256_EMPTY, fmt.Errorf("storage items mismatch count and hash")
+	}
+	return count, hash, nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/signature"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func addSnapshotTestBlock(t *testing.T, ledgerStore *LedgerStoreImp, signers []*account.Account) {
+	height, prevHash := ledgerStore.GetCurrentBlock()
+	payload, _ := json.Marshal(&vconfig.VbftBlockInfo{})
+	block := &types.Block{
+		Header: &types.Header{
+			PrevBlockHash:    prevHash,
+			BlockRoot:        ledgerStore.GetBlockRootWithPreBlockHashes(height+1, []common.Uint256{prevHash}),
+			Timestamp:        height + 1,
+			Height:           height + 1,
+			ConsensusPayload: payload,
+		},
+	}
+	hash := block.Hash()
+	for _, signer := range signers {
+		sig, err := signature.Sign(signer, hash[:])
+		assert.Nil(t, err)
+		block.Header.Bookkeepers = append(block.Header.Bookkeepers, signer.PublicKey)
+		block.Header.SigData = append(block.Header.SigData, sig)
+	}
+	result, err := ledgerStore.executeBlock(block)
+	assert.Nil(t, err)
+	assert.Nil(t, ledgerStore.submitBlock(block, result))
+}
+
+func TestSnapshot(t *testing.T) {
+	genesisConfig := config.DefConfig.Genesis
+	defer func() {
+		config.DefConfig.Genesis = genesisConfig
+	}()
+	config.DefConfig.Genesis = &config.GenesisConfig{
+		ConsensusType: config.CONSENSUS_TYPE_VBFT,
+		VBFT: &config.VBFTConfig{
+			MaxBlockChangeView: 60000,
+			VrfValue:           config.MainNetConfig.VBFT.VrfValue,
+			VrfProof:           config.MainNetConfig.VBFT.VrfProof,
+		},
+	}
+	accounts := make([]*account.Account, 0, 7)
+	bookkeepers := make([]keypair.PublicKey, 0, 7)
+	for i := 0; i < 7; i++ {
+		acc := account.NewAccount("")
+		accounts = append(accounts, acc)
+		bookkeepers = append(bookkeepers, acc.PublicKey)
+		config.DefConfig.Genesis.VBFT.Peers = append(config.DefConfig.Genesis.VBFT.Peers, &config.VBFTPeerInfo{
+			Index:      uint32(i + 1),
+			PeerPubkey: vconfig.PubkeyID(acc.PublicKey),
+			Address:    acc.Address.ToBase58(),
+		})
+	}
+	signers := accounts[:5]
+	genesisBlock, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	assert.Nil(t, err)
+
+	source, err := NewLedgerStore("test/snapshot_source")
+	assert.Nil(t, err)
+	defer source.Close()
+	assert.Nil(t, source.InitLedgerStoreWithGenesisBlock(genesisBlock, bookkeepers))
+	for i := 0; i < 5; i++ {
+		addSnapshotTestBlock(t, source, signers)
+	}
+	for i := byte(0); i < 3; i++ {
+		storageKey := append([]byte{byte(scom.ST_STORAGE), i}, common.ADDRESS_EMPTY[:]...)
+		assert.Nil(t, source.stateStore.store.Put(storageKey, []byte{i}))
+	}
+	snapshot := bytes.NewBuffer(nil)
+	metadata, err := source.ExportSnapshot(snapshot, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(5), metadata.Height)
+	assert.Equal(t, uint32(3), metadata.MerkleTail)
+	assert.NotZero(t, metadata.StorageCount)
+	assert.NotEqual(t, common.UINT256_EMPTY, metadata.StorageHash)
+
+	target, err := NewLedgerStore("test/snapshot_target")
+	assert.Nil(t, err)
+	//missing or mismatched trusted block hash fails the import
+	_, err = target.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), genesisBlock, common.UINT256_EMPTY, common.UINT256_EMPTY)
+	assert.NotNil(t, err)
+	_, err = target.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), genesisBlock, genesisBlock.Hash(), metadata.StorageHash)
+	assert.NotNil(t, err)
+
+	//tampered bookkeeper signature of the header fails the import
+	header, err := source.GetHeaderByHeight(metadata.Height)
+	assert.Nil(t, err)
+	corrupted := append([]byte{}, snapshot.Bytes()...)
+	index := bytes.LastIndex(corrupted, header.SigData[0])
+	assert.True(t, index > 0)
+	corrupted[index+len(header.SigData[0])-1] ^= 1
+	_, err = target.ImportSnapshot(bytes.NewReader(corrupted), genesisBlock, metadata.BlockHash, metadata.StorageHash)
+	assert.NotNil(t, err)
+
+	//tampered storage item fails the import
+	item := common.NewZeroCopySink(nil)
+	item.WriteVarBytes(append([]byte{2}, common.ADDRESS_EMPTY[:]...))
+	item.WriteVarBytes([]byte{2})
+	corrupted = append([]byte{}, snapshot.Bytes()...)
+	index = bytes.LastIndex(corrupted, item.Bytes())
+	assert.True(t, index > 0)
+	corrupted[index+len(item.Bytes())-1] ^= 1
+	_, err = target.ImportSnapshot(bytes.NewReader(corrupted), genesisBlock, metadata.BlockHash, metadata.StorageHash)
+	assert.NotNil(t, err)
+
+	//mismatched trusted storage hash fails the import
+	_, err = target.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), genesisBlock, metadata.BlockHash, metadata.BlockHash)
+	assert.NotNil(t, err)
+
+	imported, err := target.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), genesisBlock, metadata.BlockHash, metadata.StorageHash)
+	assert.Nil(t, err)
+	assert.Equal(t, metadata, imported)
+	_, err = target.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), genesisBlock, metadata.BlockHash, metadata.StorageHash)
+	assert.NotNil(t, err)
+	assert.Nil(t, target.Close())
+
+	target, err = NewLedgerStore("test/snapshot_target")
+	assert.Nil(t, err)
+	defer target.Close()
+	assert.Nil(t, target.InitLedgerStoreWithGenesisBlock(genesisBlock, bookkeepers))
+	assert.Equal(t, source.GetCurrentBlockHash(), target.GetCurrentBlockHash())
+	stateMerkleRoot, err := target.GetStateMerkleRoot(metadata.Height)
+	assert.Nil(t, err)
+	assert.Equal(t, metadata.StateMerkleRoot, stateMerkleRoot)
+	_, err = target.GetHeaderByHeight(1)
+	assert.Equal(t, scom.ErrNotFound, err)
+
+	for i := 0; i < 3; i++ {
+		addSnapshotTestBlock(t, source, signers)
+		addSnapshotTestBlock(t, target, signers)
+	}
+	height := source.GetCurrentBlockHeight()
+	assert.Equal(t, source.GetCurrentBlockHash(), target.GetCurrentBlockHash())
+	for _, h := range []uint32{metadata.Height, height} {
+		expected, _ := source.GetStateMerkleRoot(h)
+		root, _ := target.GetStateMerkleRoot(h)
+		assert.Equal(t, expected, root)
+	}
+	for h := uint32(0); h < height; h++ {
+		blockHash := source.GetBlockHash(h)
+		raw := blockHash.ToArray()
+		expected, err := source.GetMerkleProof(raw, h+1, height)
+		assert.Nil(t, err)
+		proof, err := target.GetMerkleProof(raw, h+1, height)
+		if h < metadata.MerkleTail {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, expected, proof)
+		}
+	}
+
+	iter := source.stateStore.store.NewIterator([]byte{byte(scom.ST_STORAGE)})
+	defer iter.Release()
+	for iter.Next() {
+		value, err := target.stateStore.store.Get(iter.Key())
+		assert.Nil(t, err)
+		assert.Equal(t, iter.Value(), value)
+	}
+}
@@ -46,6 +46,7 @@ type StateStore struct {
 	merkleTree           *merkle.CompactMerkleTree //Merkle tree of block root
 	deltaMerkleTree      *merkle.CompactMerkleTree //Merkle tree of delta state root
 	merkleHashStore      merkle.HashStore
+	merkleTail           uint32 //Lowest leaf of block merkle tree that inclusion proof is available, non zero when imported from snapshot
 	stateHashCheckHeight uint32
 }
 
@@ -111,6 +112,10 @@ func (self *StateStore) init(currBlockHeight uint32) error {
 		log.Warn("merkle store is inconsistent with ChainStore. persistence will be disabled")
 	}
 	self.merkleTree = merkle.NewTree(treeSize, hashes, self.merkleHashStore)
+	self.merkleTail, err = self.GetBlockMerkleTail()
+	if err != nil && err != scom.ErrNotFound {
+		return err
+	}
 
 	if currBlockHeight >= self.stateHashCheckHeight {
 		treeSize, hashes, err := self.GetStateMerkleTree()
@@ -141,6 +146,10 @@ func (self *StateStore) getMerkleTree(key []byte) (uint32, []common.Uint256, err
 	if err != nil {
 		return 0, nil, err
 	}
+	return parseMerkleTree(data)
+}
+
+func parseMerkleTree(data []byte) (uint32, []common.Uint256, error) {
 	value := bytes.NewBuffer(data)
 	treeSize, err := serialization.ReadUint32(value)
 	if err != nil {
@@ -269,6 +278,146 @@ func (self *StateStore) GetCrossStates(height uint32) (hashes []common.Uint256,
 	return
 }
 
+//PruneCrossStates delete the cross states of block, the cross states root is kept
+func (self *StateStore) PruneCrossStates(height uint32) {
+	self.store.BatchDelete(genCrossStatesKey(height))
+}
+
+//GetPrunedHeight return the height that the blocks before are pruned
+func (self *StateStore) GetPrunedHeight() (uint32, error) {
+	data, err := self.store.Get(self.getPrunedHeightKey())
+	if err != nil {
+		return 0, err
+	}
+	reader := bytes.NewReader(data)
+	return serialization.ReadUint32(reader)
+}
+
+//SavePrunedHeight persist pruned height to state store
+func (self *StateStore) SavePrunedHeight(height uint32) {
+	value := bytes.NewBuffer(nil)
+	serialization.WriteUint32(value, height)
+	self.store.BatchPut(self.getPrunedHeightKey(), value.Bytes())
+}
+
+//SaveStateHistory persist the previous values of the states written in block, empty value means the state did not exist.
+//The history is not overwritten when the block is executed again in recovering process
+func (self *StateStore) SaveStateHistory(height uint32, writeSet *overlaydb.MemDB) error {
+	key := genStateHistoryKey(height)
+	has, err := self.store.Has(key)
+	if err != nil {
+		return err
+	}
+	if has {
+		return nil
+	}
+	var keys, values [][]byte
+	writeSet.ForEach(func(k, _ []byte) {
+		if err != nil {
+			return
+		}
+		var value []byte
+		value, err = self.store.Get(k)
+		if err == scom.ErrNotFound {
+			value, err = nil, nil
+		}
+		keys = append(keys, k)
+		values = append(values, value)
+	})
+	if err != nil {
+		return err
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarUint(uint64(len(keys)))
+	for i := range keys {
+		sink.WriteVarBytes(keys[i])
+		sink.WriteVarBytes(values[i])
+	}
+	self.store.BatchPut(key, sink.Bytes())
+	return nil
+}
+
+//GetStateHistory return the previous values of the states written in block, empty value means the state did not exist
+func (self *StateStore) GetStateHistory(height uint32) (*overlaydb.MemDB, error) {
+	return getStateHistory(self.store, height)
+}
+
+//NewSnapshot return the read only snapshot of state store, it should be closed after use
+func (self *StateStore) NewSnapshot() (*leveldbstore.SnapshotStore, error) {
+	store, ok := self.store.(*leveldbstore.LevelDBStore)
+	if !ok {
+		return nil, fmt.Errorf("state store does not support snapshot")
+	}
+	return store.NewSnapshot()
+}
+
+func getStateHistory(store scom.PersistStore, height uint32) (*overlaydb.MemDB, error) {
+	data, err := store.Get(genStateHistoryKey(height))
+	if err != nil {
+		return nil, err
+	}
+	source := common.NewZeroCopySource(data)
+	n, eof := source.NextVarUint()
+	if eof {
+		return nil, io.ErrUnexpectedEOF
+	}
+	history := overlaydb.NewMemDB(0, 0)
+	for i := uint64(0); i < n; i++ {
+		key, eof := source.NextVarBytes()
+		if eof {
+			return nil, io.ErrUnexpectedEOF
+		}
+		value, eof := source.NextVarBytes()
+		if eof {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if len(value) == 0 {
+			history.Delete(key)
+		} else {
+			history.Put(key, value)
+		}
+	}
+	return history, nil
+}
+
+//PruneStateHistory delete the state history of block
+func (self *StateStore) PruneStateHistory(height uint32) {
+	self.store.BatchDelete(genStateHistoryKey(height))
+}
+
+//GetHistoryHeight return the lowest height that the state could be served from state history
+func (self *StateStore) GetHistoryHeight() (uint32, error) {
+	data, err := self.store.Get(self.getHistoryHeightKey())
+	if err != nil {
+		return 0, err
+	}
+	reader := bytes.NewReader(data)
+	return serialization.ReadUint32(reader)
+}
+
+//SaveHistoryHeight persist the lowest height of state history to state store
+func (self *StateStore) SaveHistoryHeight(height uint32) {
+	value := bytes.NewBuffer(nil)
+	serialization.WriteUint32(value, height)
+	self.store.BatchPut(self.getHistoryHeightKey(), value.Bytes())
+}
+
+//ClearStateHistory delete all the state history, it's called when the state history is disabled
+func (self *StateStore) ClearStateHistory() error {
+	self.store.NewBatch()
+	iter := self.store.NewIterator([]byte{byte(scom.SYS_STATE_HISTORY)})
+	for iter.Next() {
+		self.store.BatchDelete(iter.Key())
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		self.store.NewBatch() // reset the batch
+		return err
+	}
+	self.store.BatchDelete(self.getHistoryHeightKey())
+	return self.store.BatchCommit()
+}
+
 //AddBlockMerkleTreeRoot add a new tree root
 func (self *StateStore) AddBlockMerkleTreeRoot(preBlockHash common.Uint256) error {
 	key := self.genBlockMerkleTreeKey()
@@ -287,6 +436,9 @@ func (self *StateStore) AddBlockMerkleTreeRoot(preBlockHash common.Uint256) erro
 
 //GetMerkleProof return merkle proof of block hash
 func (self *StateStore) GetMerkleProof(raw []byte, proofHeight, rootHeight uint32) ([]byte, error) {
+	if proofHeight < self.merkleTail {
+		return nil, fmt.Errorf("merkle proof of leaf %d unavailable, merkle hash store starts from leaf %d", proofHeight, self.merkleTail)
+	}
 	return self.merkleTree.MerkleInclusionLeafPath(raw, proofHeight, rootHeight+1)
 }
 
@@ -398,10 +550,39 @@ func (self *StateStore) SaveCurrentBlock(height uint32, blockHash common.Uint256
 	return nil
 }
 
+//GetBlockMerkleTail return the lowest leaf of block merkle tree that the hashes for inclusion proof are stored
+func (self *StateStore) GetBlockMerkleTail() (uint32, error) {
+	data, err := self.store.Get(self.getBlockMerkleTailKey())
+	if err != nil {
+		return 0, err
+	}
+	reader := bytes.NewReader(data)
+	return serialization.ReadUint32(reader)
+}
+
+//SaveBlockMerkleTail persist the lowest leaf of block merkle tree that the hashes for inclusion proof are stored
+func (self *StateStore) SaveBlockMerkleTail(leaf uint32) {
+	value := bytes.NewBuffer(nil)
+	serialization.WriteUint32(value, leaf)
+	self.store.BatchPut(self.getBlockMerkleTailKey(), value.Bytes())
+}
+
 func (self *StateStore) getCurrentBlockKey() []byte {
 	return []byte{byte(scom.SYS_CURRENT_BLOCK)}
 }
 
+func (self *StateStore) getPrunedHeightKey() []byte {
+	return []byte{byte(scom.SYS_PRUNED_HEIGHT)}
+}
+
+func (self *StateStore) getHistoryHeightKey() []byte {
+	return []byte{byte(scom.SYS_HISTORY_HEIGHT)}
+}
+
+func (self *StateStore) getBlockMerkleTailKey() []byte {
+	return []byte{byte(scom.SYS_BLOCK_MERKLE_TAIL)}
+}
+
 func (self *StateStore) getBookkeeperKey() ([]byte, error) {
 	key := make([]byte, 1+len(BOOKKEEPER))
 	key[0] = byte(scom.ST_BOOKKEEPER)
@@ -448,6 +629,13 @@ func genCrossStatesKey(height uint32) []byte {
 	return key
 }
 
+func genStateHistoryKey(height uint32) []byte {
+	key := make([]byte, 5, 5)
+	key[0] = byte(scom.SYS_STATE_HISTORY)
+	binary.LittleEndian.PutUint32(key[1:], height)
+	return key
+}
+
 func genCrossStatesRootKey(height uint32) []byte {
 	key := make([]byte, 5, 5)
 	key[0] = byte(scom.SYS_CROSS_STATES_HASH)
@@ -34,13 +34,15 @@ import (
 
 //HandleInvokeTransaction deal with smart contract invoke transaction
 func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay *overlaydb.OverlayDB, cache *storage.CacheDB,
-	tx *types.Transaction, block *types.Block, notify *event.ExecuteNotify) ([]common.Uint256, error) {
+	tx *types.Transaction, block *types.Block, notify *event.ExecuteNotify, tracer *native.Tracer, replay bool) ([]common.Uint256, error) {
 	invoke := tx.Payload.(*payload.InvokeCode)
 	service, err := native.NewNativeService(cache, tx, block.Header.Timestamp, block.Header.Height,
 		block.Hash(), block.Header.ChainID, invoke.Code, false)
 	if err != nil {
-		return nil, fmt.Errorf("HandleInvokeTransaction Error: %+v\n", err)
+		return nil, event.NewExecuteError(event.CONTRACT_ERROR_SERVICE, "HandleInvokeTransaction Error: %+v", err)
 	}
+	service.SetTracer(tracer)
+	service.SetReplay(replay)
 	if _, err := service.Invoke(); err != nil {
 		return nil, err
 	}
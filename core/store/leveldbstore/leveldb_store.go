@@ -157,3 +157,71 @@ func (self *LevelDBStore) NewIterator(prefix []byte) common.StoreIterator {
 
 	return iter
 }
+
+//SnapshotStore is the read only view of leveldb at the time it is taken, writing to it returns error
+type SnapshotStore struct {
+	snapshot *leveldb.Snapshot
+}
+
+var errReadOnly = errors.New("leveldb snapshot is read only")
+
+//NewSnapshot return the snapshot of leveldb, it should be released after use
+func (self *LevelDBStore) NewSnapshot() (*SnapshotStore, error) {
+	snapshot, err := self.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotStore{snapshot: snapshot}, nil
+}
+
+//Put is not supported by snapshot
+func (self *SnapshotStore) Put(key []byte, value []byte) error {
+	return errReadOnly
+}
+
+//Get the value of a key from snapshot
+func (self *SnapshotStore) Get(key []byte) ([]byte, error) {
+	dat, err := self.snapshot.Get(key, nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return dat, nil
+}
+
+//Has return whether the key is exist in snapshot
+func (self *SnapshotStore) Has(key []byte) (bool, error) {
+	return self.snapshot.Has(key, nil)
+}
+
+//Delete is not supported by snapshot
+func (self *SnapshotStore) Delete(key []byte) error {
+	return errReadOnly
+}
+
+//NewBatch is not supported by snapshot
+func (self *SnapshotStore) NewBatch() {}
+
+//BatchPut is not supported by snapshot
+func (self *SnapshotStore) BatchPut(key []byte, value []byte) {}
+
+//BatchDelete is not supported by snapshot
+func (self *SnapshotStore) BatchDelete(key []byte) {}
+
+//BatchCommit is not supported by snapshot
+func (self *SnapshotStore) BatchCommit() error {
+	return errReadOnly
+}
+
+//Close release the snapshot
+func (self *SnapshotStore) Close() error {
+	self.snapshot.Release()
+	return nil
+}
+
+//NewIterator return a iterator of snapshot with the key prefix
+func (self *SnapshotStore) NewIterator(prefix []byte) common.StoreIterator {
+	return self.snapshot.NewIterator(util.BytesPrefix(prefix), nil)
+}
@@ -140,3 +140,33 @@ func TestIterator(t *testing.T) {
 	}
 
 }
+
+func TestSnapshot(t *testing.T) {
+	key := []byte("snapshot")
+	if err := testLevelDB.Put(key, []byte("v1")); err != nil {
+		t.Errorf("Put error:%s", err)
+		return
+	}
+	snapshot, err := testLevelDB.NewSnapshot()
+	if err != nil {
+		t.Errorf("NewSnapshot error:%s", err)
+		return
+	}
+	defer snapshot.Close()
+	if err := testLevelDB.Put(key, []byte("v2")); err != nil {
+		t.Errorf("Put error:%s", err)
+		return
+	}
+	v, err := snapshot.Get(key)
+	if err != nil {
+		t.Errorf("Get error:%s", err)
+		return
+	}
+	if string(v) != "v1" {
+		t.Errorf("Get error %s != v1", v)
+		return
+	}
+	if err := snapshot.Put(key, []byte("v3")); err == nil {
+		t.Errorf("Put to snapshot should fail")
+	}
+}
@@ -22,8 +22,10 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
 	cstates "github.com/polynetwork/poly/native/states"
 )
@@ -65,6 +67,12 @@ type LedgerStore interface {
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
+	GetStorageItemAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error)
+	PreExecuteContractAtHeight(tx *types.Transaction, height uint32) (*cstates.PreExecResult, error)
+	TraceBlock(height uint32) ([]*native.TxTrace, error)
+	TraceTransaction(txHash common.Uint256) (*native.TxTrace, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
+	GetCrossChainTx(fromChainID uint64, txHash []byte) (*scom.CrossChainTx, error)
+	ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*scom.CrossChainTx, error)
 }
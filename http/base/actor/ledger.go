@@ -21,7 +21,9 @@ package actor
 import (
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/ledger"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
 	cstate "github.com/polynetwork/poly/native/states"
 )
@@ -76,6 +78,11 @@ func GetStorageItem(address common.Address, key []byte) ([]byte, error) {
 	return ledger.DefLedger.GetStorageItem(address, key)
 }
 
+//GetStorageItemAtHeight from ledger
+func GetStorageItemAtHeight(address common.Address, key []byte, height uint32) ([]byte, error) {
+	return ledger.DefLedger.GetStorageItemAtHeight(address, key, height)
+}
+
 //GetTxnWithHeightByTxHash from ledger
 func GetTxnWithHeightByTxHash(hash common.Uint256) (uint32, *types.Transaction, error) {
 	tx, height, err := ledger.DefLedger.GetTransactionWithHeight(hash)
@@ -87,6 +94,21 @@ func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return ledger.DefLedger.PreExecuteContract(tx)
 }
 
+//PreExecuteContractAtHeight from ledger
+func PreExecuteContractAtHeight(tx *types.Transaction, height uint32) (*cstate.PreExecResult, error) {
+	return ledger.DefLedger.PreExecuteContractAtHeight(tx, height)
+}
+
+//TraceBlock from ledger
+func TraceBlock(height uint32) ([]*native.TxTrace, error) {
+	return ledger.DefLedger.TraceBlock(height)
+}
+
+//TraceTransaction from ledger
+func TraceTransaction(txHash common.Uint256) (*native.TxTrace, error) {
+	return ledger.DefLedger.TraceTransaction(txHash)
+}
+
 //GetEventNotifyByTxHash from ledger
 func GetEventNotifyByTxHash(txHash common.Uint256) (*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByTx(txHash)
@@ -109,3 +131,13 @@ func GetCrossStatesProof(height uint32, key []byte) ([]byte, error) {
 func GetCrossStateRoot(height uint32) (common.Uint256, error) {
 	return ledger.DefLedger.GetCrossStateRoot(height)
 }
+
+//GetCrossChainTx from ledger
+func GetCrossChainTx(fromChainID uint64, txHash []byte) (*scom.CrossChainTx, error) {
+	return ledger.DefLedger.GetCrossChainTx(fromChainID, txHash)
+}
+
+//ListCrossChainTxs from ledger
+func ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*scom.CrossChainTx, error) {
+	return ledger.DefLedger.ListCrossChainTxs(fromChainID, offset, limit)
+}
@@ -111,7 +111,7 @@ func GetTxFromPool(hash common.Uint256) (tcomn.TXEntry, error) {
 	if !ok {
 		return tcomn.TXEntry{}, errors.New("fail")
 	}
-	txnEntry := tcomn.TXEntry{rsp.Txn, txStatus.TxStatus}
+	txnEntry := tcomn.TXEntry{Tx: rsp.Txn, Attrs: txStatus.TxStatus}
 	return txnEntry, nil
 }
 
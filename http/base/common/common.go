@@ -20,17 +20,23 @@
 package common
 
 import (
+	"encoding/hex"
+
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/types"
 	ontErrors "github.com/polynetwork/poly/errors"
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	"github.com/polynetwork/poly/native/event"
+	hscom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
 	cstate "github.com/polynetwork/poly/native/states"
 )
 
 const MAX_SEARCH_HEIGHT uint32 = 100
+const MAX_CROSS_CHAIN_TX_LIMIT uint32 = 100
 
 type BalanceOfRsp struct {
 	Ont string `json:"ont"`
@@ -53,6 +59,8 @@ type ExecuteNotify struct {
 	State       byte
 	GasConsumed uint64
 	Notify      []NotifyEventInfo
+	ErrorCode   uint32 `json:",omitempty"`
+	Error       string `json:",omitempty"`
 }
 
 type PreExecuteResult struct {
@@ -66,6 +74,23 @@ type NotifyEventInfo struct {
 	States          interface{}
 }
 
+//HeaderRetention of side chain headers in header sync contract, OldestHeight is 0 when no header was pruned
+type HeaderRetention struct {
+	ChainID      uint64
+	Depth        uint64
+	OldestHeight uint64
+}
+
+type CrossChainTx struct {
+	FromChainID uint64
+	TxHash      string
+	PolyTxHash  string
+	ToChainID   uint64
+	Height      uint32
+	Key         string
+	Status      string
+}
+
 type TxAttributeInfo struct {
 	Usage types.TransactionAttributeUsage
 	Data  string
@@ -159,7 +184,43 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 		contractAddrs[v.ContractAddress.ToHexString()] = true
 	}
 	txhash := obj.TxHash.ToHexString()
-	return contractAddrs, ExecuteNotify{txhash, obj.State, obj.GasConsumed, evts}
+	return contractAddrs, ExecuteNotify{txhash, obj.State, obj.GasConsumed, evts, obj.ErrorCode, obj.Error}
+}
+
+func GetCrossChainTx(obj *scom.CrossChainTx) CrossChainTx {
+	return CrossChainTx{
+		FromChainID: obj.FromChainID,
+		TxHash:      hex.EncodeToString(obj.TxHash),
+		PolyTxHash:  obj.PolyTxHash.ToHexString(),
+		ToChainID:   obj.ToChainID,
+		Height:      obj.Height,
+		Key:         hex.EncodeToString(obj.Key),
+		Status:      obj.Status.String(),
+	}
+}
+
+//GetHeaderRetention return the header retention depth and the oldest retained header height of side chain
+func GetHeaderRetention(chainID uint64) (*HeaderRetention, error) {
+	depth, err := getHeaderSyncUint64(hscom.HEADER_RETENTION, chainID)
+	if err != nil {
+		return nil, err
+	}
+	oldest, err := getHeaderSyncUint64(hscom.OLDEST_HEADER_HEIGHT, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &HeaderRetention{ChainID: chainID, Depth: depth, OldestHeight: oldest}, nil
+}
+
+func getHeaderSyncUint64(prefix string, chainID uint64) (uint64, error) {
+	value, err := bactor.GetStorageItem(utils.HeaderSyncContractAddress, append([]byte(prefix), utils.GetUint64Bytes(chainID)...))
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return utils.GetBytesUint64(value), nil
 }
 
 func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// Names of the governance events pushed to the subscribers
+var GovernanceEventNames = map[string]bool{
+	"RegisterSideChain":              true,
+	"ApproveRegisterSideChain":       true,
+	"UpdateSideChain":                true,
+	"ApproveUpdateSideChain":         true,
+	"QuitSideChain":                  true,
+	"ApproveQuitSideChain":           true,
+	"CancelRegisterSideChain":        true,
+	"CancelUpdateSideChain":          true,
+	"CancelQuitSideChain":            true,
+	"ApplyAsset":                     true,
+	"ApproveAsset":                   true,
+	"CancelAsset":                    true,
+	"RegisterRedeem":                 true,
+	"SetBtcTxParam":                  true,
+	"putRelayerApply":                true,
+	"ApproveRegisterRelayer":         true,
+	"putRelayerRemove":               true,
+	"ApproveRemoveRelayer":           true,
+	"CancelRegisterRelayer":          true,
+	"CancelRemoveRelayer":            true,
+	"putStateValidatorApply":         true,
+	"ApproveRegisterStateValidator":  true,
+	"putStateValidatorRemove":        true,
+	"ApproveRemoveStateValidator":    true,
+	"CancelRegisterStateValidator":   true,
+	"CancelRemoveStateValidator":     true,
+	"registerCandidate":              true,
+	"unRegisterCandidate":            true,
+	"approveCandidate":               true,
+	"blackNode":                      true,
+	"whiteNode":                      true,
+	"quitNode":                       true,
+	"commitDpos":                     true,
+	"updateConfig":                   true,
+	"ReplenishTx":                    true,
+	node_manager.REVOKE_VOTE:         true,
+	node_manager.SET_PROPOSAL_CONFIG: true,
+	node_manager.SET_APPLY_EXPIRY:    true,
+	utils.APPLY_EXPIRED:              true,
+	ccom.BLACK_CHAIN:                 true,
+	ccom.WHITE_CHAIN:                 true,
+	ccom.SET_RATE_LIMIT:              true,
+	ccom.SET_QUARANTINE_CONFIG:       true,
+	ccom.NOTIFY_HOLD_TRANSFER:        true,
+	ccom.NOTIFY_PEND_TRANSFER:        true,
+	ccom.RELEASE_PENDING_TRANSFER:    true,
+	ccom.REJECT_PENDING_TRANSFER:     true,
+}
+
+// CrossChainEvent is the makeProof event of a cross chain transfer to the target chain, the proof is in the cross states of ProofHeight,
+// which is the height of the original makeProof if the event is replenished
+type CrossChainEvent struct {
+	TxHash       string
+	Height       uint32
+	FromChainID  uint64
+	ToChainID    uint64
+	SourceTxHash string
+	Key          string
+	ProofHeight  uint32
+	Replenished  bool
+}
+
+// EpochSwitchEvent is the validators switch of the side chain found by header sync
+type EpochSwitchEvent struct {
+	TxHash             string
+	Height             uint32
+	ChainID            uint64
+	BlockHash          string
+	SideChainHeight    uint64
+	NextValidatorsHash string
+	SideChainName      string
+}
+
+type GovernanceEvent struct {
+	TxHash   string
+	Height   uint32
+	Contract string
+	Name     string
+	States   []interface{}
+}
+
+type TypedEvents struct {
+	CrossChain  []CrossChainEvent
+	EpochSwitch []EpochSwitchEvent
+	Governance  []GovernanceEvent
+}
+
+// GetTypedEvents parses the events of successful transactions in block of height, the states are decoded from json as stored in ledger
+func GetTypedEvents(height uint32, notifies []*event.ExecuteNotify) *TypedEvents {
+	evts := new(TypedEvents)
+	for _, notify := range notifies {
+		if notify.State != event.CONTRACT_STATE_SUCCESS {
+			continue
+		}
+		txHash := notify.TxHash.ToHexString()
+		for _, n := range notify.Notify {
+			states, ok := n.States.([]interface{})
+			if !ok || len(states) == 0 {
+				continue
+			}
+			name, _ := states[0].(string)
+			switch {
+			case n.ContractAddress == utils.CrossChainManagerContractAddress && len(states) == 6 &&
+				(name == ccom.NOTIFY_MAKE_PROOF || name == ccom.NOTIFY_REPLENISH_MAKE_PROOF):
+				fromChainID, ok1 := toUint64(states[1])
+				toChainID, ok2 := toUint64(states[2])
+				sourceTxHash, ok3 := states[3].(string)
+				proofHeight, ok4 := toUint64(states[4])
+				key, ok5 := states[5].(string)
+				if ok1 && ok2 && ok3 && ok4 && ok5 {
+					evts.CrossChain = append(evts.CrossChain, CrossChainEvent{TxHash: txHash, Height: height,
+						FromChainID: fromChainID, ToChainID: toChainID, SourceTxHash: sourceTxHash, Key: key,
+						ProofHeight: uint32(proofHeight), Replenished: name == ccom.NOTIFY_REPLENISH_MAKE_PROOF})
+				}
+			case n.ContractAddress == utils.HeaderSyncContractAddress && len(states) == 6:
+				chainID, ok1 := toUint64(states[0])
+				blockHash, ok2 := states[1].(string)
+				sideHeight, ok3 := toUint64(states[2])
+				nextValidatorsHash, ok4 := states[3].(string)
+				sideChainName, ok5 := states[4].(string)
+				if ok1 && ok2 && ok3 && ok4 && ok5 {
+					evts.EpochSwitch = append(evts.EpochSwitch, EpochSwitchEvent{TxHash: txHash, Height: height,
+						ChainID: chainID, BlockHash: blockHash, SideChainHeight: sideHeight,
+						NextValidatorsHash: nextValidatorsHash, SideChainName: sideChainName})
+				}
+			case GovernanceEventNames[name]:
+				evts.Governance = append(evts.Governance, GovernanceEvent{TxHash: txHash, Height: height,
+					Contract: n.ContractAddress.ToHexString(), Name: name, States: states[1:]})
+			}
+		}
+	}
+	return evts
+}
+
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case float64:
+		if n < 0 {
+			return 0, false
+		}
+		return uint64(n), true
+	case uint64:
+		return n, true
+	case uint32:
+		return uint64(n), true
+	case int64:
+		return uint64(n), n >= 0
+	}
+	return 0, false
+}
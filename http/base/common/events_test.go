@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTypedEvents(t *testing.T) {
+	notify := &event.ExecuteNotify{
+		TxHash: common.Uint256{1},
+		State:  event.CONTRACT_STATE_SUCCESS,
+		Notify: []*event.NotifyEventInfo{
+			{
+				ContractAddress: utils.CrossChainManagerContractAddress,
+				States:          []interface{}{ccom.NOTIFY_MAKE_PROOF, uint64(2), uint64(6), "abcd", uint32(100), "0102"},
+			},
+			{
+				ContractAddress: utils.CrossChainManagerContractAddress,
+				States:          []interface{}{ccom.NOTIFY_REPLENISH_MAKE_PROOF, uint64(2), uint64(6), "abce", uint32(80), "0103"},
+			},
+			{
+				ContractAddress: utils.HeaderSyncContractAddress,
+				States:          []interface{}{uint64(5), "hash", int64(999), "validators", "cosmoshub-4", uint32(100)},
+			},
+			{
+				ContractAddress: utils.CrossChainManagerContractAddress,
+				States:          []interface{}{ccom.BLACK_CHAIN, uint64(3)},
+			},
+			{
+				ContractAddress: utils.HeaderSyncContractAddress,
+				States:          []interface{}{"syncHeader", uint64(2), uint32(1), "hash", uint32(100)},
+			},
+		},
+	}
+	failed := &event.ExecuteNotify{
+		TxHash: common.Uint256{2},
+		State:  event.CONTRACT_STATE_FAIL,
+		Notify: notify.Notify,
+	}
+	//the events are stored as json in ledger
+	data, err := json.Marshal([]*event.ExecuteNotify{notify, failed})
+	assert.Nil(t, err)
+	var notifies []*event.ExecuteNotify
+	assert.Nil(t, json.Unmarshal(data, &notifies))
+
+	evts := GetTypedEvents(100, notifies)
+	txHash := notify.TxHash.ToHexString()
+	assert.Equal(t, []CrossChainEvent{{TxHash: txHash, Height: 100, FromChainID: 2, ToChainID: 6,
+		SourceTxHash: "abcd", Key: "0102", ProofHeight: 100}, {TxHash: txHash, Height: 100, FromChainID: 2, ToChainID: 6,
+		SourceTxHash: "abce", Key: "0103", ProofHeight: 80, Replenished: true}}, evts.CrossChain)
+	assert.Equal(t, []EpochSwitchEvent{{TxHash: txHash, Height: 100, ChainID: 5, BlockHash: "hash",
+		SideChainHeight: 999, NextValidatorsHash: "validators", SideChainName: "cosmoshub-4"}}, evts.EpochSwitch)
+	assert.Equal(t, []GovernanceEvent{{TxHash: txHash, Height: 100, Contract: utils.CrossChainManagerContractAddress.ToHexString(),
+		Name: ccom.BLACK_CHAIN, States: []interface{}{float64(3)}}}, evts.Governance)
+}
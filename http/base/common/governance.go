@@ -0,0 +1,546 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	cstate "github.com/polynetwork/poly/native/states"
+)
+
+type SideChainInfo struct {
+	Address      string
+	ChainId      uint64
+	Router       uint64
+	Name         string
+	BlocksToWait uint64
+	CCMCAddress  string
+	ExtraInfo    string
+}
+
+type PendingSideChains struct {
+	Register []SideChainInfo
+	Update   []SideChainInfo
+	Quit     []uint64
+}
+
+type RelayerApplyInfo struct {
+	ID        uint64
+	Relayers  []string
+	Applicant string
+}
+
+type PendingRelayers struct {
+	Register []RelayerApplyInfo
+	Remove   []RelayerApplyInfo
+}
+
+type SideChainFee struct {
+	ChainId uint64
+	View    uint64
+	Fee     string
+}
+
+type AssetBindInfo struct {
+	ChainId      uint64
+	AssetMap     map[uint64]string
+	LockProxyMap map[uint64]string
+}
+
+type PeerPoolItemInfo struct {
+	Index      uint32
+	PeerPubkey string
+	Address    string
+	Status     uint8
+}
+
+type GovernanceViewInfo struct {
+	View   uint32
+	Height uint32
+	TxHash string
+}
+
+type RateLimitInfo struct {
+	FromChainID       uint64
+	ToChainID         uint64
+	ToContractAddress string
+	Window            uint32
+	MaxCount          uint64
+	MaxAmount         string
+}
+
+type AssetThresholdInfo struct {
+	AssetHash string
+	Amount    string
+}
+
+type QuarantineConfigInfo struct {
+	ChainID          uint64
+	WhitelistPeriod  uint32
+	Methods          []string
+	AmountThresholds []AssetThresholdInfo
+}
+
+type ProposalInfo struct {
+	ID            string
+	Scope         string
+	Content       string
+	Quorum        uint32
+	RequiredVotes int
+	Expiry        uint32
+	Height        uint32
+	Voters        []string
+}
+
+type AssetChainInfo struct {
+	ChainID  uint64
+	Hash     string
+	Decimals uint8
+	Model    string
+	Supply   string
+}
+
+type AssetInfo struct {
+	ID       uint64
+	Name     string
+	Decimals uint8
+	Chains   []AssetChainInfo
+}
+
+type PendingTransferInfo struct {
+	Reason        string
+	Height        uint32
+	ToMerkleValue ToMerkleValueInfo
+}
+
+//PreExecuteNativeContract pre-execute the read only method of native contract on the current block, and return the result bytes
+func PreExecuteNativeContract(contract common.Address, method string, args []byte) ([]byte, error) {
+	header, err := bactor.GetHeaderByHeight(bactor.GetCurrentBlockHeight())
+	if err != nil {
+		return nil, fmt.Errorf("get current header error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	invokeParam := &cstate.ContractInvokeParam{Address: contract, Method: method, Args: args}
+	invokeParam.Serialization(sink)
+	tx := &types.Transaction{
+		Version: types.CURR_TX_VERSION,
+		TxType:  types.Invoke,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
+		ChainID: header.ChainID,
+		Sigs:    []types.Sig{},
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, err
+	}
+	if result.State != event.CONTRACT_STATE_SUCCESS {
+		return nil, fmt.Errorf("pre-execute %s failed", method)
+	}
+	return common.HexToBytes(result.Result.(string))
+}
+
+//GetSideChain return the registered side chain, nil if not registered
+func GetSideChain(chainID uint64) (*SideChainInfo, error) {
+	sink := common.NewZeroCopySink(nil)
+	(&side_chain_manager.QueryChainParam{ChainId: chainID}).Serialization(sink)
+	res, err := PreExecuteNativeContract(utils.SideChainManagerContractAddress, side_chain_manager.GET_SIDE_CHAIN, sink.Bytes())
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+	sideChain := new(side_chain_manager.SideChain)
+	if err := sideChain.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	info := convertSideChain(sideChain)
+	return &info, nil
+}
+
+//ListSideChains return all the registered side chains
+func ListSideChains() ([]SideChainInfo, error) {
+	return listSideChains(side_chain_manager.LIST_SIDE_CHAINS)
+}
+
+//GetPendingSideChains return the side chain registrations, updates and quits waiting for approval
+func GetPendingSideChains() (*PendingSideChains, error) {
+	register, err := listSideChains(side_chain_manager.LIST_SIDE_CHAIN_APPLIES)
+	if err != nil {
+		return nil, err
+	}
+	update, err := listSideChains(side_chain_manager.LIST_UPDATE_REQUESTS)
+	if err != nil {
+		return nil, err
+	}
+	res, err := PreExecuteNativeContract(utils.SideChainManagerContractAddress, side_chain_manager.LIST_QUIT_REQUESTS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	quit := new(side_chain_manager.ChainIDList)
+	if err := quit.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	return &PendingSideChains{Register: register, Update: update, Quit: quit.ChainIDs}, nil
+}
+
+func listSideChains(method string) ([]SideChainInfo, error) {
+	res, err := PreExecuteNativeContract(utils.SideChainManagerContractAddress, method, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	sideChainList := new(side_chain_manager.SideChainList)
+	if err := sideChainList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	infos := make([]SideChainInfo, 0, len(sideChainList.SideChains))
+	for _, sideChain := range sideChainList.SideChains {
+		infos = append(infos, convertSideChain(sideChain))
+	}
+	return infos, nil
+}
+
+func convertSideChain(sideChain *side_chain_manager.SideChain) SideChainInfo {
+	return SideChainInfo{
+		Address:      sideChain.Address.ToBase58(),
+		ChainId:      sideChain.ChainId,
+		Router:       sideChain.Router,
+		Name:         sideChain.Name,
+		BlocksToWait: sideChain.BlocksToWait,
+		CCMCAddress:  hex.EncodeToString(sideChain.CCMCAddress),
+		ExtraInfo:    hex.EncodeToString(sideChain.ExtraInfo),
+	}
+}
+
+//GetSideChainFee return the voted fee of side chain
+func GetSideChainFee(chainID uint64) (*SideChainFee, error) {
+	sink := common.NewZeroCopySink(nil)
+	(&side_chain_manager.QueryChainParam{ChainId: chainID}).Serialization(sink)
+	res, err := PreExecuteNativeContract(utils.SideChainManagerContractAddress, side_chain_manager.GET_FEE, sink.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	fee := new(side_chain_manager.Fee)
+	if err := fee.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	return &SideChainFee{ChainId: chainID, View: fee.View, Fee: fee.Fee.String()}, nil
+}
+
+//GetAssetBind return the assets and lock proxies bound to side chain
+func GetAssetBind(chainID uint64) (*AssetBindInfo, error) {
+	sink := common.NewZeroCopySink(nil)
+	(&side_chain_manager.QueryChainParam{ChainId: chainID}).Serialization(sink)
+	res, err := PreExecuteNativeContract(utils.SideChainManagerContractAddress, side_chain_manager.GET_ASSET_BIND, sink.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	assetBind := new(side_chain_manager.AssetBind)
+	if err := assetBind.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	info := &AssetBindInfo{
+		ChainId:      chainID,
+		AssetMap:     make(map[uint64]string, len(assetBind.AssetMap)),
+		LockProxyMap: make(map[uint64]string, len(assetBind.LockProxyMap)),
+	}
+	for k, v := range assetBind.AssetMap {
+		info.AssetMap[k] = hex.EncodeToString(v)
+	}
+	for k, v := range assetBind.LockProxyMap {
+		info.LockProxyMap[k] = hex.EncodeToString(v)
+	}
+	return info, nil
+}
+
+//ListRelayers return the base58 addresses of all the approved relayers
+func ListRelayers() ([]string, error) {
+	res, err := PreExecuteNativeContract(utils.RelayerManagerContractAddress, relayer_manager.LIST_RELAYERS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	relayerList := new(relayer_manager.RelayerList)
+	if err := relayerList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	relayers := make([]string, 0, len(relayerList.Relayers))
+	for _, relayer := range relayerList.Relayers {
+		relayers = append(relayers, relayer.ToBase58())
+	}
+	return relayers, nil
+}
+
+//GetPendingRelayers return the relayer registrations and removals waiting for approval
+func GetPendingRelayers() (*PendingRelayers, error) {
+	register, err := listRelayerApplies(relayer_manager.LIST_RELAYER_APPLIES)
+	if err != nil {
+		return nil, err
+	}
+	remove, err := listRelayerApplies(relayer_manager.LIST_RELAYER_REMOVES)
+	if err != nil {
+		return nil, err
+	}
+	return &PendingRelayers{Register: register, Remove: remove}, nil
+}
+
+func listRelayerApplies(method string) ([]RelayerApplyInfo, error) {
+	res, err := PreExecuteNativeContract(utils.RelayerManagerContractAddress, method, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	applyList := new(relayer_manager.RelayerApplyList)
+	if err := applyList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	infos := make([]RelayerApplyInfo, 0, len(applyList.Applies))
+	for _, apply := range applyList.Applies {
+		relayers := make([]string, 0, len(apply.Param.AddressList))
+		for _, relayer := range apply.Param.AddressList {
+			relayers = append(relayers, relayer.ToBase58())
+		}
+		infos = append(infos, RelayerApplyInfo{ID: apply.ID, Relayers: relayers, Applicant: apply.Param.Address.ToBase58()})
+	}
+	return infos, nil
+}
+
+//GetPeerPool return the peers of view in the order of peer index, the current view is used if the view is 0
+func GetPeerPool(view uint32) ([]PeerPoolItemInfo, error) {
+	sink := common.NewZeroCopySink(nil)
+	(&node_manager.ViewParam{View: view}).Serialization(sink)
+	res, err := PreExecuteNativeContract(utils.NodeManagerContractAddress, node_manager.GET_PEER_POOL, sink.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	peerPoolMap := new(node_manager.PeerPoolMap)
+	if err := peerPoolMap.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	peers := make([]PeerPoolItemInfo, 0, len(peerPoolMap.PeerPoolMap))
+	for _, item := range peerPoolMap.PeerPoolMap {
+		peers = append(peers, PeerPoolItemInfo{
+			Index:      item.Index,
+			PeerPubkey: item.PeerPubkey,
+			Address:    item.Address.ToBase58(),
+			Status:     uint8(item.Status),
+		})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Index < peers[j].Index
+	})
+	return peers, nil
+}
+
+//GetGovernanceView return the current governance view
+func GetGovernanceView() (*GovernanceViewInfo, error) {
+	res, err := PreExecuteNativeContract(utils.NodeManagerContractAddress, node_manager.GET_GOVERNANCE_VIEW, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	governanceView := new(node_manager.GovernanceView)
+	if err := governanceView.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	return &GovernanceViewInfo{
+		View:   governanceView.View,
+		Height: governanceView.Height,
+		TxHash: governanceView.TxHash.ToHexString(),
+	}, nil
+}
+
+//IsChainBlacked return whether the side chain is blacked
+func IsChainBlacked(chainID uint64) (bool, error) {
+	sink := common.NewZeroCopySink(nil)
+	(&ccom.BlackChainParam{ChainID: chainID}).Serialization(sink)
+	res, err := PreExecuteNativeContract(utils.CrossChainManagerContractAddress, ccom.IS_CHAIN_BLACKED, sink.Bytes())
+	if err != nil {
+		return false, err
+	}
+	return len(res) == 1 && res[0] == utils.BYTE_TRUE[0], nil
+}
+
+//ListHeldTransfers return the cross chain transfers held until the fee of target chain is paid
+func ListHeldTransfers() ([]ToMerkleValueInfo, error) {
+	res, err := PreExecuteNativeContract(utils.CrossChainManagerContractAddress, ccom.LIST_HELD_TRANSFERS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	heldTransfers := new(ccom.HeldTransferList)
+	if err := heldTransfers.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	transfers := make([]ToMerkleValueInfo, 0, len(heldTransfers.Transfers))
+	for _, transfer := range heldTransfers.Transfers {
+		transfers = append(transfers, convertToMerkleValue(transfer))
+	}
+	return transfers, nil
+}
+
+//ListRateLimits return the rate limits of the cross chain transfers
+func ListRateLimits() ([]RateLimitInfo, error) {
+	res, err := PreExecuteNativeContract(utils.CrossChainManagerContractAddress, ccom.LIST_RATE_LIMITS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	rateLimitList := new(ccom.RateLimitList)
+	if err := rateLimitList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	rateLimits := make([]RateLimitInfo, 0, len(rateLimitList.RateLimits))
+	for _, rateLimit := range rateLimitList.RateLimits {
+		rateLimits = append(rateLimits, RateLimitInfo{
+			FromChainID:       rateLimit.FromChainID,
+			ToChainID:         rateLimit.ToChainID,
+			ToContractAddress: hex.EncodeToString(rateLimit.ToContractAddress),
+			Window:            rateLimit.Window,
+			MaxCount:          rateLimit.MaxCount,
+			MaxAmount:         rateLimit.MaxAmount.String(),
+		})
+	}
+	return rateLimits, nil
+}
+
+//ListPendingTransfers return the cross chain transfers waiting for the consensus nodes to release or reject
+func ListPendingTransfers() ([]PendingTransferInfo, error) {
+	res, err := PreExecuteNativeContract(utils.CrossChainManagerContractAddress, ccom.LIST_PENDING_TRANSFERS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	pendingList := new(ccom.PendingTransferList)
+	if err := pendingList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	transfers := make([]PendingTransferInfo, 0, len(pendingList.Transfers))
+	for _, transfer := range pendingList.Transfers {
+		transfers = append(transfers, PendingTransferInfo{
+			Reason:        transfer.Reason,
+			Height:        transfer.Height,
+			ToMerkleValue: convertToMerkleValue(transfer.ToMerkleValue),
+		})
+	}
+	return transfers, nil
+}
+
+//ListQuarantineConfigs return the rules of target chains to put the cross chain transfers to pending
+func ListQuarantineConfigs() ([]QuarantineConfigInfo, error) {
+	res, err := PreExecuteNativeContract(utils.CrossChainManagerContractAddress, ccom.LIST_QUARANTINE_CONFIGS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	configList := new(ccom.QuarantineConfigList)
+	if err := configList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	configs := make([]QuarantineConfigInfo, 0, len(configList.Configs))
+	for _, config := range configList.Configs {
+		thresholds := make([]AssetThresholdInfo, 0, len(config.AmountThresholds))
+		for _, threshold := range config.AmountThresholds {
+			thresholds = append(thresholds, AssetThresholdInfo{AssetHash: hex.EncodeToString(threshold.AssetHash),
+				Amount: threshold.Amount.String()})
+		}
+		configs = append(configs, QuarantineConfigInfo{
+			ChainID:          config.ChainID,
+			WhitelistPeriod:  config.WhitelistPeriod,
+			Methods:          config.Methods,
+			AmountThresholds: thresholds,
+		})
+	}
+	return configs, nil
+}
+
+//ListProposals return the open proposals voted by the consensus nodes
+func ListProposals() ([]ProposalInfo, error) {
+	res, err := PreExecuteNativeContract(utils.NodeManagerContractAddress, node_manager.GET_OPEN_PROPOSALS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	proposalList := new(node_manager.ProposalList)
+	if err := proposalList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	proposals := make([]ProposalInfo, 0, len(proposalList.Proposals))
+	for _, proposal := range proposalList.Proposals {
+		voters := make([]string, 0, len(proposal.Votes))
+		for addr := range proposal.Votes {
+			voters = append(voters, addr.ToBase58())
+		}
+		sort.Strings(voters)
+		proposals = append(proposals, ProposalInfo{
+			ID:            proposal.ID.ToHexString(),
+			Scope:         proposal.Scope,
+			Content:       hex.EncodeToString(proposal.Content),
+			Quorum:        proposal.Quorum,
+			RequiredVotes: proposal.RequiredVotes(int(proposalList.ConsensusPeers)),
+			Expiry:        proposal.Expiry,
+			Height:        proposal.Height,
+			Voters:        voters,
+		})
+	}
+	return proposals, nil
+}
+
+//ListAssets return the registered assets with their supplies on the chains in the decimals of asset
+func ListAssets() ([]AssetInfo, error) {
+	res, err := PreExecuteNativeContract(utils.SideChainManagerContractAddress, side_chain_manager.LIST_ASSETS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	assetList := new(side_chain_manager.AssetList)
+	if err := assetList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+		return nil, err
+	}
+	assets := make([]AssetInfo, 0, len(assetList.Assets))
+	for _, asset := range assetList.Assets {
+		sink := common.NewZeroCopySink(nil)
+		(&side_chain_manager.QueryAssetParam{ID: asset.ID}).Serialization(sink)
+		res, err := PreExecuteNativeContract(utils.CrossChainManagerContractAddress, ccom.GET_ASSET_SUPPLY, sink.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		supplyList := new(ccom.AssetSupplyList)
+		if err := supplyList.Deserialization(common.NewZeroCopySource(res)); err != nil {
+			return nil, err
+		}
+		supplies := make(map[uint64]string, len(supplyList.Supplies))
+		for _, supply := range supplyList.Supplies {
+			supplies[supply.ChainID] = supply.Amount.String()
+		}
+		info := AssetInfo{ID: asset.ID, Name: asset.Name, Decimals: asset.Decimals,
+			Chains: make([]AssetChainInfo, 0, len(asset.Chains))}
+		for _, chain := range asset.Chains {
+			info.Chains = append(info.Chains, AssetChainInfo{
+				ChainID:  chain.ChainID,
+				Hash:     hex.EncodeToString(chain.Hash),
+				Decimals: chain.Decimals,
+				Model:    chain.Model.String(),
+				Supply:   supplies[chain.ChainID],
+			})
+		}
+		assets = append(assets, info)
+	}
+	return assets, nil
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/signature"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/merkle"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Wire formats of the header signatures expected by the cross chain manager contracts of the target chains
+const (
+	WIRE_FORMAT_EVM  = "evm"  //concatenated 65 bytes ethereum compatible signatures
+	WIRE_FORMAT_POLY = "poly" //var bytes list of the poly signatures
+)
+
+//Routers of the target chains running the evm cross chain manager contract
+var evmRouters = map[uint64]bool{
+	utils.ETH_ROUTER:         true,
+	utils.BSC_ROUTER:         true,
+	utils.HECO_ROUTER:        true,
+	utils.QUORUM_ROUTER:      true,
+	utils.MSC_ROUTER:         true,
+	utils.OKEX_ROUTER:        true,
+	utils.POLYGON_BOR_ROUTER: true,
+	utils.PIXIECHAIN_ROUTER:  true,
+	utils.HSC_ROUTER:         true,
+	utils.HARMONY_ROUTER:     true,
+	utils.BYTOM_ROUTER:       true,
+	utils.ETH_BEACON_ROUTER:  true,
+}
+
+type MakeTxParamInfo struct {
+	TxHash              string
+	CrossChainID        string
+	FromContractAddress string
+	ToChainID           uint64
+	ToContractAddress   string
+	Method              string
+	Args                string
+}
+
+type ToMerkleValueInfo struct {
+	TxHash      string
+	FromChainID uint64
+	MakeTxParam MakeTxParamInfo
+}
+
+//CrossChainProofBundle is all that the cross chain manager contract of target chain needs to verify and execute the cross chain tx,
+//the signatures are of the anchor header if there is, otherwise of the header
+type CrossChainProofBundle struct {
+	TxHash           string
+	Height           uint32
+	ToChainID        uint64
+	Key              string
+	ToMerkleValue    ToMerkleValueInfo
+	CrossStatesProof string
+	Header           string
+	HeaderProof      string
+	AnchorHeader     string
+	Signatures       string
+	WireFormat       string
+}
+
+//GetCrossChainProofBundles return the proof bundles of the cross chain txs in poly tx to target chain, one for every makeProof
+//in the order of events, knownEpochHeight is the start height of the poly epoch known by the target chain
+func GetCrossChainProofBundles(txHash common.Uint256, toChainID uint64, knownEpochHeight uint32) ([]*CrossChainProofBundle, error) {
+	sideChain, err := GetSideChain(toChainID)
+	if err != nil {
+		return nil, fmt.Errorf("get side chain %d error: %s", toChainID, err)
+	}
+	if sideChain == nil {
+		return nil, fmt.Errorf("side chain %d is not registered", toChainID)
+	}
+	height, _, err := bactor.GetTxnWithHeightByTxHash(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("get tx %s error: %s", txHash.ToHexString(), err)
+	}
+	notify, err := bactor.GetEventNotifyByTxHash(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("get events of tx %s error: %s", txHash.ToHexString(), err)
+	}
+	keys := make([]string, 0)
+	for _, e := range GetTypedEvents(height, []*event.ExecuteNotify{notify}).CrossChain {
+		if e.ToChainID == toChainID {
+			keys = append(keys, e.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no cross chain tx to chain %d in tx %s", toChainID, txHash.ToHexString())
+	}
+
+	//the cross states root of block is in the next header
+	headerHeight := height + 1
+	if headerHeight > bactor.GetCurrentBlockHeight() {
+		return nil, fmt.Errorf("header of height %d is not committed yet", headerHeight)
+	}
+	header, err := bactor.GetHeaderByHeight(headerHeight)
+	if err != nil {
+		return nil, fmt.Errorf("get header of height %d error: %s", headerHeight, err)
+	}
+	bundles, err := proveCrossChainTxs(height, keys, header.CrossStateRoot, bactor.GetCrossStatesProof)
+	if err != nil {
+		return nil, err
+	}
+
+	wireFormat := WIRE_FORMAT_POLY
+	if evmRouters[sideChain.Router] {
+		wireFormat = WIRE_FORMAT_EVM
+	}
+	var anchorHeader, headerProof string
+	signed := header
+	//the header is signed by the validators of an epoch unknown to target chain, prove it by the header of the known epoch
+	if headerHeight < knownEpochHeight {
+		if knownEpochHeight > bactor.GetCurrentBlockHeight() {
+			return nil, fmt.Errorf("known epoch height %d is higher than current height", knownEpochHeight)
+		}
+		anchor, err := bactor.GetHeaderByHeight(knownEpochHeight)
+		if err != nil {
+			return nil, fmt.Errorf("get anchor header of height %d error: %s", knownEpochHeight, err)
+		}
+		proof, err := bactor.GetMerkleProof(headerHeight, knownEpochHeight)
+		if err != nil {
+			return nil, fmt.Errorf("get header proof error: %s", err)
+		}
+		anchorHeader = hex.EncodeToString(anchor.GetMessage())
+		headerProof = hex.EncodeToString(proof)
+		signed = anchor
+	}
+	sigs, err := encodeSignatures(signed, wireFormat)
+	if err != nil {
+		return nil, err
+	}
+	for _, bundle := range bundles {
+		bundle.TxHash = txHash.ToHexString()
+		bundle.ToChainID = toChainID
+		bundle.Header = hex.EncodeToString(header.GetMessage())
+		bundle.HeaderProof = headerProof
+		bundle.AnchorHeader = anchorHeader
+		bundle.Signatures = hex.EncodeToString(sigs)
+		bundle.WireFormat = wireFormat
+	}
+	return bundles, nil
+}
+
+//Prove the cross chain txs of keys in block of height by the cross states root, one bundle for each key
+func proveCrossChainTxs(height uint32, keys []string, root common.Uint256,
+	getProof func(uint32, []byte) ([]byte, error)) ([]*CrossChainProofBundle, error) {
+	bundles := make([]*CrossChainProofBundle, 0, len(keys))
+	for _, key := range keys {
+		rawKey, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cross chain tx key %s", key)
+		}
+		proof, err := getProof(height, rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("get cross states proof of key %s error: %s", key, err)
+		}
+		value, err := merkle.MerkleProve(proof, root[:])
+		if err != nil {
+			return nil, fmt.Errorf("verify cross states proof of key %s error: %s", key, err)
+		}
+		toMerkleValue := new(ccom.ToMerkleValue)
+		if err := toMerkleValue.Deserialization(common.NewZeroCopySource(value)); err != nil {
+			return nil, fmt.Errorf("deserialize to merkle value of key %s error: %s", key, err)
+		}
+		bundles = append(bundles, &CrossChainProofBundle{
+			Height:           height,
+			Key:              key,
+			ToMerkleValue:    convertToMerkleValue(toMerkleValue),
+			CrossStatesProof: hex.EncodeToString(proof),
+		})
+	}
+	return bundles, nil
+}
+
+func encodeSignatures(header *types.Header, format string) ([]byte, error) {
+	if format == WIRE_FORMAT_EVM {
+		sigs := make([]byte, 0, len(header.SigData)*65)
+		for _, sig := range header.SigData {
+			temp := make([]byte, len(sig))
+			copy(temp, sig)
+			ethSig, err := signature.ConvertToEthCompatible(temp)
+			if err != nil {
+				return nil, fmt.Errorf("convert signature of header %d error: %s", header.Height, err)
+			}
+			sigs = append(sigs, ethSig...)
+		}
+		return sigs, nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarUint(uint64(len(header.SigData)))
+	for _, sig := range header.SigData {
+		sink.WriteVarBytes(sig)
+	}
+	return sink.Bytes(), nil
+}
+
+func convertToMerkleValue(value *ccom.ToMerkleValue) ToMerkleValueInfo {
+	param := value.MakeTxParam
+	return ToMerkleValueInfo{
+		TxHash:      hex.EncodeToString(value.TxHash),
+		FromChainID: value.FromChainID,
+		MakeTxParam: MakeTxParamInfo{
+			TxHash:              hex.EncodeToString(param.TxHash),
+			CrossChainID:        hex.EncodeToString(param.CrossChainID),
+			FromContractAddress: hex.EncodeToString(param.FromContractAddress),
+			ToChainID:           param.ToChainID,
+			ToContractAddress:   hex.EncodeToString(param.ToContractAddress),
+			Method:              param.Method,
+			Args:                hex.EncodeToString(param.Args),
+		},
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/signature"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeSignatures(t *testing.T) {
+	header := &types.Header{Height: 10}
+	for i := 0; i < 2; i++ {
+		priv, _, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.SECP256K1)
+		assert.Nil(t, err)
+		sig, err := signature.Sign(signature.SHA3_256withECDSA, priv, header.GetMessage(), nil)
+		assert.Nil(t, err)
+		data, err := signature.Serialize(sig)
+		assert.Nil(t, err)
+		header.SigData = append(header.SigData, data)
+	}
+
+	sigs, err := encodeSignatures(header, WIRE_FORMAT_EVM)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*65, len(sigs))
+	for i, sig := range header.SigData {
+		//the recovery id is moved to the end
+		assert.Equal(t, sig[2:66], sigs[i*65:i*65+64])
+		assert.Equal(t, sig[1]-27, sigs[i*65+64])
+	}
+
+	sigs, err = encodeSignatures(header, WIRE_FORMAT_POLY)
+	assert.Nil(t, err)
+	source := common.NewZeroCopySource(sigs)
+	n, eof := source.NextVarUint()
+	assert.False(t, eof)
+	assert.Equal(t, uint64(2), n)
+	for _, sig := range header.SigData {
+		data, eof := source.NextVarBytes()
+		assert.False(t, eof)
+		assert.Equal(t, sig, data)
+	}
+
+	header.SigData = [][]byte{{1, 2, 3}}
+	_, err = encodeSignatures(header, WIRE_FORMAT_EVM)
+	assert.NotNil(t, err)
+}
+
+func TestProveCrossChainTxs(t *testing.T) {
+	//two makeProofs to chain 2 and one to chain 3 in a block, the cross states are stored by key
+	states := make(map[string][]byte)
+	hashes := make([]common.Uint256, 0)
+	keys := make([]string, 0)
+	for i, toChainID := range []uint64{2, 3, 2} {
+		sink := common.NewZeroCopySink(nil)
+		(&ccom.ToMerkleValue{
+			TxHash:      []byte{byte(i)},
+			FromChainID: 1,
+			MakeTxParam: &ccom.MakeTxParam{TxHash: []byte{byte(i)}, CrossChainID: []byte{byte(i)}, ToChainID: toChainID,
+				Method: "unlock", Args: []byte{byte(i)}},
+		}).Serialization(sink)
+		key := fmt.Sprintf("%02x", i)
+		states[key] = sink.Bytes()
+		hashes = append(hashes, merkle.HashLeaf(sink.Bytes()))
+		if toChainID == 2 {
+			keys = append(keys, key)
+		}
+	}
+	root := merkle.HashChildren(merkle.HashChildren(hashes[0], hashes[1]), hashes[2])
+	getProof := func(height uint32, key []byte) ([]byte, error) {
+		assert.Equal(t, uint32(100), height)
+		return merkle.MerkleLeafPath(states[hex.EncodeToString(key)], hashes)
+	}
+
+	bundles, err := proveCrossChainTxs(100, keys, root, getProof)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(bundles))
+	for i, bundle := range bundles {
+		assert.Equal(t, keys[i], bundle.Key)
+		assert.Equal(t, uint32(100), bundle.Height)
+		assert.Equal(t, uint64(2), bundle.ToMerkleValue.MakeTxParam.ToChainID)
+		assert.Equal(t, keys[i], bundle.ToMerkleValue.MakeTxParam.TxHash)
+		proof, _ := getProof(100, []byte{byte(2 * i)})
+		assert.Equal(t, hex.EncodeToString(proof), bundle.CrossStatesProof)
+	}
+
+	_, err = proveCrossChainTxs(100, keys, common.Uint256{1}, getProof)
+	assert.NotNil(t, err)
+}
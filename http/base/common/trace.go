@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
+	"github.com/polynetwork/poly/native/service/governance/neo3_state_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/replenish"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+type TxTrace struct {
+	TxHash      string
+	State       byte
+	ErrorCode   uint32 `json:",omitempty"`
+	Error       string `json:",omitempty"`
+	Notify      []NotifyEventInfo
+	CrossHashes []string
+	Call        *CallTrace
+}
+
+type CallTrace struct {
+	Contract     string
+	ContractName string
+	Method       string
+	Error        string `json:",omitempty"`
+	Storage      []StorageTrace
+	Calls        []*CallTrace `json:",omitempty"`
+}
+
+//StorageTrace of native contract, the key is decoded to the owner contract, the known key prefix and the rest key in hex
+type StorageTrace struct {
+	Op       string
+	Contract string
+	Prefix   string `json:",omitempty"`
+	Key      string
+	Value    string
+}
+
+var nativeContractNames = map[common.Address]string{
+	utils.HeaderSyncContractAddress:        "header_sync",
+	utils.CrossChainManagerContractAddress: "cross_chain_manager",
+	utils.SideChainManagerContractAddress:  "side_chain_manager",
+	utils.NodeManagerContractAddress:       "node_manager",
+	utils.RelayerManagerContractAddress:    "relayer_manager",
+	utils.Neo3StateManagerContractAddress:  "neo3_state_manager",
+	utils.SignatureManagerContractAddress:  "signature_manager",
+	utils.ReplenishContractAddress:         "replenish",
+}
+
+//storageKeyPrefixes of native contracts, sorted by length in descending order to match the longest prefix
+var storageKeyPrefixes = sortKeyPrefixes([]string{
+	utils.INDEX, utils.APPLY_INFO,
+	hscom.CROSS_CHAIN_MSG, hscom.CURRENT_MSG_HEIGHT, hscom.BLOCK_HEADER, hscom.CURRENT_HEADER_HEIGHT, hscom.HEADER_INDEX,
+	hscom.CONSENSUS_PEER, hscom.CONSENSUS_PEER_BLOCK_HEIGHT, hscom.KEY_HEIGHTS, hscom.ETH_CACHE, hscom.GENESIS_HEADER,
+	hscom.MAIN_CHAIN, hscom.POLYGON_SPAN, hscom.SYNC_COMMITTEE, hscom.HEADER_RETENTION, hscom.OLDEST_HEADER_HEIGHT,
+	ccom.BLACKED_CHAIN, ccom.KEY_PREFIX_BTC, ccom.KEY_PREFIX_BTC_VOTE, ccom.REQUEST, ccom.REQUEST_HEIGHT, ccom.DONE_TX,
+	ccom.MULTISIGN_INFO, ccom.RIPPLE_TX_INFO, ccom.FEE_PAYMENT, ccom.HELD_TRANSFER, ccom.RATE_LIMIT, ccom.RATE_LIMIT_USAGE,
+	ccom.PENDING_TRANSFER, ccom.QUARANTINE_CONFIG, ccom.WHITE_CHAIN_HEIGHT, ccom.ASSET_SUPPLY,
+	consensus_vote.VOTE_INFO, consensus_vote.VOTE_PROPOSAL,
+	side_chain_manager.SIDE_CHAIN_APPLY, side_chain_manager.UPDATE_SIDE_CHAIN_REQUEST, side_chain_manager.QUIT_SIDE_CHAIN_REQUEST,
+	side_chain_manager.SIDE_CHAIN, side_chain_manager.REDEEM_BIND, side_chain_manager.BIND_SIGN_INFO, side_chain_manager.BTC_TX_PARAM,
+	side_chain_manager.REDEEM_SCRIPT, side_chain_manager.ASSET_BIND, side_chain_manager.FEE, side_chain_manager.FEE_INFO,
+	side_chain_manager.ASSET_INFO, side_chain_manager.ASSET_APPLY, side_chain_manager.ASSET_HASH,
+	node_manager.GOVERNANCE_VIEW, node_manager.VBFT_CONFIG, node_manager.CANDIDITE_INDEX, node_manager.PEER_APPLY,
+	node_manager.PEER_POOL, node_manager.PEER_INDEX, node_manager.BLACK_LIST, node_manager.PROPOSAL,
+	relayer_manager.RELAYER, relayer_manager.RELAYER_APPLY, relayer_manager.RELAYER_REMOVE, relayer_manager.APPLY_ID,
+	relayer_manager.REMOVE_ID,
+	neo3_state_manager.STATE_VALIDATOR, neo3_state_manager.STATE_VALIDATOR_APPLY, neo3_state_manager.STATE_VALIDATOR_REMOVE,
+	neo3_state_manager.STATE_VALIDATOR_APPLY_ID, neo3_state_manager.STATE_VALIDATOR_REMOVE_ID,
+	replenish.REPLENISH_HEIGHT,
+})
+
+func sortKeyPrefixes(prefixes []string) []string {
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+	return prefixes
+}
+
+func GetTxTrace(trace *native.TxTrace) TxTrace {
+	_, notify := GetExecuteNotify(trace.Notify)
+	crossHashes := make([]string, 0, len(trace.CrossHashes))
+	for _, hash := range trace.CrossHashes {
+		crossHashes = append(crossHashes, hash.ToHexString())
+	}
+	return TxTrace{
+		TxHash:      notify.TxHash,
+		State:       notify.State,
+		ErrorCode:   notify.ErrorCode,
+		Error:       notify.Error,
+		Notify:      notify.Notify,
+		CrossHashes: crossHashes,
+		Call:        getCallTrace(trace.Call),
+	}
+}
+
+func getCallTrace(call *native.CallTrace) *CallTrace {
+	if call == nil {
+		return nil
+	}
+	result := &CallTrace{
+		Contract:     call.Contract.ToHexString(),
+		ContractName: nativeContractNames[call.Contract],
+		Method:       call.Method,
+		Error:        call.Error,
+		Storage:      make([]StorageTrace, 0, len(call.Storage)),
+	}
+	for _, s := range call.Storage {
+		result.Storage = append(result.Storage, getStorageTrace(s))
+	}
+	for _, c := range call.Calls {
+		result.Calls = append(result.Calls, getCallTrace(c))
+	}
+	return result
+}
+
+func getStorageTrace(s *native.StorageTrace) StorageTrace {
+	result := StorageTrace{Op: s.Op, Key: common.ToHexString(s.Key), Value: common.ToHexString(s.Value)}
+	if len(s.Key) < common.ADDR_LEN {
+		return result
+	}
+	addr, _ := common.AddressParseFromBytes(s.Key[:common.ADDR_LEN])
+	result.Contract = addr.ToHexString()
+	key := s.Key[common.ADDR_LEN:]
+	for _, prefix := range storageKeyPrefixes {
+		if bytes.HasPrefix(key, []byte(prefix)) {
+			result.Prefix = prefix
+			key = key[len(prefix):]
+			break
+		}
+	}
+	result.Key = common.ToHexString(key)
+	return result
+}
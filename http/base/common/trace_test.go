@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTxTrace(t *testing.T) {
+	sideChainKey := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(side_chain_manager.SIDE_CHAIN_APPLY), []byte{2})
+	doneTxKey := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(ccom.DONE_TX), []byte{3})
+	unknownKey := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte("unknown"))
+	trace := &native.TxTrace{
+		Notify: &event.ExecuteNotify{
+			TxHash:    common.Uint256{1},
+			State:     event.CONTRACT_STATE_FAIL,
+			ErrorCode: event.CONTRACT_ERROR_EXECUTE,
+			Error:     "failed",
+		},
+		CrossHashes: []common.Uint256{{2}},
+		Call: &native.CallTrace{
+			Contract: utils.CrossChainManagerContractAddress,
+			Method:   "importOuterTransfer",
+			Error:    "failed",
+			Storage: []*native.StorageTrace{
+				{Op: native.STORAGE_READ, Key: doneTxKey},
+				{Op: native.STORAGE_WRITE, Key: unknownKey, Value: []byte{4}},
+			},
+			Calls: []*native.CallTrace{{
+				Contract: utils.SideChainManagerContractAddress,
+				Method:   side_chain_manager.GET_SIDE_CHAIN,
+				Storage:  []*native.StorageTrace{{Op: native.STORAGE_DELETE, Key: sideChainKey}},
+			}},
+		},
+	}
+	result := GetTxTrace(trace)
+	assert.Equal(t, trace.Notify.TxHash.ToHexString(), result.TxHash)
+	assert.Equal(t, event.CONTRACT_ERROR_EXECUTE, result.ErrorCode)
+	assert.Equal(t, []string{trace.CrossHashes[0].ToHexString()}, result.CrossHashes)
+
+	ccm := utils.CrossChainManagerContractAddress.ToHexString()
+	scm := utils.SideChainManagerContractAddress.ToHexString()
+	assert.Equal(t, &CallTrace{
+		Contract:     ccm,
+		ContractName: "cross_chain_manager",
+		Method:       "importOuterTransfer",
+		Error:        "failed",
+		Storage: []StorageTrace{
+			{Op: native.STORAGE_READ, Contract: ccm, Prefix: ccom.DONE_TX, Key: "03", Value: ""},
+			{Op: native.STORAGE_WRITE, Contract: ccm, Key: common.ToHexString([]byte("unknown")), Value: "04"},
+		},
+		Calls: []*CallTrace{{
+			Contract:     scm,
+			ContractName: "side_chain_manager",
+			Method:       side_chain_manager.GET_SIDE_CHAIN,
+			Storage: []StorageTrace{
+				{Op: native.STORAGE_DELETE, Contract: scm, Prefix: side_chain_manager.SIDE_CHAIN_APPLY, Key: "02", Value: ""},
+			},
+		}},
+	}, result.Call)
+}
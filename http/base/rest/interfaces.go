@@ -29,7 +29,9 @@ import (
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	bcomn "github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
+	cstates "github.com/polynetwork/poly/native/states"
 	"strconv"
+	"strings"
 )
 
 const TLS_PORT int = 443
@@ -261,7 +263,16 @@ func SendRawTransaction(cmd map[string]interface{}) map[string]interface{} {
 	log.Debugf("SendRawTransaction recv %s", hash.ToHexString())
 	if txn.TxType == types.Invoke || txn.TxType == types.Deploy {
 		if preExec, ok := cmd["PreExec"].(string); ok && preExec == "1" {
-			rst, err := bactor.PreExecuteContract(txn)
+			height, history, err := getHistoryHeight(cmd)
+			if err != nil {
+				return ResponsePack(berr.INVALID_PARAMS)
+			}
+			var rst *cstates.PreExecResult
+			if history {
+				rst, err = bactor.PreExecuteContractAtHeight(txn, height)
+			} else {
+				rst, err = bactor.PreExecuteContract(txn)
+			}
 			if err != nil {
 				log.Infof("PreExec: ", err)
 				resp = ResponsePack(berr.SMARTCODE_ERROR)
@@ -363,17 +374,44 @@ func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	value, err := bactor.GetStorageItem(address, item)
+	height, history, err := getHistoryHeight(cmd)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var value []byte
+	if history {
+		value, err = bactor.GetStorageItemAtHeight(address, item, height)
+	} else {
+		value, err = bactor.GetStorageItem(address, item)
+	}
 	if err != nil {
 		if err == scom.ErrNotFound {
 			return ResponsePack(berr.SUCCESS)
 		}
+		if history {
+			resp = ResponsePack(berr.INVALID_PARAMS)
+			resp["Result"] = err.Error()
+			return resp
+		}
 		return ResponsePack(berr.INTERNAL_ERROR)
 	}
 	resp["Result"] = common.ToHexString(value)
 	return resp
 }
 
+//getHistoryHeight return the optional block height of history state in request
+func getHistoryHeight(cmd map[string]interface{}) (uint32, bool, error) {
+	str, ok := cmd["Height"].(string)
+	if !ok || len(str) == 0 {
+		return 0, false, nil
+	}
+	height, err := strconv.ParseUint(str, 10, 32)
+	if err != nil {
+		return 0, false, err
+	}
+	return uint32(height), true, nil
+}
+
 //get merkle proof by transaction hash
 func GetMerkleProof(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -437,3 +475,77 @@ func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
 	resp["Result"] = bcomn.TXNEntryInfo{attrs}
 	return resp
 }
+
+//get cross chain transaction by source chain id and source transaction hash
+func GetCrossChainTx(cmd map[string]interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+
+	resp := ResponsePack(berr.SUCCESS)
+	chainIDStr, ok := cmd["ChainID"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	fromChainID, err := strconv.ParseUint(chainIDStr, 10, 64)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	str, ok := cmd["Hash"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	txHash, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	tx, err := bactor.GetCrossChainTx(fromChainID, txHash)
+	if err != nil {
+		if scom.ErrNotFound == err {
+			return ResponsePack(berr.SUCCESS)
+		}
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetCrossChainTx(tx)
+	return resp
+}
+
+//list cross chain transactions of source chain in block height order
+func ListCrossChainTxs(cmd map[string]interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+
+	resp := ResponsePack(berr.SUCCESS)
+	chainIDStr, ok := cmd["ChainID"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	fromChainID, err := strconv.ParseUint(chainIDStr, 10, 64)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	offset, limit := uint64(0), uint64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT)
+	if str, ok := cmd["Offset"].(string); ok && len(str) > 0 {
+		offset, err = strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	if str, ok := cmd["Limit"].(string); ok && len(str) > 0 {
+		limit, err = strconv.ParseUint(str, 10, 32)
+		if err != nil || limit == 0 || limit > uint64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT) {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	txs, err := bactor.ListCrossChainTxs(fromChainID, uint32(offset), uint32(limit))
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	result := make([]bcomn.CrossChainTx, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, bcomn.GetCrossChainTx(tx))
+	}
+	resp["Result"] = result
+	return resp
+}
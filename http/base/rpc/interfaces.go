@@ -21,6 +21,7 @@ package rpc
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
@@ -32,6 +33,7 @@ import (
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	bcomn "github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
+	cstates "github.com/polynetwork/poly/native/states"
 )
 
 //get best block hash
@@ -238,8 +240,8 @@ func GetRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(tx.Raw))
 }
 
-//get storage from contract
-//   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key"], "id": 0}
+//get storage from contract, the optional block height reads the storage at history height
+//   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key", height], "id": 0}
 func GetStorage(params []interface{}) map[string]interface{} {
 	if len(params) < 2 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -270,6 +272,20 @@ func GetStorage(params []interface{}) map[string]interface{} {
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	if len(params) > 2 {
+		height, ok := params[2].(float64)
+		if !ok || height < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		value, err := bactor.GetStorageItemAtHeight(address, key, uint32(height))
+		if err != nil {
+			if err == scom.ErrNotFound {
+				return responseSuccess(nil)
+			}
+			return responsePack(berr.INVALID_PARAMS, err.Error())
+		}
+		return responseSuccess(common.ToHexString(value))
+	}
 	value, err := bactor.GetStorageItem(address, key)
 	if err != nil {
 		if err == scom.ErrNotFound {
@@ -283,6 +299,8 @@ func GetStorage(params []interface{}) map[string]interface{} {
 //send raw transaction
 // A JSON example for sendrawtransaction method as following:
 //   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
+// pre execute the transaction on the state of history block height:
+//   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex", 1, height], "id": 0}
 func SendRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -305,7 +323,17 @@ func SendRawTransaction(params []interface{}) map[string]interface{} {
 			if len(params) > 1 {
 				preExec, ok := params[1].(float64)
 				if ok && preExec == 1 {
-					result, err := bactor.PreExecuteContract(txn)
+					var result *cstates.PreExecResult
+					var err error
+					if len(params) > 2 {
+						height, ok := params[2].(float64)
+						if !ok || height < 0 {
+							return responsePack(berr.INVALID_PARAMS, "")
+						}
+						result, err = bactor.PreExecuteContractAtHeight(txn, uint32(height))
+					} else {
+						result, err = bactor.PreExecuteContract(txn)
+					}
 					if err != nil {
 						log.Infof("PreExec: ", err)
 						return responsePack(berr.SMARTCODE_ERROR, err.Error())
@@ -516,3 +544,337 @@ func GetStateMerkleRoot(params []interface{}) map[string]interface{} {
 	}
 
 }
+
+//get cross chain transaction by source chain id and source transaction hash
+func GetCrossChainTx(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	fromChainID, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	txHash, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	tx, err := bactor.GetCrossChainTx(uint64(fromChainID), txHash)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return responseSuccess(nil)
+		}
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.GetCrossChainTx(tx))
+}
+
+//list cross chain transactions of source chain in block height order, params: [fromChainID, offset, limit]
+func ListCrossChainTxs(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	fromChainID, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	offset, limit := float64(0), float64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT)
+	if len(params) > 1 {
+		if offset, ok = params[1].(float64); !ok || offset < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	if len(params) > 2 {
+		if limit, ok = params[2].(float64); !ok || limit <= 0 || limit > float64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT) {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	txs, err := bactor.ListCrossChainTxs(uint64(fromChainID), uint32(offset), uint32(limit))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	result := make([]bcomn.CrossChainTx, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, bcomn.GetCrossChainTx(tx))
+	}
+	return responseSuccess(result)
+}
+
+//get the header retention depth and the oldest retained header height of side chain, params: [chainID]
+func GetHeaderRetention(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	chainID, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	retention, err := bcomn.GetHeaderRetention(uint64(chainID))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(retention)
+}
+
+//get the proof bundles of the cross chain txs for the target chain, one for every makeProof in tx,
+//params: [txHash, targetChainID, knownEpochHeight]
+func GetCrossChainProofBundle(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	txHash, err := common.Uint256FromHexString(strings.TrimPrefix(str, "0x"))
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	toChainID, ok := params[1].(float64)
+	if !ok || toChainID < 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	knownEpochHeight := float64(0)
+	if len(params) > 2 {
+		if knownEpochHeight, ok = params[2].(float64); !ok || knownEpochHeight < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	bundles, err := bcomn.GetCrossChainProofBundles(txHash, uint64(toChainID), uint32(knownEpochHeight))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(bundles)
+}
+
+//get the registered side chain, null if not registered
+func GetSideChain(params []interface{}) map[string]interface{} {
+	chainID, ok := getChainIDParam(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	sideChain, err := bcomn.GetSideChain(chainID)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(sideChain)
+}
+
+//list all the registered side chains
+func ListSideChains(params []interface{}) map[string]interface{} {
+	sideChains, err := bcomn.ListSideChains()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(sideChains)
+}
+
+//list the side chain registrations, updates and quits waiting for approval
+func ListPendingSideChains(params []interface{}) map[string]interface{} {
+	pending, err := bcomn.GetPendingSideChains()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(pending)
+}
+
+//get the fee of side chain
+func GetSideChainFee(params []interface{}) map[string]interface{} {
+	chainID, ok := getChainIDParam(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	fee, err := bcomn.GetSideChainFee(chainID)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(fee)
+}
+
+//get the assets bound to side chain
+func GetAssetBind(params []interface{}) map[string]interface{} {
+	chainID, ok := getChainIDParam(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	assetBind, err := bcomn.GetAssetBind(chainID)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(assetBind)
+}
+
+//list all the approved relayers
+func ListRelayers(params []interface{}) map[string]interface{} {
+	relayers, err := bcomn.ListRelayers()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(relayers)
+}
+
+//list the relayer registrations and removals waiting for approval
+func ListPendingRelayers(params []interface{}) map[string]interface{} {
+	pending, err := bcomn.GetPendingRelayers()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(pending)
+}
+
+//get the peer pool of view, the current view is used if the view is omitted
+func GetPeerPool(params []interface{}) map[string]interface{} {
+	var view uint32
+	if len(params) > 0 {
+		v, ok := params[0].(float64)
+		if !ok || v < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		view = uint32(v)
+	}
+	peers, err := bcomn.GetPeerPool(view)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(peers)
+}
+
+//get the current governance view
+func GetGovernanceView(params []interface{}) map[string]interface{} {
+	governanceView, err := bcomn.GetGovernanceView()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(governanceView)
+}
+
+//check if the side chain is blacked
+func IsChainBlacked(params []interface{}) map[string]interface{} {
+	chainID, ok := getChainIDParam(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	blacked, err := bcomn.IsChainBlacked(chainID)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(blacked)
+}
+
+//list the cross chain transfers held until the fee of target chain is paid
+func ListHeldTransfers(params []interface{}) map[string]interface{} {
+	transfers, err := bcomn.ListHeldTransfers()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(transfers)
+}
+
+//list the rate limits of the cross chain transfers
+func ListRateLimits(params []interface{}) map[string]interface{} {
+	rateLimits, err := bcomn.ListRateLimits()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(rateLimits)
+}
+
+//list the cross chain transfers waiting for the consensus nodes to release or reject
+func ListPendingTransfers(params []interface{}) map[string]interface{} {
+	transfers, err := bcomn.ListPendingTransfers()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(transfers)
+}
+
+//list the rules of target chains to put the cross chain transfers to pending
+func ListQuarantineConfigs(params []interface{}) map[string]interface{} {
+	configs, err := bcomn.ListQuarantineConfigs()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(configs)
+}
+
+//list the open proposals voted by the consensus nodes
+func ListProposals(params []interface{}) map[string]interface{} {
+	proposals, err := bcomn.ListProposals()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(proposals)
+}
+
+//list the registered assets with their supplies on the chains
+func ListAssets(params []interface{}) map[string]interface{} {
+	assets, err := bcomn.ListAssets()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(assets)
+}
+
+//re-execute the block on the state of previous block, and trace the storage access, notifies and native calls of the
+//transactions, the state of previous block should be retained by state history, params: [height]
+func TraceBlock(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	height, ok := params[0].(float64)
+	if !ok || height < 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	traces, err := bactor.TraceBlock(uint32(height))
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	result := make([]bcomn.TxTrace, 0, len(traces))
+	for _, trace := range traces {
+		result = append(result, bcomn.GetTxTrace(trace))
+	}
+	return responseSuccess(result)
+}
+
+//re-execute the block of transaction until the transaction, and trace the transaction, params: [txHash]
+func TraceTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	trace, err := bactor.TraceTransaction(hash)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	return responseSuccess(bcomn.GetTxTrace(trace))
+}
+
+func getChainIDParam(params []interface{}) (uint64, bool) {
+	if len(params) < 1 {
+		return 0, false
+	}
+	chainID, ok := params[0].(float64)
+	if !ok || chainID < 0 {
+		return 0, false
+	}
+	return uint64(chainID), true
+}
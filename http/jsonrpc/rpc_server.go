@@ -56,9 +56,31 @@ func StartRPCServer() error {
 
 	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
 	rpc.HandleFunc("getcrossstatesproof", rpc.GetCrossStatesProof)
+	rpc.HandleFunc("getcrosschainproofbundle", rpc.GetCrossChainProofBundle)
 	rpc.HandleFunc("getheaderbyheight", rpc.GetHeaderByHeight)
 	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
 	rpc.HandleFunc("getstatemerkleroot", rpc.GetStateMerkleRoot)
+	rpc.HandleFunc("getcrosschaintx", rpc.GetCrossChainTx)
+	rpc.HandleFunc("listcrosschaintxs", rpc.ListCrossChainTxs)
+	rpc.HandleFunc("getheaderretention", rpc.GetHeaderRetention)
+	rpc.HandleFunc("getsidechain", rpc.GetSideChain)
+	rpc.HandleFunc("listsidechains", rpc.ListSideChains)
+	rpc.HandleFunc("listpendingsidechains", rpc.ListPendingSideChains)
+	rpc.HandleFunc("getsidechainfee", rpc.GetSideChainFee)
+	rpc.HandleFunc("getassetbind", rpc.GetAssetBind)
+	rpc.HandleFunc("listrelayers", rpc.ListRelayers)
+	rpc.HandleFunc("listpendingrelayers", rpc.ListPendingRelayers)
+	rpc.HandleFunc("getpeerpool", rpc.GetPeerPool)
+	rpc.HandleFunc("getgovernanceview", rpc.GetGovernanceView)
+	rpc.HandleFunc("ischainblacked", rpc.IsChainBlacked)
+	rpc.HandleFunc("listheldtransfers", rpc.ListHeldTransfers)
+	rpc.HandleFunc("listratelimits", rpc.ListRateLimits)
+	rpc.HandleFunc("listpendingtransfers", rpc.ListPendingTransfers)
+	rpc.HandleFunc("listquarantineconfigs", rpc.ListQuarantineConfigs)
+	rpc.HandleFunc("listproposals", rpc.ListProposals)
+	rpc.HandleFunc("listassets", rpc.ListAssets)
+	rpc.HandleFunc("traceblock", rpc.TraceBlock)
+	rpc.HandleFunc("tracetx", rpc.TraceTransaction)
 
 	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), nil)
 	if err != nil {
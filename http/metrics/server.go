@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics privides the prometheus metrics server
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/common/metrics"
+	"github.com/polynetwork/poly/core/ledger"
+	p2p "github.com/polynetwork/poly/p2pserver/net/protocol"
+)
+
+func StartServer(n p2p.P2P) {
+	metrics.NewGaugeFunc("poly_ledger_block_height", "Height of the current block",
+		func() float64 { return float64(ledger.DefLedger.GetCurrentBlockHeight()) })
+	metrics.NewGaugeFunc("poly_p2p_neighbor_peers", "Count of the neighbor peers",
+		func() float64 { return float64(n.GetNp().GetNbrNodeCnt()) })
+
+	port := int(config.DefConfig.Metrics.MetricsPort)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	err := http.ListenAndServe(":"+strconv.Itoa(port), mux)
+	if err != nil {
+		log.Errorf("metrics server ListenAndServe error: %s", err)
+	}
+}
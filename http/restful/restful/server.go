@@ -74,6 +74,8 @@ const (
 	GET_MEMPOOL_TXSTATE   = "/api/v1/mempool/txstate/:hash"
 	GET_VERSION           = "/api/v1/version"
 	GET_NETWORKID         = "/api/v1/networkid"
+	GET_CROSS_CHAIN_TX    = "/api/v1/crosschaintx/:chainid/:hash"
+	GET_CROSS_CHAIN_TXS   = "/api/v1/crosschaintxs/:chainid"
 
 	POST_RAW_TX = "/api/v1/transaction"
 )
@@ -148,6 +150,8 @@ func (this *restServer) registryMethod() {
 		GET_MEMPOOL_TXSTATE:   {name: "getmempooltxstate", handler: rest.GetMemPoolTxState},
 		GET_VERSION:           {name: "getversion", handler: rest.GetNodeVersion},
 		GET_NETWORKID:         {name: "getnetworkid", handler: rest.GetNetworkId},
+		GET_CROSS_CHAIN_TX:    {name: "getcrosschaintx", handler: rest.GetCrossChainTx},
+		GET_CROSS_CHAIN_TXS:   {name: "listcrosschaintxs", handler: rest.ListCrossChainTxs},
 	}
 
 	postMethodMap := map[string]Action{
@@ -190,6 +194,10 @@ func (this *restServer) getPath(url string) string {
 		return GET_GRANTONG
 	} else if strings.Contains(url, strings.TrimRight(GET_MEMPOOL_TXSTATE, ":hash")) {
 		return GET_MEMPOOL_TXSTATE
+	} else if strings.Contains(url, strings.TrimRight(GET_CROSS_CHAIN_TXS, ":chainid")) {
+		return GET_CROSS_CHAIN_TXS
+	} else if strings.Contains(url, strings.TrimRight(GET_CROSS_CHAIN_TX, ":chainid/:hash")) {
+		return GET_CROSS_CHAIN_TX
 	}
 	return url
 }
@@ -212,9 +220,10 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 	case GET_CONTRACT_STATE:
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
 	case POST_RAW_TX:
-		req["PreExec"] = r.FormValue("preExec")
+		req["PreExec"], req["Height"] = r.FormValue("preExec"), r.FormValue("height")
 	case GET_STORAGE:
 		req["Hash"], req["Key"] = getParam(r, "hash"), getParam(r, "key")
+		req["Height"] = r.FormValue("height")
 	case GET_SMTCOCE_EVT_TXS:
 		req["Height"] = getParam(r, "height")
 	case GET_SMTCOCE_EVTS:
@@ -234,6 +243,11 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Addr"] = getParam(r, "addr")
 	case GET_MEMPOOL_TXSTATE:
 		req["Hash"] = getParam(r, "hash")
+	case GET_CROSS_CHAIN_TX:
+		req["ChainID"], req["Hash"] = getParam(r, "chainid"), getParam(r, "hash")
+	case GET_CROSS_CHAIN_TXS:
+		req["ChainID"] = getParam(r, "chainid")
+		req["Offset"], req["Limit"] = r.FormValue("offset"), r.FormValue("limit")
 	default:
 	}
 	return req
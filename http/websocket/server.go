@@ -48,6 +48,7 @@ func sendBlock2WSclient(v interface{}) {
 		go func() {
 			pushBlock(v)
 			pushBlockTransactions(v)
+			pushTypedEvents(v)
 		}()
 	}
 }
@@ -112,6 +113,15 @@ func pushBlock(v interface{}) {
 		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_JSON_BLOCK, resp)
 	}
 }
+func pushTypedEvents(v interface{}) {
+	if ws == nil {
+		return
+	}
+	if block, ok := v.(types.Block); ok {
+		ws.PushTypedEvents(block.Header.Height)
+	}
+}
+
 func pushBlockTransactions(v interface{}) {
 	if ws == nil {
 		return
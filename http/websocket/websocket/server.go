@@ -23,6 +23,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"strconv"
@@ -33,6 +34,9 @@ import (
 	"github.com/polynetwork/poly/common"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	scom "github.com/polynetwork/poly/core/store/common"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	bcomn "github.com/polynetwork/poly/http/base/common"
 	Err "github.com/polynetwork/poly/http/base/error"
 	"github.com/polynetwork/poly/http/base/rest"
 	"github.com/polynetwork/poly/http/websocket/session"
@@ -45,6 +49,12 @@ const (
 	WSTOPIC_TXHASHS    = 4
 )
 
+//Max count of blocks the typed events could be resumed from
+const MAX_RESUME_BLOCKS = 100000
+
+//Count of blocks the typed events are replayed in a chunk when resuming
+const RESUME_CHUNK_BLOCKS = 100
+
 type handler func(map[string]interface{}) map[string]interface{}
 type Handler struct {
 	handler  handler
@@ -58,9 +68,25 @@ type subscribe struct {
 	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
 	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
 	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+
+	//typed events of the committed blocks, pushed from FromHeight
+	SubscribeCrossChain  bool     `json:"SubscribeCrossChain"`
+	FromChainIDs         []uint64 `json:"FromChainIDs"`
+	ToChainIDs           []uint64 `json:"ToChainIDs"`
+	SubscribeEpochSwitch bool     `json:"SubscribeEpochSwitch"`
+	EpochChainIDs        []uint64 `json:"EpochChainIDs"`
+	SubscribeGovernance  bool     `json:"SubscribeGovernance"`
+	FromHeight           uint32   `json:"FromHeight"`
+	resuming             bool     //replayed out of PushTypedEvents
 }
+
+func (self *subscribe) typed() bool {
+	return self.SubscribeCrossChain || self.SubscribeEpochSwitch || self.SubscribeGovernance
+}
+
 type WsServer struct {
 	sync.RWMutex
+	typedLock    sync.Mutex //serialize pushing typed events
 	Upgrader     websocket.Upgrader
 	listener     net.Listener
 	server       *http.Server
@@ -165,7 +191,40 @@ func (self *WsServer) registryMethod() {
 				}
 			}
 		}
+		typed := sub.typed()
+		if b, ok := cmd["SubscribeCrossChain"].(bool); ok {
+			sub.SubscribeCrossChain = b
+		}
+		if ids, ok := cmd["FromChainIDs"].([]interface{}); ok {
+			sub.FromChainIDs = getChainIDs(ids)
+		}
+		if ids, ok := cmd["ToChainIDs"].([]interface{}); ok {
+			sub.ToChainIDs = getChainIDs(ids)
+		}
+		if b, ok := cmd["SubscribeEpochSwitch"].(bool); ok {
+			sub.SubscribeEpochSwitch = b
+		}
+		if ids, ok := cmd["EpochChainIDs"].([]interface{}); ok {
+			sub.EpochChainIDs = getChainIDs(ids)
+		}
+		if b, ok := cmd["SubscribeGovernance"].(bool); ok {
+			sub.SubscribeGovernance = b
+		}
+		current := bactor.GetCurrentBlockHeight()
+		resume := false
+		if height, ok := cmd["FromHeight"].(float64); ok {
+			if height < 0 || uint32(height) > current+1 || uint32(height)+MAX_RESUME_BLOCKS < current {
+				return rest.ResponsePack(Err.INVALID_PARAMS)
+			}
+			sub.FromHeight = uint32(height)
+			resume = uint32(height) <= current
+		} else if !typed {
+			sub.FromHeight = current + 1
+		}
 		self.SubscribeMap[sessionId] = sub
+		if resume && sub.typed() {
+			go self.PushTypedEvents(current)
+		}
 
 		resp["Action"] = "subscribe"
 		resp["Result"] = sub
@@ -438,6 +497,159 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 	}
 }
 
+//Push the typed events of the blocks from FromHeight of every typed subscriber to current height.
+//The subscribers far behind are replayed in chunks by resumeTypedEvents, not to block pushing the new blocks
+func (self *WsServer) PushTypedEvents(current uint32) {
+	self.typedLock.Lock()
+	defer self.typedLock.Unlock()
+
+	subs := make(map[string]subscribe)
+	self.Lock()
+	for sid, v := range self.SubscribeMap {
+		if !v.typed() || v.resuming || v.FromHeight > current {
+			continue
+		}
+		if current-v.FromHeight >= RESUME_CHUNK_BLOCKS {
+			v.resuming = true
+			self.SubscribeMap[sid] = v
+			go self.resumeTypedEvents(sid)
+			continue
+		}
+		subs[sid] = v
+	}
+	self.Unlock()
+
+	blocks := make(map[uint32]*bcomn.TypedEvents)
+	for sid, v := range subs {
+		s := self.SessionList.GetSessionById(sid)
+		if s == nil {
+			continue
+		}
+		next, err := pushTypedEventsOfBlocks(s, &v, v.FromHeight, current, blocks)
+		self.advanceFromHeight(sid, v.FromHeight, next, false)
+		if err != nil {
+			log.Errorf("PushTypedEvents, %s", err)
+		}
+	}
+}
+
+//Replay the typed events of the subscriber in chunks out of typedLock, till it is close to current height
+//and handed over to PushTypedEvents
+func (self *WsServer) resumeTypedEvents(sid string) {
+	for {
+		self.RLock()
+		sub, ok := self.SubscribeMap[sid]
+		self.RUnlock()
+		if !ok || !sub.resuming {
+			return
+		}
+		s := self.SessionList.GetSessionById(sid)
+		current := bactor.GetCurrentBlockHeight()
+		if s == nil || !sub.typed() || sub.FromHeight > current || current-sub.FromHeight < RESUME_CHUNK_BLOCKS {
+			self.advanceFromHeight(sid, sub.FromHeight, sub.FromHeight, true)
+			self.PushTypedEvents(current)
+			return
+		}
+		next, err := pushTypedEventsOfBlocks(s, &sub, sub.FromHeight, sub.FromHeight+RESUME_CHUNK_BLOCKS-1,
+			make(map[uint32]*bcomn.TypedEvents))
+		if err != nil {
+			//left to PushTypedEvents to retry at the next block
+			self.advanceFromHeight(sid, sub.FromHeight, next, true)
+			log.Errorf("resumeTypedEvents, %s", err)
+			return
+		}
+		self.advanceFromHeight(sid, sub.FromHeight, next, false)
+	}
+}
+
+//Push the typed events of the blocks from height from to height to, the events of blocks are cached in blocks.
+//It returns the height next to the last block pushed
+func pushTypedEventsOfBlocks(s *session.Session, sub *subscribe, from, to uint32,
+	blocks map[uint32]*bcomn.TypedEvents) (uint32, error) {
+	for height := from; height <= to; height++ {
+		evts, ok := blocks[height]
+		if !ok {
+			notifies, err := bactor.GetEventNotifyByHeight(height)
+			if err != nil && err != scom.ErrNotFound {
+				return height, fmt.Errorf("get events of block %d error: %s", height, err)
+			}
+			evts = bcomn.GetTypedEvents(height, notifies)
+			blocks[height] = evts
+		}
+		pushTypedEvents(s, sub, evts)
+	}
+	return to + 1, nil
+}
+
+//Advance FromHeight of the subscriber to next unless it is subscribed again from another height meanwhile,
+//resumed marks the replay of resumeTypedEvents is over
+func (self *WsServer) advanceFromHeight(sid string, from, next uint32, resumed bool) {
+	self.Lock()
+	defer self.Unlock()
+	sub, ok := self.SubscribeMap[sid]
+	if !ok {
+		return
+	}
+	if sub.FromHeight == from {
+		sub.FromHeight = next
+	}
+	if resumed {
+		sub.resuming = false
+	}
+	self.SubscribeMap[sid] = sub
+}
+
+func pushTypedEvents(s *session.Session, sub *subscribe, evts *bcomn.TypedEvents) {
+	if sub.SubscribeCrossChain {
+		for _, e := range evts.CrossChain {
+			if matchChainID(sub.FromChainIDs, e.FromChainID) && matchChainID(sub.ToChainIDs, e.ToChainID) {
+				s.Send(marshalTypedEvent("crosschainevent", e))
+			}
+		}
+	}
+	if sub.SubscribeEpochSwitch {
+		for _, e := range evts.EpochSwitch {
+			if matchChainID(sub.EpochChainIDs, e.ChainID) {
+				s.Send(marshalTypedEvent("epochswitchevent", e))
+			}
+		}
+	}
+	if sub.SubscribeGovernance {
+		for _, e := range evts.Governance {
+			s.Send(marshalTypedEvent("governanceevent", e))
+		}
+	}
+}
+
+func marshalTypedEvent(action string, result interface{}) []byte {
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Action"] = action
+	resp["Result"] = result
+	return marshalResp(resp)
+}
+
+func matchChainID(filter []uint64, chainID uint64) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, id := range filter {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+func getChainIDs(ids []interface{}) []uint64 {
+	chainIDs := []uint64{}
+	for _, v := range ids {
+		if id, ok := v.(float64); ok && id >= 0 {
+			chainIDs = append(chainIDs, uint64(id))
+		}
+	}
+	return chainIDs
+}
+
 func (self *WsServer) initTlsListen() (net.Listener, error) {
 
 	certPath := cfg.DefConfig.Ws.HttpCertPath
@@ -46,6 +46,7 @@ import (
 	hserver "github.com/polynetwork/poly/http/base/actor"
 	"github.com/polynetwork/poly/http/jsonrpc"
 	"github.com/polynetwork/poly/http/localrpc"
+	"github.com/polynetwork/poly/http/metrics"
 	"github.com/polynetwork/poly/http/nodeinfo"
 	"github.com/polynetwork/poly/http/restful"
 	"github.com/polynetwork/poly/http/websocket"
@@ -72,11 +73,14 @@ func setupAPP() *cli.App {
 		cmd.InfoCommand,
 		cmd.ImportCommand,
 		cmd.ExportCommand,
+		cmd.SnapshotCommand,
 		cmd.SigTxCommand,
 		cmd.MultiSigAddrCommand,
 		cmd.MultiSigTxCommand,
 		cmd.SendTxCommand,
 		cmd.ShowTxCommand,
+		cmd.InvokeCommand,
+		cmd.GovernanceCommand,
 	}
 	app.Flags = []cli.Flag{
 		//common setting
@@ -91,6 +95,8 @@ func setupAPP() *cli.App {
 		//consensus setting
 		utils.EnableConsensusFlag,
 		utils.MaxTxInBlockFlag,
+		utils.TxSelectPolicyFlag,
+		utils.MaxTxPerRelayerInBlockFlag,
 		//txpool setting
 		utils.TxpoolPreExecDisableFlag,
 		utils.DisableBroadcastNetTxFlag,
@@ -120,6 +126,13 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//prune setting
+		utils.EnablePruneFlag,
+		utils.PruneDepthFlag,
+		utils.StateHistoryDepthFlag,
+		//metrics setting
+		utils.MetricsEnableFlag,
+		utils.MetricsPortFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -187,6 +200,7 @@ func startOntology(ctx *cli.Context) {
 	initRestful(ctx)
 	initWs(ctx)
 	initNodeInfo(ctx, p2pSvr)
+	initMetrics(ctx, p2pSvr)
 
 	go logCurrBlockHeight()
 	waitToExit()
@@ -405,6 +419,15 @@ func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	log.Infof("Nodeinfo init success")
 }
 
+func initMetrics(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
+	if !config.DefConfig.Metrics.EnableMetrics {
+		return
+	}
+	go metrics.StartServer(p2pSvr.GetNetWork())
+
+	log.Infof("Metrics init success")
+}
+
 func logCurrBlockHeight() {
 	ticker := time.NewTicker(config.DEFAULT_GEN_BLOCK_TIME * time.Second)
 	for {
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/polynetwork/poly/common"
+)
+
+// HashStoreTail is the part of hash store needed by the inclusion proofs of the leaves from Start in the tree of
+// TreeSize. It's the roots of the subtrees of the first Start leaves, followed by the hashes stored for the later leaves
+type HashStoreTail struct {
+	Start    uint32
+	TreeSize uint32
+	Hashes   []common.Uint256
+}
+
+// HashStoreTail returns the hash store tail for the inclusion proofs of the leaves from start
+func (self *CompactMerkleTree) HashStoreTail(start uint32) (*HashStoreTail, error) {
+	if start > self.treeSize {
+		return nil, errors.New("wrong parameters")
+	} else if self.hashStore == nil {
+		return nil, errors.New("hash store not available")
+	}
+	peaks := getSubTreePos(start)
+	from, to := getStoredHashNum(start), getStoredHashNum(self.treeSize)
+	tail := &HashStoreTail{
+		Start:    start,
+		TreeSize: self.treeSize,
+		Hashes:   make([]common.Uint256, 0, int64(len(peaks))+to-from),
+	}
+	for _, pos := range peaks {
+		hash, err := self.hashStore.GetHash(pos - 1)
+		if err != nil {
+			return nil, fmt.Errorf("get hash at %d error: %s", pos-1, err)
+		}
+		tail.Hashes = append(tail.Hashes, hash)
+	}
+	for pos := from; pos < to; pos++ {
+		hash, err := self.hashStore.GetHash(uint32(pos))
+		if err != nil {
+			return nil, fmt.Errorf("get hash at %d error: %s", pos, err)
+		}
+		tail.Hashes = append(tail.Hashes, hash)
+	}
+	return tail, nil
+}
+
+// Verify checks the tail by appending the stored leaves again to the subtree roots of the first Start leaves,
+// the stored hashes and the resulting subtree roots should be the same as the tail and the hashes of compact tree
+func (self *HashStoreTail) Verify(tree_size uint32, hashes []common.Uint256) error {
+	if self.TreeSize != tree_size || self.Start > self.TreeSize {
+		return fmt.Errorf("tail of tree size %d from %d mismatch tree size %d", self.TreeSize, self.Start, tree_size)
+	}
+	npeaks := int(countBit(self.Start))
+	if int64(len(self.Hashes)) != int64(npeaks)+getStoredHashNum(self.TreeSize)-getStoredHashNum(self.Start) {
+		return fmt.Errorf("tail hash count %d mismatch", len(self.Hashes))
+	}
+	peaks := make([]common.Uint256, npeaks)
+	copy(peaks, self.Hashes[:npeaks])
+	stored := self.Hashes[npeaks:]
+	store := &memHashStore{}
+	tree := NewTree(self.Start, peaks, store)
+	for len(store.hashes) < len(stored) {
+		tree.appendHash(stored[len(store.hashes)])
+	}
+	if len(store.hashes) != len(stored) || tree.TreeSize() != tree_size {
+		return errors.New("tail stored hashes mismatch tree size")
+	}
+	for i := range stored {
+		if store.hashes[i] != stored[i] {
+			return fmt.Errorf("tail stored hash at %d mismatch", i)
+		}
+	}
+	if len(tree.hashes) != len(hashes) {
+		return errors.New("tail subtree roots mismatch")
+	}
+	for i := range hashes {
+		if tree.hashes[i] != hashes[i] {
+			return errors.New("tail subtree roots mismatch")
+		}
+	}
+	return nil
+}
+
+// WriteHashStoreTail creates the hash store file of the tail, the hashes not in the tail are left empty
+func WriteHashStoreTail(name string, tail *HashStoreTail) error {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = f.Truncate(getStoredHashNum(tail.TreeSize) * int64(common.UINT256_SIZE))
+	if err != nil {
+		return err
+	}
+	peaks := getSubTreePos(tail.Start)
+	for i, pos := range peaks {
+		_, err = f.WriteAt(tail.Hashes[i][:], int64(pos-1)*int64(common.UINT256_SIZE))
+		if err != nil {
+			return err
+		}
+	}
+	buf := make([]byte, 0, (len(tail.Hashes)-len(peaks))*common.UINT256_SIZE)
+	for _, hash := range tail.Hashes[len(peaks):] {
+		buf = append(buf, hash[:]...)
+	}
+	_, err = f.WriteAt(buf, getStoredHashNum(tail.Start)*int64(common.UINT256_SIZE))
+	if err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func (self *HashStoreTail) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(self.Start)
+	sink.WriteUint32(self.TreeSize)
+	sink.WriteVarUint(uint64(len(self.Hashes)))
+	for _, hash := range self.Hashes {
+		sink.WriteHash(hash)
+	}
+}
+
+func (self *HashStoreTail) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	self.Start, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	self.TreeSize, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	n, eof := source.NextVarUint()
+	if eof || n > source.Len()/common.UINT256_SIZE {
+		return io.ErrUnexpectedEOF
+	}
+	self.Hashes = make([]common.Uint256, 0, n)
+	for i := uint64(0); i < n; i++ {
+		hash, _ := source.NextHash()
+		self.Hashes = append(self.Hashes, hash)
+	}
+	return nil
+}
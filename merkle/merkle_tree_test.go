@@ -324,3 +324,44 @@ func TestAudit(t *testing.T) {
 		assert.Equal(t, []byte(fmt.Sprintf("%d", i)), value)
 	}
 }
+
+func TestHashStoreTail(t *testing.T) {
+	store, _ := NewFileHashStore("merkletree.db", 0)
+	defer func() { os.Remove("merkletree.db") }()
+	tree := NewTree(0, nil, store)
+	for i := uint32(0); i < 13; i++ {
+		tree.Append([]byte{byte(i + 1)})
+	}
+	tail, err := tree.HashStoreTail(5)
+	assert.Nil(t, err)
+
+	sink := common.NewZeroCopySink(nil)
+	tail.Serialization(sink)
+	decoded := new(HashStoreTail)
+	assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, tail, decoded)
+	assert.Nil(t, decoded.Verify(tree.TreeSize(), tree.Hashes()))
+	assert.NotNil(t, decoded.Verify(tree.TreeSize()-1, tree.Hashes()))
+	decoded.Hashes[len(decoded.Hashes)-2][0] ^= 1
+	assert.NotNil(t, decoded.Verify(tree.TreeSize(), tree.Hashes()))
+
+	assert.Nil(t, WriteHashStoreTail("merkletail.db", tail))
+	defer func() { os.Remove("merkletail.db") }()
+	tailStore, err := NewFileHashStore("merkletail.db", tree.TreeSize())
+	assert.Nil(t, err)
+	tailTree := NewTree(tree.TreeSize(), append([]common.Uint256{}, tree.Hashes()...), tailStore)
+	for i := uint32(13); i < 20; i++ {
+		tree.Append([]byte{byte(i + 1)})
+		tailTree.Append([]byte{byte(i + 1)})
+	}
+	assert.Equal(t, tree.Root(), tailTree.Root())
+	for n := uint32(6); n <= 20; n++ {
+		for m := uint32(5); m < n; m++ {
+			expected, err := tree.MerkleInclusionLeafPath([]byte{byte(m + 1)}, m, n)
+			assert.Nil(t, err)
+			path, err := tailTree.MerkleInclusionLeafPath([]byte{byte(m + 1)}, m, n)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, path)
+		}
+	}
+}
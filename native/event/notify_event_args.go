@@ -19,6 +19,9 @@
 package event
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/polynetwork/poly/common"
 )
 
@@ -27,6 +30,20 @@ const (
 	CONTRACT_STATE_SUCCESS byte = 1
 )
 
+//error codes of failed transactions, persisted with the execute notify
+const (
+	CONTRACT_ERROR_NONE             uint32 = 0 //transaction executed successfully
+	CONTRACT_ERROR_EXECUTE          uint32 = 1 //native contract method returned an error
+	CONTRACT_ERROR_SERVICE          uint32 = 2 //failed to create native service for the transaction
+	CONTRACT_ERROR_INVALID_INVOKE   uint32 = 3 //invoke code of the transaction can not be decoded
+	CONTRACT_ERROR_UNKNOWN_CONTRACT uint32 = 4 //native contract address is not registered
+	CONTRACT_ERROR_UNKNOWN_METHOD   uint32 = 5 //native contract does not support the method
+	CONTRACT_ERROR_VERIFY           uint32 = 6 //header or cross chain proof of side chain failed to verify
+	CONTRACT_ERROR_DUPLICATE        uint32 = 7 //cross chain transaction of side chain is already imported
+	CONTRACT_ERROR_NOT_REGISTERED   uint32 = 8 //side chain is not registered
+	CONTRACT_ERROR_CHAIN_BLACKED    uint32 = 9 //side chain is blacked
+)
+
 // NotifyEventInfo describe smart contract event notify info struct
 type NotifyEventInfo struct {
 	ContractAddress common.Address
@@ -38,4 +55,43 @@ type ExecuteNotify struct {
 	State       byte
 	GasConsumed uint64
 	Notify      []*NotifyEventInfo
+	ErrorCode   uint32 `json:",omitempty"`
+	Error       string `json:",omitempty"`
+}
+
+//SetError record the failure reason of the transaction, errors not created by NewExecuteError are
+//recorded with CONTRACT_ERROR_EXECUTE
+func (this *ExecuteNotify) SetError(err error) {
+	if err == nil {
+		return
+	}
+	this.ErrorCode = ErrorCode(err)
+	this.Error = err.Error()
+}
+
+//ErrorCode get the code of the ExecuteError wrapped in err, it is CONTRACT_ERROR_EXECUTE if there is none
+func ErrorCode(err error) uint32 {
+	var e *ExecuteError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CONTRACT_ERROR_EXECUTE
+}
+
+//ExecuteError is the error of executing a transaction with an error code
+type ExecuteError struct {
+	Code uint32
+	Err  error
+}
+
+func NewExecuteError(code uint32, format string, a ...interface{}) *ExecuteError {
+	return &ExecuteError{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+func (this *ExecuteError) Error() string {
+	return this.Err.Error()
+}
+
+func (this *ExecuteError) Unwrap() error {
+	return this.Err
 }
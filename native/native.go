@@ -57,6 +57,8 @@ type NativeService struct {
 	crossHashes   []common.Uint256
 	contexts      []common.Address
 	preExec       bool
+	replay        bool
+	tracer        *Tracer
 }
 
 func NewNativeService(cacheDB *storage.CacheDB, tx *types.Transaction,
@@ -79,6 +81,21 @@ func NewNativeService(cacheDB *storage.CacheDB, tx *types.Transaction,
 	return service, nil
 }
 
+//SetTracer trace the native calls and storage access of the transaction, nil to disable tracing
+func (this *NativeService) SetTracer(tracer *Tracer) {
+	this.tracer = tracer
+	if tracer == nil {
+		this.cacheDB.SetTracer(nil)
+	} else {
+		this.cacheDB.SetTracer(tracer)
+	}
+}
+
+//SetReplay mark the transaction as re-executed for tracing, it was already executed in the block
+func (this *NativeService) SetReplay(replay bool) {
+	this.replay = replay
+}
+
 func (this *NativeService) Register(methodName string, handler Handler) {
 	this.serviceMap[methodName] = handler
 }
@@ -86,17 +103,28 @@ func (this *NativeService) Register(methodName string, handler Handler) {
 func (this *NativeService) Invoke() (interface{}, error) {
 	invokeParam := new(states.ContractInvokeParam)
 	if err := invokeParam.Deserialization(common.NewZeroCopySource(this.input)); err != nil {
-		return nil, err
+		return nil, event.NewExecuteError(event.CONTRACT_ERROR_INVALID_INVOKE, "[Invoke] Deserialize invoke param error:%s", err)
+	}
+	if this.tracer == nil {
+		return this.invoke(invokeParam)
 	}
+	this.tracer.enter(invokeParam.Address, invokeParam.Method)
+	result, err := this.invoke(invokeParam)
+	this.tracer.exit(err)
+	return result, err
+}
+
+func (this *NativeService) invoke(invokeParam *states.ContractInvokeParam) (interface{}, error) {
 	services, ok := Contracts[invokeParam.Address]
 	if !ok {
-		return false, fmt.Errorf("[Invoke] Native contract address %x haven't been registered.", invokeParam.Address)
+		return false, event.NewExecuteError(event.CONTRACT_ERROR_UNKNOWN_CONTRACT,
+			"[Invoke] Native contract address %x haven't been registered.", invokeParam.Address)
 	}
 	services(this)
 	service, ok := this.serviceMap[invokeParam.Method]
 	if !ok {
-		return false, fmt.Errorf("[Invoke] Native contract %x doesn't support this function %s.",
-			invokeParam.Address, invokeParam.Method)
+		return false, event.NewExecuteError(event.CONTRACT_ERROR_UNKNOWN_METHOD,
+			"[Invoke] Native contract %x doesn't support this function %s.", invokeParam.Address, invokeParam.Method)
 	}
 	args := this.input
 	this.input = invokeParam.Args
@@ -108,13 +136,16 @@ func (this *NativeService) Invoke() (interface{}, error) {
 		return err, nil
 	}
 	result, err := service(this)
+	this.PopContext()
+	this.input = args
 	if err != nil {
-		return result, fmt.Errorf("[Invoke] Native serivce function execute error:%s", err)
+		//the caller gets back its own notifications if it goes on after the failed call
+		this.notifications = notifications
+		this.crossHashes = hashes
+		return result, event.NewExecuteError(event.ErrorCode(err), "[Invoke] Native serivce function execute error:%w", err)
 	}
-	this.PopContext()
 	this.notifications = append(notifications, this.notifications...)
 	this.crossHashes = append(this.crossHashes, hashes...)
-	this.input = args
 	return result, nil
 }
 
@@ -223,6 +254,14 @@ func (this *NativeService) GetNotify() []*event.NotifyEventInfo {
 	return this.notifications
 }
 
+func (this *NativeService) IsPreExec() bool {
+	return this.preExec
+}
+
+func (this *NativeService) IsReplay() bool {
+	return this.replay
+}
+
 func (this *NativeService) GetCrossHashes() []common.Uint256 {
 	return this.crossHashes
 }
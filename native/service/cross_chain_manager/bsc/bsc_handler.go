@@ -35,6 +35,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/bsc"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -46,6 +47,12 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.BSC_ROUTER, func() scom.ChainHandler {
+		return NewHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -64,7 +71,7 @@ func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.Make
 	}
 
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("eth MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("eth MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("eth MakeDepositProposal, PutDoneTx error:%s", err)
@@ -41,6 +41,12 @@ func NewBTCHandler() *BTCHandler {
 	return &BTCHandler{}
 }
 
+func init() {
+	crosscommon.RegisterChainHandler(utils.BTC_ROUTER, func() crosscommon.ChainHandler {
+		return NewBTCHandler()
+	})
+}
+
 func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 	params := new(crosscommon.MultiSignParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -176,7 +182,7 @@ func (this *BTCHandler) MakeDepositProposal(service *native.NativeService) (*cro
 	}
 
 	if err := crosscommon.CheckDoneTx(service, value.TxHash, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("MakeDepositProposal, check done transaction error:%w", err)
 	}
 
 	if err := crosscommon.PutDoneTx(service, value.TxHash, params.SourceChainID); err != nil {
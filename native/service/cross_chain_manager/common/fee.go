@@ -0,0 +1,209 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package common
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+type RecordFeePaymentParam struct {
+	Address     common.Address
+	FromChainID uint64
+	TxHash      []byte
+	Fee         *big.Int
+}
+
+func (this *RecordFeePaymentParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteAddress(this.Address)
+	sink.WriteUint64(this.FromChainID)
+	sink.WriteVarBytes(this.TxHash)
+	sink.WriteVarBytes(this.Fee.Bytes())
+}
+
+func (this *RecordFeePaymentParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextAddress()
+	if eof {
+		return fmt.Errorf("RecordFeePaymentParam deserialize address error")
+	}
+	fromChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RecordFeePaymentParam deserialize from chain id error")
+	}
+	txHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RecordFeePaymentParam deserialize tx hash error")
+	}
+	fee, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RecordFeePaymentParam deserialize fee error")
+	}
+	this.Address = address
+	this.FromChainID = fromChainID
+	this.TxHash = txHash
+	this.Fee = new(big.Int).SetBytes(fee)
+	return nil
+}
+
+type HeldTransferParam struct {
+	ToChainID uint64
+	TxHash    []byte
+}
+
+func (this *HeldTransferParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ToChainID)
+	sink.WriteVarBytes(this.TxHash)
+}
+
+func (this *HeldTransferParam) Deserialization(source *common.ZeroCopySource) error {
+	toChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("HeldTransferParam deserialize to chain id error")
+	}
+	txHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("HeldTransferParam deserialize tx hash error")
+	}
+	this.ToChainID = toChainID
+	this.TxHash = txHash
+	return nil
+}
+
+type HeldTransferList struct {
+	Transfers []*ToMerkleValue
+}
+
+func (this *HeldTransferList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Transfers)))
+	for _, v := range this.Transfers {
+		v.Serialization(sink)
+	}
+}
+
+func (this *HeldTransferList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeldTransferList deserialize length error")
+	}
+	transfers := make([]*ToMerkleValue, 0)
+	for i := uint64(0); i < n; i++ {
+		transfer := new(ToMerkleValue)
+		if err := transfer.Deserialization(source); err != nil {
+			return fmt.Errorf("HeldTransferList deserialize no.%d transfer error: %v", i+1, err)
+		}
+		transfers = append(transfers, transfer)
+	}
+	this.Transfers = transfers
+	return nil
+}
+
+//Get the fee paid for the transfer of source chain tx, the fee is zero if no payment is recorded
+func GetFeePayment(native *native.NativeService, fromChainID uint64, txHash []byte) (*big.Int, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(FEE_PAYMENT), utils.GetUint64Bytes(fromChainID), txHash))
+	if err != nil {
+		return nil, fmt.Errorf("GetFeePayment, get fee payment store error: %v", err)
+	}
+	if store == nil {
+		return new(big.Int), nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetFeePayment, deserialize from raw storage item err:%v", err)
+	}
+	return new(big.Int).SetBytes(value), nil
+}
+
+func PutFeePayment(native *native.NativeService, fromChainID uint64, txHash []byte, fee *big.Int) {
+	contract := utils.CrossChainManagerContractAddress
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(FEE_PAYMENT), utils.GetUint64Bytes(fromChainID), txHash),
+		states.GenRawStorageItem(fee.Bytes()))
+}
+
+//The held transfer is keyed by the target chain id and the poly tx hash, the same as its request
+func heldTransferKey(toChainID uint64, txHash []byte) []byte {
+	return append(utils.GetUint64Bytes(toChainID), txHash...)
+}
+
+func GetHeldTransfer(native *native.NativeService, toChainID uint64, txHash []byte) (*ToMerkleValue, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(HELD_TRANSFER), heldTransferKey(toChainID, txHash)))
+	if err != nil {
+		return nil, fmt.Errorf("GetHeldTransfer, get held transfer store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetHeldTransfer, deserialize from raw storage item err:%v", err)
+	}
+	merkleValue := new(ToMerkleValue)
+	if err := merkleValue.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetHeldTransfer, deserialize held transfer error: %v", err)
+	}
+	return merkleValue, nil
+}
+
+func PutHeldTransfer(native *native.NativeService, merkleValue *ToMerkleValue) error {
+	contract := utils.CrossChainManagerContractAddress
+	key := heldTransferKey(merkleValue.MakeTxParam.ToChainID, merkleValue.TxHash)
+	sink := common.NewZeroCopySink(nil)
+	merkleValue.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(HELD_TRANSFER), key), states.GenRawStorageItem(sink.Bytes()))
+	return utils.AddIndexKey(native, contract, HELD_TRANSFER, key)
+}
+
+func DeleteHeldTransfer(native *native.NativeService, toChainID uint64, txHash []byte) error {
+	contract := utils.CrossChainManagerContractAddress
+	key := heldTransferKey(toChainID, txHash)
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(HELD_TRANSFER), key))
+	return utils.RemoveIndexKey(native, contract, HELD_TRANSFER, key)
+}
+
+//List the held transfers in the order of target chain id and poly tx hash
+func ListHeldTransfers(native *native.NativeService) ([]*ToMerkleValue, error) {
+	keys, err := utils.GetIndexKeys(native, utils.CrossChainManagerContractAddress, HELD_TRANSFER, 8+common.UINT256_SIZE)
+	if err != nil {
+		return nil, fmt.Errorf("ListHeldTransfers, GetIndexKeys error: %v", err)
+	}
+	transfers := make([]*ToMerkleValue, 0, len(keys))
+	for _, key := range keys {
+		merkleValue, err := GetHeldTransfer(native, utils.GetBytesUint64(key[:8]), key[8:])
+		if err != nil {
+			return nil, err
+		}
+		if merkleValue != nil {
+			transfers = append(transfers, merkleValue)
+		}
+	}
+	return transfers, nil
+}
+
+func NotifyHoldTransfer(native *native.NativeService, fromChainID, toChainID uint64, txHash string, fee, paid *big.Int) {
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{NOTIFY_HOLD_TRANSFER, fromChainID, toChainID, txHash, native.GetHeight(), fee.String(), paid.String()},
+		})
+}
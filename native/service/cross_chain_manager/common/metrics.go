@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/polynetwork/poly/common/metrics"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var importExTransfers = metrics.NewCounterVec("poly_ccm_import_ex_transfer_total",
+	"Count of the cross chain transfers imported from the side chains", "chain_id", "result")
+
+//Count the result of importing a cross chain transfer from the side chain, pre-executions and replays for tracing are
+//not counted and the side chains not registered are labelled unknown
+func CountImportExTransfer(native *native.NativeService, chainID uint64, err error) {
+	if native.IsPreExec() || native.IsReplay() {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	importExTransfers.WithLabelValues(utils.ChainLabel(native, chainID), result).Inc()
+}
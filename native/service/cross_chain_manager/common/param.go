@@ -31,6 +31,18 @@ const (
 	RECONSTRUCT_RIPPLE_TX      = "ReconstructRippleTx"
 	BLACK_CHAIN                = "BlackChain"
 	WHITE_CHAIN                = "WhiteChain"
+	IS_CHAIN_BLACKED           = "isChainBlacked"
+	RECORD_FEE_PAYMENT         = "recordFeePayment"
+	RELEASE_HELD_TRANSFER      = "releaseHeldTransfer"
+	LIST_HELD_TRANSFERS        = "listHeldTransfers"
+	SET_RATE_LIMIT             = "setRateLimit"
+	LIST_RATE_LIMITS           = "listRateLimits"
+	RELEASE_PENDING_TRANSFER   = "releasePendingTransfer"
+	REJECT_PENDING_TRANSFER    = "rejectPendingTransfer"
+	LIST_PENDING_TRANSFERS     = "listPendingTransfers"
+	SET_QUARANTINE_CONFIG      = "setQuarantineConfig"
+	LIST_QUARANTINE_CONFIGS    = "listQuarantineConfigs"
+	GET_ASSET_SUPPLY           = "getAssetSupply"
 
 	BLACKED_CHAIN = "BlackedChain"
 )
@@ -40,11 +52,23 @@ var (
 
 	KEY_PREFIX_BTC_VOTE = "btcVote"
 	REQUEST             = "request"
+	REQUEST_HEIGHT      = "reqHeight"
 	DONE_TX             = "doneTx"
 	MULTISIGN_INFO      = "multisignInfo"
 	RIPPLE_TX_INFO      = "rippleTxInfo"
-
-	NOTIFY_MAKE_PROOF = "makeProof"
+	FEE_PAYMENT         = "feePayment"
+	HELD_TRANSFER       = "heldTransfer"
+	RATE_LIMIT          = "rateLimit"
+	RATE_LIMIT_USAGE    = "rateLimitUsage"
+	PENDING_TRANSFER    = "pendingTransfer"
+	QUARANTINE_CONFIG   = "quarantineConfig"
+	WHITE_CHAIN_HEIGHT  = "whiteChainHeight"
+	ASSET_SUPPLY        = "assetSupply"
+
+	NOTIFY_MAKE_PROOF           = "makeProof"
+	NOTIFY_REPLENISH_MAKE_PROOF = "replenishMakeProof"
+	NOTIFY_HOLD_TRANSFER        = "holdTransfer"
+	NOTIFY_PEND_TRANSFER        = "pendTransfer"
 )
 
 type ChainHandler interface {
@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package common
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Reasons of the transfers put to pending
+const (
+	PENDING_REASON_RATE_LIMIT  = "rateLimit"
+	PENDING_REASON_WHITELISTED = "whitelisted"
+	PENDING_REASON_METHOD      = "suspiciousMethod"
+	PENDING_REASON_AMOUNT      = "amountThreshold"
+)
+
+//The transfer waiting for the consensus nodes to release or reject, it is keyed by the poly tx hash
+type PendingTransfer struct {
+	Reason        string
+	Height        uint32
+	ToMerkleValue *ToMerkleValue
+}
+
+func (this *PendingTransfer) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.Reason)
+	sink.WriteUint32(this.Height)
+	this.ToMerkleValue.Serialization(sink)
+}
+
+func (this *PendingTransfer) Deserialization(source *common.ZeroCopySource) error {
+	reason, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("PendingTransfer deserialize reason error")
+	}
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("PendingTransfer deserialize height error")
+	}
+	merkleValue := new(ToMerkleValue)
+	if err := merkleValue.Deserialization(source); err != nil {
+		return fmt.Errorf("PendingTransfer deserialize merkle value error: %v", err)
+	}
+	this.Reason = reason
+	this.Height = height
+	this.ToMerkleValue = merkleValue
+	return nil
+}
+
+type PendingTransferList struct {
+	Transfers []*PendingTransfer
+}
+
+func (this *PendingTransferList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Transfers)))
+	for _, v := range this.Transfers {
+		v.Serialization(sink)
+	}
+}
+
+func (this *PendingTransferList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("PendingTransferList deserialize length error")
+	}
+	transfers := make([]*PendingTransfer, 0)
+	for i := uint64(0); i < n; i++ {
+		transfer := new(PendingTransfer)
+		if err := transfer.Deserialization(source); err != nil {
+			return fmt.Errorf("PendingTransferList deserialize no.%d transfer error: %v", i+1, err)
+		}
+		transfers = append(transfers, transfer)
+	}
+	this.Transfers = transfers
+	return nil
+}
+
+type VotePendingTransferParam struct {
+	Address common.Address
+	TxHash  []byte
+}
+
+func (this *VotePendingTransferParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteAddress(this.Address)
+	sink.WriteVarBytes(this.TxHash)
+}
+
+func (this *VotePendingTransferParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextAddress()
+	if eof {
+		return fmt.Errorf("VotePendingTransferParam deserialize address error")
+	}
+	txHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("VotePendingTransferParam deserialize tx hash error")
+	}
+	this.Address = address
+	this.TxHash = txHash
+	return nil
+}
+
+func GetPendingTransfer(native *native.NativeService, txHash []byte) (*PendingTransfer, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PENDING_TRANSFER), txHash))
+	if err != nil {
+		return nil, fmt.Errorf("GetPendingTransfer, get pending transfer store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetPendingTransfer, deserialize from raw storage item err:%v", err)
+	}
+	transfer := new(PendingTransfer)
+	if err := transfer.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetPendingTransfer, deserialize pending transfer error: %v", err)
+	}
+	return transfer, nil
+}
+
+func PutPendingTransfer(native *native.NativeService, transfer *PendingTransfer) error {
+	contract := utils.CrossChainManagerContractAddress
+	txHash := transfer.ToMerkleValue.TxHash
+	sink := common.NewZeroCopySink(nil)
+	transfer.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PENDING_TRANSFER), txHash), states.GenRawStorageItem(sink.Bytes()))
+	return utils.AddIndexKey(native, contract, PENDING_TRANSFER, txHash)
+}
+
+func DeletePendingTransfer(native *native.NativeService, txHash []byte) error {
+	contract := utils.CrossChainManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PENDING_TRANSFER), txHash))
+	return utils.RemoveIndexKey(native, contract, PENDING_TRANSFER, txHash)
+}
+
+//List the pending transfers in the order of poly tx hash
+func ListPendingTransfers(native *native.NativeService) ([]*PendingTransfer, error) {
+	keys, err := utils.GetIndexKeys(native, utils.CrossChainManagerContractAddress, PENDING_TRANSFER, common.UINT256_SIZE)
+	if err != nil {
+		return nil, fmt.Errorf("ListPendingTransfers, GetIndexKeys error: %v", err)
+	}
+	transfers := make([]*PendingTransfer, 0, len(keys))
+	for _, key := range keys {
+		transfer, err := GetPendingTransfer(native, key)
+		if err != nil {
+			return nil, err
+		}
+		if transfer != nil {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Amount threshold of the asset, AssetHash is the asset hash on target chain, and Amount is in the decimals of the asset
+//if it is registered, otherwise in the decimals on target chain
+type AssetThreshold struct {
+	AssetHash []byte
+	Amount    *big.Int
+}
+
+//Rules to put the transfers to target chain into pending, the transfer is pending if it is in the period of blocks
+//since the target chain is registered or whitelisted, or it calls one of the methods, or its amount is over the threshold
+//of its asset
+type QuarantineConfig struct {
+	ChainID          uint64
+	WhitelistPeriod  uint32
+	Methods          []string
+	AmountThresholds []*AssetThreshold
+}
+
+func (this *QuarantineConfig) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ChainID)
+	sink.WriteUint32(this.WhitelistPeriod)
+	sink.WriteVarUint(uint64(len(this.Methods)))
+	for _, method := range this.Methods {
+		sink.WriteString(method)
+	}
+	sink.WriteVarUint(uint64(len(this.AmountThresholds)))
+	for _, threshold := range this.AmountThresholds {
+		sink.WriteVarBytes(threshold.AssetHash)
+		sink.WriteVarBytes(threshold.Amount.Bytes())
+	}
+}
+
+func (this *QuarantineConfig) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("QuarantineConfig deserialize chain id error")
+	}
+	whitelistPeriod, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("QuarantineConfig deserialize whitelist period error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("QuarantineConfig deserialize length of methods error")
+	}
+	methods := make([]string, 0)
+	for i := uint64(0); i < n; i++ {
+		method, eof := source.NextString()
+		if eof {
+			return fmt.Errorf("QuarantineConfig deserialize no.%d method error", i+1)
+		}
+		methods = append(methods, method)
+	}
+	n, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("QuarantineConfig deserialize length of amount thresholds error")
+	}
+	thresholds := make([]*AssetThreshold, 0)
+	for i := uint64(0); i < n; i++ {
+		assetHash, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("QuarantineConfig deserialize no.%d asset hash error", i+1)
+		}
+		amount, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("QuarantineConfig deserialize no.%d amount threshold error", i+1)
+		}
+		thresholds = append(thresholds, &AssetThreshold{AssetHash: assetHash, Amount: new(big.Int).SetBytes(amount)})
+	}
+	this.ChainID = chainID
+	this.WhitelistPeriod = whitelistPeriod
+	this.Methods = methods
+	this.AmountThresholds = thresholds
+	return nil
+}
+
+//Check if the config has no rule
+func (this *QuarantineConfig) IsEmpty() bool {
+	return this.WhitelistPeriod == 0 && len(this.Methods) == 0 && len(this.AmountThresholds) == 0
+}
+
+//Get the amount threshold of the asset, it is nil if the asset has no threshold
+func (this *QuarantineConfig) GetAmountThreshold(assetHash []byte) *big.Int {
+	for _, threshold := range this.AmountThresholds {
+		if bytes.Equal(threshold.AssetHash, assetHash) {
+			return threshold.Amount
+		}
+	}
+	return nil
+}
+
+func (this *QuarantineConfig) HasMethod(method string) bool {
+	for _, m := range this.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+type QuarantineConfigList struct {
+	Configs []*QuarantineConfig
+}
+
+func (this *QuarantineConfigList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Configs)))
+	for _, v := range this.Configs {
+		v.Serialization(sink)
+	}
+}
+
+func (this *QuarantineConfigList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("QuarantineConfigList deserialize length error")
+	}
+	configs := make([]*QuarantineConfig, 0)
+	for i := uint64(0); i < n; i++ {
+		config := new(QuarantineConfig)
+		if err := config.Deserialization(source); err != nil {
+			return fmt.Errorf("QuarantineConfigList deserialize no.%d config error: %v", i+1, err)
+		}
+		configs = append(configs, config)
+	}
+	this.Configs = configs
+	return nil
+}
+
+func GetQuarantineConfig(native *native.NativeService, chainID uint64) (*QuarantineConfig, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(QUARANTINE_CONFIG), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("GetQuarantineConfig, get quarantine config store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetQuarantineConfig, deserialize from raw storage item err:%v", err)
+	}
+	config := new(QuarantineConfig)
+	if err := config.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetQuarantineConfig, deserialize quarantine config error: %v", err)
+	}
+	return config, nil
+}
+
+func PutQuarantineConfig(native *native.NativeService, config *QuarantineConfig) error {
+	contract := utils.CrossChainManagerContractAddress
+	chainIDBytes := utils.GetUint64Bytes(config.ChainID)
+	sink := common.NewZeroCopySink(nil)
+	config.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(QUARANTINE_CONFIG), chainIDBytes), states.GenRawStorageItem(sink.Bytes()))
+	return utils.AddIndexKey(native, contract, QUARANTINE_CONFIG, chainIDBytes)
+}
+
+func DeleteQuarantineConfig(native *native.NativeService, chainID uint64) error {
+	contract := utils.CrossChainManagerContractAddress
+	chainIDBytes := utils.GetUint64Bytes(chainID)
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(QUARANTINE_CONFIG), chainIDBytes))
+	return utils.RemoveIndexKey(native, contract, QUARANTINE_CONFIG, chainIDBytes)
+}
+
+//List the quarantine configs in the order of chain id
+func ListQuarantineConfigs(native *native.NativeService) ([]*QuarantineConfig, error) {
+	keys, err := utils.GetIndexKeys(native, utils.CrossChainManagerContractAddress, QUARANTINE_CONFIG, 8)
+	if err != nil {
+		return nil, fmt.Errorf("ListQuarantineConfigs, GetIndexKeys error: %v", err)
+	}
+	configs := make([]*QuarantineConfig, 0, len(keys))
+	for _, key := range keys {
+		config, err := GetQuarantineConfig(native, utils.GetBytesUint64(key))
+		if err != nil {
+			return nil, err
+		}
+		if config != nil {
+			configs = append(configs, config)
+		}
+	}
+	return configs, nil
+}
+
+//Check if the height at which the chain is registered or whitelisted is recorded, it is not recorded before the activation height
+func WhiteChainHeightEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetQuarantineHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+//Record the height at which the chain is registered or whitelisted
+func PutWhiteChainHeight(native *native.NativeService, chainID uint64, height uint32) {
+	contract := utils.CrossChainManagerContractAddress
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(WHITE_CHAIN_HEIGHT), utils.GetUint64Bytes(chainID)),
+		states.GenRawStorageItem(utils.GetUint32Bytes(height)))
+}
+
+//Get the height at which the chain is registered or whitelisted, the result is false if it is not recorded
+func GetWhiteChainHeight(native *native.NativeService, chainID uint64) (uint32, bool, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(WHITE_CHAIN_HEIGHT), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return 0, false, fmt.Errorf("GetWhiteChainHeight, get white chain height store error: %v", err)
+	}
+	if store == nil {
+		return 0, false, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, false, fmt.Errorf("GetWhiteChainHeight, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint32(value), true, nil
+}
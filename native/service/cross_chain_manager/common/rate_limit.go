@@ -0,0 +1,293 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package common
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Rate limit of the transfers from source chain to the target contract on target chain, in the rolling window of poly
+//blocks. The amount is only counted for the transfers to the lock proxy bound, and MaxAmount is in the decimals of the
+//asset if it is registered, otherwise in the decimals on target chain. The limit of count or amount is not checked if it is zero
+type RateLimit struct {
+	FromChainID       uint64
+	ToChainID         uint64
+	ToContractAddress []byte
+	Window            uint32
+	MaxCount          uint64
+	MaxAmount         *big.Int
+}
+
+func (this *RateLimit) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.FromChainID)
+	sink.WriteUint64(this.ToChainID)
+	sink.WriteVarBytes(this.ToContractAddress)
+	sink.WriteUint32(this.Window)
+	sink.WriteUint64(this.MaxCount)
+	sink.WriteVarBytes(this.MaxAmount.Bytes())
+}
+
+func (this *RateLimit) Deserialization(source *common.ZeroCopySource) error {
+	fromChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize from chain id error")
+	}
+	toChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize to chain id error")
+	}
+	toContractAddress, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize to contract address error")
+	}
+	window, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize window error")
+	}
+	maxCount, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize max count error")
+	}
+	maxAmount, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize max amount error")
+	}
+	this.FromChainID = fromChainID
+	this.ToChainID = toChainID
+	this.ToContractAddress = toContractAddress
+	this.Window = window
+	this.MaxCount = maxCount
+	this.MaxAmount = new(big.Int).SetBytes(maxAmount)
+	return nil
+}
+
+type RateLimitList struct {
+	RateLimits []*RateLimit
+}
+
+func (this *RateLimitList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.RateLimits)))
+	for _, v := range this.RateLimits {
+		v.Serialization(sink)
+	}
+}
+
+func (this *RateLimitList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitList deserialize length error")
+	}
+	rateLimits := make([]*RateLimit, 0)
+	for i := uint64(0); i < n; i++ {
+		rateLimit := new(RateLimit)
+		if err := rateLimit.Deserialization(source); err != nil {
+			return fmt.Errorf("RateLimitList deserialize no.%d rate limit error: %v", i+1, err)
+		}
+		rateLimits = append(rateLimits, rateLimit)
+	}
+	this.RateLimits = rateLimits
+	return nil
+}
+
+type RateLimitRecord struct {
+	Height uint32
+	Count  uint64
+	Amount *big.Int
+}
+
+//The transfers counted by the rate limit, one record for each poly block in the window
+type RateLimitUsage struct {
+	Records []*RateLimitRecord
+}
+
+func (this *RateLimitUsage) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Records)))
+	for _, v := range this.Records {
+		sink.WriteUint32(v.Height)
+		sink.WriteUint64(v.Count)
+		sink.WriteVarBytes(v.Amount.Bytes())
+	}
+}
+
+func (this *RateLimitUsage) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitUsage deserialize length error")
+	}
+	records := make([]*RateLimitRecord, 0)
+	for i := uint64(0); i < n; i++ {
+		height, eof := source.NextUint32()
+		if eof {
+			return fmt.Errorf("RateLimitUsage deserialize no.%d height error", i+1)
+		}
+		count, eof := source.NextUint64()
+		if eof {
+			return fmt.Errorf("RateLimitUsage deserialize no.%d count error", i+1)
+		}
+		amount, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("RateLimitUsage deserialize no.%d amount error", i+1)
+		}
+		records = append(records, &RateLimitRecord{Height: height, Count: count, Amount: new(big.Int).SetBytes(amount)})
+	}
+	this.Records = records
+	return nil
+}
+
+//Remove the records out of the window ending at height
+func (this *RateLimitUsage) Prune(height, window uint32) {
+	records := make([]*RateLimitRecord, 0, len(this.Records))
+	for _, v := range this.Records {
+		if v.Height+window > height {
+			records = append(records, v)
+		}
+	}
+	this.Records = records
+}
+
+func (this *RateLimitUsage) Total() (uint64, *big.Int) {
+	count, amount := uint64(0), new(big.Int)
+	for _, v := range this.Records {
+		count += v.Count
+		amount.Add(amount, v.Amount)
+	}
+	return count, amount
+}
+
+func (this *RateLimitUsage) Add(height uint32, amount *big.Int) {
+	if l := len(this.Records); l > 0 && this.Records[l-1].Height == height {
+		this.Records[l-1].Count++
+		this.Records[l-1].Amount.Add(this.Records[l-1].Amount, amount)
+		return
+	}
+	this.Records = append(this.Records, &RateLimitRecord{Height: height, Count: 1, Amount: new(big.Int).Set(amount)})
+}
+
+//Check if the transfer of amount is allowed by the rate limit, the amount is nil if it is unknown
+func (this *RateLimitUsage) Allow(limit *RateLimit, amount *big.Int) bool {
+	count, total := this.Total()
+	if limit.MaxCount > 0 && count+1 > limit.MaxCount {
+		return false
+	}
+	if amount != nil && limit.MaxAmount.Sign() > 0 && total.Add(total, amount).Cmp(limit.MaxAmount) > 0 {
+		return false
+	}
+	return true
+}
+
+//The rate limit is keyed by the chain ids and the hash of target contract address, so that the keys have the same length
+func rateLimitKey(fromChainID, toChainID uint64, toContractAddress []byte) []byte {
+	hash := sha256.Sum256(toContractAddress)
+	return append(append(utils.GetUint64Bytes(fromChainID), utils.GetUint64Bytes(toChainID)...), hash[:]...)
+}
+
+func GetRateLimit(native *native.NativeService, fromChainID, toChainID uint64, toContractAddress []byte) (*RateLimit, error) {
+	return getRateLimitByKey(native, rateLimitKey(fromChainID, toChainID, toContractAddress))
+}
+
+func getRateLimitByKey(native *native.NativeService, key []byte) (*RateLimit, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RATE_LIMIT), key))
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimit, get rate limit store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimit, deserialize from raw storage item err:%v", err)
+	}
+	rateLimit := new(RateLimit)
+	if err := rateLimit.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetRateLimit, deserialize rate limit error: %v", err)
+	}
+	return rateLimit, nil
+}
+
+func PutRateLimit(native *native.NativeService, rateLimit *RateLimit) error {
+	contract := utils.CrossChainManagerContractAddress
+	key := rateLimitKey(rateLimit.FromChainID, rateLimit.ToChainID, rateLimit.ToContractAddress)
+	sink := common.NewZeroCopySink(nil)
+	rateLimit.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RATE_LIMIT), key), states.GenRawStorageItem(sink.Bytes()))
+	return utils.AddIndexKey(native, contract, RATE_LIMIT, key)
+}
+
+//Remove the rate limit with its usage
+func DeleteRateLimit(native *native.NativeService, fromChainID, toChainID uint64, toContractAddress []byte) error {
+	contract := utils.CrossChainManagerContractAddress
+	key := rateLimitKey(fromChainID, toChainID, toContractAddress)
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RATE_LIMIT), key))
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RATE_LIMIT_USAGE), key))
+	return utils.RemoveIndexKey(native, contract, RATE_LIMIT, key)
+}
+
+//List the rate limits in the order of source chain id and target chain id
+func ListRateLimits(native *native.NativeService) ([]*RateLimit, error) {
+	keys, err := utils.GetIndexKeys(native, utils.CrossChainManagerContractAddress, RATE_LIMIT, 16+sha256.Size)
+	if err != nil {
+		return nil, fmt.Errorf("ListRateLimits, GetIndexKeys error: %v", err)
+	}
+	rateLimits := make([]*RateLimit, 0, len(keys))
+	for _, key := range keys {
+		rateLimit, err := getRateLimitByKey(native, key)
+		if err != nil {
+			return nil, err
+		}
+		if rateLimit != nil {
+			rateLimits = append(rateLimits, rateLimit)
+		}
+	}
+	return rateLimits, nil
+}
+
+func GetRateLimitUsage(native *native.NativeService, fromChainID, toChainID uint64, toContractAddress []byte) (*RateLimitUsage, error) {
+	contract := utils.CrossChainManagerContractAddress
+	key := rateLimitKey(fromChainID, toChainID, toContractAddress)
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RATE_LIMIT_USAGE), key))
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimitUsage, get rate limit usage store error: %v", err)
+	}
+	usage := &RateLimitUsage{Records: make([]*RateLimitRecord, 0)}
+	if store == nil {
+		return usage, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimitUsage, deserialize from raw storage item err:%v", err)
+	}
+	if err := usage.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetRateLimitUsage, deserialize rate limit usage error: %v", err)
+	}
+	return usage, nil
+}
+
+func PutRateLimitUsage(native *native.NativeService, fromChainID, toChainID uint64, toContractAddress []byte, usage *RateLimitUsage) {
+	contract := utils.CrossChainManagerContractAddress
+	key := rateLimitKey(fromChainID, toChainID, toContractAddress)
+	sink := common.NewZeroCopySink(nil)
+	usage.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RATE_LIMIT_USAGE), key), states.GenRawStorageItem(sink.Bytes()))
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitUsage(t *testing.T) {
+	limit := &RateLimit{FromChainID: 2, ToChainID: 3, ToContractAddress: []byte{1}, Window: 10, MaxCount: 3, MaxAmount: big.NewInt(100)}
+	usage := &RateLimitUsage{}
+	usage.Add(1, big.NewInt(40))
+	usage.Add(1, big.NewInt(0))
+	usage.Add(5, big.NewInt(50))
+	assert.Equal(t, 2, len(usage.Records))
+
+	count, amount := usage.Total()
+	assert.Equal(t, uint64(3), count)
+	assert.Equal(t, int64(90), amount.Int64())
+	assert.False(t, usage.Allow(limit, nil))
+
+	//records of height 1 are out of the window ending at 11
+	usage.Prune(11, limit.Window)
+	assert.Equal(t, 1, len(usage.Records))
+	assert.True(t, usage.Allow(limit, nil))
+	assert.True(t, usage.Allow(limit, big.NewInt(50)))
+	assert.False(t, usage.Allow(limit, big.NewInt(51)))
+
+	sink := common.NewZeroCopySink(nil)
+	usage.Serialization(sink)
+	decoded := new(RateLimitUsage)
+	assert.NoError(t, decoded.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, usage, decoded)
+
+	sink = common.NewZeroCopySink(nil)
+	limit.Serialization(sink)
+	decodedLimit := new(RateLimit)
+	assert.NoError(t, decodedLimit.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, limit, decodedLimit)
+}
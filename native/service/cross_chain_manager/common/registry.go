@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"sort"
+)
+
+var handlers = make(map[uint64]func() ChainHandler)
+
+//Register the cross chain handler of router, router packages call it from init,
+//it must not be called after the node started
+func RegisterChainHandler(router uint64, newHandler func() ChainHandler) {
+	if newHandler == nil {
+		panic(fmt.Sprintf("RegisterChainHandler, handler of router %d is nil", router))
+	}
+	if _, ok := handlers[router]; ok {
+		panic(fmt.Sprintf("RegisterChainHandler, handler of router %d already registered", router))
+	}
+	handlers[router] = newHandler
+}
+
+func GetChainHandler(router uint64) (ChainHandler, error) {
+	newHandler, ok := handlers[router]
+	if !ok {
+		return nil, fmt.Errorf("not a supported router:%d", router)
+	}
+	return newHandler(), nil
+}
+
+//Get all routers with a cross chain handler in ascending order
+func GetChainHandlerRouters() []uint64 {
+	routers := make([]uint64, 0, len(handlers))
+	for router := range handlers {
+		routers = append(routers, router)
+	}
+	sort.Slice(routers, func(i, j int) bool {
+		return routers[i] < routers[j]
+	})
+	return routers
+}
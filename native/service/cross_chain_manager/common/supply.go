@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package common
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Supply of the asset on a chain in the decimals of asset, it is the amount locked in the lock proxy for the chains
+//locking the asset, or the amount minted for the chains minting the asset
+type AssetSupply struct {
+	ChainID uint64
+	Amount  *big.Int
+}
+
+func (this *AssetSupply) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ChainID)
+	sink.WriteVarBytes(this.Amount.Bytes())
+}
+
+func (this *AssetSupply) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("AssetSupply deserialize chain id error")
+	}
+	amount, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("AssetSupply deserialize amount error")
+	}
+	this.ChainID = chainID
+	this.Amount = new(big.Int).SetBytes(amount)
+	return nil
+}
+
+type AssetSupplyList struct {
+	AssetID  uint64
+	Supplies []*AssetSupply
+}
+
+func (this *AssetSupplyList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.AssetID)
+	sink.WriteVarUint(uint64(len(this.Supplies)))
+	for _, v := range this.Supplies {
+		v.Serialization(sink)
+	}
+}
+
+func (this *AssetSupplyList) Deserialization(source *common.ZeroCopySource) error {
+	assetID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("AssetSupplyList deserialize asset id error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("AssetSupplyList deserialize length error")
+	}
+	supplies := make([]*AssetSupply, 0)
+	for i := uint64(0); i < n; i++ {
+		supply := new(AssetSupply)
+		if err := supply.Deserialization(source); err != nil {
+			return fmt.Errorf("AssetSupplyList deserialize no.%d supply error: %v", i+1, err)
+		}
+		supplies = append(supplies, supply)
+	}
+	this.AssetID = assetID
+	this.Supplies = supplies
+	return nil
+}
+
+func assetSupplyKey(assetID, chainID uint64) []byte {
+	return utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(ASSET_SUPPLY), utils.GetUint64Bytes(assetID),
+		utils.GetUint64Bytes(chainID))
+}
+
+//Get the supply of asset tracked on the chain, it is nil if the transfers of the asset on the chain are not tracked yet
+func GetAssetSupply(native *native.NativeService, assetID, chainID uint64) (*big.Int, error) {
+	store, err := native.GetCacheDB().Get(assetSupplyKey(assetID, chainID))
+	if err != nil {
+		return nil, fmt.Errorf("GetAssetSupply, get asset supply store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetAssetSupply, deserialize from raw storage item err:%v", err)
+	}
+	return new(big.Int).SetBytes(value), nil
+}
+
+func PutAssetSupply(native *native.NativeService, assetID, chainID uint64, amount *big.Int) {
+	native.GetCacheDB().Put(assetSupplyKey(assetID, chainID), states.GenRawStorageItem(amount.Bytes()))
+}
+
+//Delete the supply of asset tracked on the chain, the supply is tracked from the registered supply again
+func DeleteAssetSupply(native *native.NativeService, assetID, chainID uint64) {
+	native.GetCacheDB().Delete(assetSupplyKey(assetID, chainID))
+}
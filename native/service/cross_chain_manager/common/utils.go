@@ -32,16 +32,67 @@ func Replace0x(s string) string {
 }
 
 func NotifyMakeProof(native *native.NativeService, fromChainID, toChainID uint64, txHash string, key string) {
+	notifyMakeProof(native, NOTIFY_MAKE_PROOF, fromChainID, toChainID, txHash, native.GetHeight(), key)
+}
+
+//Notify the makeProof of the request made at height again, the proof of request is in the cross states of the height.
+//It is emitted under a distinct name so that the status of the transfer indexed from the original makeProof is kept
+func NotifyReplenishMakeProof(native *native.NativeService, fromChainID, toChainID uint64, txHash string, height uint32, key string) {
+	notifyMakeProof(native, NOTIFY_REPLENISH_MAKE_PROOF, fromChainID, toChainID, txHash, height, key)
+}
+
+func notifyMakeProof(native *native.NativeService, name string, fromChainID, toChainID uint64, txHash string, height uint32, key string) {
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.CrossChainManagerContractAddress,
-			States:          []interface{}{NOTIFY_MAKE_PROOF, fromChainID, toChainID, txHash, native.GetHeight(), key},
+			States:          []interface{}{name, fromChainID, toChainID, txHash, height, key},
 		})
 }
 
+//Get the request to target chain made by the poly tx, the result is nil if the request is not found
+func GetRequest(native *native.NativeService, toChainID uint64, txHash []byte) ([]byte, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(REQUEST), utils.GetUint64Bytes(toChainID), txHash))
+	if err != nil {
+		return nil, fmt.Errorf("GetRequest, get request store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRequest, deserialize from raw storage item err:%v", err)
+	}
+	return value, nil
+}
+
+//Record the height at which the request is made
+func PutRequestHeight(native *native.NativeService, toChainID uint64, txHash []byte, height uint32) {
+	contract := utils.CrossChainManagerContractAddress
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(REQUEST_HEIGHT), utils.GetUint64Bytes(toChainID), txHash),
+		states.GenRawStorageItem(utils.GetUint32Bytes(height)))
+}
+
+//Get the height at which the request is made, the result is false if the height is not recorded
+func GetRequestHeight(native *native.NativeService, toChainID uint64, txHash []byte) (uint32, bool, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(REQUEST_HEIGHT), utils.GetUint64Bytes(toChainID), txHash))
+	if err != nil {
+		return 0, false, fmt.Errorf("GetRequestHeight, get request height store error: %v", err)
+	}
+	if store == nil {
+		return 0, false, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, false, fmt.Errorf("GetRequestHeight, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint32(value), true, nil
+}
+
 func PutDoneTx(native *native.NativeService, crossChainID []byte, chainID uint64) error {
 	contract := utils.CrossChainManagerContractAddress
 	chainIDBytes := utils.GetUint64Bytes(chainID)
@@ -58,7 +109,7 @@ func CheckDoneTx(native *native.NativeService, crossChainID []byte, chainID uint
 		return fmt.Errorf("checkDoneTx, native.GetCacheDB().Get error: %v", err)
 	}
 	if value != nil {
-		return fmt.Errorf("checkDoneTx, tx already done")
+		return event.NewExecuteError(event.CONTRACT_ERROR_DUPLICATE, "checkDoneTx, tx already done")
 	}
 	return nil
 }
@@ -18,19 +18,17 @@
 package consensus_vote
 
 import (
-	"encoding/hex"
 	"fmt"
-	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
-	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
 const (
-	VOTE_INFO = "voteInfo"
+	VOTE_INFO     = "voteInfo"
+	VOTE_PROPOSAL = "consensusVote"
 )
 
 func getVoteInfo(native *native.NativeService, id []byte) (*VoteInfo, error) {
@@ -63,90 +61,72 @@ func putVoteInfo(native *native.NativeService, id []byte, voteInfo *VoteInfo) {
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(VOTE_INFO), id), cstates.GenRawStorageItem(sink.Bytes()))
 }
 
+//Check the votes of consensus peers on id, true is returned only once when the votes reach the quorum
 func CheckVotes(native *native.NativeService, id []byte, address common.Address) (bool, error) {
+	//the ids passed before the votes are kept as proposals
 	voteInfo, err := getVoteInfo(native, id)
 	if err != nil {
 		return false, fmt.Errorf("CheckVotes, getVoteInfo error: %v", err)
 	}
-
-	//check voteInfo status
 	if voteInfo.Status {
 		return false, nil
 	}
 
-	//get view
-	view, err := node_manager.GetView(native)
+	//check if signer is consensus peer
+	peers, err := node_manager.GetConsensusAddresses(native)
 	if err != nil {
-		return false, fmt.Errorf("CheckVotes, GetView error: %v", err)
+		return false, fmt.Errorf("CheckVotes, %v", err)
 	}
-	//get consensus peer
-	peerPoolMap, err := node_manager.GetPeerPoolMap(native, view)
-	if err != nil {
-		return false, fmt.Errorf("CheckVotes, GetPeerPoolMap error: %v", err)
+	if !peers[address] {
+		return false, fmt.Errorf("CheckVotes, signer is not consensus peer")
 	}
 
-	//check if signer is consensus peer
-	consensus := false
-	for key, v := range peerPoolMap.PeerPoolMap {
-		if v.Status == node_manager.ConsensusStatus {
-			k, err := hex.DecodeString(key)
-			if err != nil {
-				return false, fmt.Errorf("CheckVotes, hex.DecodeString public key error: %v", err)
-			}
-			publicKey, err := keypair.DeserializePublicKey(k)
+	if !node_manager.ProposalEnabled(native) {
+		return checkLegacyVotes(native, id, address, voteInfo, peers), nil
+	}
+	proposalConfig, err := node_manager.GetProposalConfig(native)
+	if err != nil {
+		return false, fmt.Errorf("CheckVotes, %v", err)
+	}
+	//the votes made before the proposals are enabled
+	if len(voteInfo.VoteInfo) > 0 {
+		votes := make(map[common.Address][]byte)
+		for k := range voteInfo.VoteInfo {
+			addr, err := common.AddressFromBase58(k)
 			if err != nil {
-				return false, fmt.Errorf("CheckVotes, keypair.DeserializePublicKey error: %v", err)
-			}
-			addr := types.AddressFromPubKey(publicKey)
-
-			if addr == address {
-				consensus = true
-				break
+				return false, fmt.Errorf("CheckVotes, decode voter %s error: %v", k, err)
 			}
+			votes[addr] = nil
 		}
+		if err := node_manager.MigrateVotes(native, VOTE_PROPOSAL, id, votes, proposalConfig); err != nil {
+			return false, fmt.Errorf("CheckVotes, %v", err)
+		}
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(VOTE_INFO), id))
 	}
-	if !consensus {
-		return false, fmt.Errorf("CheckVotes, signer is not consensus peer")
+	_, ok, err := node_manager.VoteProposal(native, VOTE_PROPOSAL, id, nil, address, proposalConfig, true)
+	if err != nil {
+		return false, fmt.Errorf("CheckVotes, %v", err)
 	}
+	return ok, nil
+}
 
+func checkLegacyVotes(native *native.NativeService, id []byte, address common.Address, voteInfo *VoteInfo,
+	peers map[common.Address]bool) bool {
+	if !voteInfo.VoteInfo[address.ToBase58()] {
+		voteInfo.VoteInfo[address.ToBase58()] = true
+		putVoteInfo(native, id, voteInfo)
+	}
 	//check signs num
 	num := 0
-	sum := 0
-	flag := false
-	for key, v := range peerPoolMap.PeerPoolMap {
-		if v.Status == node_manager.ConsensusStatus {
-			k, err := hex.DecodeString(key)
-			if err != nil {
-				return false, fmt.Errorf("CheckVotes, hex.DecodeString public key error: %v", err)
-			}
-			publicKey, err := keypair.DeserializePublicKey(k)
-			if err != nil {
-				return false, fmt.Errorf("CheckVotes, keypair.DeserializePublicKey error: %v", err)
-			}
-			addr := types.AddressFromPubKey(publicKey)
-			_, ok := voteInfo.VoteInfo[addr.ToBase58()]
-			if ok {
-				num = num + 1
-			}
-			sum = sum + 1
-
-			//check if voted
-			_, ok = voteInfo.VoteInfo[address.ToBase58()]
-			if !ok {
-				flag = true
-			}
+	for addr := range peers {
+		if voteInfo.VoteInfo[addr.ToBase58()] {
+			num = num + 1
 		}
 	}
-	if flag {
-		voteInfo.VoteInfo[address.ToBase58()] = true
-		num = num + 1
-		putVoteInfo(native, id, voteInfo)
-	}
-	if num >= (2*sum+2)/3 {
+	if num >= (2*len(peers)+2)/3 {
 		voteInfo.Status = true
 		putVoteInfo(native, id, voteInfo)
-		return true, nil
-	} else {
-		return false, nil
+		return true
 	}
+	return false
 }
@@ -35,6 +35,12 @@ func NewVoteHandler() *VoteHandler {
 	return &VoteHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.VOTE_ROUTER, func() scom.ChainHandler {
+		return NewVoteHandler()
+	})
+}
+
 func (this *VoteHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -74,7 +80,7 @@ func (this *VoteHandler) MakeDepositProposal(service *native.NativeService) (*sc
 		}
 		if config.NETWORK_ID_TEST_NET != config.DefConfig.P2PNode.NetworkId || service.GetHeight() >= 19954185 {
 			if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
-				return nil, fmt.Errorf("vote MakeDepositProposal, check done transaction error:%s", err)
+				return nil, fmt.Errorf("vote MakeDepositProposal, check done transaction error:%w", err)
 			}
 			if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
 				return nil, fmt.Errorf("vote MakeDepositProposal, PutDoneTx error:%s", err)
@@ -24,6 +24,7 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/header_sync/cosmos"
+	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/tendermint/tendermint/crypto/merkle"
 )
 
@@ -33,6 +34,12 @@ func NewCosmosHandler() *CosmosHandler {
 	return &CosmosHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.COSMOS_ROUTER, func() scom.ChainHandler {
+		return NewCosmosHandler()
+	})
+}
+
 type CosmosProofValue struct {
 	Kp    string
 	Value []byte
@@ -103,7 +110,7 @@ func (this *CosmosHandler) MakeDepositProposal(service *native.NativeService) (*
 		return nil, fmt.Errorf("Cosmos MakeDepositProposal, deserialize merkleValue error:%s", err)
 	}
 	if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("Cosmos MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("Cosmos MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("Cosmos MakeDepositProposal, PutDoneTx error:%s", err)
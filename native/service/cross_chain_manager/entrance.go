@@ -21,32 +21,36 @@ import (
 	"fmt"
 
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/bsc"
+	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/btc"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/bytom"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/cosmos"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/harmony"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/heco"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/hsc"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/msc"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/neo"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/neo3"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/okex"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/ont"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/pixiechain"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/polygon"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/quorum"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/ripple"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/starcoin"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqa"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqalegacy"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/utils"
+
+	// router packages register their handlers in init
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/bsc"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/bytom"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/cosmos"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/harmony"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/heco"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/hsc"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/msc"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/neo"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/neo3"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/okex"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/ont"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/pixiechain"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/polygon"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/quorum"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/starcoin"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqa"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqalegacy"
 )
 
 func RegisterCrossChainManagerContract(native *native.NativeService) {
@@ -57,70 +61,39 @@ func RegisterCrossChainManagerContract(native *native.NativeService) {
 
 	native.Register(scom.BLACK_CHAIN, BlackChain)
 	native.Register(scom.WHITE_CHAIN, WhiteChain)
+	native.Register(scom.IS_CHAIN_BLACKED, IsChainBlacked)
+
+	native.Register(scom.RECORD_FEE_PAYMENT, RecordFeePayment)
+	native.Register(scom.RELEASE_HELD_TRANSFER, ReleaseHeldTransfer)
+	native.Register(scom.LIST_HELD_TRANSFERS, ListHeldTransfers)
+	native.Register(scom.SET_RATE_LIMIT, SetRateLimit)
+	native.Register(scom.LIST_RATE_LIMITS, ListRateLimits)
+	native.Register(scom.RELEASE_PENDING_TRANSFER, ReleasePendingTransfer)
+	native.Register(scom.REJECT_PENDING_TRANSFER, RejectPendingTransfer)
+	native.Register(scom.LIST_PENDING_TRANSFERS, ListPendingTransfers)
+	native.Register(scom.SET_QUARANTINE_CONFIG, SetQuarantineConfig)
+	native.Register(scom.LIST_QUARANTINE_CONFIGS, ListQuarantineConfigs)
+	native.Register(scom.GET_ASSET_SUPPLY, GetAssetSupply)
 }
 
 func GetChainHandler(router uint64) (scom.ChainHandler, error) {
-	switch router {
-	case utils.VOTE_ROUTER:
-		return consensus_vote.NewVoteHandler(), nil
-	case utils.BTC_ROUTER:
-		return btc.NewBTCHandler(), nil
-	case utils.ETH_ROUTER:
-		return eth.NewETHHandler(), nil
-	case utils.ONT_ROUTER:
-		return ont.NewONTHandler(), nil
-	case utils.NEO_ROUTER:
-		return neo.NewNEOHandler(), nil
-	case utils.NEO3_ROUTER:
-		return neo3.NewNeo3Handler(), nil
-	case utils.COSMOS_ROUTER:
-		return cosmos.NewCosmosHandler(), nil
-	case utils.QUORUM_ROUTER:
-		return quorum.NewQuorumHandler(), nil
-	case utils.BSC_ROUTER:
-		return bsc.NewHandler(), nil
-	case utils.HECO_ROUTER:
-		return heco.NewHecoHandler(), nil
-	case utils.ZILLIQA_LEGACY_ROUTER:
-		return zilliqalegacy.NewHandler(), nil
-	case utils.ZILLIQA_ROUTER:
-		return zilliqa.NewHandler(), nil
-	case utils.MSC_ROUTER:
-		return msc.NewHandler(), nil
-	case utils.OKEX_ROUTER:
-		return okex.NewHandler(), nil
-	case utils.POLYGON_BOR_ROUTER:
-		return polygon.NewHandler(), nil
-	case utils.PIXIECHAIN_ROUTER:
-		return pixiechain.NewPixieHandler(), nil
-	case utils.STARCOIN_ROUTER:
-		return starcoin.NewHandler(), nil
-	case utils.HSC_ROUTER:
-		return hsc.NewHscHandler(), nil
-	case utils.HARMONY_ROUTER:
-		return harmony.NewHandler(), nil
-	case utils.BYTOM_ROUTER:
-		return bytom.NewHandler(), nil
-	case utils.RIPPLE_ROUTER:
-		return ripple.NewRippleHandler(), nil
-	default:
-		return nil, fmt.Errorf("not a supported router:%d", router)
-	}
+	return scom.GetChainHandler(router)
 }
 
-func ImportExTransfer(native *native.NativeService) ([]byte, error) {
+func ImportExTransfer(native *native.NativeService) (res []byte, err error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, contract params deserialize error: %v", err)
 	}
 
 	chainID := params.SourceChainID
+	defer func() { scom.CountImportExTransfer(native, chainID, err) }()
 	blacked, err := scom.CheckIfChainBlacked(native, chainID)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, CheckIfChainBlacked error: %v", err)
 	}
 	if blacked {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, source chain is blacked")
+		return utils.BYTE_FALSE, event.NewExecuteError(event.CONTRACT_ERROR_CHAIN_BLACKED, "ImportExTransfer, source chain is blacked")
 	}
 
 	//check if chainid exist
@@ -129,7 +102,8 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side_chain_manager.GetSideChain error: %v", err)
 	}
 	if sideChain == nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side chain %d is not registered", chainID)
+		return utils.BYTE_FALSE, event.NewExecuteError(event.CONTRACT_ERROR_NOT_REGISTERED,
+			"ImportExTransfer, side chain %d is not registered", chainID)
 	}
 
 	handler, err := GetChainHandler(sideChain.Router)
@@ -144,7 +118,11 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 	//1. verify tx
 	txParam, err := handler.MakeDepositProposal(native)
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		//the transactions already done are told apart from the ones failed to verify
+		if event.ErrorCode(err) == event.CONTRACT_ERROR_DUPLICATE {
+			return utils.BYTE_FALSE, err
+		}
+		return utils.BYTE_FALSE, &event.ExecuteError{Code: event.CONTRACT_ERROR_VERIFY, Err: err}
 	}
 	if txParam == nil && (sideChain.Router == utils.VOTE_ROUTER || sideChain.Router == utils.RIPPLE_ROUTER) {
 		return utils.BYTE_TRUE, nil
@@ -157,7 +135,7 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, CheckIfChainBlacked error: %v", err)
 	}
 	if blacked {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, target chain is blacked")
+		return utils.BYTE_FALSE, event.NewExecuteError(event.CONTRACT_ERROR_CHAIN_BLACKED, "ImportExTransfer, target chain is blacked")
 	}
 
 	//check if chainid exist
@@ -166,31 +144,59 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side_chain_manager.GetSideChain error: %v", err)
 	}
 	if sideChain == nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side chain %d is not registered", targetid)
+		return utils.BYTE_FALSE, event.NewExecuteError(event.CONTRACT_ERROR_NOT_REGISTERED,
+			"ImportExTransfer, side chain %d is not registered", targetid)
 	}
-	if sideChain.Router == utils.BTC_ROUTER {
-		err := btc.NewBTCHandler().MakeTransaction(native, txParam, chainID)
+
+	//3. check quarantine and rate limit
+	reason, err := checkQuarantine(native, chainID, txParam)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, checkQuarantine error: %v", err)
+	}
+	if reason != "" {
+		err = pendTransfer(native, chainID, txParam, reason)
 		if err != nil {
-			return utils.BYTE_FALSE, err
+			return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, pendTransfer error: %v", err)
 		}
 		return utils.BYTE_TRUE, nil
 	}
-
-	if sideChain.Router == utils.RIPPLE_ROUTER {
-		err := ripple.NewRippleHandler().MakeTransaction(native, txParam, chainID)
+	allowed, err := checkRateLimit(native, chainID, txParam, false)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, checkRateLimit error: %v", err)
+	}
+	if !allowed {
+		err = pendTransfer(native, chainID, txParam, scom.PENDING_REASON_RATE_LIMIT)
 		if err != nil {
-			return utils.BYTE_FALSE, err
+			return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, pendTransfer error: %v", err)
 		}
 		return utils.BYTE_TRUE, nil
 	}
-	//NOTE, you need to store the tx in this
-	err = MakeTransaction(native, txParam, chainID)
+
+	err = makeTargetTransaction(native, sideChain.Router, txParam, chainID)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
 	return utils.BYTE_TRUE, nil
 }
 
+//Make the tx of target chain by its router, with the transfer of registered asset accounted in the supplies once the
+//tx is made. The transfer held for fee is accounted when it is released
+func makeTargetTransaction(native *native.NativeService, router uint64, txParam *scom.MakeTxParam, fromChainID uint64) error {
+	if router == utils.BTC_ROUTER || router == utils.RIPPLE_ROUTER {
+		if err := accountAssetSupply(native, fromChainID, txParam); err != nil {
+			return fmt.Errorf("makeTargetTransaction, accountAssetSupply error: %v", err)
+		}
+	}
+	if router == utils.BTC_ROUTER {
+		return btc.NewBTCHandler().MakeTransaction(native, txParam, fromChainID)
+	}
+	if router == utils.RIPPLE_ROUTER {
+		return ripple.NewRippleHandler().MakeTransaction(native, txParam, fromChainID)
+	}
+	//NOTE, you need to store the tx in this
+	return MakeTransaction(native, txParam, fromChainID)
+}
+
 func MultiSign(native *native.NativeService) ([]byte, error) {
 	handler := btc.NewBTCHandler()
 	//1. multi sign
@@ -222,6 +228,7 @@ func ReconstructRippleTx(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+//Make the proof of target chain tx, the transfer is held instead if the fee of target chain is not paid
 func MakeTransaction(service *native.NativeService, params *scom.MakeTxParam, fromChainID uint64) error {
 	txHash := service.GetTx().Hash()
 	merkleValue := &scom.ToMerkleValue{
@@ -229,17 +236,47 @@ func MakeTransaction(service *native.NativeService, params *scom.MakeTxParam, fr
 		FromChainID: fromChainID,
 		MakeTxParam: params,
 	}
+	return makeTransferProof(service, merkleValue)
+}
+
+//Make the proof of the merkle value, the transfer is held instead if the fee of target chain is not paid
+func makeTransferProof(service *native.NativeService, merkleValue *scom.ToMerkleValue) error {
+	params := merkleValue.MakeTxParam
+	fee, paid, err := getTransferFee(service, merkleValue.FromChainID, params)
+	if err != nil {
+		return fmt.Errorf("MakeTransaction, %v", err)
+	}
+	if paid.Cmp(fee) < 0 {
+		err = scom.PutHeldTransfer(service, merkleValue)
+		if err != nil {
+			return fmt.Errorf("MakeTransaction, PutHeldTransfer error: %v", err)
+		}
+		scom.NotifyHoldTransfer(service, merkleValue.FromChainID, params.ToChainID, hex.EncodeToString(params.TxHash), fee, paid)
+		return nil
+	}
+	return makeProof(service, merkleValue)
+}
 
+//Emit the request of target chain tx, the transfer of registered asset is accounted in the supplies
+func makeProof(service *native.NativeService, merkleValue *scom.ToMerkleValue) error {
+	params := merkleValue.MakeTxParam
+	if err := accountAssetSupply(service, merkleValue.FromChainID, params); err != nil {
+		return fmt.Errorf("MakeTransaction, accountAssetSupply error: %v", err)
+	}
 	sink := common.NewZeroCopySink(nil)
 	merkleValue.Serialization(sink)
 	err := PutRequest(service, merkleValue.TxHash, params.ToChainID, sink.Bytes())
 	if err != nil {
 		return fmt.Errorf("MakeTransaction, putRequest error:%s", err)
 	}
+	//the request height is recorded for replenishTx since it is activated
+	if service.GetHeight() >= config.GetReplenishHeight(config.DefConfig.P2PNode.NetworkId) {
+		scom.PutRequestHeight(service, params.ToChainID, merkleValue.TxHash, service.GetHeight())
+	}
 	service.PutMerkleVal(sink.Bytes())
 	chainIDBytes := utils.GetUint64Bytes(params.ToChainID)
 	key := hex.EncodeToString(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(scom.REQUEST), chainIDBytes, merkleValue.TxHash))
-	scom.NotifyMakeProof(service, fromChainID, params.ToChainID, hex.EncodeToString(params.TxHash), key)
+	scom.NotifyMakeProof(service, merkleValue.FromChainID, params.ToChainID, hex.EncodeToString(params.TxHash), key)
 	return nil
 }
 
@@ -269,6 +306,11 @@ func BlackChain(native *native.NativeService) ([]byte, error) {
 	}
 
 	scom.PutBlackChain(native, params.ChainID)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.BLACK_CHAIN, params.ChainID},
+		})
 	return utils.BYTE_TRUE, nil
 }
 
@@ -288,6 +330,34 @@ func WhiteChain(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("BlackChain, checkWitness error: %v", err)
 	}
 
+	blacked, err := scom.CheckIfChainBlacked(native, params.ChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("WhiteChain, CheckIfChainBlacked error: %v", err)
+	}
+	if blacked && scom.WhiteChainHeightEnabled(native) {
+		scom.PutWhiteChainHeight(native, params.ChainID, native.GetHeight())
+	}
 	scom.RemoveBlackChain(native, params.ChainID)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.WHITE_CHAIN, params.ChainID},
+		})
 	return utils.BYTE_TRUE, nil
 }
+
+//Check if the chain is blacked, the result is BYTE_TRUE if blacked
+func IsChainBlacked(native *native.NativeService) ([]byte, error) {
+	params := new(scom.BlackChainParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("IsChainBlacked, contract params deserialize error: %v", err)
+	}
+	blacked, err := scom.CheckIfChainBlacked(native, params.ChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("IsChainBlacked, CheckIfChainBlacked error: %v", err)
+	}
+	if blacked {
+		return utils.BYTE_TRUE, nil
+	}
+	return utils.BYTE_FALSE, nil
+}
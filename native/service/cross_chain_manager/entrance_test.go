@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportExTransferErrorCode(t *testing.T) {
+	sink := common.NewZeroCopySink(nil)
+	(&scom.EntranceParam{SourceChainID: 5}).Serialization(sink)
+	ns := newNativeService(sink.Bytes(), nil)
+
+	_, err := ImportExTransfer(ns)
+	assert.Error(t, err)
+	assert.Equal(t, event.CONTRACT_ERROR_NOT_REGISTERED, event.ErrorCode(err))
+
+	scom.PutBlackChain(ns, 5)
+	_, err = ImportExTransfer(ns)
+	assert.Error(t, err)
+	assert.Equal(t, event.CONTRACT_ERROR_CHAIN_BLACKED, event.ErrorCode(err))
+
+	//the code of done tx is kept through the errors wrapping it in chain handlers
+	assert.NoError(t, scom.CheckDoneTx(ns, []byte{1}, 5))
+	assert.NoError(t, scom.PutDoneTx(ns, []byte{1}, 5))
+	err = scom.CheckDoneTx(ns, []byte{1}, 5)
+	assert.Equal(t, event.CONTRACT_ERROR_DUPLICATE, event.ErrorCode(fmt.Errorf("check done transaction error:%w", err)))
+	assert.Equal(t, event.CONTRACT_ERROR_EXECUTE, event.ErrorCode(fmt.Errorf("check done transaction error:%v", err)))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type ETHHandler struct {
@@ -31,6 +32,15 @@ func NewETHHandler() *ETHHandler {
 	return &ETHHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.ETH_ROUTER, func() scom.ChainHandler {
+		return NewETHHandler()
+	})
+	scom.RegisterChainHandler(utils.ETH_BEACON_ROUTER, func() scom.ChainHandler {
+		return NewETHHandler()
+	})
+}
+
 func (this *ETHHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	//parse the EntranceParam from native service data
@@ -49,7 +59,7 @@ func (this *ETHHandler) MakeDepositProposal(service *native.NativeService) (*sco
 	}
 	//Look for this tx on relay chain to make sure this tx hasn't been executed yet
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("eth MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("eth MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("eth MakeDepositProposal, PutDoneTx error:%s", err)
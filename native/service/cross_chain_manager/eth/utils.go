@@ -35,22 +35,16 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cmanager "github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync/beacon"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *cmanager.SideChain) (*scom.MakeTxParam, error) {
-	bestHeader, _, err := eth.GetCurrentHeader(native, fromChainID)
+	//fetch the verified state root of the block from db
+	stateRoot, err := getStateRoot(native, fromChainID, height, sideChain)
 	if err != nil {
-		return nil, fmt.Errorf("VerifyFromEthProof, get current header fail, error:%s", err)
-	}
-	bestHeight := uint32(bestHeader.Number.Uint64())
-	if bestHeight < height || bestHeight-height < uint32(sideChain.BlocksToWait-1) {
-		return nil, fmt.Errorf("VerifyFromEthProof, transaction is not confirmed, current height: %d, input height: %d", bestHeight, height)
-	}
-	//fetch the verified block header data from db
-	blockData, _, err := eth.GetHeaderByHeight(native, uint64(height), fromChainID)
-	if err != nil {
-		return nil, fmt.Errorf("VerifyFromEthProof, get header by height, height:%d, error:%s", height, err)
+		return nil, fmt.Errorf("VerifyFromEthProof, %v", err)
 	}
 	ethProof := new(ETHProof)
 	err = json.Unmarshal(proof, ethProof)
@@ -62,7 +56,7 @@ func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChai
 	}
 	//todo 1. verify the proof with header
 	//determine where the k and v from
-	proofResult, err := VerifyMerkleProof(ethProof, blockData, sideChain.CCMCAddress)
+	proofResult, err := verifyStorageProof(ethProof, stateRoot, sideChain.CCMCAddress)
 	if err != nil {
 		return nil, fmt.Errorf("VerifyFromEthProof, verifyMerkleProof error:%v", err)
 	}
@@ -86,6 +80,34 @@ func VerifyMerkleProofLegacy(ethProof *ETHProof, blockData *types.Header, contra
 }
 
 func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
+	return verifyStorageProof(ethProof, blockData.Root, contractAddr)
+}
+
+//getStateRoot returns the state root of a confirmed block, beacon router only keeps the finalized blocks
+func getStateRoot(native *native.NativeService, fromChainID uint64, height uint32, sideChain *cmanager.SideChain) (ecom.Hash, error) {
+	if sideChain.Router == utils.ETH_BEACON_ROUTER {
+		header, err := beacon.GetHeaderByHeight(native, fromChainID, uint64(height))
+		if err != nil {
+			return ecom.Hash{}, fmt.Errorf("get finalized header by height, height:%d, error:%s", height, err)
+		}
+		return header.Execution.StateRoot, nil
+	}
+	bestHeader, _, err := eth.GetCurrentHeader(native, fromChainID)
+	if err != nil {
+		return ecom.Hash{}, fmt.Errorf("get current header fail, error:%s", err)
+	}
+	bestHeight := uint32(bestHeader.Number.Uint64())
+	if bestHeight < height || bestHeight-height < uint32(sideChain.BlocksToWait-1) {
+		return ecom.Hash{}, fmt.Errorf("transaction is not confirmed, current height: %d, input height: %d", bestHeight, height)
+	}
+	blockData, _, err := eth.GetHeaderByHeight(native, uint64(height), fromChainID)
+	if err != nil {
+		return ecom.Hash{}, fmt.Errorf("get header by height, height:%d, error:%s", height, err)
+	}
+	return blockData.Root, nil
+}
+
+func verifyStorageProof(ethProof *ETHProof, stateRoot ecom.Hash, contractAddr []byte) ([]byte, error) {
 	//1. prepare verify account
 	nodeList := new(light.NodeList)
 
@@ -102,7 +124,7 @@ func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr [
 	acctKey := crypto.Keccak256(addr)
 
 	// 2. verify account proof
-	acctVal, err := trie.VerifyProof(blockData.Root, acctKey, ns)
+	acctVal, err := trie.VerifyProof(stateRoot, acctKey, ns)
 	if err != nil {
 		return nil, fmt.Errorf("verifyMerkleProof, verify account proof error:%s\n", err)
 	}
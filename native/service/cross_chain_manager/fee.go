@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Record the fee paid on the source chain for the transfer of source chain tx, the fee is the total paid
+//and it is recorded once enough consensus nodes vote for the same amount
+func RecordFeePayment(native *native.NativeService) ([]byte, error) {
+	params := new(scom.RecordFeePaymentParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RecordFeePayment, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RecordFeePayment, checkWitness error: %v", err)
+	}
+
+	//check consensus signs
+	sink := common.NewZeroCopySink([]byte(scom.RECORD_FEE_PAYMENT))
+	sink.WriteUint64(params.FromChainID)
+	sink.WriteVarBytes(params.TxHash)
+	sink.WriteVarBytes(params.Fee.Bytes())
+	ok, err := consensus_vote.CheckVotes(native, sink.Bytes(), params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RecordFeePayment, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	scom.PutFeePayment(native, params.FromChainID, params.TxHash, params.Fee)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.RECORD_FEE_PAYMENT, params.FromChainID, hex.EncodeToString(params.TxHash), params.Fee.String()},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Release the held transfer once its fee is paid, anyone can call it
+func ReleaseHeldTransfer(native *native.NativeService) ([]byte, error) {
+	params := new(scom.HeldTransferParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, contract params deserialize error: %v", err)
+	}
+
+	merkleValue, err := scom.GetHeldTransfer(native, params.ToChainID, params.TxHash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, GetHeldTransfer error: %v", err)
+	}
+	if merkleValue == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, transfer is not held")
+	}
+	blacked, err := scom.CheckIfChainBlacked(native, params.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, CheckIfChainBlacked error: %v", err)
+	}
+	if blacked {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, target chain is blacked")
+	}
+	fee, paid, err := getTransferFee(native, merkleValue.FromChainID, merkleValue.MakeTxParam)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, %v", err)
+	}
+	if paid.Cmp(fee) < 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, fee paid: %s is less than fee: %s", paid, fee)
+	}
+
+	err = scom.DeleteHeldTransfer(native, params.ToChainID, params.TxHash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, DeleteHeldTransfer error: %v", err)
+	}
+	err = makeProof(native, merkleValue)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseHeldTransfer, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Get the transfers held for fee in the order of target chain id and poly tx hash
+func ListHeldTransfers(native *native.NativeService) ([]byte, error) {
+	transfers, err := scom.ListHeldTransfers(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListHeldTransfers, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&scom.HeldTransferList{Transfers: transfers}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the fee of target chain and the fee paid for the transfer, the fee is zero if target chain has no fee configured
+//or the transfer fee is not activated yet
+func getTransferFee(native *native.NativeService, fromChainID uint64, params *scom.MakeTxParam) (*big.Int, *big.Int, error) {
+	if native.GetHeight() < config.GetTransferFeeHeight(config.DefConfig.P2PNode.NetworkId) {
+		return new(big.Int), new(big.Int), nil
+	}
+	fee, err := side_chain_manager.GetFee(native, params.ToChainID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetFee error: %v", err)
+	}
+	if fee.Fee.Sign() <= 0 {
+		return new(big.Int), new(big.Int), nil
+	}
+	paid, err := scom.GetFeePayment(native, fromChainID, params.TxHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fee.Fee, paid, nil
+}
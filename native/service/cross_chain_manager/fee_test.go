@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNativeService(args []byte, db *storage.CacheDB) *native.NativeService {
+	if db == nil {
+		store, _ := leveldbstore.NewMemLevelDBStore()
+		db = storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	}
+	service, _ := native.NewNativeService(db, &types.Transaction{ChainID: 0}, 0, 0, common.Uint256{0}, 0, args, false)
+	return service
+}
+
+func TestFeeGatedTransfer(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	ns := newNativeService(nil, nil)
+	params := &scom.MakeTxParam{
+		TxHash:              []byte{1, 2, 3},
+		CrossChainID:        []byte{1},
+		FromContractAddress: []byte{2},
+		ToChainID:           3,
+		ToContractAddress:   []byte{4},
+		Method:              "unlock",
+		Args:                []byte{5},
+	}
+
+	//the request height is not recorded before the replenish is activated
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	mainNet := newNativeService(nil, nil)
+	assert.NoError(t, MakeTransaction(mainNet, params, 2))
+	txHash := mainNet.GetTx().Hash()
+	_, ok, err := scom.GetRequestHeight(mainNet, 3, txHash[:])
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	//no fee configured
+	assert.NoError(t, MakeTransaction(ns, params, 2))
+	assert.Equal(t, 1, len(ns.GetNotify()))
+	_, ok, err = scom.GetRequestHeight(ns, 3, txHash[:])
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	transfers, err := scom.ListHeldTransfers(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(transfers))
+
+	//underpaid transfer is held
+	side_chain_manager.PutFee(ns, 3, &side_chain_manager.Fee{View: 1, Fee: big.NewInt(100)})
+	scom.PutFeePayment(ns, 2, params.TxHash, big.NewInt(50))
+
+	//not held before the transfer fee is activated
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	ns = newNativeService(nil, ns.GetCacheDB())
+	assert.NoError(t, MakeTransaction(ns, params, 2))
+	assert.Equal(t, scom.NOTIFY_MAKE_PROOF, ns.GetNotify()[0].States.([]interface{})[0])
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	ns = newNativeService(nil, ns.GetCacheDB())
+	assert.NoError(t, MakeTransaction(ns, params, 2))
+	assert.Equal(t, scom.NOTIFY_HOLD_TRANSFER, ns.GetNotify()[0].States.([]interface{})[0])
+	transfers, err = scom.ListHeldTransfers(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, params.TxHash, transfers[0].MakeTxParam.TxHash)
+
+	sink := common.NewZeroCopySink(nil)
+	(&scom.HeldTransferParam{ToChainID: 3, TxHash: transfers[0].TxHash}).Serialization(sink)
+	ns = newNativeService(sink.Bytes(), ns.GetCacheDB())
+	_, err = ReleaseHeldTransfer(ns)
+	assert.Error(t, err)
+
+	//released once the fee is paid
+	scom.PutFeePayment(ns, 2, params.TxHash, big.NewInt(100))
+	ns = newNativeService(sink.Bytes(), ns.GetCacheDB())
+	res, err := ReleaseHeldTransfer(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, res)
+	assert.Equal(t, 1, len(ns.GetNotify()))
+	transfers, err = scom.ListHeldTransfers(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(transfers))
+
+	ns = newNativeService(sink.Bytes(), ns.GetCacheDB())
+	_, err = ReleaseHeldTransfer(ns)
+	assert.Error(t, err)
+}
@@ -34,6 +34,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/harmony"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type Handler struct {}
@@ -42,6 +43,12 @@ func NewHandler() *Handler {
 	return new(Handler)
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.HARMONY_ROUTER, func() scom.ChainHandler {
+		return NewHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *Handler) MakeDepositProposal(service *native.NativeService) (txParam *scom.MakeTxParam ,err error) {
 	params := new(scom.EntranceParam)
@@ -59,7 +66,7 @@ func (h *Handler) MakeDepositProposal(service *native.NativeService) (txParam *s
 
 	err = scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID)
 	if err != nil {
-		err = fmt.Errorf("HarmonyHandler check done transaction err: %v", err)
+		err = fmt.Errorf("HarmonyHandler check done transaction err: %w", err)
 		return
 	}
 
@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/heco"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -47,6 +48,12 @@ func NewHecoHandler() *HecoHandler {
 	return &HecoHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.HECO_ROUTER, func() scom.ChainHandler {
+		return NewHecoHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *HecoHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -65,7 +72,7 @@ func (h *HecoHandler) MakeDepositProposal(service *native.NativeService) (*scom.
 	}
 
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("heco MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("heco MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("heco MakeDepositProposal, PutDoneTx error:%s", err)
@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/hsc"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -47,6 +48,12 @@ func NewHscHandler() *HscHandler {
 	return &HscHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.HSC_ROUTER, func() scom.ChainHandler {
+		return NewHscHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *HscHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -65,7 +72,7 @@ func (h *HscHandler) MakeDepositProposal(service *native.NativeService) (*scom.M
 	}
 
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("hsc MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("hsc MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("hsc MakeDepositProposal, PutDoneTx error:%s", err)
@@ -24,6 +24,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/neo"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type NEOHandler struct {
@@ -33,6 +34,12 @@ func NewNEOHandler() *NEOHandler {
 	return &NEOHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.NEO_ROUTER, func() scom.ChainHandler {
+		return NewNEOHandler()
+	})
+}
+
 func (this *NEOHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -57,7 +64,7 @@ func (this *NEOHandler) MakeDepositProposal(service *native.NativeService) (*sco
 	}
 	// Ensure the tx has not been processed before, and mark the tx as processed
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("neo MakeDepositProposal, check done transaction error: %s", err)
+		return nil, fmt.Errorf("neo MakeDepositProposal, check done transaction error: %w", err)
 	}
 	if err = scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("neo MakeDepositProposal, putDoneTx error: %s", err)
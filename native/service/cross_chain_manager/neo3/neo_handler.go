@@ -25,6 +25,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/neo3"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type Neo3Handler struct {
@@ -34,6 +35,12 @@ func NewNeo3Handler() *Neo3Handler {
 	return &Neo3Handler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.NEO3_ROUTER, func() scom.ChainHandler {
+		return NewNeo3Handler()
+	})
+}
+
 func (this *Neo3Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -65,7 +72,7 @@ func (this *Neo3Handler) MakeDepositProposal(service *native.NativeService) (*sc
 	}
 	// Ensure the tx has not been processed before, and mark the tx as processed
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("neo3 MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("neo3 MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err = scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("neo3 MakeDepositProposal, putDoneTx error:%s", err)
@@ -65,7 +65,7 @@ func (this *Neo3Handler) MakeDepositProposal(service *native.NativeService) (*sc
 	}
 	// Ensure the tx has not been processed before, and mark the tx as processed
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("neo3 MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("neo3 MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err = scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("neo3 MakeDepositProposal, putDoneTx error:%s", err)
@@ -28,6 +28,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/okex"
+	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/tendermint/tendermint/crypto/merkle"
 )
 
@@ -37,6 +38,12 @@ func NewHandler() *OKHandler {
 	return &OKHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.OKEX_ROUTER, func() scom.ChainHandler {
+		return NewHandler()
+	})
+}
+
 type CosmosProofValue struct {
 	Kp    string
 	Value []byte
@@ -125,7 +132,7 @@ func (this *OKHandler) MakeDepositProposal(service *native.NativeService) (*scom
 		return nil, fmt.Errorf("Cosmos MakeDepositProposal, deserialize merkleValue error:%s", err)
 	}
 	if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("Cosmos MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("Cosmos MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("Cosmos MakeDepositProposal, PutDoneTx error:%s", err)
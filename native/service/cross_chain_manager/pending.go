@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Release the pending transfer to target chain once enough consensus nodes vote for it
+func ReleasePendingTransfer(native *native.NativeService) ([]byte, error) {
+	transfer, ok, err := votePendingTransfer(native, scom.RELEASE_PENDING_TRANSFER)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	merkleValue := transfer.ToMerkleValue
+	blacked, err := scom.CheckIfChainBlacked(native, merkleValue.MakeTxParam.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, CheckIfChainBlacked error: %v", err)
+	}
+	if blacked {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, target chain is blacked")
+	}
+	sideChain, err := side_chain_manager.GetSideChain(native, merkleValue.MakeTxParam.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, side chain %d is not registered", merkleValue.MakeTxParam.ToChainID)
+	}
+	//the transfer released is counted by the rate limit, though it is over the limit
+	_, err = checkRateLimit(native, merkleValue.FromChainID, merkleValue.MakeTxParam, true)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, checkRateLimit error: %v", err)
+	}
+	if sideChain.Router == utils.BTC_ROUTER || sideChain.Router == utils.RIPPLE_ROUTER {
+		err = makeTargetTransaction(native, sideChain.Router, merkleValue.MakeTxParam, merkleValue.FromChainID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, %v", err)
+		}
+		return utils.BYTE_TRUE, nil
+	}
+	//the proof is made of the merkle value stored, so that it keeps the poly tx hash announced when the transfer is pended
+	err = makeTransferProof(native, merkleValue)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleasePendingTransfer, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Reject the pending transfer once enough consensus nodes vote for it, the transfer is dropped
+func RejectPendingTransfer(native *native.NativeService) ([]byte, error) {
+	_, _, err := votePendingTransfer(native, scom.REJECT_PENDING_TRANSFER)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RejectPendingTransfer, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Get the pending transfers in the order of poly tx hash
+func ListPendingTransfers(native *native.NativeService) ([]byte, error) {
+	transfers, err := scom.ListPendingTransfers(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListPendingTransfers, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&scom.PendingTransferList{Transfers: transfers}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Vote for the decision on the pending transfer, the transfer is removed from pending with the decision notified once the votes are enough
+func votePendingTransfer(native *native.NativeService, decision string) (*scom.PendingTransfer, bool, error) {
+	params := new(scom.VotePendingTransferParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return nil, false, fmt.Errorf("contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return nil, false, fmt.Errorf("checkWitness error: %v", err)
+	}
+
+	transfer, err := scom.GetPendingTransfer(native, params.TxHash)
+	if err != nil {
+		return nil, false, err
+	}
+	if transfer == nil {
+		return nil, false, fmt.Errorf("transfer is not pending")
+	}
+
+	//check consensus signs
+	sink := common.NewZeroCopySink([]byte(decision))
+	sink.WriteVarBytes(params.TxHash)
+	ok, err := consensus_vote.CheckVotes(native, sink.Bytes(), params.Address)
+	if err != nil {
+		return nil, false, fmt.Errorf("CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	err = scom.DeletePendingTransfer(native, params.TxHash)
+	if err != nil {
+		return nil, false, fmt.Errorf("DeletePendingTransfer error: %v", err)
+	}
+	merkleValue := transfer.ToMerkleValue
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States: []interface{}{decision, merkleValue.FromChainID, merkleValue.MakeTxParam.ToChainID,
+				hex.EncodeToString(merkleValue.MakeTxParam.TxHash), hex.EncodeToString(merkleValue.TxHash)},
+		})
+	return transfer, true, nil
+}
+
+//Put the transfer to pending for the reason instead of making the target chain tx
+func pendTransfer(native *native.NativeService, fromChainID uint64, params *scom.MakeTxParam, reason string) error {
+	txHash := native.GetTx().Hash()
+	transfer := &scom.PendingTransfer{
+		Reason: reason,
+		Height: native.GetHeight(),
+		ToMerkleValue: &scom.ToMerkleValue{
+			TxHash:      txHash.ToArray(),
+			FromChainID: fromChainID,
+			MakeTxParam: params,
+		},
+	}
+	err := scom.PutPendingTransfer(native, transfer)
+	if err != nil {
+		return err
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States: []interface{}{scom.NOTIFY_PEND_TRANSFER, fromChainID, params.ToChainID, hex.EncodeToString(params.TxHash),
+				native.GetHeight(), reason, hex.EncodeToString(transfer.ToMerkleValue.TxHash)},
+		})
+	return nil
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/pixiechain"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // NewPixieHandler ...
@@ -43,6 +44,12 @@ func NewPixieHandler() *PixieHandler {
 	return &PixieHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.PIXIECHAIN_ROUTER, func() scom.ChainHandler {
+		return NewPixieHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *PixieHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -61,7 +68,7 @@ func (h *PixieHandler) MakeDepositProposal(service *native.NativeService) (*scom
 	}
 
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("pixie MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("pixie MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("pixie MakeDepositProposal, PutDoneTx error:%s", err)
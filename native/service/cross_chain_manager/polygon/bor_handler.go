@@ -35,6 +35,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/polygon"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // BorHandler ...
@@ -46,6 +47,12 @@ func NewHandler() *BorHandler {
 	return &BorHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.POLYGON_BOR_ROUTER, func() scom.ChainHandler {
+		return NewHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *BorHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -64,7 +71,7 @@ func (h *BorHandler) MakeDepositProposal(service *native.NativeService) (*scom.M
 	}
 
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("eth MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("eth MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("eth MakeDepositProposal, PutDoneTx error:%s", err)
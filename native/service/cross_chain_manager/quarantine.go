@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Set the quarantine config of target chain, the config is removed if it has no rule
+func SetQuarantineConfig(native *native.NativeService) ([]byte, error) {
+	params := new(scom.QuarantineConfig)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetQuarantineConfig, contract params deserialize error: %v", err)
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetQuarantineConfig, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetQuarantineConfig, checkWitness error: %v", err)
+	}
+
+	if params.IsEmpty() {
+		err = scom.DeleteQuarantineConfig(native, params.ChainID)
+	} else {
+		err = scom.PutQuarantineConfig(native, params)
+	}
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetQuarantineConfig, store quarantine config error: %v", err)
+	}
+	thresholds := make([]string, 0, len(params.AmountThresholds))
+	for _, threshold := range params.AmountThresholds {
+		thresholds = append(thresholds, hex.EncodeToString(threshold.AssetHash)+":"+threshold.Amount.String())
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States: []interface{}{scom.SET_QUARANTINE_CONFIG, params.ChainID, params.WhitelistPeriod, params.Methods,
+				thresholds},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Get all the quarantine configs in the order of chain id
+func ListQuarantineConfigs(native *native.NativeService) ([]byte, error) {
+	configs, err := scom.ListQuarantineConfigs(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListQuarantineConfigs, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&scom.QuarantineConfigList{Configs: configs}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Check the transfer by the quarantine config of target chain, the result is the reason to put it to pending,
+//and it is empty if the transfer is not flagged. The amount is checked by the threshold of the asset transferred
+func checkQuarantine(native *native.NativeService, fromChainID uint64, params *scom.MakeTxParam) (string, error) {
+	config, err := scom.GetQuarantineConfig(native, params.ToChainID)
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		return "", nil
+	}
+	if config.WhitelistPeriod > 0 {
+		height, ok, err := scom.GetWhiteChainHeight(native, params.ToChainID)
+		if err != nil {
+			return "", err
+		}
+		if ok && uint64(native.GetHeight()) < uint64(height)+uint64(config.WhitelistPeriod) {
+			return scom.PENDING_REASON_WHITELISTED, nil
+		}
+	}
+	if config.HasMethod(params.Method) {
+		return scom.PENDING_REASON_METHOD, nil
+	}
+	if len(config.AmountThresholds) > 0 {
+		assetHash, amount, err := getTransferAsset(native, fromChainID, params)
+		if err != nil {
+			return "", err
+		}
+		threshold := config.GetAmountThreshold(assetHash)
+		if assetHash == nil || threshold == nil || threshold.Sign() <= 0 {
+			return "", nil
+		}
+		amount, err = normalizeTransferAmount(native, params.ToChainID, assetHash, amount)
+		if err != nil {
+			return "", err
+		}
+		if amount.Cmp(threshold) > 0 {
+			return scom.PENDING_REASON_AMOUNT, nil
+		}
+	}
+	return "", nil
+}
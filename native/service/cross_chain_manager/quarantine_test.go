@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckQuarantine(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	nativeAt := func(height uint32) *native.NativeService {
+		ns, _ := native.NewNativeService(db, &types.Transaction{}, 0, height, common.Uint256{}, 0, nil, false)
+		return ns
+	}
+	params := &scom.MakeTxParam{
+		TxHash:              []byte{1},
+		CrossChainID:        []byte{1},
+		FromContractAddress: []byte{2},
+		ToChainID:           3,
+		ToContractAddress:   []byte("lock proxy"),
+		Method:              "unlock",
+		Args:                lockArgs(60),
+	}
+
+	//the height of the chain whitelisted is not recorded before the activation height
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	assert.False(t, scom.WhiteChainHeightEnabled(nativeAt(100)))
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	assert.True(t, scom.WhiteChainHeightEnabled(nativeAt(100)))
+
+	ns := nativeAt(100)
+	reason, err := checkQuarantine(ns, 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+
+	assert.NoError(t, scom.PutQuarantineConfig(ns, &scom.QuarantineConfig{ChainID: 3, WhitelistPeriod: 10,
+		Methods: []string{"upgrade"}, AmountThresholds: []*scom.AssetThreshold{
+			{AssetHash: []byte("asset"), Amount: big.NewInt(50)},
+			{AssetHash: []byte("other"), Amount: big.NewInt(100)},
+		}}))
+	scom.PutWhiteChainHeight(ns, 3, 100)
+	reason, err = checkQuarantine(nativeAt(109), 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, scom.PENDING_REASON_WHITELISTED, reason)
+
+	//the whitelist period is not wrapped
+	quarantineConfig, err := scom.GetQuarantineConfig(ns, 3)
+	assert.NoError(t, err)
+	quarantineConfig.WhitelistPeriod = 0xFFFFFFFF
+	assert.NoError(t, scom.PutQuarantineConfig(ns, quarantineConfig))
+	reason, err = checkQuarantine(nativeAt(200), 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, scom.PENDING_REASON_WHITELISTED, reason)
+	quarantineConfig.WhitelistPeriod = 10
+	assert.NoError(t, scom.PutQuarantineConfig(ns, quarantineConfig))
+
+	//the amount is unknown without the asset bind
+	reason, err = checkQuarantine(nativeAt(110), 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+
+	ns = nativeAt(110)
+	side_chain_manager.PutAssetBind(ns, 2, &side_chain_manager.AssetBind{
+		AssetMap:     map[uint64][]byte{3: []byte("asset")},
+		LockProxyMap: map[uint64][]byte{3: []byte("lock proxy")},
+	})
+	reason, err = checkQuarantine(ns, 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, scom.PENDING_REASON_AMOUNT, reason)
+
+	//the threshold is of the asset transferred
+	args := params.Args
+	params.Args = transferArgs([]byte("other"), big.NewInt(60))
+	reason, err = checkQuarantine(ns, 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	params.Args = transferArgs([]byte("none"), big.NewInt(1000))
+	reason, err = checkQuarantine(ns, 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	params.Args = args
+
+	params.Method = "upgrade"
+	reason, err = checkQuarantine(ns, 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, scom.PENDING_REASON_METHOD, reason)
+
+	configs, err := scom.ListQuarantineConfigs(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(configs))
+	assert.Equal(t, []string{"upgrade"}, configs[0].Methods)
+	assert.Equal(t, 2, len(configs[0].AmountThresholds))
+}
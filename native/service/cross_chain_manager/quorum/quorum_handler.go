@@ -26,6 +26,7 @@ import (
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/quorum"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type QuorumHandler struct{}
@@ -34,6 +35,12 @@ func NewQuorumHandler() *QuorumHandler {
 	return &QuorumHandler{}
 }
 
+func init() {
+	common.RegisterChainHandler(utils.QUORUM_ROUTER, func() common.ChainHandler {
+		return NewQuorumHandler()
+	})
+}
+
 func (this *QuorumHandler) MakeDepositProposal(ns *native.NativeService) (*common.MakeTxParam, error) {
 	params := new(common.EntranceParam)
 	if err := params.Deserialization(pcom.NewZeroCopySource(ns.GetInput())); err != nil {
@@ -53,7 +60,7 @@ func (this *QuorumHandler) MakeDepositProposal(ns *native.NativeService) (*commo
 		return nil, fmt.Errorf("Quorum MakeDepositProposal, failed to deserialize MakeTxParam: %v", err)
 	}
 	if err := common.CheckDoneTx(ns, val.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("Quorum MakeDepositProposal, check done transaction error: %v", err)
+		return nil, fmt.Errorf("Quorum MakeDepositProposal, check done transaction error: %w", err)
 	}
 	if err := common.PutDoneTx(ns, val.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("Quorum MakeDepositProposal, PutDoneTx error: %v", err)
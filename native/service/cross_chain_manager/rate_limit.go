@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Set the rate limit of the transfers from source chain to the target contract, the rate limit is removed if the window is zero
+func SetRateLimit(native *native.NativeService) ([]byte, error) {
+	params := new(scom.RateLimit)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, contract params deserialize error: %v", err)
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, checkWitness error: %v", err)
+	}
+
+	if params.Window == 0 {
+		err = scom.DeleteRateLimit(native, params.FromChainID, params.ToChainID, params.ToContractAddress)
+	} else {
+		err = scom.PutRateLimit(native, params)
+	}
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, store rate limit error: %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States: []interface{}{scom.SET_RATE_LIMIT, params.FromChainID, params.ToChainID, hex.EncodeToString(params.ToContractAddress),
+				params.Window, params.MaxCount, params.MaxAmount.String()},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Get all the rate limits in the order of source chain id and target chain id
+func ListRateLimits(native *native.NativeService) ([]byte, error) {
+	rateLimits, err := scom.ListRateLimits(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListRateLimits, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&scom.RateLimitList{RateLimits: rateLimits}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Count the transfer by the rate limit of its target contract, the result is false if the transfer is over the limit and
+//not counted. The transfer released from pending is counted even if it is over the limit. The count is always limited, and
+//the amount is only limited for the transfers to the lock proxy bound with the args parsed
+func checkRateLimit(native *native.NativeService, fromChainID uint64, params *scom.MakeTxParam, released bool) (bool, error) {
+	rateLimit, err := scom.GetRateLimit(native, fromChainID, params.ToChainID, params.ToContractAddress)
+	if err != nil {
+		return false, err
+	}
+	if rateLimit == nil {
+		return true, nil
+	}
+	assetHash, amount, err := getTransferAsset(native, fromChainID, params)
+	if err != nil {
+		return false, err
+	}
+	if assetHash != nil {
+		amount, err = normalizeTransferAmount(native, params.ToChainID, assetHash, amount)
+		if err != nil {
+			return false, err
+		}
+	}
+	usage, err := scom.GetRateLimitUsage(native, fromChainID, params.ToChainID, params.ToContractAddress)
+	if err != nil {
+		return false, err
+	}
+	usage.Prune(native.GetHeight(), rateLimit.Window)
+	if !released && !usage.Allow(rateLimit, amount) {
+		return false, nil
+	}
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	usage.Add(native.GetHeight(), amount)
+	scom.PutRateLimitUsage(native, fromChainID, params.ToChainID, params.ToContractAddress, usage)
+	return true, nil
+}
+
+//Normalize the amount of the asset on target chain to the decimals of the asset if it is registered
+func normalizeTransferAmount(native *native.NativeService, toChainID uint64, assetHash []byte, amount *big.Int) (*big.Int, error) {
+	asset, err := side_chain_manager.GetAssetByHash(native, toChainID, assetHash)
+	if err != nil {
+		return nil, fmt.Errorf("side_chain_manager.GetAssetByHash error: %v", err)
+	}
+	if asset == nil {
+		return amount, nil
+	}
+	return asset.Normalize(asset.GetChain(toChainID), amount), nil
+}
+
+//Get the target asset hash and the amount of the transfer to the lock proxy bound by the source chain, they are nil if the target
+//contract is not bound or the args are not of lock proxy. The args of lock proxy are the asset hash, the address and the amount
+//of 32 bytes in little endian
+func getTransferAsset(native *native.NativeService, fromChainID uint64, params *scom.MakeTxParam) ([]byte, *big.Int, error) {
+	assetBind, err := side_chain_manager.GetAssetBind(native, fromChainID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("side_chain_manager.GetAssetBind error: %v", err)
+	}
+	lockProxy, ok := assetBind.LockProxyMap[params.ToChainID]
+	if !ok || !bytes.Equal(lockProxy, params.ToContractAddress) {
+		return nil, nil, nil
+	}
+	source := common.NewZeroCopySource(params.Args)
+	assetHash, eof := source.NextVarBytes()
+	if eof {
+		return nil, nil, nil
+	}
+	if _, eof := source.NextVarBytes(); eof {
+		return nil, nil, nil
+	}
+	amountBytes, eof := source.NextBytes(32)
+	if eof {
+		return nil, nil, nil
+	}
+	return assetHash, new(big.Int).SetBytes(common.ToArrayReverse(amountBytes)), nil
+}
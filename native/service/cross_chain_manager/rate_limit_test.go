@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/stretchr/testify/assert"
+)
+
+func lockArgs(amount uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes([]byte("asset"))
+	sink.WriteVarBytes([]byte("address"))
+	amountBytes := [32]byte{}
+	copy(amountBytes[:], common.ToArrayReverse(new(big.Int).SetUint64(amount).Bytes()))
+	sink.WriteBytes(amountBytes[:])
+	return sink.Bytes()
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	ns := newNativeService(nil, nil)
+	params := &scom.MakeTxParam{
+		TxHash:              []byte{1},
+		CrossChainID:        []byte{1},
+		FromContractAddress: []byte{2},
+		ToChainID:           3,
+		ToContractAddress:   []byte("lock proxy"),
+		Method:              "unlock",
+		Args:                lockArgs(60),
+	}
+
+	//no rate limit
+	allowed, err := checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	//the count is limited for the target contract not bound
+	assert.NoError(t, scom.PutRateLimit(ns, &scom.RateLimit{FromChainID: 2, ToChainID: 3, ToContractAddress: []byte("lock proxy"),
+		Window: 10, MaxCount: 1, MaxAmount: big.NewInt(1)}))
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.NoError(t, scom.DeleteRateLimit(ns, 2, 3, []byte("lock proxy")))
+
+	//the amount is counted when the lock proxy is bound
+	side_chain_manager.PutAssetBind(ns, 2, &side_chain_manager.AssetBind{
+		AssetMap:     map[uint64][]byte{3: []byte("asset")},
+		LockProxyMap: map[uint64][]byte{3: []byte("lock proxy")},
+	})
+	assetHash, amount, err := getTransferAsset(ns, 2, params)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("asset"), assetHash)
+	assert.Equal(t, uint64(60), amount.Uint64())
+
+	assert.NoError(t, scom.PutRateLimit(ns, &scom.RateLimit{FromChainID: 2, ToChainID: 3, ToContractAddress: []byte("lock proxy"),
+		Window: 10, MaxCount: 5, MaxAmount: big.NewInt(100)}))
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	//the amount of the args not parsed is not limited, but the count is
+	args := params.Args
+	params.Args = []byte{1}
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	params.Args = args
+
+	assert.NoError(t, pendTransfer(ns, 2, params, scom.PENDING_REASON_RATE_LIMIT))
+	transfers, err := scom.ListPendingTransfers(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, scom.PENDING_REASON_RATE_LIMIT, transfers[0].Reason)
+	assert.Equal(t, params.TxHash, transfers[0].ToMerkleValue.MakeTxParam.TxHash)
+
+	//the transfer released from pending is counted over the limit
+	allowed, err = checkRateLimit(ns, 2, params, true)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	usage, err := scom.GetRateLimitUsage(ns, 2, 3, []byte("lock proxy"))
+	assert.NoError(t, err)
+	count, total := usage.Total()
+	assert.Equal(t, uint64(3), count)
+	assert.Equal(t, big.NewInt(120), total)
+
+	rateLimits, err := scom.ListRateLimits(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rateLimits))
+	assert.NoError(t, scom.DeleteRateLimit(ns, 2, 3, []byte("lock proxy")))
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	//the amount of registered asset is in the decimals of the asset
+	assert.NoError(t, side_chain_manager.PutAsset(ns, &side_chain_manager.Asset{ID: 1, Name: "usdt", Decimals: 8,
+		Chains: []*side_chain_manager.AssetChain{
+			{ChainID: 2, Hash: []byte("lock"), Decimals: 8, Model: side_chain_manager.AssetLock, Supply: big.NewInt(0)},
+			{ChainID: 3, Hash: []byte("asset"), Decimals: 6, Model: side_chain_manager.AssetMint, Supply: big.NewInt(0)},
+		}}))
+	assert.NoError(t, scom.PutRateLimit(ns, &scom.RateLimit{FromChainID: 2, ToChainID: 3, ToContractAddress: []byte("lock proxy"),
+		Window: 10, MaxAmount: big.NewInt(10000)}))
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = checkRateLimit(ns, 2, params, false)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+}
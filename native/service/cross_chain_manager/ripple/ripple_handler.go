@@ -43,6 +43,12 @@ func NewRippleHandler() *RippleHandler {
 	return &RippleHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.RIPPLE_ROUTER, func() scom.ChainHandler {
+		return NewRippleHandler()
+	})
+}
+
 func (this *RippleHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -81,7 +87,7 @@ func (this *RippleHandler) MakeDepositProposal(service *native.NativeService) (*
 			return nil, fmt.Errorf("vote MakeDepositProposal, deserialize MakeTxParam error:%s", err)
 		}
 		if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
-			return nil, fmt.Errorf("vote MakeDepositProposal, check done transaction error:%s", err)
+			return nil, fmt.Errorf("vote MakeDepositProposal, check done transaction error:%w", err)
 		}
 		if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
 			return nil, fmt.Errorf("vote MakeDepositProposal, PutDoneTx error:%s", err)
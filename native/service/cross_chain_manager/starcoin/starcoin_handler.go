@@ -24,6 +24,7 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cmanager "github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -35,6 +36,12 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.STARCOIN_ROUTER, func() scom.ChainHandler {
+		return NewHandler()
+	})
+}
+
 // MakeDepositProposal ...
 func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -52,7 +59,7 @@ func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.Make
 		return nil, fmt.Errorf("Starcoin MakeDepositProposal, verifyFromStarcoinTx error: %s", err)
 	}
 	if err := scom.CheckDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
-		return nil, fmt.Errorf("Starcoin MakeDepositProposal, check done transaction error:%s", err)
+		return nil, fmt.Errorf("Starcoin MakeDepositProposal, check done transaction error:%w", err)
 	}
 	if err := scom.PutDoneTx(service, value.CrossChainID, params.SourceChainID); err != nil {
 		return nil, fmt.Errorf("Starcoin MakeDepositProposal, PutDoneTx error:%s", err)
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Get the supplies of the registered asset on its chains in the order of chain id
+func GetAssetSupply(native *native.NativeService) ([]byte, error) {
+	params := new(side_chain_manager.QueryAssetParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetAssetSupply, contract params deserialize error: %v", err)
+	}
+	asset, err := side_chain_manager.GetAsset(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetAssetSupply, side_chain_manager.GetAsset error: %v", err)
+	}
+	if asset == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetAssetSupply, asset %d is not registered", params.ID)
+	}
+	supplyList := &scom.AssetSupplyList{AssetID: asset.ID, Supplies: make([]*scom.AssetSupply, 0, len(asset.Chains))}
+	for _, chain := range asset.Chains {
+		supply, err := getAssetSupply(native, asset.ID, chain)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("GetAssetSupply, %v", err)
+		}
+		supplyList.Supplies = append(supplyList.Supplies, &scom.AssetSupply{ChainID: chain.ChainID, Amount: supply})
+	}
+	sink := common.NewZeroCopySink(nil)
+	supplyList.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Account the transfer of registered asset in the supplies of source chain and target chain. The transfer is refused
+//if the chain releasing the asset has not enough supply, which means more would be minted or unlocked than was locked.
+//The amount of lock proxy args is in the decimals of the asset on target chain
+func accountAssetSupply(native *native.NativeService, fromChainID uint64, params *scom.MakeTxParam) error {
+	assetHash, amount, err := getTransferAsset(native, fromChainID, params)
+	if err != nil {
+		return err
+	}
+	if amount == nil {
+		return nil
+	}
+	asset, err := side_chain_manager.GetAssetByHash(native, params.ToChainID, assetHash)
+	if err != nil {
+		return fmt.Errorf("side_chain_manager.GetAssetByHash error: %v", err)
+	}
+	if asset == nil {
+		return nil
+	}
+	fromChain, toChain := asset.GetChain(fromChainID), asset.GetChain(params.ToChainID)
+	amount = asset.Normalize(toChain, amount)
+	//the asset is locked or burnt on source chain, and unlocked or minted on target chain. Only the chains of the
+	//asset are tracked, the source chain may be not one of them
+	if fromChain != nil {
+		if err := addAssetSupply(native, asset.ID, fromChain, amount, fromChain.Model == side_chain_manager.AssetLock); err != nil {
+			return err
+		}
+	}
+	return addAssetSupply(native, asset.ID, toChain, amount, toChain.Model == side_chain_manager.AssetMint)
+}
+
+func addAssetSupply(native *native.NativeService, assetID uint64, chain *side_chain_manager.AssetChain, amount *big.Int, increase bool) error {
+	supply, err := getAssetSupply(native, assetID, chain)
+	if err != nil {
+		return err
+	}
+	if increase {
+		supply.Add(supply, amount)
+	} else {
+		if supply.Cmp(amount) < 0 {
+			return fmt.Errorf("supply %s of asset %d on chain %d is less than the amount %s", supply.String(), assetID,
+				chain.ChainID, amount.String())
+		}
+		supply.Sub(supply, amount)
+	}
+	scom.PutAssetSupply(native, assetID, chain.ChainID, supply)
+	return nil
+}
+
+//Get the supply of asset on the chain, it is the registered supply if the chain is not tracked yet
+func getAssetSupply(native *native.NativeService, assetID uint64, chain *side_chain_manager.AssetChain) (*big.Int, error) {
+	supply, err := scom.GetAssetSupply(native, assetID, chain.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if supply == nil {
+		supply = new(big.Int).Set(chain.Supply)
+	}
+	return supply, nil
+}
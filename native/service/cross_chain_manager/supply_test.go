@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cross_chain_manager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/stretchr/testify/assert"
+)
+
+func transferArgs(assetHash []byte, amount *big.Int) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(assetHash)
+	sink.WriteVarBytes([]byte("address"))
+	amountBytes := [32]byte{}
+	copy(amountBytes[:], common.ToArrayReverse(amount.Bytes()))
+	sink.WriteBytes(amountBytes[:])
+	return sink.Bytes()
+}
+
+func TestAccountAssetSupply(t *testing.T) {
+	ns := newNativeService(nil, nil)
+	side_chain_manager.PutAssetBind(ns, 2, &side_chain_manager.AssetBind{
+		AssetMap:     map[uint64][]byte{},
+		LockProxyMap: map[uint64][]byte{3: []byte("proxy 3")},
+	})
+	side_chain_manager.PutAssetBind(ns, 3, &side_chain_manager.AssetBind{
+		AssetMap:     map[uint64][]byte{},
+		LockProxyMap: map[uint64][]byte{2: []byte("proxy 2")},
+	})
+	lockParams := &scom.MakeTxParam{TxHash: []byte{1}, ToChainID: 3, ToContractAddress: []byte("proxy 3"), Method: "unlock",
+		Args: transferArgs([]byte("mint"), big.NewInt(60))}
+	burnParams := &scom.MakeTxParam{TxHash: []byte{2}, ToChainID: 2, ToContractAddress: []byte("proxy 2"), Method: "unlock",
+		Args: transferArgs([]byte("lock"), big.NewInt(40000000000000))}
+
+	//the asset is not registered
+	assert.NoError(t, accountAssetSupply(ns, 2, lockParams))
+	supply, err := scom.GetAssetSupply(ns, 1, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, supply)
+
+	assert.NoError(t, side_chain_manager.PutAsset(ns, &side_chain_manager.Asset{ID: 1, Name: "usdt", Decimals: 18,
+		Chains: []*side_chain_manager.AssetChain{
+			{ChainID: 2, Hash: []byte("lock"), Decimals: 18, Model: side_chain_manager.AssetLock, Supply: big.NewInt(0)},
+			{ChainID: 3, Hash: []byte("mint"), Decimals: 6, Model: side_chain_manager.AssetMint, Supply: big.NewInt(0)},
+		}}))
+	//the amount to mint is in the decimals of target chain
+	assert.NoError(t, accountAssetSupply(ns, 2, lockParams))
+	assert.NoError(t, accountAssetSupply(ns, 3, burnParams))
+	for _, chainID := range []uint64{2, 3} {
+		supply, err = scom.GetAssetSupply(ns, 1, chainID)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(20000000000000), supply)
+	}
+
+	//more can not be burnt than was minted
+	assert.Error(t, accountAssetSupply(ns, 3, burnParams))
+	supply, err = scom.GetAssetSupply(ns, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(20000000000000), supply)
+
+	//the asset is not registered on source chain, only target chain is tracked
+	lockParams.ToChainID = 2
+	lockParams.ToContractAddress = []byte("proxy 2")
+	lockParams.Args = transferArgs([]byte("lock"), big.NewInt(1))
+	side_chain_manager.PutAssetBind(ns, 4, &side_chain_manager.AssetBind{
+		AssetMap:     map[uint64][]byte{},
+		LockProxyMap: map[uint64][]byte{2: []byte("proxy 2")},
+	})
+	assert.NoError(t, accountAssetSupply(ns, 4, lockParams))
+	supply, err = scom.GetAssetSupply(ns, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(19999999999999), supply)
+	supply, err = scom.GetAssetSupply(ns, 1, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, supply)
+}
+
+func TestHeldTransferSupply(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	ns := newNativeService(nil, nil)
+	side_chain_manager.PutAssetBind(ns, 2, &side_chain_manager.AssetBind{
+		AssetMap:     map[uint64][]byte{},
+		LockProxyMap: map[uint64][]byte{3: []byte("proxy 3")},
+	})
+	assert.NoError(t, side_chain_manager.PutAsset(ns, &side_chain_manager.Asset{ID: 1, Name: "usdt", Decimals: 6,
+		Chains: []*side_chain_manager.AssetChain{
+			{ChainID: 2, Hash: []byte("lock"), Decimals: 6, Model: side_chain_manager.AssetLock, Supply: big.NewInt(0)},
+			{ChainID: 3, Hash: []byte("mint"), Decimals: 6, Model: side_chain_manager.AssetMint, Supply: big.NewInt(0)},
+		}}))
+	params := &scom.MakeTxParam{TxHash: []byte{1}, ToChainID: 3, ToContractAddress: []byte("proxy 3"), Method: "unlock",
+		Args: transferArgs([]byte("mint"), big.NewInt(60))}
+	side_chain_manager.PutFee(ns, 3, &side_chain_manager.Fee{View: 1, Fee: big.NewInt(100)})
+
+	//the transfer held for fee is not accounted
+	assert.NoError(t, MakeTransaction(ns, params, 2))
+	supply, err := scom.GetAssetSupply(ns, 1, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, supply)
+
+	//accounted once released
+	scom.PutFeePayment(ns, 2, params.TxHash, big.NewInt(100))
+	txHash := ns.GetTx().Hash()
+	sink := common.NewZeroCopySink(nil)
+	(&scom.HeldTransferParam{ToChainID: 3, TxHash: txHash[:]}).Serialization(sink)
+	_, err = ReleaseHeldTransfer(newNativeService(sink.Bytes(), ns.GetCacheDB()))
+	assert.NoError(t, err)
+	supply, err = scom.GetAssetSupply(ns, 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(60), supply)
+}
+
+func TestPutAssetDeleteSupply(t *testing.T) {
+	ns := newNativeService(nil, nil)
+	lockChain := &side_chain_manager.AssetChain{ChainID: 2, Hash: []byte("lock"), Decimals: 18,
+		Model: side_chain_manager.AssetLock, Supply: big.NewInt(0)}
+	mintChain := &side_chain_manager.AssetChain{ChainID: 3, Hash: []byte("mint"), Decimals: 18,
+		Model: side_chain_manager.AssetMint, Supply: big.NewInt(0)}
+	asset := &side_chain_manager.Asset{ID: 1, Name: "usdt", Decimals: 18,
+		Chains: []*side_chain_manager.AssetChain{lockChain, mintChain}}
+	assert.NoError(t, side_chain_manager.PutAsset(ns, asset))
+	scom.PutAssetSupply(ns, 1, 2, big.NewInt(10))
+	scom.PutAssetSupply(ns, 1, 3, big.NewInt(10))
+
+	//the supply of the chain removed from the asset is deleted
+	asset.Chains = []*side_chain_manager.AssetChain{lockChain}
+	assert.NoError(t, side_chain_manager.PutAsset(ns, asset))
+	supply, err := scom.GetAssetSupply(ns, 1, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, supply)
+	supply, err = scom.GetAssetSupply(ns, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), supply)
+
+	//the chain added again is tracked from the registered supply
+	mintChain.Supply = big.NewInt(5)
+	asset.Chains = []*side_chain_manager.AssetChain{lockChain, mintChain}
+	assert.NoError(t, side_chain_manager.PutAsset(ns, asset))
+	supply, err = getAssetSupply(ns, 1, mintChain)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), supply)
+}
@@ -33,6 +33,9 @@ const (
 	APPROVE_REGISTER_STATE_VALIDATOR = "approveRegisterStateValidator"
 	REMOVE_STATE_VALIDATOR           = "removeStateValidator"
 	APPROVE_REMOVE_STATE_VALIDATOR   = "approveRemoveStateValidator"
+	CANCEL_REGISTER_STATE_VALIDATOR  = "cancelRegisterStateValidator"
+	CANCEL_REMOVE_STATE_VALIDATOR    = "cancelRemoveStateValidator"
+	SWEEP_APPLIES                    = "sweepApplies"
 
 	//key prefix
 	STATE_VALIDATOR           = "stateValidator"
@@ -49,6 +52,9 @@ func RegisterStateValidatorManagerContract(native *native.NativeService) {
 	native.Register(APPROVE_REGISTER_STATE_VALIDATOR, ApproveRegisterStateValidator)
 	native.Register(REMOVE_STATE_VALIDATOR, RemoveStateValidator)
 	native.Register(APPROVE_REMOVE_STATE_VALIDATOR, ApproveRemoveStateValidator)
+	native.Register(CANCEL_REGISTER_STATE_VALIDATOR, CancelRegisterStateValidator)
+	native.Register(CANCEL_REMOVE_STATE_VALIDATOR, CancelRemoveStateValidator)
+	native.Register(SWEEP_APPLIES, SweepApplies)
 }
 
 func GetCurrentStateValidator(native *native.NativeService) ([]byte, error) {
@@ -89,6 +95,16 @@ func ApproveRegisterStateValidator(native *native.NativeService) ([]byte, error)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterStateValidator, getStateValidatorApply error: %v", err)
 	}
+	if svListParam == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterStateValidator, id %d is not applied", params.ID)
+	}
+	expired, err := utils.IsApplyExpired(native, utils.Neo3StateManagerContractAddress, STATE_VALIDATOR_APPLY, utils.GetUint64Bytes(params.ID))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterStateValidator, IsApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterStateValidator, application is expired")
+	}
 	// check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_REGISTER_STATE_VALIDATOR, utils.GetUint64Bytes(params.ID), params.Address)
 	if err != nil {
@@ -103,7 +119,10 @@ func ApproveRegisterStateValidator(native *native.NativeService) ([]byte, error)
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterStateValidator, putStateValidators error: %v", err)
 	}
 
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.Neo3StateManagerContractAddress, []byte(STATE_VALIDATOR_APPLY), utils.GetUint64Bytes(params.ID)))
+	err = deleteStateValidatorApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterStateValidator, deleteStateValidatorApply error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.Neo3StateManagerContractAddress,
@@ -143,6 +162,16 @@ func ApproveRemoveStateValidator(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveStateValidator, getStateValidatorRemove error: %v", err)
 	}
+	if svListParam == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveStateValidator, id %d is not applied", params.ID)
+	}
+	expired, err := utils.IsApplyExpired(native, utils.Neo3StateManagerContractAddress, STATE_VALIDATOR_REMOVE, utils.GetUint64Bytes(params.ID))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveStateValidator, IsApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveStateValidator, application is expired")
+	}
 	// check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_REMOVE_STATE_VALIDATOR, utils.GetUint64Bytes(params.ID), params.Address)
 	if err != nil {
@@ -157,7 +186,10 @@ func ApproveRemoveStateValidator(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveStateValidator, removeStateValidators error: %v", err)
 	}
 
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.Neo3StateManagerContractAddress, []byte(STATE_VALIDATOR_REMOVE), utils.GetUint64Bytes(params.ID)))
+	err = deleteStateValidatorRemove(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveStateValidator, deleteStateValidatorRemove error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.Neo3StateManagerContractAddress,
@@ -165,3 +197,73 @@ func ApproveRemoveStateValidator(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+//Cancel the state validator registration by the applicant
+func CancelRegisterStateValidator(native *native.NativeService) ([]byte, error) {
+	if err := cancelStateValidatorApply(native, getStateValidatorApply, deleteStateValidatorApply,
+		APPROVE_REGISTER_STATE_VALIDATOR, "CancelRegisterStateValidator"); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterStateValidator, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Cancel the state validator removal by the applicant
+func CancelRemoveStateValidator(native *native.NativeService) ([]byte, error) {
+	if err := cancelStateValidatorApply(native, getStateValidatorRemove, deleteStateValidatorRemove,
+		APPROVE_REMOVE_STATE_VALIDATOR, "CancelRemoveStateValidator"); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRemoveStateValidator, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Cancel the application by the applicant, the consensus signs on approving it are removed as well
+func cancelStateValidatorApply(native *native.NativeService, get func(*native.NativeService, uint64) (*StateValidatorListParam, error),
+	remove func(*native.NativeService, uint64) error, approveMethod, eventName string) error {
+	params := new(ApproveStateValidatorParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("contract params deserialize error: %v", err)
+	}
+	if !utils.ApplyExpiryEnabled(native) {
+		return fmt.Errorf("cancelling applies is not activated yet")
+	}
+	// check witness
+	if err := utils.ValidateOwner(native, params.Address); err != nil {
+		return fmt.Errorf("checkWitness error: %v", err)
+	}
+	svListParam, err := get(native, params.ID)
+	if err != nil {
+		return err
+	}
+	if svListParam == nil {
+		return fmt.Errorf("id %d is not applied", params.ID)
+	}
+	if svListParam.Address != params.Address {
+		return fmt.Errorf("address is not the applicant")
+	}
+	if err := remove(native, params.ID); err != nil {
+		return err
+	}
+	if err := node_manager.RemoveProposal(native, approveMethod, utils.GetUint64Bytes(params.ID)); err != nil {
+		return err
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.Neo3StateManagerContractAddress,
+			States:          []interface{}{eventName, params.ID},
+		})
+	return nil
+}
+
+//Remove the expired state validator registrations and removals, it is invoked at commitDpos
+func SweepApplies(native *native.NativeService) ([]byte, error) {
+	if err := node_manager.CheckSweepCaller(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.Neo3StateManagerContractAddress, STATE_VALIDATOR_APPLY, APPROVE_REGISTER_STATE_VALIDATOR, deleteStateValidatorApply); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.Neo3StateManagerContractAddress, STATE_VALIDATOR_REMOVE, APPROVE_REMOVE_STATE_VALIDATOR, deleteStateValidatorRemove); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
@@ -171,6 +171,12 @@ func putStateValidatorApply(native *native.NativeService, stateValidatorListPara
 	sink := common.NewZeroCopySink(nil)
 	stateValidatorListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_APPLY), utils.GetUint64Bytes(applyID)), cstates.GenRawStorageItem(sink.Bytes()))
+	if err := utils.PutApplyInfo(native, contract, STATE_VALIDATOR_APPLY, utils.GetUint64Bytes(applyID)); err != nil {
+		return fmt.Errorf("putStateValidatorApply, %v", err)
+	}
+	if err := utils.AddIndexKey(native, contract, STATE_VALIDATOR_APPLY, utils.GetUint64Bytes(applyID)); err != nil {
+		return fmt.Errorf("putStateValidatorApply, AddIndexKey error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: contract,
@@ -179,6 +185,13 @@ func putStateValidatorApply(native *native.NativeService, stateValidatorListPara
 	return nil
 }
 
+func deleteStateValidatorApply(native *native.NativeService, applyID uint64) error {
+	contract := utils.Neo3StateManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_APPLY), utils.GetUint64Bytes(applyID)))
+	utils.DeleteApplyInfo(native, contract, STATE_VALIDATOR_APPLY, utils.GetUint64Bytes(applyID))
+	return utils.RemoveIndexKey(native, contract, STATE_VALIDATOR_APPLY, utils.GetUint64Bytes(applyID))
+}
+
 func getStateValidatorApplyID(native *native.NativeService) (uint64, error) {
 	contract := utils.Neo3StateManagerContractAddress
 	applyIDStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_APPLY_ID)))
@@ -238,6 +251,12 @@ func putStateValidatorRemove(native *native.NativeService, svListParam *StateVal
 	sink := common.NewZeroCopySink(nil)
 	svListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_REMOVE), utils.GetUint64Bytes(removeID)), cstates.GenRawStorageItem(sink.Bytes()))
+	if err := utils.PutApplyInfo(native, contract, STATE_VALIDATOR_REMOVE, utils.GetUint64Bytes(removeID)); err != nil {
+		return fmt.Errorf("putStateValidatorRemove, %v", err)
+	}
+	if err := utils.AddIndexKey(native, contract, STATE_VALIDATOR_REMOVE, utils.GetUint64Bytes(removeID)); err != nil {
+		return fmt.Errorf("putStateValidatorRemove, AddIndexKey error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: contract,
@@ -246,6 +265,13 @@ func putStateValidatorRemove(native *native.NativeService, svListParam *StateVal
 	return nil
 }
 
+func deleteStateValidatorRemove(native *native.NativeService, removeID uint64) error {
+	contract := utils.Neo3StateManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_REMOVE), utils.GetUint64Bytes(removeID)))
+	utils.DeleteApplyInfo(native, contract, STATE_VALIDATOR_REMOVE, utils.GetUint64Bytes(removeID))
+	return utils.RemoveIndexKey(native, contract, STATE_VALIDATOR_REMOVE, utils.GetUint64Bytes(removeID))
+}
+
 func getStateValidatorRemoveID(native *native.NativeService) (uint64, error) {
 	contract := utils.Neo3StateManagerContractAddress
 	removeIDStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_REMOVE_ID)))
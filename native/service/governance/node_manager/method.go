@@ -18,7 +18,10 @@
 package node_manager
 
 import (
+	"encoding/hex"
 	"fmt"
+
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/utils"
 )
@@ -55,6 +58,16 @@ func executeCommitDpos(native *native.NativeService) error {
 	}
 
 	putPeerPoolMap(native, peerPoolMap, newView)
+	if ProposalEnabled(native) {
+		if err := rebaseProposals(native, peerPoolMap); err != nil {
+			return fmt.Errorf("executeCommitDpos, %v", err)
+		}
+	}
+	if utils.ApplyExpiryEnabled(native) {
+		if err := sweepApplies(native); err != nil {
+			return fmt.Errorf("executeCommitDpos, %v", err)
+		}
+	}
 	oldView := view - 1
 	oldViewBytes := utils.GetUint32Bytes(oldView)
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PEER_POOL), oldViewBytes))
@@ -68,3 +81,50 @@ func executeCommitDpos(native *native.NativeService) error {
 	putGovernanceView(native, governanceView)
 	return nil
 }
+
+//Remove the expired governance applications of node manager and the other governance contracts
+func sweepApplies(native *native.NativeService) error {
+	contract := utils.NodeManagerContractAddress
+	keys, err := utils.ScanApplyKeys(native, contract, PEER_APPLY)
+	if err != nil {
+		return fmt.Errorf("sweepApplies, %v", err)
+	}
+	err = utils.SweepApplies(native, contract, PEER_APPLY, keys, func(key []byte) error {
+		deletePeerApply(native, key)
+		return RemoveProposal(native, APPROVE_CANDIDATE, []byte(hex.EncodeToString(key)))
+	})
+	if err != nil {
+		return fmt.Errorf("sweepApplies, sweep applies of node manager error: %v", err)
+	}
+	for _, address := range []common.Address{utils.SideChainManagerContractAddress,
+		utils.RelayerManagerContractAddress, utils.Neo3StateManagerContractAddress} {
+		if _, err := native.NativeCall(address, SWEEP_APPLIES, nil); err != nil {
+			return fmt.Errorf("sweepApplies, sweep applies of contract %s error: %v", address.ToHexString(), err)
+		}
+	}
+	return nil
+}
+
+//Remove the expired applications of prefix in the governance contract, with the proposals on approving them.
+//The applications are indexed by their uint64 ids, and remove deletes the application with its info and index
+func SweepIndexedApplies(native *native.NativeService, contract common.Address, prefix, approveMethod string,
+	remove func(*native.NativeService, uint64) error) error {
+	keys, err := utils.GetIndexKeys(native, contract, prefix, 8)
+	if err != nil {
+		return fmt.Errorf("SweepIndexedApplies, GetIndexKeys error: %v", err)
+	}
+	return utils.SweepApplies(native, contract, prefix, keys, func(key []byte) error {
+		if err := remove(native, utils.GetBytesUint64(key)); err != nil {
+			return err
+		}
+		return RemoveProposal(native, approveMethod, key)
+	})
+}
+
+//Check the sweeping is invoked by node manager at commitDpos
+func CheckSweepCaller(native *native.NativeService) error {
+	if native.CallingContext() != utils.NodeManagerContractAddress {
+		return fmt.Errorf("sweeping applies is only invoked by node manager at commitDpos")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweepIndexedApplies(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+	contract := utils.RelayerManagerContractAddress
+	key := utils.GetUint64Bytes(1)
+	//application created before the info and index are recorded
+	utils.PutBytes(ns, utils.ConcatKey(contract, []byte("apply"), key), []byte{1})
+	_, _, err := VoteProposal(ns, "approve", key, nil, peers[0].Address, &ProposalConfig{}, false)
+	assert.NoError(t, err)
+
+	removed := make([]uint64, 0)
+	remove := func(native *native.NativeService, id uint64) error {
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte("apply"), utils.GetUint64Bytes(id)))
+		utils.DeleteApplyInfo(native, contract, "apply", utils.GetUint64Bytes(id))
+		removed = append(removed, id)
+		return nil
+	}
+	//the expiry window starts at the first sweeping
+	ns = proposalNative(ns.GetCacheDB(), 10, nil, nil)
+	assert.NoError(t, SweepIndexedApplies(ns, contract, "apply", "approve", remove))
+	assert.Equal(t, 0, len(removed))
+	info, err := utils.GetApplyInfo(ns, contract, "apply", key)
+	assert.NoError(t, err)
+	assert.Equal(t, &utils.ApplyInfo{Height: 10, Expiry: utils.DEFAULT_APPLY_EXPIRY}, info)
+
+	ns = proposalNative(ns.GetCacheDB(), 10+utils.DEFAULT_APPLY_EXPIRY, nil, nil)
+	assert.NoError(t, SweepIndexedApplies(ns, contract, "apply", "approve", remove))
+	assert.Equal(t, []uint64{1}, removed)
+	proposal, err := GetProposal(ns, ProposalID("approve", key))
+	assert.NoError(t, err)
+	assert.Nil(t, proposal)
+}
+
+func TestSetApplyExpiry(t *testing.T) {
+	ns := newProposalNative(newPeers(4))
+	operator, err := GetCurConOperator(ns)
+	assert.NoError(t, err)
+	setExpiry := func(signer common.Address, expiry uint32) error {
+		sink := common.NewZeroCopySink(nil)
+		(&ApplyExpiryParam{Expiry: expiry}).Serialization(sink)
+		tx := &types.Transaction{SignedAddr: []common.Address{signer}}
+		ns, _ := native.NewNativeService(ns.GetCacheDB(), tx, 0, 1, common.Uint256{}, 0, sink.Bytes(), false)
+		_, err := SetApplyExpiry(ns)
+		return err
+	}
+	//the applications are not expired before the activation height
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	assert.Error(t, setExpiry(operator, 10), "not activated")
+	assert.NoError(t, utils.PutApplyInfo(ns, utils.RelayerManagerContractAddress, "apply", []byte{1}))
+	info, err := utils.GetApplyInfo(ns, utils.RelayerManagerContractAddress, "apply", []byte{1})
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	assert.Error(t, setExpiry(common.Address{1}, 10), "not operator")
+	assert.Error(t, setExpiry(operator, 0), "zero expiry")
+	assert.NoError(t, setExpiry(operator, 10))
+
+	expiry, err := utils.GetApplyExpiry(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), expiry)
+	assert.NoError(t, utils.PutApplyInfo(ns, utils.RelayerManagerContractAddress, "apply", []byte{1}))
+	info, err = utils.GetApplyInfo(ns, utils.RelayerManagerContractAddress, "apply", []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), info.Expiry)
+}
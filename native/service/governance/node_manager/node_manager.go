@@ -45,6 +45,15 @@ const (
 	QUIT_NODE            = "quitNode"
 	UPDATE_CONFIG        = "updateConfig"
 	COMMIT_DPOS          = "commitDpos"
+	GET_PEER_POOL        = "getPeerPool"
+	GET_GOVERNANCE_VIEW  = "getGovernanceView"
+	REVOKE_VOTE          = "revokeVote"
+	GET_PROPOSAL         = "getProposal"
+	GET_OPEN_PROPOSALS   = "getOpenProposals"
+	SET_PROPOSAL_CONFIG  = "setProposalConfig"
+	SET_APPLY_EXPIRY     = "setApplyExpiry"
+	//function of the other governance contracts called at commitDpos
+	SWEEP_APPLIES = "sweepApplies"
 
 	//key prefix
 	GOVERNANCE_VIEW = "governanceView"
@@ -55,9 +64,13 @@ const (
 	PEER_INDEX      = "peerIndex"
 	BLACK_LIST      = "blackList"
 	CONSENSUS_SIGNS = "consensusSigns"
+	PROPOSAL        = "proposal"
+	PROPOSAL_CONFIG = "proposalConfig"
 
 	//const
 	MIN_PEER_NUM = 4
+	//blocks the proposals stay open since their first votes if the expiry is not set by governance
+	DEFAULT_PROPOSAL_EXPIRY = 100000
 )
 
 //Register methods of node_manager contract
@@ -71,6 +84,13 @@ func RegisterNodeManagerContract(native *native.NativeService) {
 	native.Register(WHITE_NODE, WhiteNode)
 	native.Register(UPDATE_CONFIG, UpdateConfig)
 	native.Register(COMMIT_DPOS, CommitDpos)
+	native.Register(GET_PEER_POOL, QueryPeerPool)
+	native.Register(GET_GOVERNANCE_VIEW, QueryGovernanceView)
+	native.Register(REVOKE_VOTE, RevokeVote)
+	native.Register(GET_PROPOSAL, QueryProposal)
+	native.Register(GET_OPEN_PROPOSALS, QueryOpenProposals)
+	native.Register(SET_PROPOSAL_CONFIG, SetProposalConfig)
+	native.Register(SET_APPLY_EXPIRY, SetApplyExpiry)
 }
 
 //Init node_manager contract
@@ -191,7 +211,17 @@ func RegisterCandidate(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("registerCandidate, GetPeerApply error: %v", err)
 	}
 	if peer != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("registerCandidate, peer already applied")
+		expired, err := utils.IsApplyExpired(native, contract, PEER_APPLY, peerPubkeyPrefix)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("registerCandidate, IsApplyExpired error: %v", err)
+		}
+		if !expired {
+			return utils.BYTE_FALSE, fmt.Errorf("registerCandidate, peer already applied")
+		}
+		//the consensus signs on the expired apply are discarded
+		if err := RemoveProposal(native, APPROVE_CANDIDATE, []byte(params.PeerPubkey)); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("registerCandidate, %v", err)
+		}
 	}
 
 	//get current view
@@ -228,7 +258,6 @@ func UnRegisterCandidate(native *native.NativeService) ([]byte, error) {
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("unRegisterCandidate, contract params deserialize error: %v", err)
 	}
-	contract := utils.NodeManagerContractAddress
 
 	//check witness
 	err := utils.ValidateOwner(native, params.Address)
@@ -253,7 +282,10 @@ func UnRegisterCandidate(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("unRegisterCandidate, peerPubkey format error: %v", err)
 	}
-	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PEER_APPLY), peerPubkeyPrefix))
+	deletePeerApply(native, peerPubkeyPrefix)
+	if err := RemoveProposal(native, APPROVE_CANDIDATE, []byte(params.PeerPubkey)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("unRegisterCandidate, %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -281,9 +313,20 @@ func ApproveCandidate(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, GetPeerApply error: %v", err)
 	}
+	peerPubkeyPrefix, err := hex.DecodeString(params.PeerPubkey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, peerPubkey format error: %v", err)
+	}
 	if peer == nil {
 		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, peer is not applied")
 	}
+	expired, err := utils.IsApplyExpired(native, contract, PEER_APPLY, peerPubkeyPrefix)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, IsApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, peer apply is expired")
+	}
 
 	//check consensus signs
 	ok, err := CheckConsensusSigns(native, APPROVE_CANDIDATE, []byte(params.PeerPubkey), params.Address)
@@ -300,10 +343,6 @@ func ApproveCandidate(native *native.NativeService) ([]byte, error) {
 	}
 
 	//check if has index
-	peerPubkeyPrefix, err := hex.DecodeString(peer.PeerPubkey)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, peerPubkey format error: %v", err)
-	}
 	indexBytes, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PEER_INDEX), peerPubkeyPrefix))
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("approveCandidate, get indexBytes error: %v", err)
@@ -346,7 +385,7 @@ func ApproveCandidate(native *native.NativeService) ([]byte, error) {
 	peerPoolMap.PeerPoolMap[params.PeerPubkey] = peerPoolItem
 	putPeerPoolMap(native, peerPoolMap, view)
 
-	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PEER_APPLY), peerPubkeyPrefix))
+	deletePeerApply(native, peerPubkeyPrefix)
 
 	native.AddNotify(
 		&event.NotifyEventInfo{
@@ -647,3 +686,180 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+//Get the peer pool of view, the current view is used if the view is 0
+func QueryPeerPool(native *native.NativeService) ([]byte, error) {
+	params := new(ViewParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryPeerPool, contract params deserialize error: %v", err)
+	}
+	view := params.View
+	if view == 0 {
+		var err error
+		view, err = GetView(native)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("QueryPeerPool, get view error: %v", err)
+		}
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryPeerPool, get peerPoolMap error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	peerPoolMap.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the current governance view
+func QueryGovernanceView(native *native.NativeService) ([]byte, error) {
+	governanceView, err := GetGovernanceView(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryGovernanceView, get governanceView error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	governanceView.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Revoke the vote of consensus peer on the open proposal, the proposal is removed if no vote is left
+func RevokeVote(native *native.NativeService) ([]byte, error) {
+	params := new(RevokeVoteParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RevokeVote, contract params deserialize error: %v", err)
+	}
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RevokeVote, checkWitness error: %v", err)
+	}
+
+	proposal, err := GetProposal(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RevokeVote, %v", err)
+	}
+	if proposal == nil || proposal.Status != ProposalOpen || isProposalExpired(native, proposal) {
+		return utils.BYTE_FALSE, fmt.Errorf("RevokeVote, proposal %s is not open", params.ID.ToHexString())
+	}
+	if _, ok := proposal.Votes[params.Address]; !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("RevokeVote, address %s has not voted", params.Address.ToBase58())
+	}
+	delete(proposal.Votes, params.Address)
+	if len(proposal.Votes) == 0 {
+		err = deleteProposal(native, proposal.ID)
+	} else {
+		err = putProposal(native, proposal)
+	}
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RevokeVote, store proposal error: %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{REVOKE_VOTE, params.ID.ToHexString(), params.Address.ToBase58()},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Get the proposal of id
+func QueryProposal(native *native.NativeService) ([]byte, error) {
+	params := new(ProposalParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryProposal, contract params deserialize error: %v", err)
+	}
+	proposal, err := GetProposal(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryProposal, %v", err)
+	}
+	if proposal == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryProposal, proposal %s not found", params.ID.ToHexString())
+	}
+	sink := common.NewZeroCopySink(nil)
+	proposal.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the open proposals which are not expired
+func QueryOpenProposals(native *native.NativeService) ([]byte, error) {
+	proposals, err := ListOpenProposals(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryOpenProposals, %v", err)
+	}
+	peers, err := GetConsensusAddresses(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryOpenProposals, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&ProposalList{ConsensusPeers: uint32(len(peers)), Proposals: proposals}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Set the voting rule of the proposals made later, the quorum must be 0 for 2/3 of the consensus peers, or more
+//than half of current consensus peers
+func SetProposalConfig(native *native.NativeService) ([]byte, error) {
+	params := new(ProposalConfig)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetProposalConfig, contract params deserialize error: %v", err)
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetProposalConfig, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetProposalConfig, checkWitness error: %v", err)
+	}
+
+	peers, err := GetConsensusAddresses(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetProposalConfig, %v", err)
+	}
+	if params.Quorum != 0 && 2*int(params.Quorum) <= len(peers) {
+		return utils.BYTE_FALSE, fmt.Errorf("SetProposalConfig, quorum %d is not more than half of %d consensus peers",
+			params.Quorum, len(peers))
+	}
+
+	putProposalConfig(native, params)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{SET_PROPOSAL_CONFIG, params.Quorum, params.Expiry},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Set the blocks the governance applications created later stay valid, the applications are swept at commitDpos
+//once expired
+func SetApplyExpiry(native *native.NativeService) ([]byte, error) {
+	params := new(ApplyExpiryParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetApplyExpiry, contract params deserialize error: %v", err)
+	}
+	if !utils.ApplyExpiryEnabled(native) {
+		return utils.BYTE_FALSE, fmt.Errorf("SetApplyExpiry, apply expiry is not activated yet")
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetApplyExpiry, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetApplyExpiry, checkWitness error: %v", err)
+	}
+	if params.Expiry == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("SetApplyExpiry, expiry should be positive")
+	}
+
+	utils.PutApplyExpiry(native, params.Expiry)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{SET_APPLY_EXPIRY, params.Expiry},
+		})
+	return utils.BYTE_TRUE, nil
+}
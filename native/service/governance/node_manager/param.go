@@ -137,3 +137,82 @@ func (this *UpdateConfigParam) Deserialization(source *common.ZeroCopySource) er
 	this.Configuration = configuration
 	return nil
 }
+
+type ViewParam struct {
+	View uint32
+}
+
+func (this *ViewParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.View)
+}
+
+func (this *ViewParam) Deserialization(source *common.ZeroCopySource) error {
+	view, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize view error")
+	}
+	this.View = view
+	return nil
+}
+
+type ProposalParam struct {
+	ID common.Uint256
+}
+
+func (this *ProposalParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteHash(this.ID)
+}
+
+func (this *ProposalParam) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextHash()
+	if eof {
+		return fmt.Errorf("source.NextHash, deserialize id error")
+	}
+	this.ID = id
+	return nil
+}
+
+type RevokeVoteParam struct {
+	ID      common.Uint256
+	Address common.Address
+}
+
+func (this *RevokeVoteParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteHash(this.ID)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *RevokeVoteParam) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextHash()
+	if eof {
+		return fmt.Errorf("source.NextHash, deserialize id error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	this.ID = id
+	this.Address = addr
+	return nil
+}
+
+type ApplyExpiryParam struct {
+	Expiry uint32
+}
+
+func (this *ApplyExpiryParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.Expiry)
+}
+
+func (this *ApplyExpiryParam) Deserialization(source *common.ZeroCopySource) error {
+	expiry, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize expiry error")
+	}
+	this.Expiry = expiry
+	return nil
+}
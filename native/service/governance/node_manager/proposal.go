@@ -0,0 +1,338 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//Check if the consensus votes are kept as proposals, the legacy votes are used before
+func ProposalEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetProposalHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+//Get the voting rule of the proposals set by governance, the default rule is used if it is not set
+func GetProposalConfig(native *native.NativeService) (*ProposalConfig, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL_CONFIG)))
+	if err != nil {
+		return nil, fmt.Errorf("GetProposalConfig, get proposal config store error: %v", err)
+	}
+	if store == nil {
+		return &ProposalConfig{Expiry: DEFAULT_PROPOSAL_EXPIRY}, nil
+	}
+	configBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetProposalConfig, deserialize from raw storage item err:%v", err)
+	}
+	proposalConfig := new(ProposalConfig)
+	if err := proposalConfig.Deserialization(common.NewZeroCopySource(configBytes)); err != nil {
+		return nil, fmt.Errorf("GetProposalConfig, deserialize proposal config error: %v", err)
+	}
+	return proposalConfig, nil
+}
+
+func putProposalConfig(native *native.NativeService, proposalConfig *ProposalConfig) {
+	sink := common.NewZeroCopySink(nil)
+	proposalConfig.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL_CONFIG)), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+//Get the id of proposal from its scope and content
+func ProposalID(scope string, content []byte) common.Uint256 {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteString(scope)
+	sink.WriteVarBytes(content)
+	return sha256.Sum256(sink.Bytes())
+}
+
+//Get the proposal of id, nil is returned if the proposal does not exist
+func GetProposal(native *native.NativeService, id common.Uint256) (*Proposal, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL), id[:]))
+	if err != nil {
+		return nil, fmt.Errorf("GetProposal, get proposal store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	proposalBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetProposal, deserialize from raw storage item err:%v", err)
+	}
+	proposal := new(Proposal)
+	if err := proposal.Deserialization(common.NewZeroCopySource(proposalBytes)); err != nil {
+		return nil, fmt.Errorf("GetProposal, deserialize proposal error: %v", err)
+	}
+	return proposal, nil
+}
+
+//Open proposals are indexed so that they can be listed and rebased
+func putProposal(native *native.NativeService, proposal *Proposal) error {
+	contract := utils.NodeManagerContractAddress
+	sink := common.NewZeroCopySink(nil)
+	proposal.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PROPOSAL), proposal.ID[:]), cstates.GenRawStorageItem(sink.Bytes()))
+	if proposal.Status == ProposalOpen {
+		return utils.AddIndexKey(native, contract, PROPOSAL, proposal.ID[:])
+	}
+	return utils.RemoveIndexKey(native, contract, PROPOSAL, proposal.ID[:])
+}
+
+func deleteProposal(native *native.NativeService, id common.Uint256) error {
+	contract := utils.NodeManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PROPOSAL), id[:]))
+	return utils.RemoveIndexKey(native, contract, PROPOSAL, id[:])
+}
+
+//Remove the proposal of scope and content with its votes, e.g. when the subject voted is withdrawn. There is no
+//proposal to remove before the proposals are enabled
+func RemoveProposal(native *native.NativeService, scope string, content []byte) error {
+	if !ProposalEnabled(native) {
+		return nil
+	}
+	if err := deleteProposal(native, ProposalID(scope, content)); err != nil {
+		return fmt.Errorf("RemoveProposal, %v", err)
+	}
+	return nil
+}
+
+//List the open proposals which are not expired in the order of id
+func ListOpenProposals(native *native.NativeService) ([]*Proposal, error) {
+	keys, err := utils.GetIndexKeys(native, utils.NodeManagerContractAddress, PROPOSAL, common.UINT256_SIZE)
+	if err != nil {
+		return nil, fmt.Errorf("ListOpenProposals, GetIndexKeys error: %v", err)
+	}
+	proposals := make([]*Proposal, 0, len(keys))
+	for _, key := range keys {
+		id, err := common.Uint256ParseFromBytes(key)
+		if err != nil {
+			return nil, fmt.Errorf("ListOpenProposals, parse proposal id error: %v", err)
+		}
+		proposal, err := GetProposal(native, id)
+		if err != nil {
+			return nil, fmt.Errorf("ListOpenProposals, %v", err)
+		}
+		if proposal != nil && proposal.Status == ProposalOpen && !isProposalExpired(native, proposal) {
+			proposals = append(proposals, proposal)
+		}
+	}
+	return proposals, nil
+}
+
+func isProposalExpired(native *native.NativeService, proposal *Proposal) bool {
+	return proposal.Expiry > 0 && uint64(native.GetHeight()) >= uint64(proposal.Height)+uint64(proposal.Expiry)
+}
+
+//Votes needed to pass the proposal among sum consensus peers, the custom quorum is capped by sum
+func (this *Proposal) RequiredVotes(sum int) int {
+	if this.Quorum == 0 {
+		return (2*sum + 2) / 3
+	}
+	if int(this.Quorum) > sum {
+		return sum
+	}
+	return int(this.Quorum)
+}
+
+func consensusAddresses(peerPoolMap *PeerPoolMap) (map[common.Address]bool, error) {
+	addresses := make(map[common.Address]bool)
+	for key, v := range peerPoolMap.PeerPoolMap {
+		if v.Status != ConsensusStatus {
+			continue
+		}
+		k, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("hex.DecodeString public key error: %v", err)
+		}
+		publicKey, err := keypair.DeserializePublicKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("keypair.DeserializePublicKey error: %v", err)
+		}
+		addresses[types.AddressFromPubKey(publicKey)] = true
+	}
+	return addresses, nil
+}
+
+//Get the addresses of the consensus peers in current view
+func GetConsensusAddresses(native *native.NativeService) (map[common.Address]bool, error) {
+	view, err := GetView(native)
+	if err != nil {
+		return nil, fmt.Errorf("GetView error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, view)
+	if err != nil {
+		return nil, fmt.Errorf("GetPeerPoolMap error: %v", err)
+	}
+	return consensusAddresses(peerPoolMap)
+}
+
+func newProposal(native *native.NativeService, id common.Uint256, scope string, content []byte,
+	proposalConfig *ProposalConfig) *Proposal {
+	return &Proposal{
+		ID:      id,
+		Scope:   scope,
+		Content: content,
+		Quorum:  proposalConfig.Quorum,
+		Expiry:  proposalConfig.Expiry,
+		Height:  native.GetHeight(),
+		Status:  ProposalOpen,
+		Votes:   make(map[common.Address][]byte),
+	}
+}
+
+//Move the legacy votes on the subject into the proposal of scope and content, it is done only if the proposal does
+//not exist so that the votes made before the proposals are enabled are not lost. The votes of the addresses out of
+//consensus peers are dropped
+func MigrateVotes(native *native.NativeService, scope string, content []byte, votes map[common.Address][]byte,
+	proposalConfig *ProposalConfig) error {
+	if len(votes) == 0 {
+		return nil
+	}
+	id := ProposalID(scope, content)
+	proposal, err := GetProposal(native, id)
+	if err != nil {
+		return fmt.Errorf("MigrateVotes, %v", err)
+	}
+	if proposal != nil {
+		return nil
+	}
+	peers, err := GetConsensusAddresses(native)
+	if err != nil {
+		return fmt.Errorf("MigrateVotes, %v", err)
+	}
+	proposal = newProposal(native, id, scope, content, proposalConfig)
+	for addr, vote := range votes {
+		if peers[addr] {
+			proposal.Votes[addr] = vote
+		}
+	}
+	if len(proposal.Votes) == 0 {
+		return nil
+	}
+	if err := putProposal(native, proposal); err != nil {
+		return fmt.Errorf("MigrateVotes, putProposal error: %v", err)
+	}
+	return nil
+}
+
+//Vote for the proposal of scope and content by the consensus peer, the vote data is kept with the vote.
+//The proposal is created with the config on its first vote or after it expires, true is returned once the votes
+//reach the quorum. If once is true the passed proposal is kept so that it never passes again, otherwise it is
+//removed and can be voted again
+func VoteProposal(native *native.NativeService, scope string, content, vote []byte, address common.Address,
+	proposalConfig *ProposalConfig, once bool) (*Proposal, bool, error) {
+	peers, err := GetConsensusAddresses(native)
+	if err != nil {
+		return nil, false, fmt.Errorf("VoteProposal, %v", err)
+	}
+	if !peers[address] {
+		return nil, false, fmt.Errorf("VoteProposal, address %s is not consensus peer", address.ToBase58())
+	}
+
+	id := ProposalID(scope, content)
+	proposal, err := GetProposal(native, id)
+	if err != nil {
+		return nil, false, fmt.Errorf("VoteProposal, %v", err)
+	}
+	if proposal != nil && proposal.Status == ProposalPassed {
+		return proposal, false, nil
+	}
+	if proposal == nil || isProposalExpired(native, proposal) {
+		proposal = newProposal(native, id, scope, content, proposalConfig)
+	}
+	if _, ok := proposal.Votes[address]; !ok {
+		proposal.Votes[address] = vote
+	}
+
+	num := 0
+	for addr := range proposal.Votes {
+		if peers[addr] {
+			num = num + 1
+		}
+	}
+	if num < proposal.RequiredVotes(len(peers)) {
+		if err := putProposal(native, proposal); err != nil {
+			return nil, false, fmt.Errorf("VoteProposal, putProposal error: %v", err)
+		}
+		return proposal, false, nil
+	}
+
+	if once {
+		proposal.Status = ProposalPassed
+		err = putProposal(native, proposal)
+	} else {
+		err = deleteProposal(native, id)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("VoteProposal, store passed proposal error: %v", err)
+	}
+	return proposal, true, nil
+}
+
+//Rebase the open proposals on the new consensus peers, the expired proposals are removed and the votes of the peers
+//out of consensus are dropped
+func rebaseProposals(native *native.NativeService, peerPoolMap *PeerPoolMap) error {
+	peers, err := consensusAddresses(peerPoolMap)
+	if err != nil {
+		return fmt.Errorf("rebaseProposals, %v", err)
+	}
+	keys, err := utils.GetIndexKeys(native, utils.NodeManagerContractAddress, PROPOSAL, common.UINT256_SIZE)
+	if err != nil {
+		return fmt.Errorf("rebaseProposals, GetIndexKeys error: %v", err)
+	}
+	for _, key := range keys {
+		id, err := common.Uint256ParseFromBytes(key)
+		if err != nil {
+			return fmt.Errorf("rebaseProposals, parse proposal id error: %v", err)
+		}
+		proposal, err := GetProposal(native, id)
+		if err != nil {
+			return fmt.Errorf("rebaseProposals, %v", err)
+		}
+		if proposal == nil || proposal.Status != ProposalOpen {
+			if err := utils.RemoveIndexKey(native, utils.NodeManagerContractAddress, PROPOSAL, key); err != nil {
+				return fmt.Errorf("rebaseProposals, RemoveIndexKey error: %v", err)
+			}
+			continue
+		}
+		for addr := range proposal.Votes {
+			if !peers[addr] {
+				delete(proposal.Votes, addr)
+			}
+		}
+		if len(proposal.Votes) == 0 || isProposalExpired(native, proposal) {
+			err = deleteProposal(native, id)
+		} else {
+			err = putProposal(native, proposal)
+		}
+		if err != nil {
+			return fmt.Errorf("rebaseProposals, store proposal error: %v", err)
+		}
+	}
+	return nil
+}
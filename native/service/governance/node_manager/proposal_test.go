@@ -0,0 +1,271 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPeers(n int) []*account.Account {
+	accts := make([]*account.Account, 0, n)
+	for i := 0; i < n; i++ {
+		accts = append(accts, account.NewAccount(""))
+	}
+	return accts
+}
+
+func newPeerPoolMap(accts []*account.Account) *PeerPoolMap {
+	peerPoolMap := &PeerPoolMap{
+		PeerPoolMap: make(map[string]*PeerPoolItem),
+	}
+	for i, acct := range accts {
+		pubkey := hex.EncodeToString(keypair.SerializePublicKey(acct.PublicKey))
+		peerPoolMap.PeerPoolMap[pubkey] = &PeerPoolItem{
+			Index:      uint32(i + 1),
+			PeerPubkey: pubkey,
+			Address:    acct.Address,
+			Status:     ConsensusStatus,
+		}
+	}
+	return peerPoolMap
+}
+
+func newProposalNative(accts []*account.Account) *native.NativeService {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns := proposalNative(db, 1, nil, nil)
+	putGovernanceView(ns, &GovernanceView{View: 1, Height: 1})
+	putPeerPoolMap(ns, newPeerPoolMap(accts), 1)
+	return ns
+}
+
+func proposalNative(db *storage.CacheDB, height uint32, signer *account.Account, args []byte) *native.NativeService {
+	tx := &types.Transaction{}
+	if signer != nil {
+		tx.SignedAddr = []common.Address{signer.Address}
+	}
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{}, 0, args, false)
+	return ns
+}
+
+func TestVoteProposalOnce(t *testing.T) {
+	peers := newPeers(7)
+	ns := newProposalNative(peers)
+	config := &ProposalConfig{}
+
+	for i := 0; i < 4; i++ {
+		_, ok, err := VoteProposal(ns, "test", []byte{1}, nil, peers[i].Address, config, true)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	}
+	//votes out of consensus peers are rejected
+	_, _, err := VoteProposal(ns, "test", []byte{1}, nil, account.NewAccount("").Address, config, true)
+	assert.Error(t, err)
+	proposal, err := GetProposal(ns, ProposalID("test", []byte{1}))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(proposal.Votes))
+
+	proposals, err := ListOpenProposals(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(proposals))
+	assert.Equal(t, ProposalID("test", []byte{1}), proposals[0].ID)
+
+	proposal, ok, err := VoteProposal(ns, "test", []byte{1}, nil, peers[4].Address, config, true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, ProposalPassed, proposal.Status)
+
+	//passed only once
+	_, ok, err = VoteProposal(ns, "test", []byte{1}, nil, peers[5].Address, config, true)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	proposals, err = ListOpenProposals(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(proposals))
+}
+
+func TestVoteProposalAgain(t *testing.T) {
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+	config := &ProposalConfig{Quorum: 2}
+
+	for round := 0; round < 2; round++ {
+		_, ok, err := VoteProposal(ns, "test", []byte{1}, []byte{2}, peers[0].Address, config, false)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		_, ok, err = VoteProposal(ns, "test", []byte{1}, []byte{3}, peers[1].Address, config, false)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		proposal, err := GetProposal(ns, ProposalID("test", []byte{1}))
+		assert.NoError(t, err)
+		assert.Nil(t, proposal)
+	}
+}
+
+func TestVoteProposalExpiry(t *testing.T) {
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+	config := &ProposalConfig{Expiry: 10}
+
+	for i := 0; i < 2; i++ {
+		_, ok, err := VoteProposal(ns, "test", []byte{1}, nil, peers[i].Address, config, false)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	ns = proposalNative(ns.GetCacheDB(), 11, nil, nil)
+	proposals, err := ListOpenProposals(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(proposals))
+
+	//votes before expiry are dropped
+	proposal, ok, err := VoteProposal(ns, "test", []byte{1}, nil, peers[2].Address, config, false)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1, len(proposal.Votes))
+	assert.Equal(t, uint32(11), proposal.Height)
+}
+
+func TestRevokeVote(t *testing.T) {
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+	config := &ProposalConfig{}
+	id := ProposalID("test", []byte{1})
+
+	for i := 0; i < 2; i++ {
+		_, _, err := VoteProposal(ns, "test", []byte{1}, nil, peers[i].Address, config, false)
+		assert.NoError(t, err)
+	}
+
+	sink := common.NewZeroCopySink(nil)
+	(&RevokeVoteParam{ID: id, Address: peers[0].Address}).Serialization(sink)
+	_, err := RevokeVote(proposalNative(ns.GetCacheDB(), 1, peers[1], sink.Bytes()))
+	assert.Error(t, err)
+	_, err = RevokeVote(proposalNative(ns.GetCacheDB(), 1, peers[0], sink.Bytes()))
+	assert.NoError(t, err)
+	_, err = RevokeVote(proposalNative(ns.GetCacheDB(), 1, peers[0], sink.Bytes()))
+	assert.Error(t, err)
+
+	//revoked vote is not counted
+	_, ok, err := VoteProposal(ns, "test", []byte{1}, nil, peers[2].Address, config, false)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = VoteProposal(ns, "test", []byte{1}, nil, peers[3].Address, config, false)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRebaseProposals(t *testing.T) {
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+
+	_, _, err := VoteProposal(ns, "test", []byte{1}, nil, peers[0].Address, &ProposalConfig{}, false)
+	assert.NoError(t, err)
+	_, _, err = VoteProposal(ns, "test", []byte{1}, nil, peers[1].Address, &ProposalConfig{}, false)
+	assert.NoError(t, err)
+	_, _, err = VoteProposal(ns, "test", []byte{2}, nil, peers[0].Address, &ProposalConfig{}, false)
+	assert.NoError(t, err)
+
+	newPeerList := append(peers[1:], newPeers(1)...)
+	assert.NoError(t, rebaseProposals(ns, newPeerPoolMap(newPeerList)))
+
+	proposals, err := ListOpenProposals(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(proposals))
+	assert.Equal(t, ProposalID("test", []byte{1}), proposals[0].ID)
+	assert.Equal(t, 1, len(proposals[0].Votes))
+	_, ok := proposals[0].Votes[peers[1].Address]
+	assert.True(t, ok)
+}
+
+func TestCheckConsensusSignsMigration(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+
+	//legacy signs before the proposals are enabled
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	ok, err := CheckConsensusSigns(ns, "test", []byte{1}, peers[0].Address)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	proposal, err := GetProposal(ns, ProposalID("test", []byte{1}))
+	assert.NoError(t, err)
+	assert.Nil(t, proposal)
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	_, err = CheckConsensusSigns(ns, "test", []byte{1}, account.NewAccount("").Address)
+	assert.Error(t, err, "signer is not consensus peer")
+	ok, err = CheckConsensusSigns(ns, "test", []byte{1}, peers[1].Address)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	consensusSigns, err := getConsensusSigns(ns, sha256.Sum256(append([]byte("test"), 1)))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(consensusSigns.SignsMap))
+
+	//the legacy sign is counted
+	ok, err = CheckConsensusSigns(ns, "test", []byte{1}, peers[2].Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSetProposalConfig(t *testing.T) {
+	peers := newPeers(4)
+	ns := newProposalNative(peers)
+	proposalConfig, err := GetProposalConfig(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, &ProposalConfig{Expiry: DEFAULT_PROPOSAL_EXPIRY}, proposalConfig)
+
+	operator, err := GetCurConOperator(ns)
+	assert.NoError(t, err)
+	setConfig := func(quorum, expiry uint32) error {
+		sink := common.NewZeroCopySink(nil)
+		(&ProposalConfig{Quorum: quorum, Expiry: expiry}).Serialization(sink)
+		tx := &types.Transaction{SignedAddr: []common.Address{operator}}
+		ns, _ := native.NewNativeService(ns.GetCacheDB(), tx, 0, 1, common.Uint256{}, 0, sink.Bytes(), false)
+		_, err := SetProposalConfig(ns)
+		return err
+	}
+	assert.Error(t, setConfig(2, 10), "quorum is not more than half of peers")
+	assert.NoError(t, setConfig(3, 10))
+	proposalConfig, err = GetProposalConfig(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, &ProposalConfig{Quorum: 3, Expiry: 10}, proposalConfig)
+
+	proposal, _, err := VoteProposal(ns, "test", []byte{1}, nil, peers[0].Address, proposalConfig, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), proposal.Quorum)
+	assert.Equal(t, 3, proposal.RequiredVotes(len(peers)))
+}
@@ -179,6 +179,169 @@ func (this *GovernanceView) Deserialization(source *common.ZeroCopySource) error
 	return nil
 }
 
+type ProposalStatus uint8
+
+const (
+	ProposalOpen ProposalStatus = iota
+	ProposalPassed
+)
+
+//Proposal voted by the consensus peers, it is identified by the hash of its scope and content
+type Proposal struct {
+	ID      common.Uint256
+	Scope   string                    //method or contract the proposal is voted for
+	Content []byte                    //subject of the votes in the scope
+	Quorum  uint32                    //votes to pass the proposal, 0 for 2/3 of the consensus peers
+	Expiry  uint32                    //blocks the proposal stays open since its first vote, 0 for never expiring
+	Height  uint32                    //height of the first vote
+	Status  ProposalStatus            //open or passed
+	Votes   map[common.Address][]byte //voters with the data, e.g. signature, carried by their votes
+}
+
+func (this *Proposal) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteHash(this.ID)
+	sink.WriteString(this.Scope)
+	sink.WriteVarBytes(this.Content)
+	sink.WriteUint32(this.Quorum)
+	sink.WriteUint32(this.Expiry)
+	sink.WriteUint32(this.Height)
+	sink.WriteUint8(uint8(this.Status))
+	sink.WriteVarUint(uint64(len(this.Votes)))
+	voters := make([]common.Address, 0, len(this.Votes))
+	for k := range this.Votes {
+		voters = append(voters, k)
+	}
+	sort.SliceStable(voters, func(i, j int) bool {
+		return voters[i].ToHexString() > voters[j].ToHexString()
+	})
+	for _, v := range voters {
+		sink.WriteVarBytes(v[:])
+		sink.WriteVarBytes(this.Votes[v])
+	}
+}
+
+func (this *Proposal) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextHash()
+	if eof {
+		return fmt.Errorf("source.NextHash, deserialize id error")
+	}
+	scope, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("source.NextString, deserialize scope error")
+	}
+	content, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize content error")
+	}
+	quorum, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize quorum error")
+	}
+	expiry, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize expiry error")
+	}
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize height error")
+	}
+	status, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("source.NextUint8, deserialize status error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize length of votes error")
+	}
+	votes := make(map[common.Address][]byte)
+	for i := 0; uint64(i) < n; i++ {
+		address, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("source.NextVarBytes, deserialize address error")
+		}
+		v, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("source.NextVarBytes, deserialize vote error")
+		}
+		addr, err := common.AddressParseFromBytes(address)
+		if err != nil {
+			return fmt.Errorf("common.AddressParseFromBytes, deserialize address error")
+		}
+		votes[addr] = v
+	}
+	this.ID = id
+	this.Scope = scope
+	this.Content = content
+	this.Quorum = quorum
+	this.Expiry = expiry
+	this.Height = height
+	this.Status = ProposalStatus(status)
+	this.Votes = votes
+	return nil
+}
+
+//Open proposals with the number of current consensus peers, so that the votes required by each proposal can be known
+type ProposalList struct {
+	ConsensusPeers uint32
+	Proposals      []*Proposal
+}
+
+func (this *ProposalList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.ConsensusPeers)
+	sink.WriteVarUint(uint64(len(this.Proposals)))
+	for _, v := range this.Proposals {
+		v.Serialization(sink)
+	}
+}
+
+func (this *ProposalList) Deserialization(source *common.ZeroCopySource) error {
+	consensusPeers, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize consensus peers error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize length of proposals error")
+	}
+	proposals := make([]*Proposal, 0)
+	for i := uint64(0); i < n; i++ {
+		proposal := new(Proposal)
+		if err := proposal.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize no.%d proposal error: %v", i+1, err)
+		}
+		proposals = append(proposals, proposal)
+	}
+	this.ConsensusPeers = consensusPeers
+	this.Proposals = proposals
+	return nil
+}
+
+//Voting rule of the proposals, it applies when the proposal gets its first vote
+type ProposalConfig struct {
+	Quorum uint32 //votes to pass the proposal, 0 for 2/3 of the consensus peers
+	Expiry uint32 //blocks the proposal stays open since its first vote, 0 for never expiring
+}
+
+func (this *ProposalConfig) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.Quorum)
+	sink.WriteUint32(this.Expiry)
+}
+
+func (this *ProposalConfig) Deserialization(source *common.ZeroCopySource) error {
+	quorum, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize quorum error")
+	}
+	expiry, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize expiry error")
+	}
+	this.Quorum = quorum
+	this.Expiry = expiry
+	return nil
+}
+
+//Legacy consensus signs of the approvals made before the proposals are enabled
 type ConsensusSigns struct {
 	SignsMap map[common.Address]bool
 }
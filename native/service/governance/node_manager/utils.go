@@ -66,9 +66,18 @@ func putPeerApply(native *native.NativeService, peer *RegisterPeerParam) error {
 	sink := common.NewZeroCopySink(nil)
 	peer.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PEER_APPLY), peerPubkeyPrefix), cstates.GenRawStorageItem(sink.Bytes()))
+	if err := utils.PutApplyInfo(native, contract, PEER_APPLY, peerPubkeyPrefix); err != nil {
+		return fmt.Errorf("putPeerApply, %v", err)
+	}
 	return nil
 }
 
+func deletePeerApply(native *native.NativeService, peerPubkeyPrefix []byte) {
+	contract := utils.NodeManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PEER_APPLY), peerPubkeyPrefix))
+	utils.DeleteApplyInfo(native, contract, PEER_APPLY, peerPubkeyPrefix)
+}
+
 func GetPeerPoolMap(native *native.NativeService, view uint32) (*PeerPoolMap, error) {
 	contract := utils.NodeManagerContractAddress
 	viewBytes := utils.GetUint32Bytes(view)
@@ -268,6 +277,8 @@ func deleteConsensusSigns(native *native.NativeService, key common.Uint256) {
 	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(CONSENSUS_SIGNS), key.ToArray()))
 }
 
+//Check the approval of method with input by the consensus peers, true is returned when the votes reach the quorum,
+//and the approval can be voted again after it passes
 func CheckConsensusSigns(native *native.NativeService, method string, input []byte, address common.Address) (bool, error) {
 	message := append([]byte(method), input...)
 	key := sha256.Sum256(message)
@@ -275,6 +286,39 @@ func CheckConsensusSigns(native *native.NativeService, method string, input []by
 	if err != nil {
 		return false, fmt.Errorf("CheckConsensusSigns, GetConsensusSigns error: %v", err)
 	}
+	if !ProposalEnabled(native) {
+		return checkLegacyConsensusSigns(native, key, consensusSigns, address)
+	}
+
+	proposalConfig, err := GetProposalConfig(native)
+	if err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+	}
+	//the signs made before the proposals are enabled
+	if len(consensusSigns.SignsMap) > 0 {
+		votes := make(map[common.Address][]byte)
+		for addr := range consensusSigns.SignsMap {
+			votes[addr] = nil
+		}
+		if err := MigrateVotes(native, method, input, votes, proposalConfig); err != nil {
+			return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+		}
+		deleteConsensusSigns(native, key)
+	}
+	proposal, ok, err := VoteProposal(native, method, input, nil, address, proposalConfig, false)
+	if err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"CheckConsensusSigns", len(proposal.Votes)},
+		})
+	return ok, nil
+}
+
+func checkLegacyConsensusSigns(native *native.NativeService, key common.Uint256, consensusSigns *ConsensusSigns,
+	address common.Address) (bool, error) {
 	consensusSigns.SignsMap[address] = true
 	native.AddNotify(
 		&event.NotifyEventInfo{
@@ -282,36 +326,17 @@ func CheckConsensusSigns(native *native.NativeService, method string, input []by
 			States:          []interface{}{"CheckConsensusSigns", len(consensusSigns.SignsMap)},
 		})
 	//check signs num
-	//get view
-	view, err := GetView(native)
-	if err != nil {
-		return false, fmt.Errorf("CheckConsensusSigns, GetView error: %v", err)
-	}
-	//get consensus peer
-	peerPoolMap, err := GetPeerPoolMap(native, view)
+	peers, err := GetConsensusAddresses(native)
 	if err != nil {
-		return false, fmt.Errorf("CheckConsensusSigns, GetPeerPoolMap error: %v", err)
+		return false, fmt.Errorf("CheckConsensusSigns, %v", err)
 	}
 	num := 0
-	sum := 0
-	for key, v := range peerPoolMap.PeerPoolMap {
-		if v.Status == ConsensusStatus {
-			k, err := hex.DecodeString(key)
-			if err != nil {
-				return false, fmt.Errorf("CheckConsensusSigns, hex.DecodeString public key error: %v", err)
-			}
-			publicKey, err := keypair.DeserializePublicKey(k)
-			if err != nil {
-				return false, fmt.Errorf("CheckConsensusSigns, keypair.DeserializePublicKey error: %v", err)
-			}
-			_, ok := consensusSigns.SignsMap[types.AddressFromPubKey(publicKey)]
-			if ok {
-				num = num + 1
-			}
-			sum = sum + 1
+	for addr := range peers {
+		if consensusSigns.SignsMap[addr] {
+			num = num + 1
 		}
 	}
-	if num >= (2*sum+2)/3 {
+	if num >= (2*len(peers)+2)/3 {
 		deleteConsensusSigns(native, key)
 		return true, nil
 	} else {
@@ -320,6 +345,15 @@ func CheckConsensusSigns(native *native.NativeService, method string, input []by
 	}
 }
 
+//Check if the address is of a consensus peer in current view
+func IsConsensusAddress(native *native.NativeService, address common.Address) (bool, error) {
+	peers, err := GetConsensusAddresses(native)
+	if err != nil {
+		return false, fmt.Errorf("IsConsensusAddress, %v", err)
+	}
+	return peers[address], nil
+}
+
 // Get current epoch operator derived from current epoch consensus book keepers' public keys
 func GetCurConOperator(native *native.NativeService) (common.Address, error) {
 	view, err := GetView(native)
@@ -94,3 +94,87 @@ func (this *ApproveRelayerParam) Deserialization(source *common.ZeroCopySource)
 	this.Address = addr
 	return nil
 }
+
+type RelayerList struct {
+	Relayers []common.Address
+}
+
+func (this *RelayerList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Relayers)))
+	for _, v := range this.Relayers {
+		sink.WriteVarBytes(v[:])
+	}
+}
+
+func (this *RelayerList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize Relayers length error")
+	}
+	relayers := make([]common.Address, 0)
+	for i := 0; uint64(i) < n; i++ {
+		address, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("source.NextVarBytes, deserialize relayer error")
+		}
+		addr, err := common.AddressParseFromBytes(address)
+		if err != nil {
+			return fmt.Errorf("common.AddressParseFromBytes, deserialize relayer error: %s", err)
+		}
+		relayers = append(relayers, addr)
+	}
+	this.Relayers = relayers
+	return nil
+}
+
+type RelayerApply struct {
+	ID    uint64
+	Param *RelayerListParam
+}
+
+func (this *RelayerApply) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ID)
+	this.Param.Serialization(sink)
+}
+
+func (this *RelayerApply) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize ID error")
+	}
+	param := new(RelayerListParam)
+	if err := param.Deserialization(source); err != nil {
+		return fmt.Errorf("RelayerListParam.Deserialization, deserialize param error: %v", err)
+	}
+	this.ID = id
+	this.Param = param
+	return nil
+}
+
+type RelayerApplyList struct {
+	Applies []*RelayerApply
+}
+
+func (this *RelayerApplyList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Applies)))
+	for _, v := range this.Applies {
+		v.Serialization(sink)
+	}
+}
+
+func (this *RelayerApplyList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize Applies length error")
+	}
+	applies := make([]*RelayerApply, 0)
+	for i := 0; uint64(i) < n; i++ {
+		apply := new(RelayerApply)
+		if err := apply.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize no.%d apply error: %v", i+1, err)
+		}
+		applies = append(applies, apply)
+	}
+	this.Applies = applies
+	return nil
+}
@@ -33,6 +33,12 @@ const (
 	APPROVE_REGISTER_RELAYER = "approveRegisterRelayer"
 	REMOVE_RELAYER           = "RemoveRelayer"
 	APPROVE_REMOVE_RELAYER   = "approveRemoveRelayer"
+	LIST_RELAYERS            = "listRelayers"
+	LIST_RELAYER_APPLIES     = "listRelayerApplies"
+	LIST_RELAYER_REMOVES     = "listRelayerRemoves"
+	CANCEL_REGISTER_RELAYER  = "cancelRegisterRelayer"
+	CANCEL_REMOVE_RELAYER    = "cancelRemoveRelayer"
+	SWEEP_APPLIES            = "sweepApplies"
 
 	//key prefix
 	RELAYER        = "relayer"
@@ -48,6 +54,12 @@ func RegisterRelayerManagerContract(native *native.NativeService) {
 	native.Register(APPROVE_REGISTER_RELAYER, ApproveRegisterRelayer)
 	native.Register(REMOVE_RELAYER, RemoveRelayer)
 	native.Register(APPROVE_REMOVE_RELAYER, ApproveRemoveRelayer)
+	native.Register(LIST_RELAYERS, ListRelayers)
+	native.Register(LIST_RELAYER_APPLIES, ListRelayerApplies)
+	native.Register(LIST_RELAYER_REMOVES, ListRelayerRemoves)
+	native.Register(CANCEL_REGISTER_RELAYER, CancelRegisterRelayer)
+	native.Register(CANCEL_REMOVE_RELAYER, CancelRemoveRelayer)
+	native.Register(SWEEP_APPLIES, SweepApplies)
 }
 
 func RegisterRelayer(native *native.NativeService) ([]byte, error) {
@@ -81,6 +93,13 @@ func ApproveRegisterRelayer(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterRelayer, getRelayerApply error: %v", err)
 	}
+	expired, err := utils.IsApplyExpired(native, utils.RelayerManagerContractAddress, RELAYER_APPLY, utils.GetUint64Bytes(params.ID))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterRelayer, IsApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterRelayer, application is expired")
+	}
 
 	//check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_REGISTER_RELAYER, utils.GetUint64Bytes(params.ID), params.Address)
@@ -97,7 +116,10 @@ func ApproveRegisterRelayer(native *native.NativeService) ([]byte, error) {
 			return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterRelayer, putRelayer error: %v", err)
 		}
 	}
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER_APPLY), utils.GetUint64Bytes(params.ID)))
+	err = deleteRelayerApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterRelayer, deleteRelayerApply error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.RelayerManagerContractAddress,
@@ -138,6 +160,13 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveRelayer, getRelayerRemove error: %v", err)
 	}
+	expired, err := utils.IsApplyExpired(native, utils.RelayerManagerContractAddress, RELAYER_REMOVE, utils.GetUint64Bytes(params.ID))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveRelayer, IsApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveRelayer, application is expired")
+	}
 
 	//check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_REMOVE_RELAYER, utils.GetUint64Bytes(params.ID), params.Address)
@@ -149,7 +178,14 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 	}
 
 	for _, address := range relayerListParam.AddressList {
-		native.GetCacheDB().Delete(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER), address[:]))
+		err = deleteRelayer(native, address)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveRelayer, deleteRelayer error: %v", err)
+		}
+	}
+	err = deleteRelayerRemove(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRemoveRelayer, deleteRelayerRemove error: %v", err)
 	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
@@ -158,3 +194,101 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+//Cancel the relayer registration by the applicant
+func CancelRegisterRelayer(native *native.NativeService) ([]byte, error) {
+	if err := cancelRelayerApply(native, getRelayerApply, deleteRelayerApply, APPROVE_REGISTER_RELAYER, "CancelRegisterRelayer"); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterRelayer, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Cancel the relayer removal by the applicant
+func CancelRemoveRelayer(native *native.NativeService) ([]byte, error) {
+	if err := cancelRelayerApply(native, getRelayerRemove, deleteRelayerRemove, APPROVE_REMOVE_RELAYER, "CancelRemoveRelayer"); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRemoveRelayer, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+func cancelRelayerApply(native *native.NativeService, get func(*native.NativeService, uint64) (*RelayerListParam, error),
+	remove func(*native.NativeService, uint64) error, approveMethod, eventName string) error {
+	params := new(ApproveRelayerParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("contract params deserialize error: %v", err)
+	}
+	if !utils.ApplyExpiryEnabled(native) {
+		return fmt.Errorf("cancelling applies is not activated yet")
+	}
+	//check witness
+	if err := utils.ValidateOwner(native, params.Address); err != nil {
+		return fmt.Errorf("checkWitness error: %v", err)
+	}
+
+	relayerListParam, err := get(native, params.ID)
+	if err != nil {
+		return err
+	}
+	if relayerListParam.Address != params.Address {
+		return fmt.Errorf("address is not the applicant")
+	}
+	if err := remove(native, params.ID); err != nil {
+		return err
+	}
+	if err := node_manager.RemoveProposal(native, approveMethod, utils.GetUint64Bytes(params.ID)); err != nil {
+		return err
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.RelayerManagerContractAddress,
+			States:          []interface{}{eventName, params.ID},
+		})
+	return nil
+}
+
+//Remove the expired relayer registrations and removals, it is invoked at commitDpos
+func SweepApplies(native *native.NativeService) ([]byte, error) {
+	if err := node_manager.CheckSweepCaller(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.RelayerManagerContractAddress, RELAYER_APPLY, APPROVE_REGISTER_RELAYER, deleteRelayerApply); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.RelayerManagerContractAddress, RELAYER_REMOVE, APPROVE_REMOVE_RELAYER, deleteRelayerRemove); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Get all the approved relayers in the order of address
+func ListRelayers(native *native.NativeService) ([]byte, error) {
+	relayers, err := listRelayers(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListRelayers, listRelayers error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&RelayerList{Relayers: relayers}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the relayer registrations waiting for approval in the order of apply id
+func ListRelayerApplies(native *native.NativeService) ([]byte, error) {
+	applies, err := listRelayerApplies(native, RELAYER_APPLY, getRelayerApply)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListRelayerApplies, listRelayerApplies error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&RelayerApplyList{Applies: applies}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the relayer removals waiting for approval in the order of remove id
+func ListRelayerRemoves(native *native.NativeService) ([]byte, error) {
+	applies, err := listRelayerApplies(native, RELAYER_REMOVE, getRelayerRemove)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListRelayerRemoves, listRelayerApplies error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&RelayerApplyList{Applies: applies}).Serialization(sink)
+	return sink.Bytes(), nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
 	cstates "github.com/polynetwork/poly/core/states"
@@ -197,3 +198,130 @@ func TestRemoveRelayer(t *testing.T) {
 		}
 	}
 }
+
+func TestListRelayers(t *testing.T) {
+	nativeService = NewNative(nil, new(types.Transaction), nil)
+	relayers := []common.Address{{1, 2, 4, 6}, {1, 4, 5, 7}, {1, 3, 5, 7, 9}}
+	for _, relayer := range relayers {
+		assert.Nil(t, putRelayer(nativeService, relayer))
+	}
+	assert.Nil(t, putRelayerApply(nativeService, &RelayerListParam{AddressList: []common.Address{{2}}, Address: acct.Address}))
+	assert.Nil(t, deleteRelayer(nativeService, relayers[1]))
+
+	res, err := ListRelayers(nativeService)
+	assert.Nil(t, err)
+	relayerList := new(RelayerList)
+	assert.Nil(t, relayerList.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, []common.Address{relayers[0], relayers[2]}, relayerList.Relayers)
+}
+
+func TestListRelayerApplies(t *testing.T) {
+	tx := &types.Transaction{
+		SignedAddr: []common.Address{acct.Address},
+	}
+	nativeService = NewNative(nil, tx, nil)
+	for _, relayers := range [][]common.Address{{{1}}, {{2}, {3}}} {
+		assert.Nil(t, putRelayerApply(nativeService, &RelayerListParam{AddressList: relayers, Address: acct.Address}))
+	}
+	assert.Nil(t, putRelayerRemove(nativeService, &RelayerListParam{AddressList: []common.Address{{4}}, Address: acct.Address}))
+	assert.Nil(t, deleteRelayerApply(nativeService, 0))
+
+	res, err := ListRelayerApplies(nativeService)
+	assert.Nil(t, err)
+	applyList := new(RelayerApplyList)
+	assert.Nil(t, applyList.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, 1, len(applyList.Applies))
+	assert.Equal(t, uint64(1), applyList.Applies[0].ID)
+	assert.Equal(t, []common.Address{{2}, {3}}, applyList.Applies[0].Param.AddressList)
+
+	res, err = ListRelayerRemoves(nativeService)
+	assert.Nil(t, err)
+	applyList = new(RelayerApplyList)
+	assert.Nil(t, applyList.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, 1, len(applyList.Applies))
+	assert.Equal(t, uint64(0), applyList.Applies[0].ID)
+}
+
+func approveRelayerNative(db *storage.CacheDB, height uint32, id uint64, signer common.Address) *native.NativeService {
+	sink := common.NewZeroCopySink(nil)
+	(&ApproveRelayerParam{ID: id, Address: signer}).Serialization(sink)
+	tx := &types.Transaction{
+		SignedAddr: []common.Address{signer},
+	}
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{0}, 0, sink.Bytes(), false)
+	return ns
+}
+
+func TestCancelRelayerApply(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	nativeService = NewNative(nil, new(types.Transaction), nil)
+	db := nativeService.GetCacheDB()
+	accts := conAccts()
+	putPeerMapPoolAndView(db, accts)
+	assert.Nil(t, putRelayerApply(nativeService, &RelayerListParam{AddressList: []common.Address{{1}}, Address: acct.Address}))
+
+	signer := accts[0].Address
+	_, err := ApproveRegisterRelayer(approveRelayerNative(db, 0, 0, signer))
+	assert.Nil(t, err)
+	_, err = CancelRegisterRelayer(approveRelayerNative(db, 0, 0, signer))
+	assert.NotNil(t, err)
+
+	_, err = CancelRegisterRelayer(approveRelayerNative(db, 0, 0, acct.Address))
+	assert.Nil(t, err)
+	_, err = getRelayerApply(nativeService, 0)
+	assert.NotNil(t, err)
+	proposal, err := node_manager.GetProposal(nativeService,
+		node_manager.ProposalID(APPROVE_REGISTER_RELAYER, utils.GetUint64Bytes(0)))
+	assert.Nil(t, err)
+	assert.Nil(t, proposal)
+}
+
+func TestSweepRelayerApplies(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	nativeService = NewNative(nil, new(types.Transaction), nil)
+	db := nativeService.GetCacheDB()
+	accts := conAccts()
+	putPeerMapPoolAndView(db, accts)
+	assert.Nil(t, putRelayerApply(nativeService, &RelayerListParam{AddressList: []common.Address{{1}}, Address: acct.Address}))
+	_, err := ApproveRegisterRelayer(approveRelayerNative(db, 0, 0, accts[0].Address))
+	assert.Nil(t, err)
+
+	//only invoked by node manager
+	_, err = SweepApplies(approveRelayerNative(db, utils.DEFAULT_APPLY_EXPIRY, 0, acct.Address))
+	assert.NotNil(t, err)
+	sweepNative := func(height uint32) *native.NativeService {
+		ns := approveRelayerNative(db, height, 0, acct.Address)
+		ns.PushContext(utils.NodeManagerContractAddress)
+		ns.PushContext(utils.RelayerManagerContractAddress)
+		return ns
+	}
+
+	//not expired yet
+	_, err = SweepApplies(sweepNative(utils.DEFAULT_APPLY_EXPIRY - 1))
+	assert.Nil(t, err)
+	_, err = getRelayerApply(nativeService, 0)
+	assert.Nil(t, err)
+
+	_, err = ApproveRegisterRelayer(approveRelayerNative(db, utils.DEFAULT_APPLY_EXPIRY, 0, accts[1].Address))
+	assert.NotNil(t, err)
+
+	ns := sweepNative(utils.DEFAULT_APPLY_EXPIRY)
+	_, err = SweepApplies(ns)
+	assert.Nil(t, err)
+	_, err = getRelayerApply(nativeService, 0)
+	assert.NotNil(t, err)
+	proposal, err := node_manager.GetProposal(nativeService,
+		node_manager.ProposalID(APPROVE_REGISTER_RELAYER, utils.GetUint64Bytes(0)))
+	assert.Nil(t, err)
+	assert.Nil(t, proposal)
+	assert.Equal(t, 1, len(ns.GetNotify()))
+	assert.Equal(t, utils.APPLY_EXPIRED, ns.GetNotify()[0].States.([]interface{})[0])
+}
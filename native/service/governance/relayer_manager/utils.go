@@ -19,6 +19,7 @@ package relayer_manager
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
@@ -30,7 +31,61 @@ import (
 func putRelayer(native *native.NativeService, relayer common.Address) error {
 	contract := utils.RelayerManagerContractAddress
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER), relayer[:]), cstates.GenRawStorageItem(relayer[:]))
-	return nil
+	return utils.AddIndexKey(native, contract, RELAYER, relayer[:])
+}
+
+func deleteRelayer(native *native.NativeService, relayer common.Address) error {
+	contract := utils.RelayerManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RELAYER), relayer[:]))
+	return utils.RemoveIndexKey(native, contract, RELAYER, relayer[:])
+}
+
+//Check if the address is an approved relayer
+func IsRelayer(native *native.NativeService, address common.Address) (bool, error) {
+	value, err := native.GetCacheDB().Get(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER), address[:]))
+	if err != nil {
+		return false, fmt.Errorf("IsRelayer, get relayer error: %v", err)
+	}
+	return value != nil, nil
+}
+
+//Get the relayers in the order of address
+func listRelayers(native *native.NativeService) ([]common.Address, error) {
+	keys, err := utils.GetIndexKeys(native, utils.RelayerManagerContractAddress, RELAYER, common.ADDR_LEN)
+	if err != nil {
+		return nil, fmt.Errorf("listRelayers, GetIndexKeys error: %v", err)
+	}
+	relayers := make([]common.Address, 0, len(keys))
+	for _, key := range keys {
+		relayer, err := common.AddressParseFromBytes(key)
+		if err != nil {
+			return nil, fmt.Errorf("listRelayers, parse relayer address error: %v", err)
+		}
+		relayers = append(relayers, relayer)
+	}
+	return relayers, nil
+}
+
+//Get the pending applications of the prefix in the order of id
+func listRelayerApplies(native *native.NativeService, prefix string,
+	get func(*native.NativeService, uint64) (*RelayerListParam, error)) ([]*RelayerApply, error) {
+	keys, err := utils.GetIndexKeys(native, utils.RelayerManagerContractAddress, prefix, 8)
+	if err != nil {
+		return nil, fmt.Errorf("listRelayerApplies, GetIndexKeys error: %v", err)
+	}
+	applies := make([]*RelayerApply, 0, len(keys))
+	for _, key := range keys {
+		id := utils.GetBytesUint64(key)
+		param, err := get(native, id)
+		if err != nil {
+			return nil, fmt.Errorf("listRelayerApplies, get %s %d error: %v", prefix, id, err)
+		}
+		applies = append(applies, &RelayerApply{ID: id, Param: param})
+	}
+	sort.SliceStable(applies, func(i, j int) bool {
+		return applies[i].ID < applies[j].ID
+	})
+	return applies, nil
 }
 
 func putRelayerApply(native *native.NativeService, relayerListParam *RelayerListParam) error {
@@ -48,6 +103,12 @@ func putRelayerApply(native *native.NativeService, relayerListParam *RelayerList
 	relayerListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_APPLY), utils.GetUint64Bytes(applyID)),
 		cstates.GenRawStorageItem(sink.Bytes()))
+	if err := utils.PutApplyInfo(native, contract, RELAYER_APPLY, utils.GetUint64Bytes(applyID)); err != nil {
+		return fmt.Errorf("putRelayerApply, %v", err)
+	}
+	if err := utils.AddIndexKey(native, contract, RELAYER_APPLY, utils.GetUint64Bytes(applyID)); err != nil {
+		return fmt.Errorf("putRelayerApply, AddIndexKey error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -56,6 +117,13 @@ func putRelayerApply(native *native.NativeService, relayerListParam *RelayerList
 	return nil
 }
 
+func deleteRelayerApply(native *native.NativeService, applyID uint64) error {
+	contract := utils.RelayerManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RELAYER_APPLY), utils.GetUint64Bytes(applyID)))
+	utils.DeleteApplyInfo(native, contract, RELAYER_APPLY, utils.GetUint64Bytes(applyID))
+	return utils.RemoveIndexKey(native, contract, RELAYER_APPLY, utils.GetUint64Bytes(applyID))
+}
+
 func getRelayerApply(native *native.NativeService, applyID uint64) (*RelayerListParam, error) {
 	contract := utils.RelayerManagerContractAddress
 	relayerListParamStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RELAYER_APPLY), utils.GetUint64Bytes(applyID)))
@@ -117,6 +185,12 @@ func putRelayerRemove(native *native.NativeService, relayerListParam *RelayerLis
 	relayerListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_REMOVE), utils.GetUint64Bytes(removeID)),
 		cstates.GenRawStorageItem(sink.Bytes()))
+	if err := utils.PutApplyInfo(native, contract, RELAYER_REMOVE, utils.GetUint64Bytes(removeID)); err != nil {
+		return fmt.Errorf("putRelayerRemove, %v", err)
+	}
+	if err := utils.AddIndexKey(native, contract, RELAYER_REMOVE, utils.GetUint64Bytes(removeID)); err != nil {
+		return fmt.Errorf("putRelayerRemove, AddIndexKey error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -125,6 +199,13 @@ func putRelayerRemove(native *native.NativeService, relayerListParam *RelayerLis
 	return nil
 }
 
+func deleteRelayerRemove(native *native.NativeService, removeID uint64) error {
+	contract := utils.RelayerManagerContractAddress
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RELAYER_REMOVE), utils.GetUint64Bytes(removeID)))
+	utils.DeleteApplyInfo(native, contract, RELAYER_REMOVE, utils.GetUint64Bytes(removeID))
+	return utils.RemoveIndexKey(native, contract, RELAYER_REMOVE, utils.GetUint64Bytes(removeID))
+}
+
 func getRelayerRemove(native *native.NativeService, removeID uint64) (*RelayerListParam, error) {
 	contract := utils.RelayerManagerContractAddress
 	relayerListParamStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RELAYER_REMOVE), utils.GetUint64Bytes(removeID)))
@@ -25,6 +25,8 @@ import (
 type ReplenishTxParam struct {
 	ChainId  uint64
 	TxHashes []string
+	//optional poly heights of the txs, only used for the requests made before the request height is recorded
+	Heights []uint32
 }
 
 func (this *ReplenishTxParam) Serialization(sink *common.ZeroCopySink) {
@@ -33,6 +35,13 @@ func (this *ReplenishTxParam) Serialization(sink *common.ZeroCopySink) {
 	for _, v := range this.TxHashes {
 		sink.WriteString(v)
 	}
+	if len(this.Heights) == 0 {
+		return
+	}
+	sink.WriteVarUint(uint64(len(this.Heights)))
+	for _, v := range this.Heights {
+		sink.WriteUint32(v)
+	}
 }
 
 func (this *ReplenishTxParam) Deserialization(source *common.ZeroCopySource) error {
@@ -54,7 +63,27 @@ func (this *ReplenishTxParam) Deserialization(source *common.ZeroCopySource) err
 		txHashes = append(txHashes, txHash)
 	}
 
+	//heights are absent in the params of old clients
+	heights := make([]uint32, 0)
+	if source.Len() > 0 {
+		m, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("source.NextVarUint, deserialize heights length error")
+		}
+		if m != 0 && m != n {
+			return fmt.Errorf("heights length %d does not match txhashes length %d", m, n)
+		}
+		for i := 0; uint64(i) < m; i++ {
+			height, eof := source.NextUint32()
+			if eof {
+				return fmt.Errorf("source.NextUint32, deserialize height error")
+			}
+			heights = append(heights, height)
+		}
+	}
+
 	this.ChainId = chainId
 	this.TxHashes = txHashes
+	this.Heights = heights
 	return nil
 }
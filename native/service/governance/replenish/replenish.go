@@ -18,17 +18,28 @@
 package replenish
 
 import (
+	"encoding/hex"
 	"fmt"
-	"github.com/polynetwork/poly/native/event"
 
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
 const (
 	//function name
 	REPLENISH_TX = "replenishTx"
+
+	//key prefix
+	REPLENISH_HEIGHT = "replenishHeight"
+
+	//min poly blocks between the replenishments of the same request
+	REPLENISH_INTERVAL = 100
 )
 
 //Register methods
@@ -36,16 +47,105 @@ func RegisterReplenishContract(native *native.NativeService) {
 	native.Register(REPLENISH_TX, ReplenishTx)
 }
 
+//Re-emit the makeProof of the requests to target chain, so that relayers can pick up the stuck transfers.
+//The tx hashes are the poly tx hashes in hex string, and the caller must be a relayer or a consensus node.
+//The heights are only needed for the requests made before the request height is recorded
 func ReplenishTx(native *native.NativeService) ([]byte, error) {
 	params := new(ReplenishTxParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, contract params deserialize error: %v", err)
 	}
 
+	//before activation only the replenish event is emitted for the relayers
+	if native.GetHeight() < config.GetReplenishHeight(config.DefConfig.P2PNode.NetworkId) {
+		notifyReplenishTx(native, params)
+		return utils.BYTE_TRUE, nil
+	}
+
+	//check caller
+	if err := checkCaller(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, checkCaller error: %v", err)
+	}
+
+	chainIDBytes := utils.GetUint64Bytes(params.ChainId)
+	for i, hash := range params.TxHashes {
+		txHash, err := common.Uint256FromHexString(hash)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, parse tx hash %s error: %v", hash, err)
+		}
+		request, err := scom.GetRequest(native, params.ChainId, txHash[:])
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, GetRequest error: %v", err)
+		}
+		if request == nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, request of tx %s to chain %d not found", hash, params.ChainId)
+		}
+		height, ok, err := scom.GetRequestHeight(native, params.ChainId, txHash[:])
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, GetRequestHeight error: %v", err)
+		}
+		if !ok {
+			//the request height is not recorded for the requests made before, use the height given by caller
+			if i >= len(params.Heights) || params.Heights[i] == 0 || params.Heights[i] >= native.GetHeight() {
+				return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, height of request of tx %s is not recorded, "+
+					"a valid poly height of the tx is required", hash)
+			}
+			height = params.Heights[i]
+		}
+
+		//check replenish interval
+		lastHeight, ok, err := getReplenishHeight(native, chainIDBytes, txHash)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, getReplenishHeight error: %v", err)
+		}
+		if ok && native.GetHeight() < lastHeight+REPLENISH_INTERVAL {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, tx %s was replenished at height %d, try again after height %d",
+				hash, lastHeight, lastHeight+REPLENISH_INTERVAL)
+		}
+		putReplenishHeight(native, chainIDBytes, txHash)
+
+		merkleValue := new(scom.ToMerkleValue)
+		if err := merkleValue.Deserialization(common.NewZeroCopySource(request)); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, deserialize request error: %v", err)
+		}
+		key := hex.EncodeToString(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(scom.REQUEST), chainIDBytes, txHash[:]))
+		scom.NotifyReplenishMakeProof(native, merkleValue.FromChainID, params.ChainId, hex.EncodeToString(merkleValue.MakeTxParam.TxHash),
+			height, key)
+	}
+
+	notifyReplenishTx(native, params)
+	return utils.BYTE_TRUE, nil
+}
+
+func notifyReplenishTx(native *native.NativeService, params *ReplenishTxParam) {
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.ReplenishContractAddress,
 			States:          []interface{}{"ReplenishTx", params.TxHashes, params.ChainId},
 		})
-	return utils.BYTE_TRUE, nil
+}
+
+//Check if one of the signers is a relayer or a consensus node
+func checkCaller(native *native.NativeService) error {
+	addresses, err := native.GetTx().GetSignatureAddresses()
+	if err != nil {
+		return fmt.Errorf("get signature addresses error: %v", err)
+	}
+	for _, address := range addresses {
+		ok, err := relayer_manager.IsRelayer(native, address)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		ok, err = node_manager.IsConsensusAddress(native, address)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("caller is neither relayer nor consensus node")
 }
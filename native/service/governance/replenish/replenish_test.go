@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package replenish
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplenishTx(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	relayer := common.Address{1}
+	txHash := common.Uint256{2}
+	var heights []uint32
+	newNative := func(height uint32, signer common.Address) *native.NativeService {
+		sink := common.NewZeroCopySink(nil)
+		(&ReplenishTxParam{ChainId: 3, TxHashes: []string{txHash.ToHexString()}, Heights: heights}).Serialization(sink)
+		tx := &types.Transaction{SignedAddr: []common.Address{signer}}
+		ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{}, 0, sink.Bytes(), false)
+		return ns
+	}
+
+	ns := newNative(10, relayer)
+	utils.PutBytes(ns, utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(relayer_manager.RELAYER), relayer[:]), relayer[:])
+	_, err := ReplenishTx(ns)
+	assert.Error(t, err, "request not found")
+
+	merkleValue := &scom.ToMerkleValue{
+		TxHash:      txHash[:],
+		FromChainID: 2,
+		MakeTxParam: &scom.MakeTxParam{TxHash: []byte{4}, CrossChainID: []byte{5}, ToChainID: 3, Method: "unlock"},
+	}
+	sink := common.NewZeroCopySink(nil)
+	merkleValue.Serialization(sink)
+	utils.PutBytes(ns, utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(scom.REQUEST), utils.GetUint64Bytes(3), txHash[:]), sink.Bytes())
+
+	//request made before the request height is recorded
+	_, err = ReplenishTx(newNative(10, relayer))
+	assert.Error(t, err, "height of request is required")
+	heights = []uint32{10}
+	_, err = ReplenishTx(newNative(10, relayer))
+	assert.Error(t, err, "height of request is not before current height")
+	heights = []uint32{7}
+	ns = newNative(10, relayer)
+	_, err = ReplenishTx(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), ns.GetNotify()[0].States.([]interface{})[4])
+
+	heights = nil
+	scom.PutRequestHeight(ns, 3, txHash[:], 8)
+	ns.GetCacheDB().Delete(utils.ConcatKey(utils.ReplenishContractAddress, []byte(REPLENISH_HEIGHT), utils.GetUint64Bytes(3), txHash[:]))
+
+	_, err = ReplenishTx(newNative(10, common.Address{9}))
+	assert.Error(t, err, "caller is not relayer")
+
+	ns = newNative(10, relayer)
+	res, err := ReplenishTx(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, res)
+	states := ns.GetNotify()[0].States.([]interface{})
+	assert.Equal(t, scom.NOTIFY_REPLENISH_MAKE_PROOF, states[0])
+	assert.Equal(t, uint32(8), states[4])
+
+	_, err = ReplenishTx(newNative(10+REPLENISH_INTERVAL-1, relayer))
+	assert.Error(t, err, "replenished too often")
+	_, err = ReplenishTx(newNative(10+REPLENISH_INTERVAL, relayer))
+	assert.NoError(t, err)
+
+	//only the replenish event before activation
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	ns = newNative(10+REPLENISH_INTERVAL, common.Address{9})
+	_, err = ReplenishTx(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(ns.GetNotify()))
+	assert.Equal(t, "ReplenishTx", ns.GetNotify()[0].States.([]interface{})[0])
+}
+
+func TestReplenishTxParam(t *testing.T) {
+	param := &ReplenishTxParam{ChainId: 3, TxHashes: []string{"ab", "cd"}}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	oldParam := new(ReplenishTxParam)
+	assert.NoError(t, oldParam.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, 0, len(oldParam.Heights))
+
+	param.Heights = []uint32{1, 2}
+	sink = common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	newParam := new(ReplenishTxParam)
+	assert.NoError(t, newParam.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, param, newParam)
+
+	param.Heights = []uint32{1}
+	sink = common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	assert.Error(t, new(ReplenishTxParam).Deserialization(common.NewZeroCopySource(sink.Bytes())))
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package replenish
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func getReplenishHeight(native *native.NativeService, chainIDBytes []byte, txHash common.Uint256) (uint32, bool, error) {
+	contract := utils.ReplenishContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(REPLENISH_HEIGHT), chainIDBytes, txHash[:]))
+	if err != nil {
+		return 0, false, fmt.Errorf("getReplenishHeight, get replenish height store error: %v", err)
+	}
+	if store == nil {
+		return 0, false, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, false, fmt.Errorf("getReplenishHeight, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint32(value), true, nil
+}
+
+func putReplenishHeight(native *native.NativeService, chainIDBytes []byte, txHash common.Uint256) {
+	contract := utils.ReplenishContractAddress
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(REPLENISH_HEIGHT), chainIDBytes, txHash[:]),
+		cstates.GenRawStorageItem(utils.GetUint32Bytes(native.GetHeight())))
+}
@@ -124,6 +124,23 @@ func (this *ChainidParam) Deserialization(source *common.ZeroCopySource) error {
 	return nil
 }
 
+type QueryChainParam struct {
+	ChainId uint64
+}
+
+func (this *QueryChainParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainId)
+}
+
+func (this *QueryChainParam) Deserialization(source *common.ZeroCopySource) error {
+	chainId, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize chainid error")
+	}
+	this.ChainId = chainId
+	return nil
+}
+
 type RegisterRedeemParam struct {
 	RedeemChainID   uint64
 	ContractChainID uint64
@@ -468,3 +485,76 @@ func (this *UpdateFeeParam) Deserialization(source *common.ZeroCopySource) error
 	this.Fee = new(big.Int).SetBytes(fee)
 	return nil
 }
+
+type ApplyAssetParam struct {
+	Address common.Address
+	Asset   *Asset
+}
+
+func (this *ApplyAssetParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+	this.Asset.Serialization(sink)
+}
+
+func (this *ApplyAssetParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("ApplyAssetParam deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("ApplyAssetParam deserialize address error: %v", err)
+	}
+	asset := new(Asset)
+	if err := asset.Deserialization(source); err != nil {
+		return fmt.Errorf("ApplyAssetParam deserialize asset error: %v", err)
+	}
+	this.Address = addr
+	this.Asset = asset
+	return nil
+}
+
+type AssetParam struct {
+	ID      uint64
+	Address common.Address
+}
+
+func (this *AssetParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ID)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *AssetParam) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("AssetParam deserialize id error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("AssetParam deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("AssetParam deserialize address error: %v", err)
+	}
+	this.ID = id
+	this.Address = addr
+	return nil
+}
+
+type QueryAssetParam struct {
+	ID uint64
+}
+
+func (this *QueryAssetParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ID)
+}
+
+func (this *QueryAssetParam) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("QueryAssetParam deserialize id error")
+	}
+	this.ID = id
+	return nil
+}
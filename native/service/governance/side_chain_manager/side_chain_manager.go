@@ -23,6 +23,7 @@ import (
 	"math/big"
 	"sort"
 
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
 
 	"github.com/btcsuite/btcd/txscript"
@@ -31,6 +32,7 @@ import (
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -46,6 +48,24 @@ const (
 	REGISTER_ASSET              = "registerAsset"
 	UPDATE_FEE                  = "updateFee"
 	SET_BTC_TX_PARAM            = "setBtcTxParam"
+	GET_ROUTERS                 = "getRouters"
+	GET_SIDE_CHAIN              = "getSideChain"
+	LIST_SIDE_CHAINS            = "listSideChains"
+	GET_FEE                     = "getFee"
+	GET_ASSET_BIND              = "getAssetBind"
+	LIST_SIDE_CHAIN_APPLIES     = "listSideChainApplies"
+	LIST_UPDATE_REQUESTS        = "listUpdateSideChainRequests"
+	LIST_QUIT_REQUESTS          = "listQuitSideChainRequests"
+	CANCEL_REGISTER_SIDE_CHAIN  = "cancelRegisterSideChain"
+	CANCEL_UPDATE_SIDE_CHAIN    = "cancelUpdateSideChain"
+	CANCEL_QUIT_SIDE_CHAIN      = "cancelQuitSideChain"
+	SWEEP_APPLIES               = "sweepApplies"
+	APPLY_ASSET                 = "applyAsset"
+	APPROVE_ASSET               = "approveAsset"
+	CANCEL_ASSET                = "cancelAsset"
+	GET_ASSET                   = "getAsset"
+	GET_ASSET_APPLY             = "getAssetApply"
+	LIST_ASSETS                 = "listAssets"
 
 	//key prefix
 	SIDE_CHAIN_APPLY          = "sideChainApply"
@@ -59,6 +79,9 @@ const (
 	ASSET_BIND                = "assetBind"
 	FEE                       = "fee"
 	FEE_INFO                  = "feeInfo"
+	ASSET_INFO                = "assetInfo"
+	ASSET_APPLY               = "assetApply"
+	ASSET_HASH                = "assetHash"
 
 	UPDATE_FEE_TIMEOUT = 300
 )
@@ -71,11 +94,30 @@ func RegisterSideChainManagerContract(native *native.NativeService) {
 	native.Register(APPROVE_UPDATE_SIDE_CHAIN, ApproveUpdateSideChain)
 	native.Register(QUIT_SIDE_CHAIN, QuitSideChain)
 	native.Register(APPROVE_QUIT_SIDE_CHAIN, ApproveQuitSideChain)
+	native.Register(CANCEL_REGISTER_SIDE_CHAIN, CancelRegisterSideChain)
+	native.Register(CANCEL_UPDATE_SIDE_CHAIN, CancelUpdateSideChain)
+	native.Register(CANCEL_QUIT_SIDE_CHAIN, CancelQuitSideChain)
+	native.Register(SWEEP_APPLIES, SweepApplies)
+	native.Register(APPLY_ASSET, ApplyAsset)
+	native.Register(APPROVE_ASSET, ApproveAsset)
+	native.Register(CANCEL_ASSET, CancelAsset)
 	native.Register(REGISTER_ASSET, RegisterAsset)
 	native.Register(UPDATE_FEE, UpdateFee)
 
 	native.Register(REGISTER_REDEEM, RegisterRedeem)
 	native.Register(SET_BTC_TX_PARAM, SetBtcTxParam)
+
+	native.Register(GET_ROUTERS, GetRouters)
+	native.Register(GET_SIDE_CHAIN, QuerySideChain)
+	native.Register(LIST_SIDE_CHAINS, ListSideChains)
+	native.Register(GET_FEE, QueryFee)
+	native.Register(GET_ASSET_BIND, QueryAssetBind)
+	native.Register(GET_ASSET, QueryAsset)
+	native.Register(GET_ASSET_APPLY, QueryAssetApply)
+	native.Register(LIST_ASSETS, ListAssets)
+	native.Register(LIST_SIDE_CHAIN_APPLIES, ListSideChainApplies)
+	native.Register(LIST_UPDATE_REQUESTS, ListUpdateSideChainRequests)
+	native.Register(LIST_QUIT_REQUESTS, ListQuitSideChainRequests)
 }
 
 func RegisterSideChain(native *native.NativeService) ([]byte, error) {
@@ -95,7 +137,18 @@ func RegisterSideChain(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, getRegisterSideChain error: %v", err)
 	}
 	if registerSideChain != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, chainid already requested")
+		expired, err := isApplyExpired(native, SIDE_CHAIN_APPLY, params.ChainId)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, isApplyExpired error: %v", err)
+		}
+		if !expired {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, chainid already requested")
+		}
+		//the consensus signs on the expired apply are discarded
+		err = node_manager.RemoveProposal(native, APPROVE_REGISTER_SIDE_CHAIN, utils.GetUint64Bytes(params.ChainId))
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, %v", err)
+		}
 	}
 	sideChain, err := GetSideChain(native, params.ChainId)
 	if err != nil {
@@ -144,6 +197,13 @@ func ApproveRegisterSideChain(native *native.NativeService) ([]byte, error) {
 	if registerSideChain == nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterSideChain, chainid is not requested")
 	}
+	expired, err := isApplyExpired(native, SIDE_CHAIN_APPLY, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterSideChain, isApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterSideChain, register request is expired")
+	}
 
 	//check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_REGISTER_SIDE_CHAIN, utils.GetUint64Bytes(params.Chainid),
@@ -159,7 +219,14 @@ func ApproveRegisterSideChain(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterSideChain, putSideChain error: %v", err)
 	}
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN_APPLY), utils.GetUint64Bytes(params.Chainid)))
+	err = deleteSideChainApply(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterSideChain, deleteSideChainApply error: %v", err)
+	}
+	//the transfers to the chain newly registered are quarantined as the ones to the chain whitelisted
+	if scom.WhiteChainHeightEnabled(native) {
+		scom.PutWhiteChainHeight(native, params.Chainid, native.GetHeight())
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -230,6 +297,13 @@ func ApproveUpdateSideChain(native *native.NativeService) ([]byte, error) {
 	if sideChain == nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveUpdateSideChain, chainid is not requested update")
 	}
+	expired, err := isApplyExpired(native, UPDATE_SIDE_CHAIN_REQUEST, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveUpdateSideChain, isApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveUpdateSideChain, update request is expired")
+	}
 
 	//check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_UPDATE_SIDE_CHAIN, utils.GetUint64Bytes(params.Chainid),
@@ -245,8 +319,10 @@ func ApproveUpdateSideChain(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveUpdateSideChain, putSideChain error: %v", err)
 	}
-	chainidByte := utils.GetUint64Bytes(params.Chainid)
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(UPDATE_SIDE_CHAIN_REQUEST), chainidByte))
+	err = deleteUpdateSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveUpdateSideChain, deleteUpdateSideChain error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -306,6 +382,13 @@ func ApproveQuitSideChain(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveQuitSideChain, getQuitSideChain error: %v", err)
 	}
+	expired, err := isApplyExpired(native, QUIT_SIDE_CHAIN_REQUEST, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveQuitSideChain, isApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveQuitSideChain, quit request is expired")
+	}
 
 	//check consensus signs
 	ok, err := node_manager.CheckConsensusSigns(native, QUIT_SIDE_CHAIN, utils.GetUint64Bytes(params.Chainid),
@@ -317,9 +400,14 @@ func ApproveQuitSideChain(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_TRUE, nil
 	}
 
-	chainidByte := utils.GetUint64Bytes(params.Chainid)
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(QUIT_SIDE_CHAIN), chainidByte))
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN), chainidByte))
+	err = deleteQuitSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveQuitSideChain, deleteQuitSideChain error: %v", err)
+	}
+	err = deleteSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveQuitSideChain, deleteSideChain error: %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -328,6 +416,299 @@ func ApproveQuitSideChain(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+//Cancel the side chain registration by the applicant
+func CancelRegisterSideChain(native *native.NativeService) ([]byte, error) {
+	params := new(ChainidParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, contract params deserialize error: %v", err)
+	}
+	if !utils.ApplyExpiryEnabled(native) {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, cancelling applies is not activated yet")
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, checkWitness error: %v", err)
+	}
+
+	registerSideChain, err := getSideChainApply(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, getRegisterSideChain error: %v", err)
+	}
+	if registerSideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, chainid is not requested")
+	}
+	if registerSideChain.Address != params.Address {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, address is not the applicant")
+	}
+
+	err = deleteSideChainApply(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, deleteSideChainApply error: %v", err)
+	}
+	err = node_manager.RemoveProposal(native, APPROVE_REGISTER_SIDE_CHAIN, utils.GetUint64Bytes(params.Chainid))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelRegisterSideChain, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"CancelRegisterSideChain", params.Chainid},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Cancel the side chain update by the side chain owner
+func CancelUpdateSideChain(native *native.NativeService) ([]byte, error) {
+	params := new(ChainidParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, contract params deserialize error: %v", err)
+	}
+	if !utils.ApplyExpiryEnabled(native) {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, cancelling applies is not activated yet")
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, checkWitness error: %v", err)
+	}
+
+	sideChain, err := getUpdateSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, getUpdateSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, chainid is not requested update")
+	}
+	if sideChain.Address != params.Address {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, side chain owner is wrong")
+	}
+
+	err = deleteUpdateSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, deleteUpdateSideChain error: %v", err)
+	}
+	err = node_manager.RemoveProposal(native, APPROVE_UPDATE_SIDE_CHAIN, utils.GetUint64Bytes(params.Chainid))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelUpdateSideChain, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"CancelUpdateSideChain", params.Chainid},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Cancel the side chain quit by the side chain owner
+func CancelQuitSideChain(native *native.NativeService) ([]byte, error) {
+	params := new(ChainidParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, contract params deserialize error: %v", err)
+	}
+	if !utils.ApplyExpiryEnabled(native) {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, cancelling applies is not activated yet")
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, checkWitness error: %v", err)
+	}
+
+	err = getQuitSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, getQuitSideChain error: %v", err)
+	}
+	sideChain, err := GetSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, getSideChain error: %v", err)
+	}
+	if sideChain == nil || sideChain.Address != params.Address {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, side chain owner is wrong")
+	}
+
+	err = deleteQuitSideChain(native, params.Chainid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, deleteQuitSideChain error: %v", err)
+	}
+	err = node_manager.RemoveProposal(native, QUIT_SIDE_CHAIN, utils.GetUint64Bytes(params.Chainid))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelQuitSideChain, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"CancelQuitSideChain", params.Chainid},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Remove the expired side chain registrations, updates and quits, it is invoked at commitDpos
+func SweepApplies(native *native.NativeService) ([]byte, error) {
+	if err := node_manager.CheckSweepCaller(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.SideChainManagerContractAddress, SIDE_CHAIN_APPLY, APPROVE_REGISTER_SIDE_CHAIN, deleteSideChainApply); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.SideChainManagerContractAddress, UPDATE_SIDE_CHAIN_REQUEST, APPROVE_UPDATE_SIDE_CHAIN, deleteUpdateSideChain); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.SideChainManagerContractAddress, QUIT_SIDE_CHAIN_REQUEST, QUIT_SIDE_CHAIN, deleteQuitSideChain); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	if err := node_manager.SweepIndexedApplies(native, utils.SideChainManagerContractAddress, ASSET_APPLY, APPROVE_ASSET, deleteAssetApply); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SweepApplies, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Apply to register the asset or update the registered asset, it takes effect once approved by consensus nodes
+func ApplyAsset(native *native.NativeService) ([]byte, error) {
+	params := new(ApplyAssetParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, checkWitness error: %v", err)
+	}
+
+	apply, err := getAssetApply(native, params.Asset.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, %v", err)
+	}
+	if apply != nil {
+		expired, err := isApplyExpired(native, ASSET_APPLY, params.Asset.ID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, isApplyExpired error: %v", err)
+		}
+		if !expired {
+			return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, asset %d already applied", params.Asset.ID)
+		}
+		//the consensus signs on the expired apply are discarded
+		err = node_manager.RemoveProposal(native, APPROVE_ASSET, utils.GetUint64Bytes(params.Asset.ID))
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, %v", err)
+		}
+	}
+	if err := checkAsset(native, params.Asset); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, checkAsset error: %v", err)
+	}
+
+	err = putAssetApply(native, params)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApplyAsset, putAssetApply error: %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.SideChainManagerContractAddress,
+			States:          []interface{}{"ApplyAsset", params.Asset.ID, params.Asset.Name},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+func ApproveAsset(native *native.NativeService) ([]byte, error) {
+	params := new(AssetParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, checkWitness error: %v", err)
+	}
+
+	apply, err := getAssetApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, %v", err)
+	}
+	if apply == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, asset %d is not applied", params.ID)
+	}
+	expired, err := isApplyExpired(native, ASSET_APPLY, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, isApplyExpired error: %v", err)
+	}
+	if expired {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, asset apply is expired")
+	}
+
+	//check consensus signs
+	ok, err := node_manager.CheckConsensusSigns(native, APPROVE_ASSET, utils.GetUint64Bytes(params.ID), params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	//side chains and asset contracts may be changed since the apply
+	if err := checkAsset(native, apply.Asset); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, checkAsset error: %v", err)
+	}
+	err = PutAsset(native, apply.Asset)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, PutAsset error: %v", err)
+	}
+	err = deleteAssetApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ApproveAsset, deleteAssetApply error: %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.SideChainManagerContractAddress,
+			States:          []interface{}{"ApproveAsset", params.ID},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//Cancel the asset apply by the applicant
+func CancelAsset(native *native.NativeService) ([]byte, error) {
+	params := new(AssetParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, checkWitness error: %v", err)
+	}
+
+	apply, err := getAssetApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, %v", err)
+	}
+	if apply == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, asset %d is not applied", params.ID)
+	}
+	if apply.Address != params.Address {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, address is not the applicant")
+	}
+
+	err = deleteAssetApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, deleteAssetApply error: %v", err)
+	}
+	err = node_manager.RemoveProposal(native, APPROVE_ASSET, utils.GetUint64Bytes(params.ID))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("CancelAsset, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.SideChainManagerContractAddress,
+			States:          []interface{}{"CancelAsset", params.ID},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
 func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 	params := new(RegisterRedeemParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -551,3 +932,183 @@ func UpdateFee(native *native.NativeService) ([]byte, error) {
 	PutFee(native, params.ChainId, fee)
 	return utils.BYTE_TRUE, nil
 }
+
+//Get routers supported by this node, with the handlers registered for each of them
+func GetRouters(native *native.NativeService) ([]byte, error) {
+	routerInfos := make(map[uint64]*RouterInfo)
+	getRouterInfo := func(router uint64) *RouterInfo {
+		routerInfo, ok := routerInfos[router]
+		if !ok {
+			routerInfo = &RouterInfo{
+				Router:     router,
+				StartBlock: utils.GetRouterStartBlock(router),
+			}
+			routerInfos[router] = routerInfo
+		}
+		return routerInfo
+	}
+	for _, router := range hscommon.GetHeaderSyncRouters() {
+		getRouterInfo(router).HeaderSync = true
+	}
+	for _, router := range scom.GetChainHandlerRouters() {
+		getRouterInfo(router).CrossChain = true
+	}
+
+	routerInfoList := &RouterInfoList{RouterInfos: make([]*RouterInfo, 0, len(routerInfos))}
+	for _, routerInfo := range routerInfos {
+		routerInfoList.RouterInfos = append(routerInfoList.RouterInfos, routerInfo)
+	}
+	sort.SliceStable(routerInfoList.RouterInfos, func(i, j int) bool {
+		return routerInfoList.RouterInfos[i].Router < routerInfoList.RouterInfos[j].Router
+	})
+	sink := common.NewZeroCopySink(nil)
+	routerInfoList.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the registered side chain, the result is empty if the chain is not registered
+func QuerySideChain(native *native.NativeService) ([]byte, error) {
+	params := new(QueryChainParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QuerySideChain, contract params deserialize error: %v", err)
+	}
+	sideChain, err := GetSideChain(native, params.ChainId)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QuerySideChain, getSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return []byte{}, nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := sideChain.Serialization(sink); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QuerySideChain, serialize side chain error: %v", err)
+	}
+	return sink.Bytes(), nil
+}
+
+//Get all the registered side chains in the order of chain id
+func ListSideChains(native *native.NativeService) ([]byte, error) {
+	sideChains, err := listSideChains(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListSideChains, listSideChains error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := (&SideChainList{SideChains: sideChains}).Serialization(sink); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListSideChains, serialize side chains error: %v", err)
+	}
+	return sink.Bytes(), nil
+}
+
+//Get the side chain registrations waiting for approval in the order of chain id
+func ListSideChainApplies(native *native.NativeService) ([]byte, error) {
+	sideChains, err := listSideChainApplies(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListSideChainApplies, listSideChainApplies error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := (&SideChainList{SideChains: sideChains}).Serialization(sink); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListSideChainApplies, serialize side chains error: %v", err)
+	}
+	return sink.Bytes(), nil
+}
+
+//Get the side chain updates waiting for approval in the order of chain id
+func ListUpdateSideChainRequests(native *native.NativeService) ([]byte, error) {
+	sideChains, err := listUpdateSideChains(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListUpdateSideChainRequests, listUpdateSideChains error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := (&SideChainList{SideChains: sideChains}).Serialization(sink); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListUpdateSideChainRequests, serialize side chains error: %v", err)
+	}
+	return sink.Bytes(), nil
+}
+
+//Get the chain ids of side chains waiting for quit approval
+func ListQuitSideChainRequests(native *native.NativeService) ([]byte, error) {
+	chainIDs, err := getChainIDIndex(native, QUIT_SIDE_CHAIN_REQUEST)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListQuitSideChainRequests, getChainIDIndex error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&ChainIDList{ChainIDs: chainIDs}).Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the fee of side chain, the fee is zero if it has never been voted
+func QueryFee(native *native.NativeService) ([]byte, error) {
+	params := new(QueryChainParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryFee, contract params deserialize error: %v", err)
+	}
+	fee, err := GetFee(native, params.ChainId)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryFee, GetFee error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	fee.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the assets and lock proxies bound to side chain
+func QueryAssetBind(native *native.NativeService) ([]byte, error) {
+	params := new(QueryChainParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryAssetBind, contract params deserialize error: %v", err)
+	}
+	assetBind, err := GetAssetBind(native, params.ChainId)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryAssetBind, GetAssetBind error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	assetBind.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the registered asset, the result is empty if the asset is not registered
+func QueryAsset(native *native.NativeService) ([]byte, error) {
+	params := new(QueryAssetParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryAsset, contract params deserialize error: %v", err)
+	}
+	asset, err := GetAsset(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryAsset, %v", err)
+	}
+	if asset == nil {
+		return []byte{}, nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	asset.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get the asset apply waiting for approval, the result is empty if the asset is not applied
+func QueryAssetApply(native *native.NativeService) ([]byte, error) {
+	params := new(QueryAssetParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryAssetApply, contract params deserialize error: %v", err)
+	}
+	apply, err := getAssetApply(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("QueryAssetApply, %v", err)
+	}
+	if apply == nil {
+		return []byte{}, nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	apply.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//Get all the registered assets in the order of id
+func ListAssets(native *native.NativeService) ([]byte, error) {
+	assets, err := listAssets(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ListAssets, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&AssetList{Assets: assets}).Serialization(sink)
+	return sink.Bytes(), nil
+}
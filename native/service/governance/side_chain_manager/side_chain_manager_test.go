@@ -23,6 +23,7 @@ import (
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/genesis"
+	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
@@ -30,6 +31,7 @@ import (
 	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/polynetwork/poly/native/storage"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"strings"
 	"testing"
 )
@@ -242,3 +244,149 @@ func TestSetBtcTxParam(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, utils.BYTE_FALSE, ok)
 }
+
+func TestQuerySideChain(t *testing.T) {
+	ns := getNativeFunc(nil)
+	for _, chainID := range []uint64{300, 2, 10} {
+		assert.NoError(t, PutSideChain(ns, &SideChain{Address: acct.Address, ChainId: chainID, Router: 3, Name: "chain"}))
+	}
+	assert.NoError(t, putSideChainApply(ns, &SideChain{Address: acct.Address, ChainId: 5, Router: 3, Name: "apply"}))
+	PutFee(ns, 2, &Fee{View: 3, Fee: big.NewInt(100)})
+
+	sink := common.NewZeroCopySink(nil)
+	(&QueryChainParam{ChainId: 10}).Serialization(sink)
+	ns = NewNative(sink.Bytes(), new(types.Transaction), ns.GetCacheDB())
+	res, err := QuerySideChain(ns)
+	assert.NoError(t, err)
+	sideChain := new(SideChain)
+	assert.NoError(t, sideChain.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, uint64(10), sideChain.ChainId)
+
+	sink = common.NewZeroCopySink(nil)
+	(&QueryChainParam{ChainId: 5}).Serialization(sink)
+	ns = NewNative(sink.Bytes(), new(types.Transaction), ns.GetCacheDB())
+	res, err = QuerySideChain(ns)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	ns = NewNative(nil, new(types.Transaction), ns.GetCacheDB())
+	res, err = ListSideChains(ns)
+	assert.NoError(t, err)
+	sideChainList := new(SideChainList)
+	assert.NoError(t, sideChainList.Deserialization(common.NewZeroCopySource(res)))
+	chainIDs := make([]uint64, 0)
+	for _, sideChain := range sideChainList.SideChains {
+		chainIDs = append(chainIDs, sideChain.ChainId)
+	}
+	assert.Equal(t, []uint64{2, 10, 300}, chainIDs)
+
+	sink = common.NewZeroCopySink(nil)
+	(&QueryChainParam{ChainId: 2}).Serialization(sink)
+	ns = NewNative(sink.Bytes(), new(types.Transaction), ns.GetCacheDB())
+	res, err = QueryFee(ns)
+	assert.NoError(t, err)
+	fee := new(Fee)
+	assert.NoError(t, fee.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, uint64(3), fee.View)
+	assert.Equal(t, big.NewInt(100), fee.Fee)
+}
+
+func TestSideChainIndex(t *testing.T) {
+	ns := getNativeFunc(nil)
+	//side chain stored before the index is introduced
+	sink := common.NewZeroCopySink(nil)
+	assert.NoError(t, (&SideChain{Address: acct.Address, ChainId: 7, Name: "legacy"}).Serialization(sink))
+	ns.GetCacheDB().Put(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN), utils.GetUint64Bytes(7)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+	assert.NoError(t, PutSideChain(ns, &SideChain{Address: acct.Address, ChainId: 3, Name: "new"}))
+	assert.NoError(t, PutSideChain(ns, &SideChain{Address: acct.Address, ChainId: 3, Name: "renamed"}))
+	chainIDs, err := getChainIDIndex(ns, SIDE_CHAIN)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 7}, chainIDs)
+
+	assert.NoError(t, putSideChainApply(ns, &SideChain{Address: acct.Address, ChainId: 9, Name: "apply"}))
+	assert.NoError(t, putUpdateSideChain(ns, &SideChain{Address: acct.Address, ChainId: 3, Name: "update"}))
+	assert.NoError(t, putQuitSideChain(ns, 7))
+	applies, err := listSideChainApplies(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(applies))
+	assert.Equal(t, "apply", applies[0].Name)
+	updates, err := listUpdateSideChains(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, "update", updates[0].Name)
+
+	assert.NoError(t, deleteSideChainApply(ns, 9))
+	assert.NoError(t, deleteUpdateSideChain(ns, 3))
+	assert.NoError(t, deleteQuitSideChain(ns, 7))
+	assert.NoError(t, deleteSideChain(ns, 7))
+	for _, prefix := range []string{SIDE_CHAIN_APPLY, UPDATE_SIDE_CHAIN_REQUEST, QUIT_SIDE_CHAIN_REQUEST} {
+		chainIDs, err := getChainIDIndex(ns, prefix)
+		assert.NoError(t, err)
+		assert.Empty(t, chainIDs, prefix)
+	}
+	sideChains, err := listSideChains(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sideChains))
+	assert.Equal(t, "renamed", sideChains[0].Name)
+}
+
+func TestAssetRegistry(t *testing.T) {
+	ns := getNativeFunc(nil)
+	db := ns.GetCacheDB()
+	for _, chainID := range []uint64{2, 3} {
+		assert.NoError(t, PutSideChain(ns, &SideChain{Address: acct.Address, ChainId: chainID, Name: "chain"}))
+	}
+	asset := &Asset{ID: 1, Name: "usdt", Decimals: 18, Chains: []*AssetChain{
+		{ChainID: 2, Hash: []byte("lock"), Decimals: 6, Model: AssetLock, Supply: big.NewInt(0)},
+		{ChainID: 3, Hash: []byte("mint"), Decimals: 18, Model: AssetMint, Supply: big.NewInt(0)},
+	}}
+	apply := func(addr common.Address, asset *Asset) error {
+		sink := common.NewZeroCopySink(nil)
+		(&ApplyAssetParam{Address: addr, Asset: asset}).Serialization(sink)
+		_, err := ApplyAsset(NewNative(sink.Bytes(), &types.Transaction{SignedAddr: []common.Address{addr}}, db))
+		return err
+	}
+	assert.NoError(t, apply(acct.Address, asset))
+	assert.Error(t, apply(acct.Address, asset))
+	stored, err := getAssetApply(ns, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, asset, stored.Asset)
+
+	assert.Error(t, checkAsset(ns, &Asset{ID: 2, Name: "x", Decimals: 6, Chains: []*AssetChain{
+		{ChainID: 2, Hash: []byte("x"), Decimals: 8, Model: AssetLock, Supply: big.NewInt(0)}}}))
+	assert.Error(t, checkAsset(ns, &Asset{ID: 2, Name: "x", Decimals: 6, Chains: []*AssetChain{
+		{ChainID: 4, Hash: []byte("x"), Decimals: 6, Model: AssetLock, Supply: big.NewInt(0)}}}))
+	assert.Error(t, checkAsset(ns, &Asset{ID: 2, Name: "x", Decimals: 6, Chains: []*AssetChain{
+		{ChainID: 3, Hash: []byte("x"), Decimals: 6, Model: AssetMint, Supply: big.NewInt(0)}}}))
+
+	assert.NoError(t, PutAsset(ns, asset))
+	found, err := GetAssetByHash(ns, 3, []byte("mint"))
+	assert.NoError(t, err)
+	assert.Equal(t, asset, found)
+	assert.Equal(t, big.NewInt(1e18), found.Normalize(found.GetChain(2), big.NewInt(1e6)))
+	//the contract of registered asset can not be registered by another asset
+	assert.Error(t, checkAsset(ns, &Asset{ID: 2, Name: "x", Decimals: 6, Chains: []*AssetChain{
+		{ChainID: 2, Hash: []byte("lock"), Decimals: 6, Model: AssetLock, Supply: big.NewInt(0)}}}))
+
+	assert.NoError(t, PutAsset(ns, &Asset{ID: 1, Name: "usdt", Decimals: 18, Chains: asset.Chains[:1]}))
+	found, err = GetAssetByHash(ns, 3, []byte("mint"))
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+	assets, err := listAssets(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(assets))
+
+	cancel := func(addr common.Address) error {
+		sink := common.NewZeroCopySink(nil)
+		(&AssetParam{ID: 1, Address: addr}).Serialization(sink)
+		_, err := CancelAsset(NewNative(sink.Bytes(), &types.Transaction{SignedAddr: []common.Address{addr}}, db))
+		return err
+	}
+	other := account.NewAccount("")
+	assert.Error(t, cancel(other.Address))
+	assert.NoError(t, cancel(acct.Address))
+	stored, err = getAssetApply(ns, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
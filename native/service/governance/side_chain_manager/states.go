@@ -289,3 +289,298 @@ func (this *RippleExtraInfo) Deserialization(source *common.ZeroCopySource) erro
 	this.ReserveAmount = new(big.Int).SetBytes(reserveAmount)
 	return nil
 }
+
+type RouterInfo struct {
+	Router     uint64
+	HeaderSync bool
+	CrossChain bool
+	StartBlock uint32
+}
+
+func (this *RouterInfo) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Router)
+	sink.WriteBool(this.HeaderSync)
+	sink.WriteBool(this.CrossChain)
+	sink.WriteUint32(this.StartBlock)
+}
+
+func (this *RouterInfo) Deserialization(source *common.ZeroCopySource) error {
+	router, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RouterInfo deserialize router error")
+	}
+	headerSync, eof := source.NextBool()
+	if eof {
+		return fmt.Errorf("RouterInfo deserialize header sync error")
+	}
+	crossChain, eof := source.NextBool()
+	if eof {
+		return fmt.Errorf("RouterInfo deserialize cross chain error")
+	}
+	startBlock, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RouterInfo deserialize start block error")
+	}
+	this.Router = router
+	this.HeaderSync = headerSync
+	this.CrossChain = crossChain
+	this.StartBlock = startBlock
+	return nil
+}
+
+type RouterInfoList struct {
+	RouterInfos []*RouterInfo
+}
+
+func (this *RouterInfoList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.RouterInfos)))
+	for _, v := range this.RouterInfos {
+		v.Serialization(sink)
+	}
+}
+
+func (this *RouterInfoList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RouterInfoList deserialize length error")
+	}
+	routerInfos := make([]*RouterInfo, 0, n)
+	for i := uint64(0); i < n; i++ {
+		routerInfo := new(RouterInfo)
+		if err := routerInfo.Deserialization(source); err != nil {
+			return fmt.Errorf("RouterInfoList deserialize no.%d router info error: %v", i+1, err)
+		}
+		routerInfos = append(routerInfos, routerInfo)
+	}
+	this.RouterInfos = routerInfos
+	return nil
+}
+
+//Side chains are written as var bytes, since the extra info of side chain is optional
+type SideChainList struct {
+	SideChains []*SideChain
+}
+
+func (this *SideChainList) Serialization(sink *common.ZeroCopySink) error {
+	sink.WriteVarUint(uint64(len(this.SideChains)))
+	for _, v := range this.SideChains {
+		sideChainSink := common.NewZeroCopySink(nil)
+		if err := v.Serialization(sideChainSink); err != nil {
+			return err
+		}
+		sink.WriteVarBytes(sideChainSink.Bytes())
+	}
+	return nil
+}
+
+func (this *SideChainList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SideChainList deserialize length error")
+	}
+	sideChains := make([]*SideChain, 0)
+	for i := uint64(0); i < n; i++ {
+		data, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("SideChainList deserialize no.%d side chain bytes error", i+1)
+		}
+		sideChain := new(SideChain)
+		if err := sideChain.Deserialization(common.NewZeroCopySource(data)); err != nil {
+			return fmt.Errorf("SideChainList deserialize no.%d side chain error: %v", i+1, err)
+		}
+		sideChains = append(sideChains, sideChain)
+	}
+	this.SideChains = sideChains
+	return nil
+}
+
+type ChainIDList struct {
+	ChainIDs []uint64
+}
+
+func (this *ChainIDList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.ChainIDs)))
+	for _, v := range this.ChainIDs {
+		sink.WriteVarUint(v)
+	}
+}
+
+func (this *ChainIDList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ChainIDList deserialize length error")
+	}
+	chainIDs := make([]uint64, 0)
+	for i := uint64(0); i < n; i++ {
+		chainID, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("ChainIDList deserialize no.%d chain id error", i+1)
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	this.ChainIDs = chainIDs
+	return nil
+}
+
+//Model of the asset on a chain, the asset is either locked in the lock proxy or minted by the asset contract
+type AssetModel uint8
+
+const (
+	AssetLock AssetModel = iota
+	AssetMint
+)
+
+func (this AssetModel) String() string {
+	switch this {
+	case AssetLock:
+		return "lock"
+	case AssetMint:
+		return "mint"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(this))
+}
+
+//The asset contract on a chain, supply is the amount locked or minted on the chain in the decimals of asset
+//before the transfers of it are tracked by cross chain manager
+type AssetChain struct {
+	ChainID  uint64
+	Hash     []byte
+	Decimals uint8
+	Model    AssetModel
+	Supply   *big.Int
+}
+
+func (this *AssetChain) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarBytes(this.Hash)
+	sink.WriteByte(this.Decimals)
+	sink.WriteByte(byte(this.Model))
+	sink.WriteVarBytes(this.Supply.Bytes())
+}
+
+func (this *AssetChain) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("AssetChain deserialize chain id error")
+	}
+	hash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("AssetChain deserialize hash error")
+	}
+	decimals, eof := source.NextByte()
+	if eof {
+		return fmt.Errorf("AssetChain deserialize decimals error")
+	}
+	model, eof := source.NextByte()
+	if eof {
+		return fmt.Errorf("AssetChain deserialize model error")
+	}
+	if AssetModel(model) != AssetLock && AssetModel(model) != AssetMint {
+		return fmt.Errorf("AssetChain deserialize model error: unknown model %d", model)
+	}
+	supply, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("AssetChain deserialize supply error")
+	}
+	this.ChainID = chainID
+	this.Hash = hash
+	this.Decimals = decimals
+	this.Model = AssetModel(model)
+	this.Supply = new(big.Int).SetBytes(supply)
+	return nil
+}
+
+//Canonical asset of poly with the contracts on the chains in the order of chain id, decimals of the asset is
+//the precision the amounts on all chains are accounted in
+type Asset struct {
+	ID       uint64
+	Name     string
+	Decimals uint8
+	Chains   []*AssetChain
+}
+
+func (this *Asset) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ID)
+	sink.WriteString(this.Name)
+	sink.WriteByte(this.Decimals)
+	sink.WriteVarUint(uint64(len(this.Chains)))
+	for _, v := range this.Chains {
+		v.Serialization(sink)
+	}
+}
+
+func (this *Asset) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("Asset deserialize id error")
+	}
+	name, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("Asset deserialize name error")
+	}
+	decimals, eof := source.NextByte()
+	if eof {
+		return fmt.Errorf("Asset deserialize decimals error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("Asset deserialize length of chains error")
+	}
+	chains := make([]*AssetChain, 0)
+	for i := uint64(0); i < n; i++ {
+		chain := new(AssetChain)
+		if err := chain.Deserialization(source); err != nil {
+			return fmt.Errorf("Asset deserialize no.%d chain error: %v", i+1, err)
+		}
+		chains = append(chains, chain)
+	}
+	this.ID = id
+	this.Name = name
+	this.Decimals = decimals
+	this.Chains = chains
+	return nil
+}
+
+//Get the asset contract on the chain, it is nil if the asset is not on the chain
+func (this *Asset) GetChain(chainID uint64) *AssetChain {
+	for _, v := range this.Chains {
+		if v.ChainID == chainID {
+			return v
+		}
+	}
+	return nil
+}
+
+//Convert the amount in the decimals of asset contract on the chain to the decimals of asset
+func (this *Asset) Normalize(chain *AssetChain, amount *big.Int) *big.Int {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(this.Decimals-chain.Decimals)), nil)
+	return new(big.Int).Mul(amount, scale)
+}
+
+type AssetList struct {
+	Assets []*Asset
+}
+
+func (this *AssetList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Assets)))
+	for _, v := range this.Assets {
+		v.Serialization(sink)
+	}
+}
+
+func (this *AssetList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("AssetList deserialize length error")
+	}
+	assets := make([]*Asset, 0)
+	for i := uint64(0); i < n; i++ {
+		asset := new(Asset)
+		if err := asset.Deserialization(source); err != nil {
+			return fmt.Errorf("AssetList deserialize no.%d asset error: %v", i+1, err)
+		}
+		assets = append(assets, asset)
+	}
+	this.Assets = assets
+	return nil
+}
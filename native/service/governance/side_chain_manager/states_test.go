@@ -20,6 +20,7 @@ package side_chain_manager
 import (
 	"github.com/polynetwork/poly/common"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 )
 
@@ -38,3 +39,39 @@ func TestSideChain_Serialization(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, paramDeserialize, paramSerialize)
 }
+
+func TestRouterInfoList_Serialization(t *testing.T) {
+	paramSerialize := &RouterInfoList{
+		RouterInfos: []*RouterInfo{
+			{Router: 0, CrossChain: true},
+			{Router: 15, HeaderSync: true},
+			{Router: 21, HeaderSync: true, CrossChain: true, StartBlock: 18823000},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	paramSerialize.Serialization(sink)
+
+	paramDeserialize := new(RouterInfoList)
+	err := paramDeserialize.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, paramDeserialize, paramSerialize)
+}
+
+func TestAssetList_Serialization(t *testing.T) {
+	paramSerialize := &AssetList{
+		Assets: []*Asset{
+			{ID: 1, Name: "usdt", Decimals: 18, Chains: []*AssetChain{
+				{ChainID: 2, Hash: []byte{1, 2}, Decimals: 6, Model: AssetLock, Supply: big.NewInt(100)},
+				{ChainID: 6, Hash: []byte{3}, Decimals: 18, Model: AssetMint, Supply: big.NewInt(100000)},
+			}},
+			{ID: 3, Name: "eth", Decimals: 18, Chains: []*AssetChain{}},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	paramSerialize.Serialization(sink)
+
+	paramDeserialize := new(AssetList)
+	err := paramDeserialize.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, paramDeserialize, paramSerialize)
+}
@@ -20,6 +20,7 @@ package side_chain_manager
 import (
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -28,11 +29,68 @@ import (
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
 var netParam = &chaincfg.TestNet3Params
 
+//Get the chain ids, or the other uint64 ids such as asset id, indexed for the prefix in ascending order
+func getChainIDIndex(native *native.NativeService, prefix string) ([]uint64, error) {
+	keys, err := utils.GetIndexKeys(native, utils.SideChainManagerContractAddress, prefix, 8)
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := make([]uint64, 0, len(keys))
+	for _, key := range keys {
+		chainIDs = append(chainIDs, utils.GetBytesUint64(key))
+	}
+	sort.SliceStable(chainIDs, func(i, j int) bool {
+		return chainIDs[i] < chainIDs[j]
+	})
+	return chainIDs, nil
+}
+
+func listSideChains(native *native.NativeService) ([]*SideChain, error) {
+	chainIDs, err := getChainIDIndex(native, SIDE_CHAIN)
+	if err != nil {
+		return nil, fmt.Errorf("listSideChains, getChainIDIndex error: %v", err)
+	}
+	return listSideChainsOf(native, chainIDs, GetSideChain)
+}
+
+func listSideChainApplies(native *native.NativeService) ([]*SideChain, error) {
+	chainIDs, err := getChainIDIndex(native, SIDE_CHAIN_APPLY)
+	if err != nil {
+		return nil, fmt.Errorf("listSideChainApplies, getChainIDIndex error: %v", err)
+	}
+	return listSideChainsOf(native, chainIDs, getSideChainApply)
+}
+
+func listUpdateSideChains(native *native.NativeService) ([]*SideChain, error) {
+	chainIDs, err := getChainIDIndex(native, UPDATE_SIDE_CHAIN_REQUEST)
+	if err != nil {
+		return nil, fmt.Errorf("listUpdateSideChains, getChainIDIndex error: %v", err)
+	}
+	return listSideChainsOf(native, chainIDs, getUpdateSideChain)
+}
+
+func listSideChainsOf(native *native.NativeService, chainIDs []uint64,
+	get func(*native.NativeService, uint64) (*SideChain, error)) ([]*SideChain, error) {
+	sideChains := make([]*SideChain, 0, len(chainIDs))
+	for _, chainID := range chainIDs {
+		sideChain, err := get(native, chainID)
+		if err != nil {
+			return nil, err
+		}
+		if sideChain == nil {
+			return nil, fmt.Errorf("indexed side chain %d not found", chainID)
+		}
+		sideChains = append(sideChains, sideChain)
+	}
+	return sideChains, nil
+}
+
 func getSideChainApply(native *native.NativeService, chanid uint64) (*SideChain, error) {
 	contract := utils.SideChainManagerContractAddress
 	chainidByte := utils.GetUint64Bytes(chanid)
@@ -69,7 +127,19 @@ func putSideChainApply(native *native.NativeService, sideChain *SideChain) error
 
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(SIDE_CHAIN_APPLY), chainidByte),
 		cstates.GenRawStorageItem(sink.Bytes()))
-	return nil
+	if err := utils.PutApplyInfo(native, contract, SIDE_CHAIN_APPLY, chainidByte); err != nil {
+		return fmt.Errorf("putSideChainApply, %v", err)
+	}
+	return utils.AddIndexKey(native, contract, SIDE_CHAIN_APPLY, chainidByte)
+}
+
+func deleteSideChainApply(native *native.NativeService, chainid uint64) error {
+	contract := utils.SideChainManagerContractAddress
+	chainidByte := utils.GetUint64Bytes(chainid)
+
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(SIDE_CHAIN_APPLY), chainidByte))
+	utils.DeleteApplyInfo(native, contract, SIDE_CHAIN_APPLY, chainidByte)
+	return utils.RemoveIndexKey(native, contract, SIDE_CHAIN_APPLY, chainidByte)
 }
 
 func GetSideChain(native *native.NativeService, chainID uint64) (*SideChain, error) {
@@ -108,7 +178,15 @@ func PutSideChain(native *native.NativeService, sideChain *SideChain) error {
 
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(SIDE_CHAIN), chainidByte),
 		cstates.GenRawStorageItem(sink.Bytes()))
-	return nil
+	return utils.AddIndexKey(native, contract, SIDE_CHAIN, chainidByte)
+}
+
+func deleteSideChain(native *native.NativeService, chainid uint64) error {
+	contract := utils.SideChainManagerContractAddress
+	chainidByte := utils.GetUint64Bytes(chainid)
+
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(SIDE_CHAIN), chainidByte))
+	return utils.RemoveIndexKey(native, contract, SIDE_CHAIN, chainidByte)
 }
 
 func getUpdateSideChain(native *native.NativeService, chanid uint64) (*SideChain, error) {
@@ -147,7 +225,19 @@ func putUpdateSideChain(native *native.NativeService, sideChain *SideChain) erro
 
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(UPDATE_SIDE_CHAIN_REQUEST), chainidByte),
 		cstates.GenRawStorageItem(sink.Bytes()))
-	return nil
+	if err := utils.PutApplyInfo(native, contract, UPDATE_SIDE_CHAIN_REQUEST, chainidByte); err != nil {
+		return fmt.Errorf("putUpdateSideChain, %v", err)
+	}
+	return utils.AddIndexKey(native, contract, UPDATE_SIDE_CHAIN_REQUEST, chainidByte)
+}
+
+func deleteUpdateSideChain(native *native.NativeService, chainid uint64) error {
+	contract := utils.SideChainManagerContractAddress
+	chainidByte := utils.GetUint64Bytes(chainid)
+
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(UPDATE_SIDE_CHAIN_REQUEST), chainidByte))
+	utils.DeleteApplyInfo(native, contract, UPDATE_SIDE_CHAIN_REQUEST, chainidByte)
+	return utils.RemoveIndexKey(native, contract, UPDATE_SIDE_CHAIN_REQUEST, chainidByte)
 }
 
 func getQuitSideChain(native *native.NativeService, chainid uint64) error {
@@ -171,7 +261,19 @@ func putQuitSideChain(native *native.NativeService, chainid uint64) error {
 
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(QUIT_SIDE_CHAIN_REQUEST), chainidByte),
 		cstates.GenRawStorageItem(chainidByte))
-	return nil
+	if err := utils.PutApplyInfo(native, contract, QUIT_SIDE_CHAIN_REQUEST, chainidByte); err != nil {
+		return fmt.Errorf("putQuitSideChain, %v", err)
+	}
+	return utils.AddIndexKey(native, contract, QUIT_SIDE_CHAIN_REQUEST, chainidByte)
+}
+
+func deleteQuitSideChain(native *native.NativeService, chainid uint64) error {
+	contract := utils.SideChainManagerContractAddress
+	chainidByte := utils.GetUint64Bytes(chainid)
+
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(QUIT_SIDE_CHAIN_REQUEST), chainidByte))
+	utils.DeleteApplyInfo(native, contract, QUIT_SIDE_CHAIN_REQUEST, chainidByte)
+	return utils.RemoveIndexKey(native, contract, QUIT_SIDE_CHAIN_REQUEST, chainidByte)
 }
 
 func GetContractBind(native *native.NativeService, redeemChainID, contractChainID uint64,
@@ -480,4 +582,185 @@ func PutRippleExtraInfo(native *native.NativeService, chainId uint64, rippleExtr
 		return fmt.Errorf("PutRippleExtraInfo, PutSideChain error: %v", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+//Check if the application of prefix for the chain id is expired
+func isApplyExpired(native *native.NativeService, prefix string, chainid uint64) (bool, error) {
+	return utils.IsApplyExpired(native, utils.SideChainManagerContractAddress, prefix, utils.GetUint64Bytes(chainid))
+}
+
+func getAssetApply(native *native.NativeService, id uint64) (*ApplyAssetParam, error) {
+	contract := utils.SideChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(ASSET_APPLY), utils.GetUint64Bytes(id)))
+	if err != nil {
+		return nil, fmt.Errorf("getAssetApply, get asset apply store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	applyBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getAssetApply, deserialize from raw storage item err:%v", err)
+	}
+	apply := new(ApplyAssetParam)
+	if err := apply.Deserialization(common.NewZeroCopySource(applyBytes)); err != nil {
+		return nil, fmt.Errorf("getAssetApply, deserialize asset apply error: %v", err)
+	}
+	return apply, nil
+}
+
+func putAssetApply(native *native.NativeService, apply *ApplyAssetParam) error {
+	contract := utils.SideChainManagerContractAddress
+	idBytes := utils.GetUint64Bytes(apply.Asset.ID)
+	sink := common.NewZeroCopySink(nil)
+	apply.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(ASSET_APPLY), idBytes), cstates.GenRawStorageItem(sink.Bytes()))
+	if err := utils.PutApplyInfo(native, contract, ASSET_APPLY, idBytes); err != nil {
+		return fmt.Errorf("putAssetApply, %v", err)
+	}
+	return utils.AddIndexKey(native, contract, ASSET_APPLY, idBytes)
+}
+
+func deleteAssetApply(native *native.NativeService, id uint64) error {
+	contract := utils.SideChainManagerContractAddress
+	idBytes := utils.GetUint64Bytes(id)
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(ASSET_APPLY), idBytes))
+	utils.DeleteApplyInfo(native, contract, ASSET_APPLY, idBytes)
+	return utils.RemoveIndexKey(native, contract, ASSET_APPLY, idBytes)
+}
+
+func GetAsset(native *native.NativeService, id uint64) (*Asset, error) {
+	contract := utils.SideChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(ASSET_INFO), utils.GetUint64Bytes(id)))
+	if err != nil {
+		return nil, fmt.Errorf("GetAsset, get asset store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	assetBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetAsset, deserialize from raw storage item err:%v", err)
+	}
+	asset := new(Asset)
+	if err := asset.Deserialization(common.NewZeroCopySource(assetBytes)); err != nil {
+		return nil, fmt.Errorf("GetAsset, deserialize asset error: %v", err)
+	}
+	return asset, nil
+}
+
+//Get the asset of the asset contract on the chain, it is nil if the contract is not registered
+func GetAssetByHash(native *native.NativeService, chainID uint64, hash []byte) (*Asset, error) {
+	id, ok, err := getAssetID(native, chainID, hash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return GetAsset(native, id)
+}
+
+func assetHashKey(chainID uint64, hash []byte) []byte {
+	return utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(ASSET_HASH), utils.GetUint64Bytes(chainID), hash)
+}
+
+func getAssetID(native *native.NativeService, chainID uint64, hash []byte) (uint64, bool, error) {
+	store, err := native.GetCacheDB().Get(assetHashKey(chainID, hash))
+	if err != nil {
+		return 0, false, fmt.Errorf("getAssetID, get asset id store error: %v", err)
+	}
+	if store == nil {
+		return 0, false, nil
+	}
+	idBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, false, fmt.Errorf("getAssetID, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint64(idBytes), true, nil
+}
+
+//Store the asset and map its contracts to it, the contracts removed from the asset are unmapped and the supplies
+//tracked on the chains removed from the asset are deleted
+func PutAsset(native *native.NativeService, asset *Asset) error {
+	contract := utils.SideChainManagerContractAddress
+	idBytes := utils.GetUint64Bytes(asset.ID)
+	old, err := GetAsset(native, asset.ID)
+	if err != nil {
+		return fmt.Errorf("PutAsset, %v", err)
+	}
+	if old != nil {
+		for _, chain := range old.Chains {
+			native.GetCacheDB().Delete(assetHashKey(chain.ChainID, chain.Hash))
+			if asset.GetChain(chain.ChainID) == nil {
+				scom.DeleteAssetSupply(native, asset.ID, chain.ChainID)
+			}
+		}
+	}
+	for _, chain := range asset.Chains {
+		native.GetCacheDB().Put(assetHashKey(chain.ChainID, chain.Hash), cstates.GenRawStorageItem(idBytes))
+	}
+	sink := common.NewZeroCopySink(nil)
+	asset.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(ASSET_INFO), idBytes), cstates.GenRawStorageItem(sink.Bytes()))
+	return utils.AddIndexKey(native, contract, ASSET_INFO, idBytes)
+}
+
+//Get all the registered assets in the order of id
+func listAssets(native *native.NativeService) ([]*Asset, error) {
+	ids, err := getChainIDIndex(native, ASSET_INFO)
+	if err != nil {
+		return nil, fmt.Errorf("listAssets, getChainIDIndex error: %v", err)
+	}
+	assets := make([]*Asset, 0, len(ids))
+	for _, id := range ids {
+		asset, err := GetAsset(native, id)
+		if err != nil {
+			return nil, fmt.Errorf("listAssets, %v", err)
+		}
+		if asset == nil {
+			return nil, fmt.Errorf("listAssets, indexed asset %d not found", id)
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+//Check the asset contracts are on registered side chains once each, with the decimals not more than the asset,
+//and not registered by other assets. The asset should be locked on one chain at least
+func checkAsset(native *native.NativeService, asset *Asset) error {
+	if len(asset.Name) == 0 {
+		return fmt.Errorf("name of asset is empty")
+	}
+	locked := false
+	for i, chain := range asset.Chains {
+		if i > 0 && chain.ChainID <= asset.Chains[i-1].ChainID {
+			return fmt.Errorf("chains of asset should be in ascending order of chain id")
+		}
+		if len(chain.Hash) == 0 {
+			return fmt.Errorf("hash of asset on chain %d is empty", chain.ChainID)
+		}
+		if chain.Decimals > asset.Decimals {
+			return fmt.Errorf("decimals %d of asset on chain %d is more than decimals %d of asset",
+				chain.Decimals, chain.ChainID, asset.Decimals)
+		}
+		sideChain, err := GetSideChain(native, chain.ChainID)
+		if err != nil {
+			return err
+		}
+		if sideChain == nil {
+			return fmt.Errorf("side chain %d is not registered", chain.ChainID)
+		}
+		id, ok, err := getAssetID(native, chain.ChainID, chain.Hash)
+		if err != nil {
+			return err
+		}
+		if ok && id != asset.ID {
+			return fmt.Errorf("hash %x on chain %d is registered by asset %d", chain.Hash, chain.ChainID, id)
+		}
+		if chain.Model == AssetLock {
+			locked = true
+		}
+	}
+	if !locked {
+		return fmt.Errorf("asset is not locked on any chain")
+	}
+	return nil
+}
@@ -17,6 +17,7 @@
 package signature_manager
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/core/store/overlaydb"
@@ -123,3 +125,59 @@ func TestConsensusSig(t *testing.T) {
 	}
 
 }
+
+func addSignature(t *testing.T, db *storage.CacheDB, acct *account.Account, subject []byte) bool {
+	tx := &types.Transaction{
+		SignedAddr: []common.Address{acct.Address},
+	}
+	param := AddSignatureParam{
+		Address:   acct.Address,
+		Subject:   subject,
+		Signature: acct.Address[:],
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+
+	nativeService := NewNative(sink.Bytes(), tx, db)
+	res, err := AddSignature(nativeService)
+	assert.Nil(t, err)
+	assert.Equal(t, res, []byte{1})
+	return len(nativeService.GetNotify()) > 0
+}
+
+func TestConsensusSigProposal(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	Init(db)
+
+	//legacy signatures before the proposals are enabled
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	for i := 0; i < 2; i++ {
+		assert.False(t, addSignature(t, db, acctList1[i], []byte("demo")))
+	}
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, i == 4, addSignature(t, db, acctList1[i], []byte("passed")))
+	}
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	//the quorum reached by legacy signatures is not emitted again
+	assert.False(t, addSignature(t, db, acct6, []byte("passed")))
+
+	//legacy signatures are counted
+	for i := 2; i < 5; i++ {
+		assert.Equal(t, i == 4, addSignature(t, db, acctList1[i], []byte("demo")))
+	}
+	assert.False(t, addSignature(t, db, acct6, []byte("demo")))
+
+	//signatures are kept for relayers
+	id := sha256.Sum256([]byte("demo"))
+	sigInfo, err := getSigInfo(NewNative(nil, &types.Transaction{}, db), id[:])
+	assert.Nil(t, err)
+	assert.True(t, sigInfo.Status)
+	assert.Equal(t, 5, len(sigInfo.SigInfo))
+	assert.Equal(t, acct1.Address[:], sigInfo.SigInfo[acct1.Address.ToBase58()])
+}
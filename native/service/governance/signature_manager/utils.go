@@ -17,13 +17,10 @@
 package signature_manager
 
 import (
-	"encoding/hex"
 	"fmt"
 
-	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
-	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/utils"
@@ -33,90 +30,88 @@ const (
 	SIG_INFO = "sigInfo"
 )
 
+//Check the signatures of consensus peers on id, true is returned only once when the signatures reach the quorum.
+//The signatures are always kept in the sig info of id for the relayers
 func CheckSigns(native *native.NativeService, id, sig []byte, address common.Address) (bool, error) {
 	sigInfo, err := getSigInfo(native, id)
 	if err != nil {
 		return false, fmt.Errorf("CheckSigs, getSigInfo error: %v", err)
 	}
 
-	//get view
-	view, err := node_manager.GetView(native)
+	//check if signer is consensus peer
+	consensus, err := node_manager.IsConsensusAddress(native, address)
 	if err != nil {
-		return false, fmt.Errorf("CheckSigs, GetView error: %v", err)
+		return false, fmt.Errorf("CheckSigs, %v", err)
 	}
-	//get consensus peer
-	peerPoolMap, err := node_manager.GetPeerPoolMap(native, view)
-	if err != nil {
-		return false, fmt.Errorf("CheckSigs, GetPeerPoolMap error: %v", err)
+	if !consensus {
+		return false, fmt.Errorf("CheckSigs, signer is not consensus peer")
 	}
 
-	//check if signer is consensus peer
-	consensus := false
-	for key, v := range peerPoolMap.PeerPoolMap {
-		if v.Status == node_manager.ConsensusStatus {
-			k, err := hex.DecodeString(key)
-			if err != nil {
-				return false, fmt.Errorf("CheckSigs, hex.DecodeString public key error: %v", err)
-			}
-			publicKey, err := keypair.DeserializePublicKey(k)
-			if err != nil {
-				return false, fmt.Errorf("CheckSigs, keypair.DeserializePublicKey error: %v", err)
-			}
-			addr := types.AddressFromPubKey(publicKey)
+	if !node_manager.ProposalEnabled(native) {
+		return checkLegacySigns(native, id, sig, address, sigInfo)
+	}
+	//the quorum is reached already, including the ones before the proposals are enabled
+	if sigInfo.Status {
+		return false, nil
+	}
 
-			if addr == address {
-				consensus = true
-				break
-			}
+	proposalConfig, err := node_manager.GetProposalConfig(native)
+	if err != nil {
+		return false, fmt.Errorf("CheckSigs, %v", err)
+	}
+	votes := make(map[common.Address][]byte)
+	for k, v := range sigInfo.SigInfo {
+		addr, err := common.AddressFromBase58(k)
+		if err != nil {
+			return false, fmt.Errorf("CheckSigs, decode signer %s error: %v", k, err)
 		}
+		votes[addr] = v
 	}
-	if !consensus {
-		return false, fmt.Errorf("CheckSigs, signer is not consensus peer")
+	if err := node_manager.MigrateVotes(native, ADD_SIGNATURE, id, votes, proposalConfig); err != nil {
+		return false, fmt.Errorf("CheckSigs, %v", err)
+	}
+	proposal, ok, err := node_manager.VoteProposal(native, ADD_SIGNATURE, id, sig, address, proposalConfig, true)
+	if err != nil {
+		return false, fmt.Errorf("CheckSigs, %v", err)
 	}
 
-	//check signs num
-	num := 0
-	sum := 0
-	flag := false
-	for key, v := range peerPoolMap.PeerPoolMap {
-		if v.Status == node_manager.ConsensusStatus {
-			k, err := hex.DecodeString(key)
-			if err != nil {
-				return false, fmt.Errorf("CheckSigs, hex.DecodeString public key error: %v", err)
-			}
-			publicKey, err := keypair.DeserializePublicKey(k)
-			if err != nil {
-				return false, fmt.Errorf("CheckSigs, keypair.DeserializePublicKey error: %v", err)
-			}
-			addr := types.AddressFromPubKey(publicKey)
-			_, ok := sigInfo.SigInfo[addr.ToBase58()]
-			if ok {
-				num = num + 1
-			}
-			sum = sum + 1
+	sigInfo = &SigInfo{
+		Status:  ok,
+		SigInfo: make(map[string][]byte),
+	}
+	for addr, v := range proposal.Votes {
+		sigInfo.SigInfo[addr.ToBase58()] = v
+	}
+	putSigInfo(native, id, sigInfo)
+	return ok, nil
+}
 
-			//check if voted
-			_, ok = sigInfo.SigInfo[address.ToBase58()]
-			if !ok {
-				flag = true
-			}
-		}
+func checkLegacySigns(native *native.NativeService, id, sig []byte, address common.Address, sigInfo *SigInfo) (bool, error) {
+	peers, err := node_manager.GetConsensusAddresses(native)
+	if err != nil {
+		return false, fmt.Errorf("CheckSigs, %v", err)
 	}
-	if flag {
+	_, voted := sigInfo.SigInfo[address.ToBase58()]
+	if !voted {
 		sigInfo.SigInfo[address.ToBase58()] = sig
-		num = num + 1
-		if num < (2*sum+2)/3 {
-			putSigInfo(native, id, sigInfo)
+	}
+	//check signs num
+	num := 0
+	for addr := range peers {
+		if _, ok := sigInfo.SigInfo[addr.ToBase58()]; ok {
+			num = num + 1
 		}
 	}
-	if num >= (2*sum+2)/3 {
+	if num >= (2*len(peers)+2)/3 {
 		shouldEmit := !sigInfo.Status
 		sigInfo.Status = true
 		putSigInfo(native, id, sigInfo)
 		return shouldEmit, nil
-	} else {
-		return false, nil
 	}
+	if !voted {
+		putSigInfo(native, id, sigInfo)
+	}
+	return false, nil
 }
 
 func getSigInfo(native *native.NativeService, id []byte) (*SigInfo, error) {
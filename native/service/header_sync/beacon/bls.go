@@ -0,0 +1,295 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+// BLS signatures of the beacon chain: minimal-pubkey-size with proof of possession,
+// public keys are compressed G1 points and signatures are compressed G2 points.
+
+var SIGNATURE_DST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+var (
+	fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	fieldHalf       = new(big.Int).Rsh(new(big.Int).Sub(fieldModulus, big.NewInt(1)), 1)
+	fieldSqrtExp    = new(big.Int).Rsh(new(big.Int).Add(fieldModulus, big.NewInt(1)), 2)
+	fp2SqrtExp      = new(big.Int).Rsh(new(big.Int).Sub(fieldModulus, big.NewInt(3)), 2)
+)
+
+const (
+	fpByteSize      = 48
+	compressedFlag  = 0x80
+	infinityFlag    = 0x40
+	signFlag        = 0x20
+	flagMask        = 0x1f
+	hashToFieldSize = 64
+)
+
+// Element c0 + c1 * u of Fp2 = Fp[u] / (u^2 + 1)
+type fp2 struct {
+	c0, c1 *big.Int
+}
+
+func newFp2(c0, c1 *big.Int) *fp2 {
+	return &fp2{new(big.Int).Mod(c0, fieldModulus), new(big.Int).Mod(c1, fieldModulus)}
+}
+
+func (a *fp2) add(b *fp2) *fp2 {
+	return newFp2(new(big.Int).Add(a.c0, b.c0), new(big.Int).Add(a.c1, b.c1))
+}
+
+func (a *fp2) mul(b *fp2) *fp2 {
+	c0 := new(big.Int).Sub(new(big.Int).Mul(a.c0, b.c0), new(big.Int).Mul(a.c1, b.c1))
+	c1 := new(big.Int).Add(new(big.Int).Mul(a.c0, b.c1), new(big.Int).Mul(a.c1, b.c0))
+	return newFp2(c0, c1)
+}
+
+func (a *fp2) exp(e *big.Int) *fp2 {
+	r := newFp2(big.NewInt(1), big.NewInt(0))
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = r.mul(r)
+		if e.Bit(i) == 1 {
+			r = r.mul(a)
+		}
+	}
+	return r
+}
+
+func (a *fp2) equal(b *fp2) bool {
+	return a.c0.Cmp(b.c0) == 0 && a.c1.Cmp(b.c1) == 0
+}
+
+// Square root in Fp2 for p = 3 mod 4, returns nil for non-residues
+func (a *fp2) sqrt() *fp2 {
+	a1 := a.exp(fp2SqrtExp)
+	alpha := a1.mul(a1).mul(a)
+	x0 := a1.mul(a)
+	var x *fp2
+	if alpha.equal(newFp2(big.NewInt(-1), big.NewInt(0))) {
+		x = x0.mul(newFp2(big.NewInt(0), big.NewInt(1)))
+	} else {
+		b := alpha.add(newFp2(big.NewInt(1), big.NewInt(0))).exp(fieldHalf)
+		x = b.mul(x0)
+	}
+	if !x.mul(x).equal(a) {
+		return nil
+	}
+	return x
+}
+
+// Lexicographically largest element as defined by the zcash serialization format
+func (a *fp2) isLargest() bool {
+	if a.c1.Sign() != 0 {
+		return a.c1.Cmp(fieldHalf) > 0
+	}
+	return a.c0.Cmp(fieldHalf) > 0
+}
+
+func (a *fp2) neg() *fp2 {
+	return newFp2(new(big.Int).Neg(a.c0), new(big.Int).Neg(a.c1))
+}
+
+// Bytes in the c1 || c0 order used by bls12381
+func (a *fp2) bytes() []byte {
+	out := make([]byte, 2*fpByteSize)
+	fillBytes(a.c1, out[:fpByteSize])
+	fillBytes(a.c0, out[fpByteSize:])
+	return out
+}
+
+// Big endian bytes of v left padded to the length of buf
+func fillBytes(v *big.Int, buf []byte) {
+	b := v.Bytes()
+	copy(buf[len(buf)-len(b):], b)
+}
+
+func decodeCompressed(data []byte, size int) (flags byte, x []*big.Int, err error) {
+	if len(data) != size {
+		return 0, nil, fmt.Errorf("invalid compressed point length %d", len(data))
+	}
+	flags = data[0] &^ flagMask
+	if flags&compressedFlag == 0 {
+		return 0, nil, fmt.Errorf("point is not compressed")
+	}
+	buf := make([]byte, size)
+	copy(buf, data)
+	buf[0] &= flagMask
+	if flags&infinityFlag != 0 {
+		if flags&signFlag != 0 || new(big.Int).SetBytes(buf).Sign() != 0 {
+			return 0, nil, fmt.Errorf("invalid infinity point encoding")
+		}
+		return flags, nil, nil
+	}
+	for i := 0; i < size; i += fpByteSize {
+		v := new(big.Int).SetBytes(buf[i : i+fpByteSize])
+		if v.Cmp(fieldModulus) >= 0 {
+			return 0, nil, fmt.Errorf("invalid field element")
+		}
+		x = append(x, v)
+	}
+	return flags, x, nil
+}
+
+// Decompress a G1 point and check it's in the correct subgroup, infinity point is rejected as a public key
+func decompressG1(g *bls12381.G1, data []byte) (*bls12381.PointG1, error) {
+	flags, x, err := decodeCompressed(data, fpByteSize)
+	if err != nil {
+		return nil, err
+	}
+	if x == nil {
+		return nil, fmt.Errorf("infinity public key")
+	}
+	// y^2 = x^3 + 4
+	y2 := new(big.Int).Exp(x[0], big.NewInt(3), fieldModulus)
+	y2.Add(y2, big.NewInt(4)).Mod(y2, fieldModulus)
+	y := new(big.Int).Exp(y2, fieldSqrtExp, fieldModulus)
+	if new(big.Int).Exp(y, big.NewInt(2), fieldModulus).Cmp(y2) != 0 {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	if (y.Cmp(fieldHalf) > 0) != (flags&signFlag != 0) {
+		y.Sub(fieldModulus, y)
+	}
+	out := make([]byte, 2*fpByteSize)
+	fillBytes(x[0], out[:fpByteSize])
+	fillBytes(y, out[fpByteSize:])
+	p, err := g.FromBytes(out)
+	if err != nil {
+		return nil, err
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, fmt.Errorf("point is not in the correct subgroup")
+	}
+	return p, nil
+}
+
+// Decompress a G2 point and check it's in the correct subgroup
+func decompressG2(g *bls12381.G2, data []byte) (*bls12381.PointG2, error) {
+	flags, x, err := decodeCompressed(data, 2*fpByteSize)
+	if err != nil {
+		return nil, err
+	}
+	if x == nil {
+		return g.Zero(), nil
+	}
+	// y^2 = x^3 + 4(u + 1)
+	xe := newFp2(x[1], x[0])
+	y2 := xe.mul(xe).mul(xe).add(newFp2(big.NewInt(4), big.NewInt(4)))
+	y := y2.sqrt()
+	if y == nil {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	if y.isLargest() != (flags&signFlag != 0) {
+		y = y.neg()
+	}
+	p, err := g.FromBytes(append(xe.bytes(), y.bytes()...))
+	if err != nil {
+		return nil, err
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, fmt.Errorf("point is not in the correct subgroup")
+	}
+	return p, nil
+}
+
+// expand_message_xmd with SHA-256 as in RFC 9380
+func expandMessageXMD(msg, dst []byte, length int) ([]byte, error) {
+	ell := (length + sha256.Size - 1) / sha256.Size
+	if ell > 255 || len(dst) > 255 {
+		return nil, fmt.Errorf("invalid expand message length")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	hasher := sha256.New()
+	hasher.Write(make([]byte, sha256.BlockSize))
+	hasher.Write(msg)
+	hasher.Write([]byte{byte(length >> 8), byte(length), 0})
+	hasher.Write(dstPrime)
+	b0 := hasher.Sum(nil)
+
+	out := make([]byte, 0, ell*sha256.Size)
+	bi := make([]byte, sha256.Size)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		hasher.Reset()
+		hasher.Write(bi)
+		hasher.Write([]byte{byte(i)})
+		hasher.Write(dstPrime)
+		bi = hasher.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// hash_to_curve for BLS12381G2_XMD:SHA-256_SSWU_RO_
+func hashToG2(g *bls12381.G2, msg, dst []byte) (*bls12381.PointG2, error) {
+	uniform, err := expandMessageXMD(msg, dst, 4*hashToFieldSize)
+	if err != nil {
+		return nil, err
+	}
+	p := g.Zero()
+	for i := 0; i < 2; i++ {
+		offset := 2 * i * hashToFieldSize
+		c0 := new(big.Int).SetBytes(uniform[offset : offset+hashToFieldSize])
+		c1 := new(big.Int).SetBytes(uniform[offset+hashToFieldSize : offset+2*hashToFieldSize])
+		q, err := g.MapToCurve(newFp2(c0, c1).bytes())
+		if err != nil {
+			return nil, err
+		}
+		g.Add(p, p, q)
+	}
+	return g.Affine(p), nil
+}
+
+// Verify the aggregated signature of the public keys over the same message
+func FastAggregateVerify(pubkeys []BLSPubkey, msg []byte, sig BLSSignature) error {
+	if len(pubkeys) == 0 {
+		return fmt.Errorf("no public keys to verify")
+	}
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+	aggPubkey := g1.Zero()
+	for i, pubkey := range pubkeys {
+		p, err := decompressG1(g1, pubkey[:])
+		if err != nil {
+			return fmt.Errorf("invalid public key %d, err: %v", i, err)
+		}
+		g1.Add(aggPubkey, aggPubkey, p)
+	}
+	signature, err := decompressG2(g2, sig[:])
+	if err != nil {
+		return fmt.Errorf("invalid signature, err: %v", err)
+	}
+	hash, err := hashToG2(g2, msg, SIGNATURE_DST)
+	if err != nil {
+		return fmt.Errorf("failed to hash message to curve, err: %v", err)
+	}
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(g1.Affine(aggPubkey), hash)
+	engine.AddPairInv(g1.One(), signature)
+	if !engine.Check() {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// Ethereum beacon chain light client Header Sync Handler,
+// it follows the finalized execution payloads with the sync committee signatures
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return new(Handler)
+}
+
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.ETH_BEACON_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHandler()
+	})
+	utils.RegisterRouterStartBlock(utils.ETH_BEACON_ROUTER, config.NETWORK_ID_MAIN_NET, constants.ETH_BEACON_ROUTER_HEIGHT_MAINNET)
+	utils.RegisterRouterStartBlock(utils.ETH_BEACON_ROUTER, config.NETWORK_ID_TEST_NET, constants.ETH_BEACON_ROUTER_HEIGHT_TESTNET)
+}
+
+// Sync trusted checkpoint as genesis header
+func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
+	params := new(scom.SyncGenesisHeaderParam)
+	err = params.Deserialization(common.NewZeroCopySource(native.GetInput()))
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to deserialize genesis header params, err: %v", err)
+	}
+
+	// Get current consensus address
+	operator, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to parse current consensus operator, err: %v", err)
+	}
+
+	// Check consensus witness
+	err = utils.ValidateOwner(native, operator)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to check operator witness, err: %v", err)
+	}
+
+	// Check genesis header existence
+	headerExist, err := getGenesisHeader(native, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler get genesis header from storage failed, err: %v", err)
+	}
+	if headerExist != nil {
+		return fmt.Errorf("BeaconHandler genesis header was already set")
+	}
+
+	ctx, err := getContext(native, params.ChainID)
+	if err != nil {
+		return err
+	}
+
+	bootstrap := new(LightClientBootstrap)
+	err = json.Unmarshal(params.GenesisHeader, bootstrap)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to unmarshal light client bootstrap, err: %v", err)
+	}
+	err = ctx.VerifyBootstrap(bootstrap)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to verify light client bootstrap, err: %v", err)
+	}
+
+	err = storeGenesisHeader(native, params.ChainID, &bootstrap.Header)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to store genesis header, err: %v", err)
+	}
+	period := computeSyncCommitteePeriodAtSlot(uint64(bootstrap.Header.Beacon.Slot))
+	storeSyncCommittee(native, params.ChainID, period, bootstrap.CurrentSyncCommittee)
+	err = storeFinalizedHeader(native, params.ChainID, &bootstrap.Header)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to store finalized header, err: %v", err)
+	}
+	return
+}
+
+// Sync light client updates
+func (h *Handler) SyncBlockHeader(native *native.NativeService) (err error) {
+	params := new(scom.SyncBlockHeaderParam)
+	err = params.Deserialization(common.NewZeroCopySource(native.GetInput()))
+	if err != nil {
+		return fmt.Errorf("BeaconHandler failed to deserialize headers params, err: %v", err)
+	}
+
+	ctx, err := getContext(native, params.ChainID)
+	if err != nil {
+		return err
+	}
+
+	for idx, data := range params.Headers {
+		update := new(LightClientUpdate)
+		err = json.Unmarshal(data, update)
+		if err != nil {
+			return fmt.Errorf("BeaconHandler failed to unmarshal light client update, idx %d, err: %v", idx, err)
+		}
+		err = processUpdate(native, ctx, params.ChainID, update)
+		if err != nil {
+			return fmt.Errorf("BeaconHandler failed to process light client update, idx %d, err: %v", idx, err)
+		}
+	}
+	return
+}
+
+// SyncCrossChainMsg ...
+func (h *Handler) SyncCrossChainMsg(native *native.NativeService) error {
+	return nil
+}
+
+func getContext(native *native.NativeService, chainID uint64) (*Context, error) {
+	side, err := side_chain_manager.GetSideChain(native, chainID)
+	if err != nil || side == nil {
+		return nil, fmt.Errorf("BeaconHandler failed to get side chain, err: %v", err)
+	}
+	if side.Router != utils.ETH_BEACON_ROUTER {
+		return nil, fmt.Errorf("BeaconHandler side chain %d router %d is not beacon router", chainID, side.Router)
+	}
+	ctx, err := DecodeContext(side.ExtraInfo)
+	if err != nil {
+		return nil, fmt.Errorf("BeaconHandler failed to decode context, err: %v", err)
+	}
+	return ctx, nil
+}
+
+// Verify the update against the finalized header and sync committees in store,
+// then advance the finalized header and learn the next sync committee
+func processUpdate(native *native.NativeService, ctx *Context, chainID uint64, update *LightClientUpdate) error {
+	current, err := GetCurrentHeader(native, chainID)
+	if err != nil {
+		return err
+	}
+	storePeriod := computeSyncCommitteePeriodAtSlot(uint64(current.Beacon.Slot))
+	nextCommittee, err := getSyncCommittee(native, chainID, storePeriod+1)
+	if err != nil {
+		return err
+	}
+
+	attestedSlot := uint64(update.AttestedHeader.Beacon.Slot)
+	finalizedSlot := uint64(update.FinalizedHeader.Beacon.Slot)
+	signatureSlot := uint64(update.SignatureSlot)
+	if !(signatureSlot > attestedSlot && attestedSlot >= finalizedSlot) {
+		return fmt.Errorf("invalid slots, signature %d, attested %d, finalized %d", signatureSlot, attestedSlot, finalizedSlot)
+	}
+	signaturePeriod := computeSyncCommitteePeriodAtSlot(signatureSlot)
+	if signaturePeriod != storePeriod && (signaturePeriod != storePeriod+1 || nextCommittee == nil) {
+		return fmt.Errorf("signature period %d is not supported by store period %d", signaturePeriod, storePeriod)
+	}
+
+	// Skip updates already known
+	attestedPeriod := computeSyncCommitteePeriodAtSlot(attestedSlot)
+	learnCommittee := update.IsSyncCommitteeUpdate() && attestedPeriod == storePeriod && nextCommittee == nil
+	if finalizedSlot <= uint64(current.Beacon.Slot) && !learnCommittee {
+		log.Warnf("BeaconHandler, update of finalized slot %d is not newer than %d", finalizedSlot, current.Beacon.Slot)
+		return nil
+	}
+
+	err = ctx.VerifyUpdate(update)
+	if err != nil {
+		return err
+	}
+	if update.IsSyncCommitteeUpdate() && attestedPeriod == storePeriod && nextCommittee != nil {
+		if update.NextSyncCommittee.HashTreeRoot() != nextCommittee.HashTreeRoot() {
+			return fmt.Errorf("next sync committee of period %d does not match the one in store", attestedPeriod+1)
+		}
+	}
+
+	committee, err := getSyncCommittee(native, chainID, signaturePeriod)
+	if err != nil {
+		return err
+	}
+	if committee == nil {
+		return fmt.Errorf("sync committee of period %d not found", signaturePeriod)
+	}
+	err = ctx.VerifySyncAggregate(committee, update)
+	if err != nil {
+		return err
+	}
+
+	if learnCommittee {
+		storeSyncCommittee(native, chainID, storePeriod+1, update.NextSyncCommittee)
+	}
+	if finalizedSlot > uint64(current.Beacon.Slot) {
+		if update.FinalizedHeader.Execution.BlockNumber <= current.Execution.BlockNumber {
+			return fmt.Errorf("finalized execution block %d is not newer than %d",
+				update.FinalizedHeader.Execution.BlockNumber, current.Execution.BlockNumber)
+		}
+		return storeFinalizedHeader(native, chainID, &update.FinalizedHeader)
+	}
+	return nil
+}
+
+// Verify the current sync committee of the bootstrap
+func (this *Context) VerifyBootstrap(bootstrap *LightClientBootstrap) error {
+	err := this.VerifyLightClientHeader(&bootstrap.Header)
+	if err != nil {
+		return err
+	}
+	committee := bootstrap.CurrentSyncCommittee
+	if committee == nil || len(committee.Pubkeys) != SYNC_COMMITTEE_SIZE {
+		return fmt.Errorf("invalid current sync committee")
+	}
+	fork := this.ForkAtSlot(uint64(bootstrap.Header.Beacon.Slot))
+	if !isValidMerkleBranch(committee.HashTreeRoot(), bootstrap.CurrentSyncCommitteeBranch, fork.SyncCommitteeBranchDepth(),
+		CURRENT_SYNC_COMMITTEE_INDEX, bootstrap.Header.Beacon.StateRoot) {
+		return fmt.Errorf("invalid current sync committee branch")
+	}
+	return nil
+}
+
+// Verify the finality and next sync committee proofs of the update against the attested header
+func (this *Context) VerifyUpdate(update *LightClientUpdate) error {
+	err := this.VerifyLightClientHeader(&update.AttestedHeader)
+	if err != nil {
+		return fmt.Errorf("invalid attested header, %v", err)
+	}
+	err = this.VerifyLightClientHeader(&update.FinalizedHeader)
+	if err != nil {
+		return fmt.Errorf("invalid finalized header, %v", err)
+	}
+	fork := this.ForkAtSlot(uint64(update.AttestedHeader.Beacon.Slot))
+	if !isValidMerkleBranch(update.FinalizedHeader.Beacon.HashTreeRoot(), update.FinalityBranch, fork.FinalityBranchDepth(),
+		FINALIZED_ROOT_INDEX, update.AttestedHeader.Beacon.StateRoot) {
+		return fmt.Errorf("invalid finality branch")
+	}
+	if update.IsSyncCommitteeUpdate() {
+		committee := update.NextSyncCommittee
+		if len(committee.Pubkeys) != SYNC_COMMITTEE_SIZE {
+			return fmt.Errorf("invalid next sync committee size %d", len(committee.Pubkeys))
+		}
+		if !isValidMerkleBranch(committee.HashTreeRoot(), update.NextSyncCommitteeBranch, fork.SyncCommitteeBranchDepth(),
+			NEXT_SYNC_COMMITTEE_INDEX, update.AttestedHeader.Beacon.StateRoot) {
+			return fmt.Errorf("invalid next sync committee branch")
+		}
+	}
+	return nil
+}
+
+// Verify the sync committee signature over the attested header with supermajority participants
+func (this *Context) VerifySyncAggregate(committee *SyncCommittee, update *LightClientUpdate) error {
+	bits := &update.SyncAggregate.SyncCommitteeBits
+	participants := bits.Count()
+	if participants*3 < SYNC_COMMITTEE_SIZE*2 {
+		return fmt.Errorf("insufficient sync committee participants %d", participants)
+	}
+	pubkeys := make([]BLSPubkey, 0, participants)
+	for i, pubkey := range committee.Pubkeys {
+		if bits.Get(i) {
+			pubkeys = append(pubkeys, pubkey)
+		}
+	}
+	signatureSlot := uint64(update.SignatureSlot)
+	fork := this.ForkAtEpoch(computeEpochAtSlot(signatureSlot - 1))
+	domain := computeDomain(DOMAIN_SYNC_COMMITTEE, fork.Version, this.GenesisValidatorsRoot)
+	signingRoot := computeSigningRoot(update.AttestedHeader.Beacon.HashTreeRoot(), domain)
+	err := FastAggregateVerify(pubkeys, signingRoot[:], update.SyncAggregate.SyncCommitteeSignature)
+	if err != nil {
+		return fmt.Errorf("invalid sync committee signature, %v", err)
+	}
+	return nil
+}
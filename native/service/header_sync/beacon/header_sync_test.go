@@ -0,0 +1,395 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	acct          = account.NewAccount("")
+	beaconChainID = uint64(2)
+	contextJson   = `{"genesis_validators_root":"0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95",` +
+		`"forks":[{"name":"capella","epoch":"0","version":"0x03000000"},{"name":"deneb","epoch":"10","version":"0x04000000"}]}`
+)
+
+func init() {
+	genesis.GenesisBookkeepers = []keypair.PublicKey{acct.PublicKey}
+}
+
+func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) (*native.NativeService, error) {
+	if db == nil {
+		store, _ := leveldbstore.NewMemLevelDBStore()
+		db = storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+		sink := common.NewZeroCopySink(nil)
+		view := &node_manager.GovernanceView{
+			TxHash: common.UINT256_EMPTY,
+			Height: 0,
+			View:   0,
+		}
+		view.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)), states.GenRawStorageItem(sink.Bytes()))
+
+		peerPoolMap := &node_manager.PeerPoolMap{
+			PeerPoolMap: map[string]*node_manager.PeerPoolItem{
+				vconfig.PubkeyID(acct.PublicKey): {
+					Address:    acct.Address,
+					Status:     node_manager.ConsensusStatus,
+					PeerPubkey: vconfig.PubkeyID(acct.PublicKey),
+					Index:      0,
+				},
+			},
+		}
+		sink.Reset()
+		peerPoolMap.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.NodeManagerContractAddress,
+			[]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
+
+		service, err := native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+		if err != nil {
+			return nil, err
+		}
+		err = side_chain_manager.PutSideChain(service, &side_chain_manager.SideChain{
+			ChainId:   beaconChainID,
+			Router:    utils.ETH_BEACON_ROUTER,
+			ExtraInfo: []byte(contextJson),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+}
+
+// Test signer of a sync committee with secret keys 1..SYNC_COMMITTEE_SIZE
+type testCommittee struct {
+	keys      []*big.Int
+	committee *SyncCommittee
+}
+
+func newTestCommittee(offset int64) *testCommittee {
+	g1 := bls12381.NewG1()
+	c := &testCommittee{committee: new(SyncCommittee)}
+	agg := g1.Zero()
+	for i := int64(0); i < SYNC_COMMITTEE_SIZE; i++ {
+		key := big.NewInt(offset + i + 1)
+		p := g1.New()
+		g1.MulScalar(p, g1.One(), key)
+		g1.Add(agg, agg, p)
+		c.keys = append(c.keys, key)
+		c.committee.Pubkeys = append(c.committee.Pubkeys, compressG1(g1, p))
+	}
+	c.committee.AggregatePubkey = compressG1(g1, agg)
+	return c
+}
+
+func (c *testCommittee) sign(t *testing.T, msg []byte, bits *SyncCommitteeBits) BLSSignature {
+	g2 := bls12381.NewG2()
+	hash, err := hashToG2(g2, msg, SIGNATURE_DST)
+	assert.NoError(t, err)
+	key := new(big.Int)
+	for i, k := range c.keys {
+		if bits.Get(i) {
+			key.Add(key, k)
+		}
+	}
+	sig := g2.New()
+	g2.MulScalar(sig, hash, key)
+	return compressG2(g2, sig)
+}
+
+func compressG1(g *bls12381.G1, p *bls12381.PointG1) (pubkey BLSPubkey) {
+	raw := g.ToBytes(p)
+	copy(pubkey[:], raw[:fpByteSize])
+	pubkey[0] |= compressedFlag
+	if new(big.Int).SetBytes(raw[fpByteSize:]).Cmp(fieldHalf) > 0 {
+		pubkey[0] |= signFlag
+	}
+	return
+}
+
+func compressG2(g *bls12381.G2, p *bls12381.PointG2) (sig BLSSignature) {
+	raw := g.ToBytes(p)
+	copy(sig[:], raw[:2*fpByteSize])
+	sig[0] |= compressedFlag
+	y := newFp2(new(big.Int).SetBytes(raw[3*fpByteSize:]), new(big.Int).SetBytes(raw[2*fpByteSize:3*fpByteSize]))
+	if y.isLargest() {
+		sig[0] |= signFlag
+	}
+	return
+}
+
+// Merkle root and branch of the leaf at index
+func merkleProof(leaves []ecom.Hash, index int) (root ecom.Hash, branch []ecom.Hash) {
+	layer := leaves
+	for len(layer) > 1 {
+		branch = append(branch, layer[index^1])
+		next := make([]ecom.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+		index /= 2
+	}
+	return layer[0], branch
+}
+
+func newLightClientHeader(slot, number uint64, deneb bool) LightClientHeader {
+	payload := &ExecutionPayloadHeader{
+		BlockNumber: Uint64(number),
+		ExtraData:   []byte("beacon"),
+		GasLimit:    30000000,
+	}
+	payload.StateRoot[0] = byte(number)
+	payload.BlockHash[0] = byte(number)
+	payload.BaseFeePerGas, _ = NewUint256(big.NewInt(7))
+	if deneb {
+		payload.BlobGasUsed = new(Uint64)
+		payload.ExcessBlobGas = new(Uint64)
+	}
+	body := make([]ecom.Hash, 16)
+	body[EXECUTION_PAYLOAD_INDEX] = payload.HashTreeRoot()
+	bodyRoot, branch := merkleProof(body, EXECUTION_PAYLOAD_INDEX)
+	return LightClientHeader{
+		Beacon:          BeaconBlockHeader{Slot: Uint64(slot), ProposerIndex: 1, BodyRoot: bodyRoot},
+		Execution:       payload,
+		ExecutionBranch: branch,
+	}
+}
+
+func newBootstrap(slot uint64, committee *SyncCommittee) *LightClientBootstrap {
+	bootstrap := &LightClientBootstrap{
+		Header:               newLightClientHeader(slot, 100, false),
+		CurrentSyncCommittee: committee,
+	}
+	state := make([]ecom.Hash, 32)
+	state[CURRENT_SYNC_COMMITTEE_INDEX] = committee.HashTreeRoot()
+	bootstrap.Header.Beacon.StateRoot, bootstrap.CurrentSyncCommitteeBranch = merkleProof(state, CURRENT_SYNC_COMMITTEE_INDEX)
+	return bootstrap
+}
+
+func newUpdate(t *testing.T, ctx *Context, signer *testCommittee, attested, finalized LightClientHeader, next *SyncCommittee, participants int) *LightClientUpdate {
+	update := &LightClientUpdate{
+		FinalizedHeader: finalized,
+		SignatureSlot:   attested.Beacon.Slot + 1,
+	}
+	state := make([]ecom.Hash, 32)
+	epochChunk := uint64Chunk(computeEpochAtSlot(uint64(finalized.Beacon.Slot)))
+	state[FINALIZED_ROOT_INDEX/2] = hashPair(epochChunk, finalized.Beacon.HashTreeRoot())
+	if next != nil {
+		state[NEXT_SYNC_COMMITTEE_INDEX] = next.HashTreeRoot()
+	}
+	var branch []ecom.Hash
+	attested.Beacon.StateRoot, branch = merkleProof(state, FINALIZED_ROOT_INDEX/2)
+	update.FinalityBranch = append([]ecom.Hash{epochChunk}, branch...)
+	if next != nil {
+		update.NextSyncCommittee = next
+		_, update.NextSyncCommitteeBranch = merkleProof(state, NEXT_SYNC_COMMITTEE_INDEX)
+	}
+	update.AttestedHeader = attested
+
+	for i := 0; i < participants; i++ {
+		update.SyncAggregate.SyncCommitteeBits.Set(i)
+	}
+	fork := ctx.ForkAtSlot(uint64(update.SignatureSlot) - 1)
+	domain := computeDomain(DOMAIN_SYNC_COMMITTEE, fork.Version, ctx.GenesisValidatorsRoot)
+	signingRoot := computeSigningRoot(attested.Beacon.HashTreeRoot(), domain)
+	update.SyncAggregate.SyncCommitteeSignature = signer.sign(t, signingRoot[:], &update.SyncAggregate.SyncCommitteeBits)
+	return update
+}
+
+func syncGenesis(t *testing.T, bootstrap *LightClientBootstrap, db *storage.CacheDB) error {
+	data, err := json.Marshal(bootstrap)
+	assert.NoError(t, err)
+	param := &scom.SyncGenesisHeaderParam{ChainID: beaconChainID, GenesisHeader: data}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	service, err := NewNative(sink.Bytes(), &types.Transaction{SignedAddr: []common.Address{acct.Address}}, db)
+	assert.NoError(t, err)
+	return NewHandler().SyncGenesisHeader(service)
+}
+
+func syncUpdates(t *testing.T, db *storage.CacheDB, updates ...*LightClientUpdate) (*native.NativeService, error) {
+	param := &scom.SyncBlockHeaderParam{ChainID: beaconChainID}
+	for _, update := range updates {
+		data, err := json.Marshal(update)
+		assert.NoError(t, err)
+		param.Headers = append(param.Headers, data)
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	service, err := NewNative(sink.Bytes(), &types.Transaction{}, db)
+	assert.NoError(t, err)
+	return service, NewHandler().SyncBlockHeader(service)
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	out, err := expandMessageXMD([]byte{}, []byte("QUUX-V01-CS02-with-expander-SHA256-128"), 0x20)
+	assert.NoError(t, err)
+	assert.Equal(t, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235", hex.EncodeToString(out))
+}
+
+func TestHashToG2(t *testing.T) {
+	g2 := bls12381.NewG2()
+	p, err := hashToG2(g2, []byte{}, []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"))
+	assert.NoError(t, err)
+	raw := g2.ToBytes(p)
+	assert.Equal(t, "0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a",
+		hex.EncodeToString(raw[fpByteSize:2*fpByteSize]))
+	assert.Equal(t, "05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d",
+		hex.EncodeToString(raw[:fpByteSize]))
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+	msg := []byte("poly network")
+	hash, err := hashToG2(g2, msg, SIGNATURE_DST)
+	assert.NoError(t, err)
+	var pubkeys []BLSPubkey
+	sig := g2.Zero()
+	for i := int64(1); i <= 8; i++ {
+		key := big.NewInt(i * 7919)
+		p := g1.New()
+		g1.MulScalar(p, g1.One(), key)
+		pubkeys = append(pubkeys, compressG1(g1, p))
+		s := g2.New()
+		g2.MulScalar(s, hash, key)
+		g2.Add(sig, sig, s)
+	}
+	signature := compressG2(g2, sig)
+	assert.NoError(t, FastAggregateVerify(pubkeys, msg, signature))
+	assert.Error(t, FastAggregateVerify(pubkeys[1:], msg, signature))
+	assert.Error(t, FastAggregateVerify(pubkeys, []byte("poly"), signature))
+	signature[10] ^= 1
+	assert.Error(t, FastAggregateVerify(pubkeys, msg, signature))
+}
+
+func TestDecompressG1(t *testing.T) {
+	g1 := bls12381.NewG1()
+	pubkey := compressG1(g1, g1.One())
+	p, err := decompressG1(g1, pubkey[:])
+	assert.NoError(t, err)
+	assert.True(t, g1.Equal(p, g1.One()))
+
+	//a point on curve but out of the subgroup is rejected
+	for x := int64(1); ; x++ {
+		y2 := new(big.Int).Exp(big.NewInt(x), big.NewInt(3), fieldModulus)
+		y2.Add(y2, big.NewInt(4)).Mod(y2, fieldModulus)
+		y := new(big.Int).Exp(y2, fieldSqrtExp, fieldModulus)
+		if new(big.Int).Exp(y, big.NewInt(2), fieldModulus).Cmp(y2) != 0 {
+			continue
+		}
+		data := make([]byte, fpByteSize)
+		fillBytes(big.NewInt(x), data)
+		data[0] |= compressedFlag
+		_, err = decompressG1(g1, data)
+		assert.EqualError(t, err, "point is not in the correct subgroup")
+		break
+	}
+}
+
+func TestSyncCommitteeSerialization(t *testing.T) {
+	committee := newTestCommittee(0).committee
+	sink := common.NewZeroCopySink(nil)
+	committee.Serialization(sink)
+	decoded := new(SyncCommittee)
+	assert.NoError(t, decoded.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, committee, decoded)
+	assert.Error(t, decoded.Deserialization(common.NewZeroCopySource(sink.Bytes()[:100])))
+}
+
+func TestHandler_SyncBlockHeader(t *testing.T) {
+	ctx, err := DecodeContext([]byte(contextJson))
+	assert.NoError(t, err)
+	current := newTestCommittee(0)
+	next := newTestCommittee(1000)
+
+	// bootstrap with a mismatched sync committee branch
+	bootstrap := newBootstrap(64, current.committee)
+	bootstrap.CurrentSyncCommitteeBranch[0][0] ^= 1
+	assert.Error(t, syncGenesis(t, bootstrap, nil))
+
+	bootstrap = newBootstrap(64, current.committee)
+	service, err := NewNative(nil, &types.Transaction{}, nil)
+	assert.NoError(t, err)
+	db := service.GetCacheDB()
+	assert.NoError(t, syncGenesis(t, bootstrap, db))
+	assert.Error(t, syncGenesis(t, bootstrap, db), "genesis header should be set only once")
+
+	// insufficient participants
+	update := newUpdate(t, ctx, current, newLightClientHeader(200, 160, false), newLightClientHeader(150, 150, false), next.committee, 300)
+	_, err = syncUpdates(t, db, update)
+	assert.Error(t, err)
+
+	// invalid signature
+	update = newUpdate(t, ctx, next, newLightClientHeader(200, 160, false), newLightClientHeader(150, 150, false), next.committee, 400)
+	_, err = syncUpdates(t, db, update)
+	assert.Error(t, err)
+
+	update = newUpdate(t, ctx, current, newLightClientHeader(200, 160, false), newLightClientHeader(150, 150, false), next.committee, 400)
+	service, err = syncUpdates(t, db, update, update)
+	assert.NoError(t, err)
+	height, err := GetCurrentHeaderHeight(service, beaconChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(150), height)
+	header, err := GetHeaderByHeight(service, beaconChainID, 150)
+	assert.NoError(t, err)
+	assert.Equal(t, update.FinalizedHeader.Execution.StateRoot, header.Execution.StateRoot)
+	committee, err := getSyncCommittee(service, beaconChainID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, next.committee, committee)
+
+	// deneb payload requires blob gas fields
+	slot := uint64(SLOTS_PER_EPOCH*EPOCHS_PER_SYNC_COMMITTEE_PERIOD + 10*SLOTS_PER_EPOCH)
+	update = newUpdate(t, ctx, next, newLightClientHeader(slot+10, 300, true), newLightClientHeader(slot, 290, false), nil, 512)
+	_, err = syncUpdates(t, db, update)
+	assert.Error(t, err)
+
+	// signed by the next sync committee learnt
+	update = newUpdate(t, ctx, next, newLightClientHeader(slot+10, 300, true), newLightClientHeader(slot, 290, true), nil, 512)
+	service, err = syncUpdates(t, db, update)
+	assert.NoError(t, err)
+	header, err = GetCurrentHeader(service, beaconChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(290), uint64(header.Execution.BlockNumber))
+	_, err = GetHeaderByHeight(service, beaconChainID, 291)
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+)
+
+// SSZ hash tree root of the light client containers
+
+var DOMAIN_SYNC_COMMITTEE = [4]byte{0x07, 0x00, 0x00, 0x00}
+
+func hashPair(a, b ecom.Hash) (h ecom.Hash) {
+	hasher := sha256.New()
+	hasher.Write(a[:])
+	hasher.Write(b[:])
+	copy(h[:], hasher.Sum(nil))
+	return
+}
+
+// Merkleize chunks padded with zero chunks to the next power of two of limit
+func merkleize(chunks []ecom.Hash, limit int) ecom.Hash {
+	if limit < len(chunks) {
+		limit = len(chunks)
+	}
+	width := 1
+	for width < limit {
+		width <<= 1
+	}
+	layer := make([]ecom.Hash, len(chunks))
+	copy(layer, chunks)
+	zero := ecom.Hash{}
+	for ; width > 1; width >>= 1 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zero)
+		}
+		next := make([]ecom.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+		zero = hashPair(zero, zero)
+	}
+	if len(layer) == 0 {
+		return zero
+	}
+	return layer[0]
+}
+
+func mixInLength(root ecom.Hash, length uint64) ecom.Hash {
+	return hashPair(root, uint64Chunk(length))
+}
+
+func uint64Chunk(v uint64) (chunk ecom.Hash) {
+	binary.LittleEndian.PutUint64(chunk[:8], v)
+	return
+}
+
+// Pack bytes into chunks, the last one right padded with zeros
+func packBytes(data []byte) []ecom.Hash {
+	chunks := make([]ecom.Hash, (len(data)+31)/32)
+	for i := range chunks {
+		copy(chunks[i][:], data[i*32:])
+	}
+	return chunks
+}
+
+// Check merkle branch of leaf at index with depth against root, as is_valid_merkle_branch in consensus specs
+func isValidMerkleBranch(leaf ecom.Hash, branch []ecom.Hash, depth int, index uint64, root ecom.Hash) bool {
+	if len(branch) != depth {
+		return false
+	}
+	value := leaf
+	for i := 0; i < depth; i++ {
+		if (index>>uint(i))&1 == 1 {
+			value = hashPair(branch[i], value)
+		} else {
+			value = hashPair(value, branch[i])
+		}
+	}
+	return value == root
+}
+
+func (this *BeaconBlockHeader) HashTreeRoot() ecom.Hash {
+	return merkleize([]ecom.Hash{
+		uint64Chunk(uint64(this.Slot)),
+		uint64Chunk(uint64(this.ProposerIndex)),
+		this.ParentRoot,
+		this.StateRoot,
+		this.BodyRoot,
+	}, 0)
+}
+
+func (this *ExecutionPayloadHeader) HashTreeRoot() ecom.Hash {
+	var feeRecipient ecom.Hash
+	copy(feeRecipient[:], this.FeeRecipient[:])
+	fields := []ecom.Hash{
+		this.ParentHash,
+		feeRecipient,
+		this.StateRoot,
+		this.ReceiptsRoot,
+		merkleize(packBytes(this.LogsBloom[:]), 0),
+		this.PrevRandao,
+		uint64Chunk(uint64(this.BlockNumber)),
+		uint64Chunk(uint64(this.GasLimit)),
+		uint64Chunk(uint64(this.GasUsed)),
+		uint64Chunk(uint64(this.Timestamp)),
+		mixInLength(merkleize(packBytes(this.ExtraData), (MAX_EXTRA_DATA_BYTES+31)/32), uint64(len(this.ExtraData))),
+		ecom.Hash(this.BaseFeePerGas),
+		this.BlockHash,
+		this.TransactionsRoot,
+		this.WithdrawalsRoot,
+	}
+	if this.BlobGasUsed != nil && this.ExcessBlobGas != nil {
+		fields = append(fields, uint64Chunk(uint64(*this.BlobGasUsed)), uint64Chunk(uint64(*this.ExcessBlobGas)))
+	}
+	return merkleize(fields, 0)
+}
+
+func (this *SyncCommittee) HashTreeRoot() ecom.Hash {
+	pubkeys := make([]ecom.Hash, len(this.Pubkeys))
+	for i, pubkey := range this.Pubkeys {
+		pubkeys[i] = merkleize(packBytes(pubkey[:]), 0)
+	}
+	return hashPair(merkleize(pubkeys, SYNC_COMMITTEE_SIZE), merkleize(packBytes(this.AggregatePubkey[:]), 0))
+}
+
+func computeForkDataRoot(version Version, genesisValidatorsRoot ecom.Hash) ecom.Hash {
+	var chunk ecom.Hash
+	copy(chunk[:], version[:])
+	return hashPair(chunk, genesisValidatorsRoot)
+}
+
+func computeDomain(domainType [4]byte, version Version, genesisValidatorsRoot ecom.Hash) (domain ecom.Hash) {
+	forkDataRoot := computeForkDataRoot(version, genesisValidatorsRoot)
+	copy(domain[:4], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return
+}
+
+func computeSigningRoot(root ecom.Hash, domain ecom.Hash) ecom.Hash {
+	return hashPair(root, domain)
+}
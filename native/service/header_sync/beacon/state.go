@@ -0,0 +1,322 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// Beacon chain forks known by the light client, in activation order
+var FORKS = []string{"phase0", "altair", "bellatrix", "capella", "deneb", "electra", "fulu"}
+
+const (
+	FORK_CAPELLA = 3
+	FORK_DENEB   = 4
+	FORK_ELECTRA = 5
+)
+
+// Generalized indices of the light client proofs, rebased to the index in its depth
+const (
+	EXECUTION_PAYLOAD_INDEX      = 9
+	EXECUTION_PAYLOAD_DEPTH      = 4
+	FINALIZED_ROOT_INDEX         = 41
+	NEXT_SYNC_COMMITTEE_INDEX    = 23
+	CURRENT_SYNC_COMMITTEE_INDEX = 22
+)
+
+type Fork struct {
+	Name    string  `json:"name"`
+	Epoch   Uint64  `json:"epoch"`
+	Version Version `json:"version"`
+	index   int
+}
+
+// Beacon chain config context, decoded from side chain extra info
+type Context struct {
+	GenesisValidatorsRoot ecom.Hash `json:"genesis_validators_root"`
+	Forks                 []*Fork   `json:"forks"`
+}
+
+func DecodeContext(data []byte) (*Context, error) {
+	ctx := new(Context)
+	err := json.Unmarshal(data, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal beacon context, err: %v", err)
+	}
+	if ctx.GenesisValidatorsRoot == (ecom.Hash{}) {
+		return nil, fmt.Errorf("missing genesis validators root")
+	}
+	if len(ctx.Forks) == 0 || ctx.Forks[0].Epoch != 0 {
+		return nil, fmt.Errorf("fork schedule should start at epoch 0")
+	}
+	for i, fork := range ctx.Forks {
+		fork.index = -1
+		for j, name := range FORKS {
+			if fork.Name == name {
+				fork.index = j
+			}
+		}
+		if fork.index < 0 {
+			return nil, fmt.Errorf("unknown fork %s", fork.Name)
+		}
+		if i > 0 && (fork.index <= ctx.Forks[i-1].index || fork.Epoch < ctx.Forks[i-1].Epoch) {
+			return nil, fmt.Errorf("fork %s is out of order", fork.Name)
+		}
+	}
+	return ctx, nil
+}
+
+// Fork active at epoch
+func (this *Context) ForkAtEpoch(epoch uint64) *Fork {
+	fork := this.Forks[0]
+	for _, f := range this.Forks {
+		if uint64(f.Epoch) <= epoch {
+			fork = f
+		}
+	}
+	return fork
+}
+
+func (this *Context) ForkAtSlot(slot uint64) *Fork {
+	return this.ForkAtEpoch(computeEpochAtSlot(slot))
+}
+
+// Depth of the beacon state proofs, beacon state grew over 32 fields since electra
+func (this *Fork) stateProofDepth() int {
+	if this.index >= FORK_ELECTRA {
+		return 6
+	}
+	return 5
+}
+
+func (this *Fork) FinalityBranchDepth() int {
+	// finalized checkpoint root is the second field of the checkpoint
+	return this.stateProofDepth() + 1
+}
+
+func (this *Fork) SyncCommitteeBranchDepth() int {
+	return this.stateProofDepth()
+}
+
+// Verify the light client header's execution payload against the beacon block body root
+func (this *Context) VerifyLightClientHeader(header *LightClientHeader) error {
+	fork := this.ForkAtSlot(uint64(header.Beacon.Slot))
+	if fork.index < FORK_CAPELLA {
+		return fmt.Errorf("fork %s at slot %d has no execution payload header", fork.Name, header.Beacon.Slot)
+	}
+	payload := header.Execution
+	if payload == nil {
+		return fmt.Errorf("missing execution payload header")
+	}
+	if len(payload.ExtraData) > MAX_EXTRA_DATA_BYTES {
+		return fmt.Errorf("invalid execution payload extra data size %d", len(payload.ExtraData))
+	}
+	if (fork.index >= FORK_DENEB) != (payload.BlobGasUsed != nil && payload.ExcessBlobGas != nil) {
+		return fmt.Errorf("blob gas fields do not match fork %s", fork.Name)
+	}
+	if !isValidMerkleBranch(payload.HashTreeRoot(), header.ExecutionBranch, EXECUTION_PAYLOAD_DEPTH,
+		EXECUTION_PAYLOAD_INDEX, header.Beacon.BodyRoot) {
+		return fmt.Errorf("invalid execution branch")
+	}
+	return nil
+}
+
+func (this *SyncCommittee) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Pubkeys)))
+	for _, pubkey := range this.Pubkeys {
+		sink.WriteBytes(pubkey[:])
+	}
+	sink.WriteBytes(this.AggregatePubkey[:])
+}
+
+func (this *SyncCommittee) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SyncCommittee deserialize pubkey count error")
+	}
+	pubkeys := make([]BLSPubkey, 0, n)
+	for i := uint64(0); i < n; i++ {
+		data, eof := source.NextBytes(uint64(len(BLSPubkey{})))
+		if eof {
+			return fmt.Errorf("SyncCommittee deserialize pubkey error")
+		}
+		var pubkey BLSPubkey
+		copy(pubkey[:], data)
+		pubkeys = append(pubkeys, pubkey)
+	}
+	data, eof := source.NextBytes(uint64(len(BLSPubkey{})))
+	if eof {
+		return fmt.Errorf("SyncCommittee deserialize aggregate pubkey error")
+	}
+	this.Pubkeys = pubkeys
+	copy(this.AggregatePubkey[:], data)
+	return nil
+}
+
+// Storage Keys
+func keyForGenesisHeader(chainID uint64) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID))
+}
+
+func keyForHeaderHeight(chainID uint64) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID))
+}
+
+func keyForMainChain(chainID, height uint64) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID),
+		utils.GetUint64Bytes(height))
+}
+
+func keyForHeaderIndex(chainID uint64, hash ecom.Hash) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID),
+		hash.Bytes())
+}
+
+func keyForSyncCommittee(chainID, period uint64) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.SYNC_COMMITTEE), utils.GetUint64Bytes(chainID),
+		utils.GetUint64Bytes(period))
+}
+
+func getGenesisHeader(native *native.NativeService, chainID uint64) (header *LightClientHeader, err error) {
+	return getLightClientHeader(native, keyForGenesisHeader(chainID))
+}
+
+func getLightClientHeader(native *native.NativeService, key []byte) (header *LightClientHeader, err error) {
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("get header from storage err: %v", err)
+	}
+	if store == nil {
+		return
+	}
+	data, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize header from raw storage item err: %v", err)
+	}
+	header = new(LightClientHeader)
+	err = json.Unmarshal(data, header)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal light client header err: %v", err)
+	}
+	return
+}
+
+func storeGenesisHeader(native *native.NativeService, chainID uint64, header *LightClientHeader) error {
+	data, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("marshal light client header err: %v", err)
+	}
+	native.GetCacheDB().Put(keyForGenesisHeader(chainID), cstates.GenRawStorageItem(data))
+	return nil
+}
+
+// Store the finalized header, indexed by its execution block number
+func storeFinalizedHeader(native *native.NativeService, chainID uint64, header *LightClientHeader) error {
+	data, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("marshal light client header err: %v", err)
+	}
+	height := uint64(header.Execution.BlockNumber)
+	hash := header.Execution.BlockHash
+	native.GetCacheDB().Put(keyForHeaderIndex(chainID, hash), cstates.GenRawStorageItem(data))
+	native.GetCacheDB().Put(keyForMainChain(chainID, height), cstates.GenRawStorageItem(hash.Bytes()))
+	native.GetCacheDB().Put(keyForHeaderHeight(chainID), cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+	scom.NotifyPutHeader(native, chainID, height, hash.String())
+	return nil
+}
+
+func getSyncCommittee(native *native.NativeService, chainID, period uint64) (*SyncCommittee, error) {
+	store, err := native.GetCacheDB().Get(keyForSyncCommittee(chainID, period))
+	if err != nil {
+		return nil, fmt.Errorf("get sync committee from storage err: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	data, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize sync committee from raw storage item err: %v", err)
+	}
+	committee := new(SyncCommittee)
+	err = committee.Deserialization(common.NewZeroCopySource(data))
+	if err != nil {
+		return nil, err
+	}
+	return committee, nil
+}
+
+func storeSyncCommittee(native *native.NativeService, chainID, period uint64, committee *SyncCommittee) {
+	sink := common.NewZeroCopySink(nil)
+	committee.Serialization(sink)
+	native.GetCacheDB().Put(keyForSyncCommittee(chainID, period), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// Get the latest finalized execution block number
+func GetCurrentHeaderHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	store, err := native.GetCacheDB().Get(keyForHeaderHeight(chainID))
+	if err != nil {
+		return 0, fmt.Errorf("GetCurrentHeaderHeight, get height from storage err: %v", err)
+	}
+	if store == nil {
+		return 0, fmt.Errorf("GetCurrentHeaderHeight, height of chain %d not found", chainID)
+	}
+	data, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, fmt.Errorf("GetCurrentHeaderHeight, deserialize height from raw storage item err: %v", err)
+	}
+	return utils.GetBytesUint64(data), nil
+}
+
+func GetCurrentHeader(native *native.NativeService, chainID uint64) (*LightClientHeader, error) {
+	height, err := GetCurrentHeaderHeight(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return GetHeaderByHeight(native, chainID, height)
+}
+
+// Get the finalized header by its execution block number, only finalized blocks synced are available
+func GetHeaderByHeight(native *native.NativeService, chainID, height uint64) (*LightClientHeader, error) {
+	store, err := native.GetCacheDB().Get(keyForMainChain(chainID, height))
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderByHeight, get block hash from storage err: %v", err)
+	}
+	if store == nil {
+		return nil, fmt.Errorf("GetHeaderByHeight, finalized header of height %d not found", height)
+	}
+	hash, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderByHeight, deserialize block hash from raw storage item err: %v", err)
+	}
+	header, err := getLightClientHeader(native, keyForHeaderIndex(chainID, ecom.BytesToHash(hash)))
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderByHeight, %v", err)
+	}
+	if header == nil {
+		return nil, fmt.Errorf("GetHeaderByHeight, header %x not found", hash)
+	}
+	return header, nil
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	SLOTS_PER_EPOCH                  = 32
+	EPOCHS_PER_SYNC_COMMITTEE_PERIOD = 256
+	SYNC_COMMITTEE_SIZE              = 512
+	MAX_EXTRA_DATA_BYTES             = 32
+)
+
+var (
+	pubkeyT    = reflect.TypeOf(BLSPubkey{})
+	signatureT = reflect.TypeOf(BLSSignature{})
+	versionT   = reflect.TypeOf(Version{})
+	bitsT      = reflect.TypeOf(SyncCommitteeBits{})
+)
+
+// Uint64 is an uint64 encoded as a quoted decimal string as in the beacon API
+type Uint64 uint64
+
+func (v Uint64) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(strconv.FormatUint(uint64(v), 10))), nil
+}
+
+func (v *Uint64) UnmarshalJSON(input []byte) error {
+	s, err := strconv.Unquote(string(input))
+	if err != nil {
+		return fmt.Errorf("invalid uint64 %s", input)
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid uint64 %s", s)
+	}
+	*v = Uint64(n)
+	return nil
+}
+
+// Uint256 is a little endian uint256 encoded as a quoted decimal string as in the beacon API
+type Uint256 [32]byte
+
+func NewUint256(v *big.Int) (u Uint256, err error) {
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		err = fmt.Errorf("value %s out of uint256 range", v)
+		return
+	}
+	b := v.Bytes()
+	for i := range b {
+		u[i] = b[len(b)-1-i]
+	}
+	return
+}
+
+func (u Uint256) Big() *big.Int {
+	b := make([]byte, len(u))
+	for i := range u {
+		b[i] = u[len(u)-1-i]
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+func (u Uint256) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(u.Big().String())), nil
+}
+
+func (u *Uint256) UnmarshalJSON(input []byte) error {
+	s, err := strconv.Unquote(string(input))
+	if err != nil {
+		return fmt.Errorf("invalid uint256 %s", input)
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid uint256 %s", s)
+	}
+	*u, err = NewUint256(v)
+	return err
+}
+
+type BLSPubkey [48]byte
+
+func (b BLSPubkey) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+func (b *BLSPubkey) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(pubkeyT, input, b[:])
+}
+
+type BLSSignature [96]byte
+
+func (b BLSSignature) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+func (b *BLSSignature) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(signatureT, input, b[:])
+}
+
+type Version [4]byte
+
+func (b Version) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+func (b *Version) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(versionT, input, b[:])
+}
+
+// Bitvector[SYNC_COMMITTEE_SIZE]
+type SyncCommitteeBits [SYNC_COMMITTEE_SIZE / 8]byte
+
+func (b SyncCommitteeBits) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+func (b *SyncCommitteeBits) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(bitsT, input, b[:])
+}
+
+func (b *SyncCommitteeBits) Get(i int) bool {
+	return b[i/8]>>(uint(i)%8)&1 == 1
+}
+
+func (b *SyncCommitteeBits) Set(i int) {
+	b[i/8] |= 1 << (uint(i) % 8)
+}
+
+// Count the participants
+func (b *SyncCommitteeBits) Count() (n int) {
+	for i := 0; i < SYNC_COMMITTEE_SIZE; i++ {
+		if b.Get(i) {
+			n++
+		}
+	}
+	return
+}
+
+type BeaconBlockHeader struct {
+	Slot          Uint64    `json:"slot"`
+	ProposerIndex Uint64    `json:"proposer_index"`
+	ParentRoot    ecom.Hash `json:"parent_root"`
+	StateRoot     ecom.Hash `json:"state_root"`
+	BodyRoot      ecom.Hash `json:"body_root"`
+}
+
+// Execution payload header since capella, blob gas fields are set since deneb
+type ExecutionPayloadHeader struct {
+	ParentHash       ecom.Hash     `json:"parent_hash"`
+	FeeRecipient     ecom.Address  `json:"fee_recipient"`
+	StateRoot        ecom.Hash     `json:"state_root"`
+	ReceiptsRoot     ecom.Hash     `json:"receipts_root"`
+	LogsBloom        types.Bloom   `json:"logs_bloom"`
+	PrevRandao       ecom.Hash     `json:"prev_randao"`
+	BlockNumber      Uint64        `json:"block_number"`
+	GasLimit         Uint64        `json:"gas_limit"`
+	GasUsed          Uint64        `json:"gas_used"`
+	Timestamp        Uint64        `json:"timestamp"`
+	ExtraData        hexutil.Bytes `json:"extra_data"`
+	BaseFeePerGas    Uint256       `json:"base_fee_per_gas"`
+	BlockHash        ecom.Hash     `json:"block_hash"`
+	TransactionsRoot ecom.Hash     `json:"transactions_root"`
+	WithdrawalsRoot  ecom.Hash     `json:"withdrawals_root"`
+	BlobGasUsed      *Uint64       `json:"blob_gas_used,omitempty"`
+	ExcessBlobGas    *Uint64       `json:"excess_blob_gas,omitempty"`
+}
+
+type LightClientHeader struct {
+	Beacon          BeaconBlockHeader       `json:"beacon"`
+	Execution       *ExecutionPayloadHeader `json:"execution"`
+	ExecutionBranch []ecom.Hash             `json:"execution_branch"`
+}
+
+type SyncCommittee struct {
+	Pubkeys         []BLSPubkey `json:"pubkeys"`
+	AggregatePubkey BLSPubkey   `json:"aggregate_pubkey"`
+}
+
+type SyncAggregate struct {
+	SyncCommitteeBits      SyncCommitteeBits `json:"sync_committee_bits"`
+	SyncCommitteeSignature BLSSignature      `json:"sync_committee_signature"`
+}
+
+// Trusted checkpoint used as the genesis header of the light client
+type LightClientBootstrap struct {
+	Header                     LightClientHeader `json:"header"`
+	CurrentSyncCommittee       *SyncCommittee    `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch []ecom.Hash       `json:"current_sync_committee_branch"`
+}
+
+// Light client update, a finality update is accepted as well as it omits the next sync committee only
+type LightClientUpdate struct {
+	AttestedHeader          LightClientHeader `json:"attested_header"`
+	NextSyncCommittee       *SyncCommittee    `json:"next_sync_committee,omitempty"`
+	NextSyncCommitteeBranch []ecom.Hash       `json:"next_sync_committee_branch,omitempty"`
+	FinalizedHeader         LightClientHeader `json:"finalized_header"`
+	FinalityBranch          []ecom.Hash       `json:"finality_branch"`
+	SyncAggregate           SyncAggregate     `json:"sync_aggregate"`
+	SignatureSlot           Uint64            `json:"signature_slot"`
+}
+
+// Check if the update carries a next sync committee, the beacon API fills absent ones with zero values
+func (this *LightClientUpdate) IsSyncCommitteeUpdate() bool {
+	return this.NextSyncCommittee != nil && !isZeroBranch(this.NextSyncCommitteeBranch)
+}
+
+func isZeroBranch(branch []ecom.Hash) bool {
+	for _, node := range branch {
+		if node != (ecom.Hash{}) {
+			return false
+		}
+	}
+	return true
+}
+
+func computeEpochAtSlot(slot uint64) uint64 {
+	return slot / SLOTS_PER_EPOCH
+}
+
+func computeSyncCommitteePeriod(epoch uint64) uint64 {
+	return epoch / EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+}
+
+func computeSyncCommitteePeriodAtSlot(slot uint64) uint64 {
+	return computeSyncCommitteePeriod(computeEpochAtSlot(slot))
+}
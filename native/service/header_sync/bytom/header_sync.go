@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"io"
 	"math/big"
-	"time"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -30,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
@@ -49,6 +49,13 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.BYTOM_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHandler()
+	})
+	utils.RegisterRouterStartBlock(utils.BYTOM_ROUTER, config.NETWORK_ID_MAIN_NET, 18823000)
+}
+
 // GenesisHeader ...
 type GenesisHeader struct {
 	Header         types.Header
@@ -418,7 +425,7 @@ func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSu
 	native.GetCacheDB().Put(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.Header.Hash().Bytes()),
 		cstates.GenRawStorageItem(headerBytes))
-	return
+	return scom.PutHeaderHash(native, chainID, headerWithSum.Header.Number.Uint64(), headerWithSum.Header.Hash().Bytes())
 }
 
 func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
@@ -500,6 +507,10 @@ func addHeader(native *native.NativeService, header *types.Header, phv *HeightAn
 		// Extend the canonical chain with the new header
 		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
 		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
+		err = scom.PruneHeaders(native, ctx.ChainID, header.Number.Uint64())
+		if err != nil {
+			return
+		}
 	}
 
 	return nil
@@ -680,8 +691,8 @@ var (
 func verifyHeader(native *native.NativeService, header *types.Header, ctx *Context) (signer ecommon.Address, err error) {
 
 	// Don't waste time checking blocks from the future
-	if header.Time > uint64(time.Now().Unix()) {
-		err = errors.New("block in the future")
+	err = scom.VerifyHeaderTime(native, ctx.ChainID, header.Time, 0)
+	if err != nil {
 		return
 	}
 
@@ -44,12 +44,19 @@ const (
 	SYNC_HEADER_NAME            = "syncHeader"
 	SYNC_CROSSCHAIN_MSG         = "syncCrossChainMsg"
 	POLYGON_SPAN                = "polygonSpan"
+	SYNC_COMMITTEE              = "syncCommittee"
+	HEADER_RETENTION            = "headerRetention"
+	OLDEST_HEADER_HEIGHT        = "oldestHeaderHeight"
+	HEADER_HASHES               = "headerHashes"
+	FUTURE_BLOCK_TIME           = "futureBlockTime"
 )
 
 const (
-	SYNC_GENESIS_HEADER  = "syncGenesisHeader"
-	SYNC_BLOCK_HEADER    = "syncBlockHeader"
-	SYNC_CROSS_CHAIN_MSG = "syncCrossChainMsg"
+	SYNC_GENESIS_HEADER   = "syncGenesisHeader"
+	SYNC_BLOCK_HEADER     = "syncBlockHeader"
+	SYNC_CROSS_CHAIN_MSG  = "syncCrossChainMsg"
+	SET_HEADER_RETENTION  = "setHeaderRetention"
+	SET_FUTURE_BLOCK_TIME = "setFutureBlockTime"
 )
 
 type HeaderSyncHandler interface {
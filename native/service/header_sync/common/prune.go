@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+const (
+	//Min retention depth of a side chain, keeps enough ancestors for the epoch validators lookup and chain reorganization
+	MIN_HEADER_RETENTION uint64 = 1000
+	//Max number of heights pruned when a header is appended, so that the cost of a sync transaction stays bounded
+	HEADER_PRUNE_BATCH uint64 = 16
+)
+
+type SetHeaderRetentionParam struct {
+	ChainID uint64
+	Depth   uint64
+}
+
+func (this *SetHeaderRetentionParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ChainID)
+	sink.WriteUint64(this.Depth)
+}
+
+func (this *SetHeaderRetentionParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("SetHeaderRetentionParam deserialize chainID error")
+	}
+	depth, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("SetHeaderRetentionParam deserialize depth error")
+	}
+	this.ChainID = chainID
+	this.Depth = depth
+	return nil
+}
+
+//Get the header retention depth of side chain, 0 means headers are never pruned
+func GetHeaderRetention(native *native.NativeService, chainID uint64) (uint64, error) {
+	return getUint64(native, utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_RETENTION), utils.GetUint64Bytes(chainID)))
+}
+
+func PutHeaderRetention(native *native.NativeService, chainID uint64, depth uint64) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_RETENTION), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(depth)))
+}
+
+//Get the oldest header height of side chain still retained besides the genesis header, 0 means no header was pruned
+func GetOldestHeaderHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	return getUint64(native, utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(OLDEST_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)))
+}
+
+func putOldestHeaderHeight(native *native.NativeService, chainID uint64, height uint64) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(OLDEST_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+}
+
+func headerHashesKey(chainID uint64, height uint64) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_HASHES), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height))
+}
+
+//Put the hash of header stored at height to the hash list of the height, so that the side fork headers are pruned along
+//with the main chain header. The list is only kept when the header retention of side chain is set
+func PutHeaderHash(native *native.NativeService, chainID uint64, height uint64, hash []byte) error {
+	depth, err := GetHeaderRetention(native, chainID)
+	if err != nil {
+		return fmt.Errorf("PutHeaderHash, get header retention error: %v", err)
+	}
+	if depth == 0 {
+		return nil
+	}
+	hashes, err := getHeaderHashes(native, chainID, height)
+	if err != nil {
+		return fmt.Errorf("PutHeaderHash, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	for _, h := range hashes {
+		if bytes.Equal(h, hash) {
+			return nil
+		}
+		sink.WriteVarBytes(h)
+	}
+	sink.WriteVarBytes(hash)
+	native.GetCacheDB().Put(headerHashesKey(chainID, height), cstates.GenRawStorageItem(sink.Bytes()))
+	return nil
+}
+
+func getHeaderHashes(native *native.NativeService, chainID uint64, height uint64) ([][]byte, error) {
+	store, err := native.GetCacheDB().Get(headerHashesKey(chainID, height))
+	if err != nil {
+		return nil, fmt.Errorf("get header hashes error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize header hashes error: %v", err)
+	}
+	hashes := make([][]byte, 0)
+	source := common.NewZeroCopySource(value)
+	for source.Len() > 0 {
+		hash, eof := source.NextVarBytes()
+		if eof {
+			return nil, fmt.Errorf("deserialize header hashes error")
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+//Prune the headers of eth family side chain older than its retention depth, called after the header at height is
+//appended. The main chain header and the side fork headers in the hash list of each height are deleted. The genesis
+//header is always kept, and at most HEADER_PRUNE_BATCH heights are pruned each time. The genesis height is only loaded
+//the first time headers of the chain are pruned.
+func PruneHeaders(native *native.NativeService, chainID uint64, height uint64) error {
+	depth, err := GetHeaderRetention(native, chainID)
+	if err != nil {
+		return fmt.Errorf("PruneHeaders, get header retention error: %v", err)
+	}
+	if depth == 0 || height <= depth {
+		return nil
+	}
+	oldest, err := GetOldestHeaderHeight(native, chainID)
+	if err != nil {
+		return fmt.Errorf("PruneHeaders, get oldest header height error: %v", err)
+	}
+	if oldest == 0 {
+		genesis, err := getGenesisHeight(native, chainID)
+		if err != nil {
+			return fmt.Errorf("PruneHeaders, get genesis height error: %v", err)
+		}
+		oldest = genesis + 1
+	}
+	end := height - depth
+	if end > oldest+HEADER_PRUNE_BATCH {
+		end = oldest + HEADER_PRUNE_BATCH
+	}
+	if end <= oldest {
+		return nil
+	}
+	for h := oldest; h < end; h++ {
+		hashes, err := getHeaderHashes(native, chainID, h)
+		if err != nil {
+			return fmt.Errorf("PruneHeaders, %v", err)
+		}
+		for _, hash := range hashes {
+			native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_INDEX), utils.GetUint64Bytes(chainID), hash))
+		}
+		native.GetCacheDB().Delete(headerHashesKey(chainID, h))
+		key := utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(h))
+		hashStore, err := native.GetCacheDB().Get(key)
+		if err != nil {
+			return fmt.Errorf("PruneHeaders, get main chain hash error: %v", err)
+		}
+		if hashStore == nil {
+			continue
+		}
+		hash, err := cstates.GetValueFromRawStorageItem(hashStore)
+		if err != nil {
+			return fmt.Errorf("PruneHeaders, deserialize main chain hash error: %v", err)
+		}
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_INDEX), utils.GetUint64Bytes(chainID), hash))
+		native.GetCacheDB().Delete(key)
+	}
+	putOldestHeaderHeight(native, chainID, end)
+	return nil
+}
+
+//Get the height of the genesis header of eth family side chain, the genesis is stored in json either as the header, or
+//with the header in field Header
+func getGenesisHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return 0, err
+	}
+	if store == nil {
+		return 0, fmt.Errorf("genesis header not found")
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, err
+	}
+	genesis := new(struct {
+		Number *hexutil.Big `json:"number"`
+		Header *struct {
+			Number *hexutil.Big `json:"number"`
+		}
+	})
+	if err := json.Unmarshal(value, genesis); err != nil {
+		return 0, fmt.Errorf("unmarshal genesis header error: %v", err)
+	}
+	switch {
+	case genesis.Header != nil && genesis.Header.Number != nil:
+		return genesis.Header.Number.ToInt().Uint64(), nil
+	case genesis.Number != nil:
+		return genesis.Number.ToInt().Uint64(), nil
+	default:
+		return 0, fmt.Errorf("genesis header number not found")
+	}
+}
+
+func getUint64(native *native.NativeService, key []byte) (uint64, error) {
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if store == nil {
+		return 0, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, err
+	}
+	return utils.GetBytesUint64(value), nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneHeaders(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	service, err := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{}, 0, nil, false)
+	assert.NoError(t, err)
+
+	chainID, genesis, current := uint64(2), uint64(100), uint64(1200)
+	hashAt := func(h uint64) []byte { return utils.GetUint64Bytes(h) }
+	mainKey := func(h uint64) []byte {
+		return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(h))
+	}
+	indexKey := func(h uint64) []byte {
+		return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_INDEX), utils.GetUint64Bytes(chainID), hashAt(h))
+	}
+	forkAt := func(h uint64) []byte { return append([]byte("fork"), utils.GetUint64Bytes(h)...) }
+	forkKey := func(h uint64) []byte {
+		return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_INDEX), utils.GetUint64Bytes(chainID), forkAt(h))
+	}
+	for h := genesis; h <= current; h++ {
+		db.Put(mainKey(h), cstates.GenRawStorageItem(hashAt(h)))
+		db.Put(indexKey(h), cstates.GenRawStorageItem([]byte("header")))
+	}
+	db.Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(GENESIS_HEADER), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem([]byte(`{"header":{"number":"0x64"},"difficultySum":1}`)))
+
+	//retention not set
+	assert.NoError(t, PutHeaderHash(service, chainID, genesis+1, forkAt(genesis+1)))
+	hashes, err := getHeaderHashes(service, chainID, genesis+1)
+	assert.NoError(t, err)
+	assert.Empty(t, hashes)
+	assert.NoError(t, PruneHeaders(service, chainID, current))
+	oldest, err := GetOldestHeaderHeight(service, chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), oldest)
+
+	//side fork headers are listed with the main chain header of the same height
+	PutHeaderRetention(service, chainID, MIN_HEADER_RETENTION)
+	for h := genesis + 1; h <= current; h++ {
+		db.Put(forkKey(h), cstates.GenRawStorageItem([]byte("fork header")))
+		assert.NoError(t, PutHeaderHash(service, chainID, h, hashAt(h)))
+		assert.NoError(t, PutHeaderHash(service, chainID, h, forkAt(h)))
+		assert.NoError(t, PutHeaderHash(service, chainID, h, forkAt(h)))
+	}
+	hashes, err = getHeaderHashes(service, chainID, genesis+1)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{hashAt(genesis + 1), forkAt(genesis + 1)}, hashes)
+
+	assert.NoError(t, PruneHeaders(service, chainID, current))
+	oldest, err = GetOldestHeaderHeight(service, chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, genesis+1+HEADER_PRUNE_BATCH, oldest)
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, PruneHeaders(service, chainID, current))
+	}
+	oldest, err = GetOldestHeaderHeight(service, chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, current-MIN_HEADER_RETENTION, oldest)
+
+	for h := genesis; h <= current; h++ {
+		mainStore, _ := db.Get(mainKey(h))
+		indexStore, _ := db.Get(indexKey(h))
+		retained := h == genesis || h >= oldest
+		assert.Equal(t, retained, mainStore != nil, h)
+		assert.Equal(t, retained, indexStore != nil, h)
+		if h > genesis {
+			forkStore, _ := db.Get(forkKey(h))
+			hashesStore, _ := db.Get(headerHashesKey(chainID, h))
+			assert.Equal(t, retained, forkStore != nil, h)
+			assert.Equal(t, retained, hashesStore != nil, h)
+		}
+	}
+}
+
+func TestGetGenesisHeight(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	service, err := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{}, 0, nil, false)
+	assert.NoError(t, err)
+
+	_, err = getGenesisHeight(service, 3)
+	assert.Error(t, err)
+
+	//plain header, as stored by bsc, heco and the other eth like chains
+	db.Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(GENESIS_HEADER), utils.GetUint64Bytes(3)),
+		cstates.GenRawStorageItem([]byte(`{"number":"0xc8"}`)))
+	height, err := getGenesisHeight(service, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), height)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+const (
+	//Min time a side chain header is allowed to be ahead of the poly block time, unless governance sets it for the chain
+	DEFAULT_ALLOWED_FUTURE_BLOCK_TIME = 15 * time.Second
+	//Max allowed future block time governance can set for a chain
+	MAX_ALLOWED_FUTURE_BLOCK_TIME uint64 = 3600
+)
+
+type SetFutureBlockTimeParam struct {
+	ChainID uint64
+	Seconds uint64
+}
+
+func (this *SetFutureBlockTimeParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ChainID)
+	sink.WriteUint64(this.Seconds)
+}
+
+func (this *SetFutureBlockTimeParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("SetFutureBlockTimeParam deserialize chainID error")
+	}
+	seconds, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("SetFutureBlockTimeParam deserialize seconds error")
+	}
+	this.ChainID = chainID
+	this.Seconds = seconds
+	return nil
+}
+
+//Get the allowed future block time in seconds of side chain set by governance, 0 means not set
+func GetAllowedFutureBlockTime(native *native.NativeService, chainID uint64) (uint64, error) {
+	return getUint64(native, utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(FUTURE_BLOCK_TIME), utils.GetUint64Bytes(chainID)))
+}
+
+func PutAllowedFutureBlockTime(native *native.NativeService, chainID uint64, seconds uint64) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(FUTURE_BLOCK_TIME), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(seconds)))
+}
+
+//Check the side chain header time in seconds is not from the future. Since the header time check height, the poly block
+//time is used instead of the local clock, so that all the validators and block replays get the same result, and the
+//allowed future time is the one set by governance for the chain, or the larger of the handler's and the default one.
+//Before the height, the local clock and the handler's allowed future time are used as the blocks were executed.
+func VerifyHeaderTime(native *native.NativeService, chainID uint64, headerTime uint64, allowedFutureBlockTime time.Duration) error {
+	if native.GetHeight() < config.GetHeaderTimeCheckHeight(config.DefConfig.P2PNode.NetworkId) {
+		checkTime := uint64(time.Now().Add(allowedFutureBlockTime).Unix())
+		if headerTime > checkTime {
+			return fmt.Errorf("block in the future, header time: %d, check time: %d", headerTime, checkTime)
+		}
+		return nil
+	}
+
+	allowed, err := GetAllowedFutureBlockTime(native, chainID)
+	if err != nil {
+		return fmt.Errorf("get allowed future block time error: %v", err)
+	}
+	if allowed == 0 {
+		if allowedFutureBlockTime < DEFAULT_ALLOWED_FUTURE_BLOCK_TIME {
+			allowedFutureBlockTime = DEFAULT_ALLOWED_FUTURE_BLOCK_TIME
+		}
+		allowed = uint64(allowedFutureBlockTime / time.Second)
+	}
+	blockTime := uint64(native.GetTime())
+	if headerTime > blockTime+allowed {
+		return fmt.Errorf("block in the future, header time: %d, block time: %d, allowed future block time: %ds",
+			headerTime, blockTime, allowed)
+	}
+	return nil
+}
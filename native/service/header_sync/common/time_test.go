@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyHeaderTime(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	service, err := native.NewNativeService(db, &types.Transaction{}, 1000, 0, common.Uint256{}, 0, nil, false)
+	assert.NoError(t, err)
+	chainID := uint64(2)
+	assert.NoError(t, VerifyHeaderTime(service, chainID, 900, 0))
+	assert.NoError(t, VerifyHeaderTime(service, chainID, 1015, 0))
+	assert.Error(t, VerifyHeaderTime(service, chainID, 1016, 0))
+	assert.NoError(t, VerifyHeaderTime(service, chainID, 1030, 30*time.Second))
+	assert.Error(t, VerifyHeaderTime(service, chainID, 1031, 30*time.Second))
+
+	//set by governance
+	PutAllowedFutureBlockTime(service, chainID, 5)
+	assert.NoError(t, VerifyHeaderTime(service, chainID, 1005, 30*time.Second))
+	assert.Error(t, VerifyHeaderTime(service, chainID, 1006, 30*time.Second))
+	assert.Error(t, VerifyHeaderTime(service, chainID+1, 1016, 0))
+
+	//before the check height, the header time is checked with the local clock
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	now := uint64(time.Now().Unix())
+	assert.NoError(t, VerifyHeaderTime(service, chainID, now-10, 0))
+	assert.Error(t, VerifyHeaderTime(service, chainID, now+100, 0))
+}
+
+// Header sync handlers must not depend on the local clock
+func TestNoWallClockInHandlers(t *testing.T) {
+	err := filepath.Walk("..", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		//the local clock is only used by VerifyHeaderTime for the blocks before the header time check height
+		if filepath.Base(path) == "time.go" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		assert.NotContains(t, string(data), "time.Now()", path)
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -41,6 +41,12 @@ func NewCosmosHandler() *CosmosHandler {
 	return &CosmosHandler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.COSMOS_ROUTER, func() hscommon.HeaderSyncHandler {
+		return NewCosmosHandler()
+	})
+}
+
 var Cdc = codec.New()
 
 func init() {
@@ -22,29 +22,34 @@ import (
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
-	"github.com/polynetwork/poly/native/service/header_sync/bsc"
-	"github.com/polynetwork/poly/native/service/header_sync/btc"
-	"github.com/polynetwork/poly/native/service/header_sync/bytom"
 	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
-	"github.com/polynetwork/poly/native/service/header_sync/cosmos"
-	"github.com/polynetwork/poly/native/service/header_sync/eth"
-	"github.com/polynetwork/poly/native/service/header_sync/harmony"
-	"github.com/polynetwork/poly/native/service/header_sync/heco"
-	"github.com/polynetwork/poly/native/service/header_sync/hsc"
-	"github.com/polynetwork/poly/native/service/header_sync/msc"
-	"github.com/polynetwork/poly/native/service/header_sync/neo"
-	"github.com/polynetwork/poly/native/service/header_sync/neo3"
-	"github.com/polynetwork/poly/native/service/header_sync/neo3legacy"
-	"github.com/polynetwork/poly/native/service/header_sync/okex"
-	"github.com/polynetwork/poly/native/service/header_sync/ont"
-	"github.com/polynetwork/poly/native/service/header_sync/pixiechain"
-	"github.com/polynetwork/poly/native/service/header_sync/polygon"
-	"github.com/polynetwork/poly/native/service/header_sync/quorum"
-	"github.com/polynetwork/poly/native/service/header_sync/starcoin"
-	"github.com/polynetwork/poly/native/service/header_sync/zilliqa"
-	"github.com/polynetwork/poly/native/service/header_sync/zilliqalegacy"
 	"github.com/polynetwork/poly/native/service/utils"
+
+	// router packages register their handlers in init
+	_ "github.com/polynetwork/poly/native/service/header_sync/beacon"
+	_ "github.com/polynetwork/poly/native/service/header_sync/bsc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/btc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/bytom"
+	_ "github.com/polynetwork/poly/native/service/header_sync/cosmos"
+	_ "github.com/polynetwork/poly/native/service/header_sync/eth"
+	_ "github.com/polynetwork/poly/native/service/header_sync/harmony"
+	_ "github.com/polynetwork/poly/native/service/header_sync/heco"
+	_ "github.com/polynetwork/poly/native/service/header_sync/hsc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/msc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/neo"
+	_ "github.com/polynetwork/poly/native/service/header_sync/neo3"
+	_ "github.com/polynetwork/poly/native/service/header_sync/neo3legacy"
+	_ "github.com/polynetwork/poly/native/service/header_sync/okex"
+	_ "github.com/polynetwork/poly/native/service/header_sync/ont"
+	_ "github.com/polynetwork/poly/native/service/header_sync/pixiechain"
+	_ "github.com/polynetwork/poly/native/service/header_sync/polygon"
+	_ "github.com/polynetwork/poly/native/service/header_sync/quorum"
+	_ "github.com/polynetwork/poly/native/service/header_sync/starcoin"
+	_ "github.com/polynetwork/poly/native/service/header_sync/zilliqa"
+	_ "github.com/polynetwork/poly/native/service/header_sync/zilliqalegacy"
 )
 
 //Register methods of node_manager contract
@@ -52,55 +57,12 @@ func RegisterHeaderSyncContract(native *native.NativeService) {
 	native.Register(hscommon.SYNC_GENESIS_HEADER, SyncGenesisHeader)
 	native.Register(hscommon.SYNC_BLOCK_HEADER, SyncBlockHeader)
 	native.Register(hscommon.SYNC_CROSS_CHAIN_MSG, SyncCrossChainMsg)
+	native.Register(hscommon.SET_HEADER_RETENTION, SetHeaderRetention)
+	native.Register(hscommon.SET_FUTURE_BLOCK_TIME, SetFutureBlockTime)
 }
 
 func GetChainHandler(router uint64) (hscommon.HeaderSyncHandler, error) {
-	switch router {
-	case utils.BTC_ROUTER:
-		return btc.NewBTCHandler(), nil
-	case utils.ETH_ROUTER:
-		return eth.NewETHHandler(), nil
-	case utils.ONT_ROUTER:
-		return ont.NewONTHandler(), nil
-	case utils.NEO_ROUTER:
-		return neo.NewNEOHandler(), nil
-	case utils.NEO3_ROUTER:
-		return neo3.NewNeo3Handler(), nil
-	case utils.NEO3_LEGACY_ROUTER:
-		return neo3legacy.NewNeo3Handler(), nil
-	case utils.COSMOS_ROUTER:
-		return cosmos.NewCosmosHandler(), nil
-	case utils.QUORUM_ROUTER:
-		return quorum.NewQuorumHandler(), nil
-	case utils.BSC_ROUTER:
-		return bsc.NewHandler(), nil
-	case utils.HECO_ROUTER:
-		return heco.NewHecoHandler(), nil
-	case utils.ZILLIQA_LEGACY_ROUTER:
-		return zilliqalegacy.NewHandler(), nil
-	case utils.ZILLIQA_ROUTER:
-		return zilliqa.NewHandler(), nil
-	case utils.MSC_ROUTER:
-		return msc.NewHandler(), nil
-	case utils.OKEX_ROUTER:
-		return okex.NewHandler(), nil
-	case utils.POLYGON_HEIMDALL_ROUTER:
-		return polygon.NewHeimdallHandler(), nil
-	case utils.POLYGON_BOR_ROUTER:
-		return polygon.NewBorHandler(), nil
-	case utils.PIXIECHAIN_ROUTER:
-		return pixiechain.NewPixieHandler(), nil
-	case utils.STARCOIN_ROUTER:
-		return starcoin.NewSTCHandler(), nil
-	case utils.HSC_ROUTER:
-		return hsc.NewHscHandler(), nil
-	case utils.HARMONY_ROUTER:
-		return harmony.NewHandler(), nil
-	case utils.BYTOM_ROUTER:
-		return bytom.NewHandler(), nil
-	default:
-		return nil, fmt.Errorf("not a supported router:%d", router)
-	}
+	return hscommon.GetHeaderSyncHandler(router)
 }
 
 func SyncGenesisHeader(native *native.NativeService) ([]byte, error) {
@@ -135,12 +97,13 @@ func SyncGenesisHeader(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
-func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
+func SyncBlockHeader(native *native.NativeService) (res []byte, err error) {
 	params := new(hscommon.SyncBlockHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, contract params deserialize error: %v", err)
 	}
 	chainID := params.ChainID
+	defer func() { hscommon.CountSyncBlockHeader(native, chainID, err) }()
 
 	//check if chainid exist
 	sideChain, err := side_chain_manager.GetSideChain(native, chainID)
@@ -148,7 +111,7 @@ func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, side_chain_manager.GetSideChain error: %v", err)
 	}
 	if sideChain == nil {
-		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, side chain is not registered")
+		return utils.BYTE_FALSE, event.NewExecuteError(event.CONTRACT_ERROR_NOT_REGISTERED, "SyncBlockHeader, side chain is not registered")
 	}
 
 	handler, err := GetChainHandler(sideChain.Router)
@@ -162,7 +125,7 @@ func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
 
 	err = handler.SyncBlockHeader(native)
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		return utils.BYTE_FALSE, &event.ExecuteError{Code: event.CONTRACT_ERROR_VERIFY, Err: err}
 	}
 	return utils.BYTE_TRUE, nil
 }
@@ -198,3 +161,76 @@ func SyncCrossChainMsg(native *native.NativeService) ([]byte, error) {
 	}
 	return utils.BYTE_TRUE, nil
 }
+
+//Set the number of recent headers of a side chain kept in storage, older ones are pruned when new headers are appended,
+//depth 0 disables the pruning
+func SetHeaderRetention(native *native.NativeService) ([]byte, error) {
+	params := new(hscommon.SetHeaderRetentionParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, contract params deserialize error: %v", err)
+	}
+
+	// get operator from database
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, get current consensus operator address error: %v", err)
+	}
+
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, checkWitness error: %v", err)
+	}
+
+	//check if chainid exist
+	sideChain, err := side_chain_manager.GetSideChain(native, params.ChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, side chain is not registered")
+	}
+	if params.Depth != 0 && (params.Depth < hscommon.MIN_HEADER_RETENTION || params.Depth <= sideChain.BlocksToWait) {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, depth %d should be at least %d and larger than blocks to wait %d",
+			params.Depth, hscommon.MIN_HEADER_RETENTION, sideChain.BlocksToWait)
+	}
+
+	hscommon.PutHeaderRetention(native, params.ChainID, params.Depth)
+	return utils.BYTE_TRUE, nil
+}
+
+//Set the time in seconds a header of side chain is allowed to be ahead of the poly block time, 0 resets it to the default
+func SetFutureBlockTime(native *native.NativeService) ([]byte, error) {
+	params := new(hscommon.SetFutureBlockTimeParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFutureBlockTime, contract params deserialize error: %v", err)
+	}
+
+	// get operator from database
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFutureBlockTime, get current consensus operator address error: %v", err)
+	}
+
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFutureBlockTime, checkWitness error: %v", err)
+	}
+
+	//check if chainid exist
+	sideChain, err := side_chain_manager.GetSideChain(native, params.ChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFutureBlockTime, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFutureBlockTime, side chain is not registered")
+	}
+	if params.Seconds > hscommon.MAX_ALLOWED_FUTURE_BLOCK_TIME {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFutureBlockTime, seconds %d should be at most %d",
+			params.Seconds, hscommon.MAX_ALLOWED_FUTURE_BLOCK_TIME)
+	}
+
+	hscommon.PutAllowedFutureBlockTime(native, params.ChainID, params.Seconds)
+	return utils.BYTE_TRUE, nil
+}
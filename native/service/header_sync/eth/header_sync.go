@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"hash"
 	"math/big"
-	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/polynetwork/poly/common/log"
@@ -32,7 +31,6 @@ import (
 	"golang.org/x/crypto/sha3"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/polynetwork/poly/common"
@@ -58,6 +56,12 @@ func NewETHHandler() *ETHHandler {
 	return &ETHHandler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.ETH_ROUTER, func() scom.HeaderSyncHandler {
+		return NewETHHandler()
+	})
+}
+
 func (this *ETHHandler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(scom.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -143,8 +147,8 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 			return fmt.Errorf("SyncBlockHeader, SyncBlockHeader extra-data too long: %d > %d, header: %s", len(header.Extra), params.MaximumExtraDataSize, string(v))
 		}
 		//verify current time validity
-		if header.Time > uint64(time.Now().Add(allowedFutureBlockTime).Unix()) {
-			return fmt.Errorf("SyncBlockHeader,  verify header time error:%s, checktime: %d, header: %s", consensus.ErrFutureBlock, time.Now().Add(allowedFutureBlockTime).Unix(), string(v))
+		if err := scom.VerifyHeaderTime(native, headerParams.ChainID, header.Time, allowedFutureBlockTime); err != nil {
+			return fmt.Errorf("SyncBlockHeader,  verify header time error:%s, header: %s", err, string(v))
 		}
 		//verify whether current header time and prevent header time validity
 		if header.Time <= parentHeader.Time {
@@ -200,7 +204,10 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 		//make sure the block header synchronization is ordered
 		if bytes.Equal(currentHeader.Hash().Bytes(), header.ParentHash.Bytes()) {
 			//if header is just the next one
-			appendHeader2Main(native, header.Number.Uint64(), headerHash, headerParams.ChainID)
+			err = appendHeader2Main(native, header.Number.Uint64(), headerHash, headerParams.ChainID)
+			if err != nil {
+				return fmt.Errorf("SyncBlockHeader, append header to main chain error: %v", err)
+			}
 		} else {
 			//The block to be synchronized belongs to another fork and has larger difficulty sum
 			if headerDifficultySum.Cmp(currentDifficultySum) > 0 {
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
@@ -119,7 +120,7 @@ func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) *native.
 			[]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
 
 	}
-	ret, _ := native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+	ret, _ := native.NewNativeService(db, tx, uint32(time.Now().Unix()), 0, common.Uint256{0}, 0, args, false)
 	return ret
 }
 
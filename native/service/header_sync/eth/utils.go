@@ -35,7 +35,7 @@ import (
 
 const (
 	// source from https://github.com/ethereum/go-ethereum/blob/master/consensus/ethash/consensus.go#L45
-	allowedFutureBlockTime = 15 * time.Second // Max time from current time allowed for blocks, before they're considered future blocks
+	allowedFutureBlockTime = 15 * time.Second // Max time from poly block time allowed for blocks, before they're considered future blocks
 	epochLength            = 30000
 	maxEpoch               = 2048
 	datasetInitBytes       = 1 << 30
@@ -88,7 +88,7 @@ func putBlockHeader(native *native.NativeService, blockHeader Header, difficulty
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), blockHeader.Hash().Bytes()),
 		cstates.GenRawStorageItem(storeBytes))
 	scom.NotifyPutHeader(native, chainID, blockHeader.Number.Uint64(), blockHeader.Hash().String())
-	return nil
+	return scom.PutHeaderHash(native, chainID, blockHeader.Number.Uint64(), blockHeader.Hash().Bytes())
 }
 //appendHeader2Main stores the mapping between block height and block header hash with key MAIN_CHAIN and update the CURRENT_HEADER_HEIGHT
 func appendHeader2Main(native *native.NativeService, height uint64, txhash common.Hash, chainID uint64) error {
@@ -98,8 +98,9 @@ func appendHeader2Main(native *native.NativeService, height uint64, txhash commo
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(scom.CURRENT_HEADER_HEIGHT),
 		utils.GetUint64Bytes(chainID)), cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
 	scom.NotifyPutHeader(native, chainID, height, txhash.String())
-	return nil
+	return scom.PruneHeaders(native, chainID, height)
 }
+
 func GetCurrentHeader(native *native.NativeService, chainID uint64) (*Header, *big.Int, error) {
 	height, err := GetCurrentHeaderHeight(native, chainID)
 	if err != nil {
@@ -225,7 +226,9 @@ func RestructChain(native *native.NativeService, current, new *Header, chainID u
 	//put all headers on poly chain
 	newHashs = append(newHashs, new.Hash())
 	for i := len(newHashs) - 1; i >= 0; i-- {
-		appendHeader2Main(native, ti, newHashs[i], chainID)
+		if err := appendHeader2Main(native, ti, newHashs[i], chainID); err != nil {
+			return fmt.Errorf("RestructChain appendHeader2Main height:%d error:%s", ti, err)
+		}
 		ti++
 	}
 	return nil
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
@@ -35,6 +36,13 @@ func NewHandler() *Handler {
 	return new(Handler)
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.HARMONY_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHandler()
+	})
+	utils.RegisterRouterStartBlock(utils.HARMONY_ROUTER, config.NETWORK_ID_MAIN_NET, 18823000)
+}
+
 // Sync Genesis header
 func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	params := new(scom.SyncGenesisHeaderParam)
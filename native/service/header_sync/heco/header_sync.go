@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"io"
 	"math/big"
-	"time"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -53,6 +52,12 @@ func NewHecoHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.HECO_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHecoHandler()
+	})
+}
+
 // GenesisHeader ...
 type GenesisHeader struct {
 	Header         eth.Header
@@ -410,7 +415,7 @@ func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSu
 	native.GetCacheDB().Put(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.Header.Hash().Bytes()),
 		cstates.GenRawStorageItem(headerBytes))
-	return
+	return scom.PutHeaderHash(native, chainID, headerWithSum.Header.Number.Uint64(), headerWithSum.Header.Hash().Bytes())
 }
 
 func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
@@ -492,6 +497,10 @@ func addHeader(native *native.NativeService, header *eth.Header, phv *HeightAndV
 		// Extend the canonical chain with the new header
 		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
 		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
+		err = scom.PruneHeaders(native, ctx.ChainID, header.Number.Uint64())
+		if err != nil {
+			return
+		}
 	}
 
 	return nil
@@ -671,8 +680,8 @@ var (
 func verifyHeader(native *native.NativeService, header *eth.Header, ctx *Context) (signer ecommon.Address, err error) {
 
 	// Don't waste time checking blocks from the future
-	if header.Time > uint64(time.Now().Unix()) {
-		err = errors.New("block in the future")
+	err = scom.VerifyHeaderTime(native, ctx.ChainID, header.Time, 0)
+	if err != nil {
 		return
 	}
 
@@ -22,12 +22,12 @@ import (
 	"fmt"
 	"io"
 	"math/big"
-	"time"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
@@ -52,6 +52,13 @@ func NewHscHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.HSC_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHscHandler()
+	})
+	utils.RegisterRouterStartBlock(utils.HSC_ROUTER, config.NETWORK_ID_MAIN_NET, 18823000)
+}
+
 // GenesisHeader ...
 type GenesisHeader struct {
 	Header         eth.Header
@@ -405,7 +412,7 @@ func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSu
 	native.GetCacheDB().Put(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.Header.Hash().Bytes()),
 		cstates.GenRawStorageItem(headerBytes))
-	return
+	return scom.PutHeaderHash(native, chainID, headerWithSum.Header.Number.Uint64(), headerWithSum.Header.Hash().Bytes())
 }
 
 func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
@@ -487,6 +494,10 @@ func addHeader(native *native.NativeService, header *eth.Header, phv *HeightAndV
 		// Extend the canonical chain with the new header
 		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
 		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
+		err = scom.PruneHeaders(native, ctx.ChainID, header.Number.Uint64())
+		if err != nil {
+			return
+		}
 	}
 
 	return nil
@@ -666,8 +677,8 @@ var (
 func verifyHeader(native *native.NativeService, header *eth.Header, ctx *Context) (signer ecommon.Address, err error) {
 
 	// Don't waste time checking blocks from the future
-	if header.Time > uint64(time.Now().Unix()) {
-		err = errors.New("block in the future")
+	err = scom.VerifyHeaderTime(native, ctx.ChainID, header.Time, 0)
+	if err != nil {
 		return
 	}
 
@@ -90,7 +90,7 @@ func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) (*native
 			[]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
 
 	}
-	return native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+	return native.NewNativeService(db, tx, uint32(time.Now().Unix()), 0, common.Uint256{0}, 0, args, false)
 }
 
 type chainClient struct {
@@ -22,7 +22,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"time"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -48,6 +47,12 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.MSC_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHandler()
+	})
+}
+
 // SyncGenesisHeader ...
 func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	params := new(scom.SyncGenesisHeaderParam)
@@ -342,7 +347,7 @@ func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSu
 	native.GetCacheDB().Put(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.Header.Hash().Bytes()),
 		cstates.GenRawStorageItem(headerBytes))
-	return
+	return scom.PutHeaderHash(native, chainID, headerWithSum.Header.Number.Uint64(), headerWithSum.Header.Hash().Bytes())
 }
 
 func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
@@ -437,6 +442,10 @@ func addHeader(native *native.NativeService, header *types.Header, ctx *Context)
 		// Extend the canonical chain with the new header
 		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
 		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
+		err = scom.PruneHeaders(native, ctx.ChainID, header.Number.Uint64())
+		if err != nil {
+			return
+		}
 	}
 
 	return nil
@@ -625,10 +634,6 @@ var (
 	// block has a beneficiary set to non-zeroes.
 	errInvalidCheckpointBeneficiary = errors.New("beneficiary in checkpoint block non-zero")
 
-	// errFutureBlock is returned when a block's timestamp is in the future according
-	// to the current node.
-	errFutureBlock = errors.New("block in the future")
-
 	// errUnknownAncestor is returned when validating a block requires an ancestor
 	// that is unknown.
 	errUnknownAncestor = errors.New("unknown ancestor")
@@ -645,8 +650,8 @@ func verifyHeader(native *native.NativeService, header *types.Header, ctx *Conte
 	number := header.Number.Uint64()
 
 	// Don't waste time checking blocks from the future
-	if header.Time > uint64(time.Now().Unix()) {
-		err = errFutureBlock
+	err = scom.VerifyHeaderTime(native, ctx.ChainID, header.Time, 0)
+	if err != nil {
 		return
 	}
 
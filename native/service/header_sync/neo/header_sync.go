@@ -34,6 +34,12 @@ func NewNEOHandler() *NEOHandler {
 	return &NEOHandler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.NEO_ROUTER, func() hscommon.HeaderSyncHandler {
+		return NewNEOHandler()
+	})
+}
+
 func (this *NEOHandler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(hscommon.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
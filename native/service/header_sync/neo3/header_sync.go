@@ -36,6 +36,12 @@ func NewNeo3Handler() *Neo3Handler {
 	return &Neo3Handler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.NEO3_ROUTER, func() hscommon.HeaderSyncHandler {
+		return NewNeo3Handler()
+	})
+}
+
 func (this *Neo3Handler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(hscommon.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
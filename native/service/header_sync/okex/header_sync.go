@@ -47,6 +47,12 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.OKEX_ROUTER, func() hscommon.HeaderSyncHandler {
+		return NewHandler()
+	})
+}
+
 // NewCDC ...
 func NewCDC() *codec.Codec {
 	cdc := codec.New()
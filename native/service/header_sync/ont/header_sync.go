@@ -37,6 +37,12 @@ func NewONTHandler() *ONTHandler {
 	return &ONTHandler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.ONT_ROUTER, func() hscommon.HeaderSyncHandler {
+		return NewONTHandler()
+	})
+}
+
 func (this *ONTHandler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(hscommon.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -25,7 +25,6 @@ import (
 	"io"
 	"math/big"
 	"sort"
-	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	ecommon "github.com/ethereum/go-ethereum/common"
@@ -56,6 +55,12 @@ func NewBorHandler() *BorHandler {
 	return &BorHandler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.POLYGON_BOR_ROUTER, func() scom.HeaderSyncHandler {
+		return NewBorHandler()
+	})
+}
+
 // HeaderWithOptionalSnap ...
 type HeaderWithOptionalSnap struct {
 	Header   eth.Header
@@ -355,7 +360,7 @@ func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSu
 	native.GetCacheDB().Put(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.HeaderWithOptionalSnap.Header.Hash().Bytes()),
 		cstates.GenRawStorageItem(headerBytes))
-	return
+	return scom.PutHeaderHash(native, chainID, headerWithSum.HeaderWithOptionalSnap.Header.Number.Uint64(), headerWithSum.HeaderWithOptionalSnap.Header.Hash().Bytes())
 }
 
 func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
@@ -442,6 +447,10 @@ func addHeader(native *native.NativeService, header *eth.Header, snap *Snapshot,
 		// Extend the canonical chain with the new header
 		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
 		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
+		err = scom.PruneHeaders(native, ctx.ChainID, header.Number.Uint64())
+		if err != nil {
+			return
+		}
 	}
 
 	return nil
@@ -486,8 +495,8 @@ func verifyHeader(native *native.NativeService, headerWOP *HeaderWithOptionalPro
 	}
 	number := header.Number.Uint64()
 	// Don't waste time checking blocks from the future
-	if header.Time > uint64(time.Now().Unix()) {
-		err = errors.New("block in the future")
+	err = scom.VerifyHeaderTime(native, ctx.ChainID, header.Time, 0)
+	if err != nil {
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
@@ -68,7 +69,7 @@ func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) (service
 			[]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
 
 	}
-	service, err = native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+	service, err = native.NewNativeService(db, tx, uint32(time.Now().Unix()), 0, common.Uint256{0}, 0, args, false)
 	if err != nil {
 		return
 	}
@@ -44,6 +44,12 @@ func NewHeimdallHandler() *HeimdallHandler {
 	return &HeimdallHandler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.POLYGON_HEIMDALL_ROUTER, func() hscommon.HeaderSyncHandler {
+		return NewHeimdallHandler()
+	})
+}
+
 type CosmosHeader struct {
 	Header  polygonTypes.Header
 	Commit  *polygonTypes.Commit
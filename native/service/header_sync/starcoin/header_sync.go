@@ -23,7 +23,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
-	"time"
 
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
@@ -61,6 +60,12 @@ func NewSTCHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.STARCOIN_ROUTER, func() scom.HeaderSyncHandler {
+		return NewSTCHandler()
+	})
+}
+
 // SyncGenesisHeader ...
 func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	params := new(scom.SyncGenesisHeaderParam)
@@ -187,8 +192,8 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 			return errors.Errorf("SyncBlockHeader, SyncBlockHeader extra-data too long: %d > %d, header: %s", len(header.BlockHeader.Extra), params.MaximumExtraDataSize, string(v))
 		}
 		//verify current time validity
-		if header.BlockHeader.Timestamp > uint64(time.Now().Add(allowedFutureBlockTime).Unix()*1000) {
-			return errors.Errorf("SyncBlockHeader,  verify header time error: checktime: %d, header: %s", time.Now().Add(allowedFutureBlockTime).Unix(), string(v))
+		if err := scom.VerifyHeaderTime(native, headerParams.ChainID, timestampSeconds(header.BlockHeader.Timestamp), allowedFutureBlockTime); err != nil {
+			return errors.Errorf("SyncBlockHeader,  verify header time error: %v, header: %s", err, string(v))
 		}
 		//verify whether current header time and prevent header time validity
 		if header.BlockHeader.Timestamp <= parentHeader.BlockHeader.Timestamp {
@@ -47,6 +47,7 @@ import (
 	//stcutils "github.com/starcoinorg/starcoin-go/utils"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -251,7 +252,7 @@ func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) *native.
 			[]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
 
 	}
-	ret, _ := native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+	ret, _ := native.NewNativeService(db, tx, uint32(time.Now().Unix()), 0, common.Uint256{0}, 0, args, false)
 	return ret
 }
 
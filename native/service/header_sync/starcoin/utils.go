@@ -38,6 +38,14 @@ type BlockDiffInfo struct {
 
 const allowedFutureBlockTime = 30 * time.Second
 
+//Starcoin block timestamp is in milliseconds, round it up to seconds
+func timestampSeconds(timestamp uint64) uint64 {
+	if timestamp%1000 != 0 {
+		return timestamp/1000 + 1
+	}
+	return timestamp / 1000
+}
+
 //const KEY_PART_TOTAL_DIFFICULTY = "totalDifficulty"
 //const KEY_PART_BLOCK_INFO = "blockInfo"
 
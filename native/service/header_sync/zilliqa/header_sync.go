@@ -41,6 +41,12 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.ZILLIQA_ROUTER, func() scom.HeaderSyncHandler {
+		return NewHandler()
+	})
+}
+
 // SyncGenesisHeader ...
 func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	params := new(scom.SyncGenesisHeaderParam)
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+)
+
+const (
+	//Key prefix of the governance application info, the info of application P + K in contract C is stored at C + APPLY_INFO + P + K
+	APPLY_INFO = "applyInfo"
+	//Key prefix of the expiry window of the governance applications set by node manager
+	APPLY_EXPIRY = "applyExpiry"
+	//Blocks the governance applications stay valid since they are created if the expiry is not set
+	DEFAULT_APPLY_EXPIRY = 100000
+	//Event name of the expired applications removed by sweeping
+	APPLY_EXPIRED = "applyExpired"
+)
+
+//Creation height and expiry window of a governance application
+type ApplyInfo struct {
+	Height uint32
+	Expiry uint32
+}
+
+func (this *ApplyInfo) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.Height)
+	sink.WriteUint32(this.Expiry)
+}
+
+func (this *ApplyInfo) Deserialization(source *common.ZeroCopySource) error {
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("ApplyInfo deserialize height error")
+	}
+	expiry, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("ApplyInfo deserialize expiry error")
+	}
+	this.Height = height
+	this.Expiry = expiry
+	return nil
+}
+
+func (this *ApplyInfo) IsExpired(height uint32) bool {
+	return uint64(height) >= uint64(this.Height)+uint64(this.Expiry)
+}
+
+//Check if the governance applications are expired, cancelled and swept, they live till approved before the activation height
+func ApplyExpiryEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetApplyExpiryHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+func applyInfoKey(contract common.Address, prefix string, key []byte) []byte {
+	return ConcatKey(contract, []byte(APPLY_INFO), []byte(prefix), key)
+}
+
+//Get the expiry window of the applications created from now on, the default is used if it is not set
+func GetApplyExpiry(native *native.NativeService) (uint32, error) {
+	item, err := GetStorageItem(native, ConcatKey(NodeManagerContractAddress, []byte(APPLY_EXPIRY)))
+	if err != nil {
+		return 0, fmt.Errorf("GetApplyExpiry, %v", err)
+	}
+	if item == nil {
+		return DEFAULT_APPLY_EXPIRY, nil
+	}
+	return GetBytesUint32(item.Value), nil
+}
+
+func PutApplyExpiry(native *native.NativeService, expiry uint32) {
+	PutBytes(native, ConcatKey(NodeManagerContractAddress, []byte(APPLY_EXPIRY)), GetUint32Bytes(expiry))
+}
+
+//Record the application of prefix and key created at current height, it is not recorded before the activation height
+func PutApplyInfo(native *native.NativeService, contract common.Address, prefix string, key []byte) error {
+	if !ApplyExpiryEnabled(native) {
+		return nil
+	}
+	expiry, err := GetApplyExpiry(native)
+	if err != nil {
+		return fmt.Errorf("PutApplyInfo, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&ApplyInfo{Height: native.GetHeight(), Expiry: expiry}).Serialization(sink)
+	native.GetCacheDB().Put(applyInfoKey(contract, prefix, key), cstates.GenRawStorageItem(sink.Bytes()))
+	return nil
+}
+
+//Get the info of application, nil is returned for the applications created before the info is recorded
+func GetApplyInfo(native *native.NativeService, contract common.Address, prefix string, key []byte) (*ApplyInfo, error) {
+	store, err := native.GetCacheDB().Get(applyInfoKey(contract, prefix, key))
+	if err != nil {
+		return nil, fmt.Errorf("GetApplyInfo, get apply info of %s error: %v", prefix, err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetApplyInfo, deserialize from raw storage item err:%v", err)
+	}
+	info := new(ApplyInfo)
+	if err := info.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetApplyInfo, deserialize apply info of %s error: %v", prefix, err)
+	}
+	return info, nil
+}
+
+func DeleteApplyInfo(native *native.NativeService, contract common.Address, prefix string, key []byte) {
+	native.GetCacheDB().Delete(applyInfoKey(contract, prefix, key))
+}
+
+//Check if the application is expired, the applications without info are not expired until they are swept, and
+//no application is expired before the activation height
+func IsApplyExpired(native *native.NativeService, contract common.Address, prefix string, key []byte) (bool, error) {
+	if !ApplyExpiryEnabled(native) {
+		return false, nil
+	}
+	info, err := GetApplyInfo(native, contract, prefix, key)
+	if err != nil {
+		return false, err
+	}
+	return info != nil && info.IsExpired(native.GetHeight()), nil
+}
+
+//Get the keys of applications stored for the prefix whose keys are not of fixed length, so no other prefix of
+//the contract should start with the prefix
+func ScanApplyKeys(native *native.NativeService, contract common.Address, prefix string) ([][]byte, error) {
+	storePrefix := ConcatKey(contract, []byte(prefix))
+	iter := native.GetCacheDB().NewIterator(storePrefix)
+	defer iter.Release()
+	keys := make([][]byte, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		key := make([]byte, len(iter.Key())-len(storePrefix))
+		copy(key, iter.Key()[len(storePrefix):])
+		keys = append(keys, key)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("ScanApplyKeys, iterate %s error: %v", prefix, err)
+	}
+	return keys, nil
+}
+
+//Remove the expired applications of prefix by remove which deletes the info as well, the applications created before the info is recorded
+//start their expiry window at current height
+func SweepApplies(native *native.NativeService, contract common.Address, prefix string, keys [][]byte,
+	remove func(key []byte) error) error {
+	for _, key := range keys {
+		info, err := GetApplyInfo(native, contract, prefix, key)
+		if err != nil {
+			return fmt.Errorf("SweepApplies, %v", err)
+		}
+		if info == nil {
+			if err := PutApplyInfo(native, contract, prefix, key); err != nil {
+				return fmt.Errorf("SweepApplies, %v", err)
+			}
+			continue
+		}
+		if !info.IsExpired(native.GetHeight()) {
+			continue
+		}
+		if err := remove(key); err != nil {
+			return fmt.Errorf("SweepApplies, remove %s %x error: %v", prefix, key, err)
+		}
+		native.AddNotify(
+			&event.NotifyEventInfo{
+				ContractAddress: contract,
+				States:          []interface{}{APPLY_EXPIRED, prefix, hex.EncodeToString(key)},
+			})
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+)
+
+//Key prefix of the storage indexes. Every key indexed for prefix P in contract C is stored as an entry at
+//C + INDEX + len(P) + P + key, and the entry without key marks the index as built
+const INDEX = "index"
+
+//Check if the storage indexes are written, the keys are only scanned from the storage before the activation height
+func IndexEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetStorageIndexHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+//Get the keys indexed for the prefix of contract storage in bytes order, every key has the length of keyLen.
+//If the index is not built yet, the keys are scanned from the storage so that the items put before are included
+func GetIndexKeys(native *native.NativeService, contract common.Address, prefix string, keyLen int) ([][]byte, error) {
+	built, err := isIndexBuilt(native, contract, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !built {
+		return scanKeys(native, ConcatKey(contract, []byte(prefix)), keyLen)
+	}
+	return scanKeys(native, indexPrefix(contract, prefix), keyLen)
+}
+
+//Add the key to the index of prefix, the index is built from the storage first if it is not built yet. Nothing is
+//written before the index is enabled
+func AddIndexKey(native *native.NativeService, contract common.Address, prefix string, key []byte) error {
+	if !IndexEnabled(native) {
+		return nil
+	}
+	if err := buildIndex(native, contract, prefix, len(key)); err != nil {
+		return err
+	}
+	native.GetCacheDB().Put(indexKey(contract, prefix, key), cstates.GenRawStorageItem([]byte{1}))
+	return nil
+}
+
+//Remove the key from the index of prefix, the index is built from the storage first if it is not built yet. Nothing
+//is written before the index is enabled
+func RemoveIndexKey(native *native.NativeService, contract common.Address, prefix string, key []byte) error {
+	if !IndexEnabled(native) {
+		return nil
+	}
+	if err := buildIndex(native, contract, prefix, len(key)); err != nil {
+		return err
+	}
+	native.GetCacheDB().Delete(indexKey(contract, prefix, key))
+	return nil
+}
+
+//The length of prefix is put ahead so that the entries of a prefix never collide with the ones of a longer prefix
+func indexPrefix(contract common.Address, prefix string) []byte {
+	return ConcatKey(contract, []byte(INDEX), []byte{byte(len(prefix))}, []byte(prefix))
+}
+
+func indexKey(contract common.Address, prefix string, key []byte) []byte {
+	return ConcatKey(contract, []byte(INDEX), []byte{byte(len(prefix))}, []byte(prefix), key)
+}
+
+func isIndexBuilt(native *native.NativeService, contract common.Address, prefix string) (bool, error) {
+	store, err := native.GetCacheDB().Get(indexPrefix(contract, prefix))
+	if err != nil {
+		return false, fmt.Errorf("isIndexBuilt, get index of %s error: %v", prefix, err)
+	}
+	return store != nil, nil
+}
+
+func buildIndex(native *native.NativeService, contract common.Address, prefix string, keyLen int) error {
+	built, err := isIndexBuilt(native, contract, prefix)
+	if err != nil || built {
+		return err
+	}
+	keys, err := scanKeys(native, ConcatKey(contract, []byte(prefix)), keyLen)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		native.GetCacheDB().Put(indexKey(contract, prefix, key), cstates.GenRawStorageItem([]byte{1}))
+	}
+	native.GetCacheDB().Put(indexPrefix(contract, prefix), cstates.GenRawStorageItem([]byte{1}))
+	return nil
+}
+
+//Keys of other prefixes starting with the store prefix are skipped by the key length
+func scanKeys(native *native.NativeService, storePrefix []byte, keyLen int) ([][]byte, error) {
+	iter := native.GetCacheDB().NewIterator(storePrefix)
+	defer iter.Release()
+	keys := make([][]byte, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		if len(iter.Key()) != len(storePrefix)+keyLen {
+			continue
+		}
+		key := make([]byte, keyLen)
+		copy(key, iter.Key()[len(storePrefix):])
+		keys = append(keys, key)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("scanKeys, iterate %x error: %v", storePrefix, err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexKeys(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	service, err := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{}, 0, nil, false)
+	assert.NoError(t, err)
+	contract := SideChainManagerContractAddress
+	networkID := config.DefConfig.P2PNode.NetworkId
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkID
+	}()
+
+	//items put before the index is built
+	db.Put(ConcatKey(contract, []byte("apply"), GetUint64Bytes(3)), cstates.GenRawStorageItem([]byte{1}))
+	db.Put(ConcatKey(contract, []byte("apply"), GetUint64Bytes(1)), cstates.GenRawStorageItem([]byte{1}))
+	keys, err := GetIndexKeys(service, contract, "apply", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{GetUint64Bytes(1), GetUint64Bytes(3)}, keys)
+
+	//nothing is written before the index is enabled
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	assert.NoError(t, AddIndexKey(service, contract, "apply", GetUint64Bytes(2)))
+	assert.NoError(t, RemoveIndexKey(service, contract, "apply", GetUint64Bytes(1)))
+	built, err := isIndexBuilt(service, contract, "apply")
+	assert.NoError(t, err)
+	assert.False(t, built)
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+
+	assert.NoError(t, AddIndexKey(service, contract, "apply", GetUint64Bytes(2)))
+	assert.NoError(t, AddIndexKey(service, contract, "apply", GetUint64Bytes(2)))
+	assert.NoError(t, AddIndexKey(service, contract, "applyAsset", GetUint64Bytes(5)))
+	keys, err = GetIndexKeys(service, contract, "apply", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{GetUint64Bytes(1), GetUint64Bytes(2), GetUint64Bytes(3)}, keys)
+
+	assert.NoError(t, RemoveIndexKey(service, contract, "apply", GetUint64Bytes(1)))
+	assert.NoError(t, RemoveIndexKey(service, contract, "apply", GetUint64Bytes(4)))
+	keys, err = GetIndexKeys(service, contract, "apply", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{GetUint64Bytes(2), GetUint64Bytes(3)}, keys)
+
+	keys, err = GetIndexKeys(service, contract, "applyAsset", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{GetUint64Bytes(5)}, keys)
+}
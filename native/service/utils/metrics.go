@@ -0,0 +1,40 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"strconv"
+
+	"github.com/polynetwork/poly/native"
+)
+
+//Label value of the chains not registered in side chain manager
+const UNKNOWN_CHAIN_LABEL = "unknown"
+
+//Key prefix of the registered side chains in side chain manager, which depends on the packages labelling metrics
+const sideChainKey = "sideChain"
+
+//Get the chain id label of metrics, the chains not registered share the label unknown so that the labels are bounded
+func ChainLabel(native *native.NativeService, chainID uint64) string {
+	store, err := native.GetCacheDB().Get(ConcatKey(SideChainManagerContractAddress, []byte(sideChainKey), GetUint64Bytes(chainID)))
+	if err != nil || store == nil {
+		return UNKNOWN_CHAIN_LABEL
+	}
+	return strconv.FormatUint(chainID, 10)
+}
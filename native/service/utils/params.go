@@ -68,19 +68,30 @@ var (
 	HARMONY_ROUTER          = uint64(21)
 	BYTOM_ROUTER            = uint64(22)
 	RIPPLE_ROUTER           = uint64(23)
+	ETH_BEACON_ROUTER       = uint64(24)
 )
 
+var routerStartBlocks = make(map[uint64]map[uint32]uint32)
+
+//Register the poly block height from which router is supported on the network,
+//router packages call it from init, it must not be called after the node started
+func RegisterRouterStartBlock(router uint64, networkId uint32, startBlock uint32) {
+	if routerStartBlocks[router] == nil {
+		routerStartBlocks[router] = make(map[uint32]uint32)
+	}
+	routerStartBlocks[router][networkId] = startBlock
+}
+
+//Get router StartBlock on current network, 0 means the router is supported since genesis
+func GetRouterStartBlock(router uint64) uint32 {
+	return routerStartBlocks[router][config.DefConfig.P2PNode.NetworkId]
+}
+
 //Check router StartBlock to prevent hard forks
 func CheckRouterStartBlock(router uint64, block uint32) (err error) {
-	var startBLock uint32
-	switch router {
-	case HARMONY_ROUTER, HSC_ROUTER, BYTOM_ROUTER:
-		if config.DefConfig.P2PNode.NetworkId == config.NETWORK_ID_MAIN_NET {
-			startBLock = 18823000
-		}
-	}
+	startBLock := GetRouterStartBlock(router)
 	if startBLock > 0 && block < startBLock {
 		return fmt.Errorf("not a supported router:%d", router)
 	}
 	return
-}
\ No newline at end of file
+}
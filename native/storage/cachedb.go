@@ -30,6 +30,14 @@ type CacheDB struct {
 	memdb      *overlaydb.MemDB
 	backend    *overlaydb.OverlayDB
 	keyScratch []byte
+	tracer     Tracer
+}
+
+// Tracer is notified of the storage read and written by smart contract, the key is without storage prefix
+// and deleted value is empty
+type Tracer interface {
+	TraceRead(key, value []byte)
+	TraceWrite(key, value []byte)
 }
 
 const initCap = 16 * 1024
@@ -47,6 +55,11 @@ func (self *CacheDB) Reset() {
 	self.memdb.Reset()
 }
 
+// SetTracer set the tracer of storage access, nil to disable tracing
+func (self *CacheDB) SetTracer(tracer Tracer) {
+	self.tracer = tracer
+}
+
 func ensureBuffer(b []byte, n int) []byte {
 	if cap(b) < n {
 		return make([]byte, n)
@@ -74,6 +87,9 @@ func (self *CacheDB) Commit() {
 
 func (self *CacheDB) Put(key []byte, value []byte) {
 	self.put(common.ST_STORAGE, key, value)
+	if self.tracer != nil {
+		self.tracer.TraceWrite(key, value)
+	}
 }
 
 func (self *CacheDB) put(prefix common.DataEntryPrefix, key []byte, value []byte) {
@@ -82,7 +98,11 @@ func (self *CacheDB) put(prefix common.DataEntryPrefix, key []byte, value []byte
 }
 
 func (self *CacheDB) Get(key []byte) ([]byte, error) {
-	return self.get(common.ST_STORAGE, key)
+	value, err := self.get(common.ST_STORAGE, key)
+	if err == nil && self.tracer != nil {
+		self.tracer.TraceRead(key, value)
+	}
+	return value, err
 }
 
 func (self *CacheDB) get(prefix common.DataEntryPrefix, key []byte) ([]byte, error) {
@@ -101,6 +121,9 @@ func (self *CacheDB) get(prefix common.DataEntryPrefix, key []byte) ([]byte, err
 
 func (self *CacheDB) Delete(key []byte) {
 	self.delete(common.ST_STORAGE, key)
+	if self.tracer != nil {
+		self.tracer.TraceWrite(key, nil)
+	}
 }
 
 // Delete item from cache
@@ -117,11 +140,28 @@ func (self *CacheDB) NewIterator(key []byte) common.StoreIterator {
 	backIter := self.backend.NewIterator(pkey)
 	memIter := self.memdb.NewIterator(prefixRange)
 
-	return &Iter{overlaydb.NewJoinIter(memIter, backIter)}
+	return &Iter{overlaydb.NewJoinIter(memIter, backIter), self.tracer}
 }
 
 type Iter struct {
 	*overlaydb.JoinIter
+	tracer Tracer
+}
+
+// First move to the first item, the iterated items are traced as read
+func (self *Iter) First() bool {
+	return self.trace(self.JoinIter.First())
+}
+
+func (self *Iter) Next() bool {
+	return self.trace(self.JoinIter.Next())
+}
+
+func (self *Iter) trace(has bool) bool {
+	if has && self.tracer != nil {
+		self.tracer.TraceRead(self.Key(), self.Value())
+	}
+	return has
 }
 
 func (self *Iter) Key() []byte {
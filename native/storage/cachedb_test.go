@@ -82,3 +82,37 @@ func TestCacheDB(t *testing.T) {
 	}
 
 }
+
+type testTracer struct {
+	ops []string
+}
+
+func (self *testTracer) TraceRead(key, value []byte) {
+	self.ops = append(self.ops, "read "+string(key)+"="+string(value))
+}
+
+func (self *testTracer) TraceWrite(key, value []byte) {
+	self.ops = append(self.ops, "write "+string(key)+"="+string(value))
+}
+
+func TestCacheDBTracer(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	cache := NewCacheDB(overlaydb.NewOverlayDB(memback))
+	tracer := &testTracer{}
+	cache.SetTracer(tracer)
+
+	cache.Put([]byte("a1"), []byte("1"))
+	cache.Put([]byte("a2"), []byte("2"))
+	cache.Delete([]byte("a1"))
+	value, err := cache.Get([]byte("a2"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("2"), value)
+	iter := cache.NewIterator([]byte("a"))
+	for has := iter.First(); has; has = iter.Next() {
+	}
+	iter.Release()
+
+	cache.SetTracer(nil)
+	cache.Put([]byte("a3"), []byte("3"))
+	assert.Equal(t, []string{"write a1=1", "write a2=2", "write a1=", "read a2=2", "read a2=2"}, tracer.ops)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package native
+
+import (
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/event"
+)
+
+const (
+	STORAGE_READ   = "read"
+	STORAGE_WRITE  = "write"
+	STORAGE_DELETE = "delete"
+)
+
+//TxTrace is the trace of re-executing a transaction
+type TxTrace struct {
+	Notify      *event.ExecuteNotify
+	CrossHashes []common.Uint256
+	Call        *CallTrace //nil if the invoke param of transaction could not be decoded
+}
+
+//CallTrace is the trace of invoking a native contract method, the native calls in the method are traced as nested calls
+type CallTrace struct {
+	Contract common.Address
+	Method   string
+	Error    string
+	Storage  []*StorageTrace //storage access of the method in execution order, the access of native calls is in the nested calls
+	Calls    []*CallTrace
+}
+
+//StorageTrace is a storage access, the key is prefixed with the address of the contract owning the storage
+type StorageTrace struct {
+	Op    string
+	Key   []byte
+	Value []byte
+}
+
+//Tracer records the native calls and storage access of a transaction, it implements storage.Tracer
+type Tracer struct {
+	call  *CallTrace
+	stack []*CallTrace
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+//Call return the trace of the top level invoke
+func (this *Tracer) Call() *CallTrace {
+	return this.call
+}
+
+func (this *Tracer) enter(contract common.Address, method string) {
+	call := &CallTrace{Contract: contract, Method: method}
+	if len(this.stack) == 0 {
+		this.call = call
+	} else {
+		parent := this.stack[len(this.stack)-1]
+		parent.Calls = append(parent.Calls, call)
+	}
+	this.stack = append(this.stack, call)
+}
+
+func (this *Tracer) exit(err error) {
+	if len(this.stack) == 0 {
+		return
+	}
+	if err != nil {
+		this.stack[len(this.stack)-1].Error = err.Error()
+	}
+	this.stack = this.stack[:len(this.stack)-1]
+}
+
+func (this *Tracer) TraceRead(key, value []byte) {
+	this.trace(STORAGE_READ, key, value)
+}
+
+func (this *Tracer) TraceWrite(key, value []byte) {
+	if len(value) == 0 {
+		this.trace(STORAGE_DELETE, key, nil)
+	} else {
+		this.trace(STORAGE_WRITE, key, value)
+	}
+}
+
+func (this *Tracer) trace(op string, key, value []byte) {
+	if len(this.stack) == 0 {
+		return
+	}
+	call := this.stack[len(this.stack)-1]
+	call.Storage = append(call.Storage, &StorageTrace{
+		Op:    op,
+		Key:   append([]byte{}, key...),
+		Value: append([]byte{}, value...),
+	})
+}
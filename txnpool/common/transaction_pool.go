@@ -39,6 +39,10 @@ type TXAttr struct {
 type TXEntry struct {
 	Tx    *types.Transaction // transaction which has been verified
 	Attrs []*TXAttr          // the result from each validator
+
+	seq     uint64         // the sequence that tx is added to the pool
+	class   txClass        // the priority class to select tx in block
+	relayer common.Address // the relayer who submits tx
 }
 
 // TXPool contains all currently valid transactions. Transactions
@@ -48,6 +52,7 @@ type TXEntry struct {
 type TXPool struct {
 	sync.RWMutex
 	txList map[common.Uint256]*TXEntry // Transactions which have been verified
+	seq    uint64                      // The sequence of the latest added transaction
 }
 
 // Init creates a new transaction pool to gather.
@@ -71,6 +76,10 @@ func (tp *TXPool) AddTxList(txEntry *TXEntry) bool {
 		return false
 	}
 
+	tp.seq++
+	txEntry.seq = tp.seq
+	txEntry.class = classifyTx(txEntry.Tx)
+	txEntry.relayer = txRelayer(txEntry.Tx)
 	tp.txList[txHash] = txEntry
 	return true
 }
@@ -119,41 +128,36 @@ func (tp *TXPool) compareTxHeight(txEntry *TXEntry, height uint32) bool {
 }
 
 // GetTxPool gets the transaction lists from the pool for the consensus,
-// if the byCount is marked, return the configured number at most; if the
-// the byCount is not marked, return all of the current transaction pool.
+// if the byCount is marked, return the configured number at most, with
+// the configured number of each relayer at most; if the byCount is not
+// marked, return all of the current transaction pool. The transactions
+// are ordered by the configured select policy.
 func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*TXEntry,
 	[]*types.Transaction) {
 	tp.RLock()
 	defer tp.RUnlock()
 
-	orderByFee := make([]*TXEntry, 0, len(tp.txList))
+	avlTxList := make([]*TXEntry, 0, len(tp.txList))
+	oldTxList := make([]*types.Transaction, 0)
 	for _, txEntry := range tp.txList {
-		orderByFee = append(orderByFee, txEntry)
+		if !tp.compareTxHeight(txEntry, height) {
+			oldTxList = append(oldTxList, txEntry.Tx)
+			continue
+		}
+		avlTxList = append(avlTxList, txEntry)
 	}
 
 	count := int(config.DefConfig.Consensus.MaxTxInBlock)
+	quota := int(config.DefConfig.Consensus.MaxTxPerRelayerInBlock)
 	if count <= 0 {
 		byCount = false
 	}
-	if len(tp.txList) < count || !byCount {
-		count = len(tp.txList)
-	}
-
-	var num int
-	txList := make([]*TXEntry, 0, count)
-	oldTxList := make([]*types.Transaction, 0)
-	for _, txEntry := range orderByFee {
-		if !tp.compareTxHeight(txEntry, height) {
-			oldTxList = append(oldTxList, txEntry.Tx)
-			continue
-		}
-		txList = append(txList, txEntry)
-		num++
-		if num >= count {
-			break
-		}
+	if !byCount {
+		count = len(avlTxList)
+		quota = 0
 	}
 
+	txList := selectTxList(avlTxList, config.DefConfig.Consensus.TxSelectPolicy, count, quota, height)
 	return txList, oldTxList
 }
 
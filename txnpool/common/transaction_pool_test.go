@@ -34,13 +34,13 @@ var (
 func init() {
 	log.Init(log.PATH, log.Stdout)
 
-	mutable := &types.MutableTransaction{
+	mutable := &types.Transaction{
 		TxType:  types.Invoke,
 		Nonce:   uint32(time.Now().Unix()),
 		Payload: &payload.InvokeCode{Code: []byte{}},
 	}
 
-	txn, _ = mutable.IntoImmutable()
+	txn, _ = types.TransactionFromRawBytes(mutable.ToArray())
 }
 
 func TestTxPool(t *testing.T) {
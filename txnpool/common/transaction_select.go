@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+// txClass is the priority class of a transaction to be selected in block,
+// the lower class is selected first.
+type txClass uint8
+
+const (
+	txClassHeaderSync txClass = iota // Header and cross chain msg syncs that the transfers depend on
+	txClassOther                     // Governance and other transactions
+	txClassTransfer                  // Cross chain transfers imported from the side chains
+)
+
+// The method names of the native contracts that the transactions are
+// classified by, the same as the header sync and cross chain manager
+// contracts, kept here so that the pool does not depend on them.
+const (
+	syncGenesisHeaderMethod   = "syncGenesisHeader"
+	syncBlockHeaderMethod     = "syncBlockHeader"
+	syncCrossChainMsgMethod   = "syncCrossChainMsg"
+	importOuterTransferMethod = "ImportOuterTransfer"
+)
+
+// classifyTx returns the priority class of a transaction by the native
+// contract and method it invokes.
+func classifyTx(tx *types.Transaction) txClass {
+	invokeCode, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return txClassOther
+	}
+	param := new(states.ContractInvokeParam)
+	if err := param.Deserialization(common.NewZeroCopySource(invokeCode.Code)); err != nil {
+		return txClassOther
+	}
+	switch param.Address {
+	case utils.HeaderSyncContractAddress:
+		switch param.Method {
+		case syncGenesisHeaderMethod, syncBlockHeaderMethod, syncCrossChainMsgMethod:
+			return txClassHeaderSync
+		}
+	case utils.CrossChainManagerContractAddress:
+		if param.Method == importOuterTransferMethod {
+			return txClassTransfer
+		}
+	}
+	return txClassOther
+}
+
+// txRelayer returns the relayer of a transaction, which is the first
+// signer, or the payer if the signatures are invalid.
+func txRelayer(tx *types.Transaction) common.Address {
+	addrs, err := tx.GetSignatureAddresses()
+	if err != nil || len(addrs) == 0 {
+		return tx.Payer
+	}
+	return addrs[0]
+}
+
+// relayerQueue is the transactions of a relayer to be selected in turn.
+type relayerQueue struct {
+	relayer common.Address
+	txs     []*TXEntry
+}
+
+// txSelector selects the transactions in block, with count in total and
+// quota of each relayer at most, 0 quota means no limit.
+type txSelector struct {
+	count  int
+	quota  int
+	used   map[common.Address]int
+	txList []*TXEntry
+}
+
+func (ts *txSelector) full() bool {
+	return len(ts.txList) >= ts.count
+}
+
+// add selects the transaction if the quota of the relayer is not used up.
+func (ts *txSelector) add(txEntry *TXEntry) bool {
+	if ts.quota > 0 && ts.used[txEntry.relayer] >= ts.quota {
+		return false
+	}
+	ts.used[txEntry.relayer]++
+	ts.txList = append(ts.txList, txEntry)
+	return true
+}
+
+// addRoundRobin selects the transactions of the relayer queues in turn,
+// one of each relayer in a round. The first relayer of the rounds is
+// rotated by block height, so that none is always favored when the block
+// is full.
+func (ts *txSelector) addRoundRobin(queues []*relayerQueue, height uint32) {
+	if len(queues) == 0 {
+		return
+	}
+	offset := int(height % uint32(len(queues)))
+	for !ts.full() {
+		selected := false
+		for i := 0; i < len(queues) && !ts.full(); i++ {
+			queue := queues[(offset+i)%len(queues)]
+			if len(queue.txs) == 0 || !ts.add(queue.txs[0]) {
+				continue
+			}
+			queue.txs = queue.txs[1:]
+			selected = true
+		}
+		if !selected {
+			return
+		}
+	}
+}
+
+// selectTxList orders the transactions by the select policy, and returns
+// count of them at most, with quota of each relayer at most.
+//
+// With TX_SELECT_POLICY_FIFO, the transactions are in the order that they
+// are added to the pool.
+//
+// With TX_SELECT_POLICY_PRIORITY, the header syncs are selected first, then
+// the other transactions, and the cross chain transfers at last, so that a
+// transfer is executed after the header it depends on in the same block.
+// In each class the relayers are selected in turn, and the transactions of
+// a relayer are ordered by nonce.
+//
+// The result only depends on the transactions in the pool and the height,
+// but not the iteration order of the pool.
+func selectTxList(entries []*TXEntry, policy string, count, quota int, height uint32) []*TXEntry {
+	ts := &txSelector{
+		count:  count,
+		quota:  quota,
+		used:   make(map[common.Address]int),
+		txList: make([]*TXEntry, 0, count),
+	}
+	if policy == config.TX_SELECT_POLICY_FIFO {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].seq < entries[j].seq
+		})
+		for _, txEntry := range entries {
+			if ts.full() {
+				break
+			}
+			ts.add(txEntry)
+		}
+		return ts.txList
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.class != b.class {
+			return a.class < b.class
+		}
+		if c := bytes.Compare(a.relayer[:], b.relayer[:]); c != 0 {
+			return c < 0
+		}
+		if a.Tx.Nonce != b.Tx.Nonce {
+			return a.Tx.Nonce < b.Tx.Nonce
+		}
+		ha, hb := a.Tx.Hash(), b.Tx.Hash()
+		return bytes.Compare(ha[:], hb[:]) < 0
+	})
+	for start := 0; start < len(entries) && !ts.full(); {
+		var queues []*relayerQueue
+		end := start
+		for ; end < len(entries) && entries[end].class == entries[start].class; end++ {
+			if len(queues) == 0 || queues[len(queues)-1].relayer != entries[end].relayer {
+				queues = append(queues, &relayerQueue{relayer: entries[end].relayer})
+			}
+			queue := queues[len(queues)-1]
+			queue.txs = append(queue.txs, entries[end])
+		}
+		ts.addRoundRobin(queues, height)
+		start = end
+	}
+	return ts.txList
+}
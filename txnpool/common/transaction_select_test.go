@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSelectTestTx(t *testing.T, relayer byte, nonce uint32, contract common.Address, method string) *types.Transaction {
+	param := &states.ContractInvokeParam{Address: contract, Method: method, Args: []byte{}}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	mutable := &types.Transaction{
+		TxType:  types.Invoke,
+		Nonce:   nonce,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
+		Payer:   common.Address{relayer},
+	}
+	sink = common.NewZeroCopySink(nil)
+	if err := mutable.Serialization(sink); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := types.TransactionFromRawBytes(sink.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestClassifyMethod(t *testing.T) {
+	assert.Equal(t, hscommon.SYNC_GENESIS_HEADER, syncGenesisHeaderMethod)
+	assert.Equal(t, hscommon.SYNC_BLOCK_HEADER, syncBlockHeaderMethod)
+	assert.Equal(t, hscommon.SYNC_CROSS_CHAIN_MSG, syncCrossChainMsgMethod)
+	assert.Equal(t, scom.IMPORT_OUTER_TRANSFER_NAME, importOuterTransferMethod)
+}
+
+func TestGetTxPoolOrder(t *testing.T) {
+	consensus := *config.DefConfig.Consensus
+	defer func() {
+		*config.DefConfig.Consensus = consensus
+	}()
+
+	syncA := newSelectTestTx(t, 1, 5, utils.HeaderSyncContractAddress, syncBlockHeaderMethod)
+	transferA := newSelectTestTx(t, 1, 1, utils.CrossChainManagerContractAddress, importOuterTransferMethod)
+	otherA := newSelectTestTx(t, 1, 9, utils.RelayerManagerContractAddress, "registerRelayer")
+	syncB1 := newSelectTestTx(t, 2, 1, utils.HeaderSyncContractAddress, syncGenesisHeaderMethod)
+	syncB2 := newSelectTestTx(t, 2, 2, utils.HeaderSyncContractAddress, syncBlockHeaderMethod)
+	transferB := newSelectTestTx(t, 2, 3, utils.CrossChainManagerContractAddress, importOuterTransferMethod)
+	added := []*types.Transaction{transferA, syncB2, otherA, transferB, syncA, syncB1}
+
+	txPool := &TXPool{}
+	txPool.Init()
+	for _, tx := range added {
+		assert.True(t, txPool.AddTxList(&TXEntry{Tx: tx, Attrs: []*TXAttr{}}))
+	}
+	hashes := func(byCount bool, height uint32) []common.Uint256 {
+		txList, oldTxList := txPool.GetTxPool(byCount, height)
+		assert.Equal(t, 0, len(oldTxList))
+		ret := make([]common.Uint256, 0, len(txList))
+		for _, txEntry := range txList {
+			ret = append(ret, txEntry.Tx.Hash())
+		}
+		return ret
+	}
+	expect := func(txs ...*types.Transaction) []common.Uint256 {
+		ret := make([]common.Uint256, 0, len(txs))
+		for _, tx := range txs {
+			ret = append(ret, tx.Hash())
+		}
+		return ret
+	}
+
+	config.DefConfig.Consensus.TxSelectPolicy = config.TX_SELECT_POLICY_PRIORITY
+	config.DefConfig.Consensus.MaxTxInBlock = 100
+	config.DefConfig.Consensus.MaxTxPerRelayerInBlock = 0
+	// header syncs first, round robin across relayers, by nonce of relayer
+	expected := expect(syncA, syncB1, syncB2, otherA, transferA, transferB)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, hashes(true, 0))
+	}
+	assert.Equal(t, expected, hashes(false, 0))
+	// the first relayer is rotated by height
+	assert.Equal(t, expect(syncB1, syncA, syncB2, otherA, transferB, transferA), hashes(true, 1))
+
+	config.DefConfig.Consensus.MaxTxInBlock = 2
+	assert.Equal(t, expect(syncA, syncB1), hashes(true, 0))
+	assert.Equal(t, len(added), len(hashes(false, 0)))
+
+	config.DefConfig.Consensus.MaxTxInBlock = 100
+	config.DefConfig.Consensus.MaxTxPerRelayerInBlock = 2
+	assert.Equal(t, expect(syncA, syncB1, syncB2, otherA), hashes(true, 0))
+	assert.Equal(t, len(added), len(hashes(false, 0)))
+
+	config.DefConfig.Consensus.TxSelectPolicy = config.TX_SELECT_POLICY_FIFO
+	config.DefConfig.Consensus.MaxTxPerRelayerInBlock = 0
+	assert.Equal(t, expect(added...), hashes(true, 0))
+	config.DefConfig.Consensus.MaxTxPerRelayerInBlock = 1
+	assert.Equal(t, expect(transferA, syncB2), hashes(true, 0))
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proc
+
+import (
+	"github.com/polynetwork/poly/common/metrics"
+)
+
+//Reasons of the transactions rejected before verification
+const (
+	REJECT_INVALID_SENDER = "invalid sender"
+	REJECT_OVERSIZE       = "oversize"
+	REJECT_DUPLICATE      = "duplicated"
+	REJECT_POOL_FULL      = "pool full"
+)
+
+var rejectedTxs = metrics.NewCounterVec("poly_txnpool_rejected_txs_total",
+	"Count of the transactions rejected by the tx pool", "reason")
+
+//Register the gauges of the server, the values are got when the metrics are collected
+func (s *TXPoolServer) registerMetrics() {
+	metrics.NewGaugeFunc("poly_txnpool_pending_txs", "Count of the transactions under verification",
+		func() float64 { return float64(s.getPendingListSize()) })
+	metrics.NewGaugeFunc("poly_txnpool_verified_txs", "Count of the verified transactions waiting for packing",
+		func() float64 { return float64(s.getTransactionCount()) })
+}
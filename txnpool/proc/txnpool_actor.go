@@ -154,6 +154,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 	if err != nil {
 		log.Debugf("handleTransaction: invalid sender for tx %x",
 			txn.Hash())
+		rejectedTxs.WithLabelValues(REJECT_INVALID_SENDER).Inc()
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, txn.Hash(), errors.ErrUnknown,
 				"invalid sender for tx")
@@ -163,6 +164,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 	ta.server.increaseStats(tc.RcvStats)
 	if len(txn.ToArray()) > tc.MAX_TX_SIZE {
 		log.Debugf("handleTransaction: reject a transaction due to size over 1M")
+		rejectedTxs.WithLabelValues(REJECT_OVERSIZE).Inc()
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, txn.Hash(), errors.ErrUnknown, "size is over 1M")
 		}
@@ -174,6 +176,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 			txn.Hash())
 
 		ta.server.increaseStats(tc.DuplicateStats)
+		rejectedTxs.WithLabelValues(REJECT_DUPLICATE).Inc()
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, txn.Hash(), errors.ErrDuplicateInput,
 				fmt.Sprintf("transaction %x is already in the tx pool", txn.Hash()))
@@ -183,6 +186,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 			txn.Hash())
 
 		ta.server.increaseStats(tc.FailureStats)
+		rejectedTxs.WithLabelValues(REJECT_POOL_FULL).Inc()
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, txn.Hash(), errors.ErrTxPoolFull,
 				"transaction pool is full")
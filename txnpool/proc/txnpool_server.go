@@ -125,6 +125,7 @@ func (s *TXPoolServer) init(num uint8, disablePreExec, disableBroadcastNetTx boo
 		s.workers[i].init(i, s)
 		go s.workers[i].start()
 	}
+	s.registerMetrics()
 }
 
 // checkPendingBlockOk checks whether a block from consensus is verified.
@@ -213,6 +214,9 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 	if pt.sender == tc.HttpSender && pt.ch != nil {
 		replyTxResult(pt.ch, hash, err, err.Error())
 	}
+	if err != errors.ErrNoError {
+		rejectedTxs.WithLabelValues(err.Error()).Inc()
+	}
 
 	delete(s.allPendingTxs, hash)
 
@@ -264,6 +268,7 @@ func (s *TXPoolServer) assignTxToWorker(tx *tx.Transaction,
 
 	if ok := s.setPendingTx(tx, sender, txResultCh); !ok {
 		s.increaseStats(tc.DuplicateStats)
+		rejectedTxs.WithLabelValues(REJECT_DUPLICATE).Inc()
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, tx.Hash(), errors.ErrDuplicateInput,
 				"duplicated transaction input detected")